@@ -14,27 +14,44 @@ import (
 )
 
 type Server struct {
-	healthHandler           *v1.HealthHandler
-	helloWorldHandler       *v1.HelloWorldHandler
-	authMiddleware          *middleware.AuthMiddleware
-	userHandler             *v1.UserHandler
-	productHandler          *v1.ProductHandler
-	productBomHandler       *v1.ProductBomHandler
-	productCategoryHandler  *v1.ProductCategoryHandler
-	unitOfMeasureHandler    *v1.UnitOfMeasureHandler
-	inventoryHandler        *v1.InventoryHandler
-	inventoryHistoryHandler *v1.InventoryHistoryHandler
-	inventoryReceiptHandler *v1.InventoryReceiptHandler
-	customerHandler         *v1.CustomerHandler
-	statisticsHandler       *v1.StatisticsHandler
-	productImageHandler     *v1.ProductImageHandler
-	orderHandler            *v1.OrderHandler
+	healthHandler             *v1.HealthHandler
+	helloWorldHandler         *v1.HelloWorldHandler
+	authMiddleware            *middleware.AuthMiddleware
+	idempotencyMiddleware     *middleware.IdempotencyMiddleware
+	userHandler               *v1.UserHandler
+	productHandler            *v1.ProductHandler
+	productBomHandler         *v1.ProductBomHandler
+	productCategoryHandler    *v1.ProductCategoryHandler
+	unitOfMeasureHandler      *v1.UnitOfMeasureHandler
+	inventoryHandler          *v1.InventoryHandler
+	inventoryHistoryHandler   *v1.InventoryHistoryHandler
+	inventoryReceiptHandler   *v1.InventoryReceiptHandler
+	customerHandler           *v1.CustomerHandler
+	statisticsHandler         *v1.StatisticsHandler
+	productImageHandler       *v1.ProductImageHandler
+	orderHandler              *v1.OrderHandler
+	paymentHandler            *v1.PaymentHandler
+	reportHandler             *v1.ReportHandler
+	salesReturnHandler        *v1.SalesReturnHandler
+	orderDocumentHandler      *v1.OrderDocumentHandler
+	quotationHandler          *v1.QuotationHandler
+	workOrderHandler          *v1.WorkOrderHandler
+	unitConversionHandler     *v1.UnitConversionHandler
+	mrpRunHandler             *v1.MrpRunHandler
+	supplierHandler           *v1.SupplierHandler
+	purchaseOrderHandler      *v1.PurchaseOrderHandler
+	warehouseHandler          *v1.WarehouseHandler
+	stockTransferHandler      *v1.StockTransferHandler
+	inventoryLotHandler       *v1.InventoryLotHandler
+	productCostHistoryHandler *v1.ProductCostHistoryHandler
+	inventoryCostLayerHandler *v1.InventoryCostLayerHandler
 }
 
 func NewServer(
 	healthHandler *v1.HealthHandler,
 	helloWorldHandler *v1.HelloWorldHandler,
 	authMiddleware *middleware.AuthMiddleware,
+	idempotencyMiddleware *middleware.IdempotencyMiddleware,
 	userHandler *v1.UserHandler,
 	productHandler *v1.ProductHandler,
 	productBomHandler *v1.ProductBomHandler,
@@ -47,23 +64,54 @@ func NewServer(
 	statisticsHandler *v1.StatisticsHandler,
 	productImageHandler *v1.ProductImageHandler,
 	orderHandler *v1.OrderHandler,
+	paymentHandler *v1.PaymentHandler,
+	reportHandler *v1.ReportHandler,
+	salesReturnHandler *v1.SalesReturnHandler,
+	orderDocumentHandler *v1.OrderDocumentHandler,
+	quotationHandler *v1.QuotationHandler,
+	workOrderHandler *v1.WorkOrderHandler,
+	unitConversionHandler *v1.UnitConversionHandler,
+	mrpRunHandler *v1.MrpRunHandler,
+	supplierHandler *v1.SupplierHandler,
+	purchaseOrderHandler *v1.PurchaseOrderHandler,
+	warehouseHandler *v1.WarehouseHandler,
+	stockTransferHandler *v1.StockTransferHandler,
+	inventoryLotHandler *v1.InventoryLotHandler,
+	productCostHistoryHandler *v1.ProductCostHistoryHandler,
+	inventoryCostLayerHandler *v1.InventoryCostLayerHandler,
 ) *Server {
 	return &Server{
-		healthHandler:           healthHandler,
-		helloWorldHandler:       helloWorldHandler,
-		authMiddleware:          authMiddleware,
-		userHandler:             userHandler,
-		productHandler:          productHandler,
-		productBomHandler:       productBomHandler,
-		productCategoryHandler:  productCategoryHandler,
-		unitOfMeasureHandler:    unitOfMeasureHandler,
-		inventoryHandler:        inventoryHandler,
-		inventoryHistoryHandler: inventoryHistoryHandler,
-		inventoryReceiptHandler: inventoryReceiptHandler,
-		customerHandler:         customerHandler,
-		statisticsHandler:       statisticsHandler,
-		productImageHandler:     productImageHandler,
-		orderHandler:            orderHandler,
+		healthHandler:             healthHandler,
+		helloWorldHandler:         helloWorldHandler,
+		authMiddleware:            authMiddleware,
+		idempotencyMiddleware:     idempotencyMiddleware,
+		userHandler:               userHandler,
+		productHandler:            productHandler,
+		productBomHandler:         productBomHandler,
+		productCategoryHandler:    productCategoryHandler,
+		unitOfMeasureHandler:      unitOfMeasureHandler,
+		inventoryHandler:          inventoryHandler,
+		inventoryHistoryHandler:   inventoryHistoryHandler,
+		inventoryReceiptHandler:   inventoryReceiptHandler,
+		customerHandler:           customerHandler,
+		statisticsHandler:         statisticsHandler,
+		productImageHandler:       productImageHandler,
+		orderHandler:              orderHandler,
+		paymentHandler:            paymentHandler,
+		reportHandler:             reportHandler,
+		salesReturnHandler:        salesReturnHandler,
+		orderDocumentHandler:      orderDocumentHandler,
+		quotationHandler:          quotationHandler,
+		workOrderHandler:          workOrderHandler,
+		unitConversionHandler:     unitConversionHandler,
+		mrpRunHandler:             mrpRunHandler,
+		supplierHandler:           supplierHandler,
+		purchaseOrderHandler:      purchaseOrderHandler,
+		warehouseHandler:          warehouseHandler,
+		stockTransferHandler:      stockTransferHandler,
+		inventoryLotHandler:       inventoryLotHandler,
+		productCostHistoryHandler: productCostHistoryHandler,
+		inventoryCostLayerHandler: inventoryCostLayerHandler,
 	}
 }
 
@@ -92,7 +140,23 @@ func (s *Server) Run() {
 		s.statisticsHandler,
 		s.productImageHandler,
 		s.orderHandler,
+		s.paymentHandler,
+		s.reportHandler,
+		s.salesReturnHandler,
+		s.orderDocumentHandler,
+		s.quotationHandler,
+		s.workOrderHandler,
+		s.unitConversionHandler,
+		s.mrpRunHandler,
+		s.supplierHandler,
+		s.purchaseOrderHandler,
+		s.warehouseHandler,
+		s.stockTransferHandler,
+		s.inventoryLotHandler,
+		s.productCostHistoryHandler,
+		s.inventoryCostLayerHandler,
 		s.authMiddleware,
+		s.idempotencyMiddleware,
 	)
 	err := httpServerInstance.ListenAndServe()
 	if err != nil {
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+type IdempotencyMiddleware struct {
+	idempotencyService service.IdempotencyService
+}
+
+func NewIdempotencyMiddleware(idempotencyService service.IdempotencyService) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		idempotencyService: idempotencyService,
+	}
+}
+
+// responseRecorder keeps a copy of the response body so that it can be stored for replays
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Deduplicate makes a create endpoint safe to retry. Requests carrying an Idempotency-Key
+// run once per user and key; retries with the same payload get the original response back,
+// a different payload with the same key is rejected. Requests without the header run as usual.
+// Must be placed after VerifyAccessToken.
+func (m *IdempotencyMiddleware) Deduplicate(c *gin.Context) {
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		c.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, idempotencyKeyHeader)
+		c.AbortWithStatusJSON(statusCode, errResponse)
+		return
+	}
+
+	// Fingerprint the request, then put the body back for the handler
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "")
+		c.AbortWithStatusJSON(statusCode, errResponse)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.FullPath() + "\n"))
+	hash.Write(body)
+	requestHash := hex.EncodeToString(hash.Sum(nil))
+
+	request, errCode := m.idempotencyService.Begin(c, GetUserIdHelper(c), key, requestHash)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, idempotencyKeyHeader)
+		c.AbortWithStatusJSON(statusCode, errResponse)
+		return
+	}
+
+	if request.Replayed {
+		c.Header(idempotentReplayedHeader, "true")
+		c.Data(request.ResponseStatusCode, "application/json; charset=utf-8", request.ResponseBody)
+		c.Abort()
+		return
+	}
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+	c.Writer = recorder
+
+	c.Next()
+
+	// Only successful responses are kept; after a failure the client may retry with the same key
+	statusCode := recorder.Status()
+	if statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices {
+		if errCode := m.idempotencyService.Complete(c, request.ID, statusCode, recorder.body.Bytes()); errCode != "" {
+			log.Error("IdempotencyMiddleware.Deduplicate Error when store response: " + errCode)
+		}
+		return
+	}
+
+	if errCode := m.idempotencyService.Release(c, request.ID); errCode != "" {
+		log.Error("IdempotencyMiddleware.Deduplicate Error when release key: " + errCode)
+	}
+}
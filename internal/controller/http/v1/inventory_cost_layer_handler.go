@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type InventoryCostLayerHandler struct {
+	inventoryCostLayerService service.InventoryCostLayerService
+}
+
+func NewInventoryCostLayerHandler(inventoryCostLayerService service.InventoryCostLayerService) *InventoryCostLayerHandler {
+	return &InventoryCostLayerHandler{
+		inventoryCostLayerService: inventoryCostLayerService,
+	}
+}
+
+// @Summary Get Stock Valuation
+// @Description Value the stock on hand with the valuation method of the deployment (INVENTORY_VALUATION_METHOD), listing the FIFO cost layers left per product
+// @Tags Inventory
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param product_id query int false "Product ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetInventoryValuationResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /inventory/valuation [get]
+func (h *InventoryCostLayerHandler) GetValuation(ctx *gin.Context) {
+	productID := 0
+	if productIDStr := ctx.Query("product_id"); productIDStr != "" {
+		id, err := strconv.Atoi(productIDStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "product_id")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		productID = id
+	}
+
+	response, errCode := h.inventoryCostLayerService.GetValuation(ctx, productID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
@@ -23,17 +23,25 @@ func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHa
 }
 
 // @Summary Get All Inventory
-// @Description Retrieve all inventory information with product details
+// @Description Retrieve the inventory of each product per warehouse with product details, showing on-hand, reserved and available quantities separately, together with the totals per warehouse
 // @Tags Inventory
 // @Produce json
 // @Param  Authorization header string true "Authorization: Bearer"
+// @Param warehouse_id query int false "Only the inventory of this warehouse"
 // @Success 200 {object} httpcommon.HttpResponse[model.GetAllInventoryResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
 // @Failure 500 {object} httpcommon.HttpResponse[any]
 // @Router /inventory [get]
 func (h *InventoryHandler) GetAll(ctx *gin.Context) {
 	context := ctx.Request.Context()
 
-	response, errCode := h.inventoryService.GetAll(context)
+	warehouseID, ok := parseWarehouseIDQuery(ctx)
+	if !ok {
+		return
+	}
+
+	response, errCode := h.inventoryService.GetAll(context, warehouseID)
 	if errCode != "" {
 		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
 		ctx.JSON(statusCode, errResponse)
@@ -44,11 +52,12 @@ func (h *InventoryHandler) GetAll(ctx *gin.Context) {
 }
 
 // @Summary Get Inventory by Product ID
-// @Description Retrieve inventory information for a specific product
+// @Description Retrieve inventory information for a specific product in one warehouse (the default warehouse when none is given), including reserved and available quantities
 // @Tags Inventory
 // @Produce json
 // @Param  Authorization header string true "Authorization: Bearer"
 // @Param productId path int true "Product ID"
+// @Param warehouse_id query int false "Warehouse ID"
 // @Success 200 {object} httpcommon.HttpResponse[model.InventoryResponse]
 // @Failure 400 {object} httpcommon.HttpResponse[any]
 // @Failure 404 {object} httpcommon.HttpResponse[any]
@@ -63,7 +72,12 @@ func (h *InventoryHandler) GetByProductID(ctx *gin.Context) {
 		return
 	}
 
-	response, errCode := h.inventoryService.GetByProductID(ctx, productID)
+	warehouseID, ok := parseWarehouseIDQuery(ctx)
+	if !ok {
+		return
+	}
+
+	response, errCode := h.inventoryService.GetByProductID(ctx, productID, warehouseID)
 	if errCode != "" {
 		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
 		ctx.JSON(statusCode, errResponse)
@@ -74,7 +88,7 @@ func (h *InventoryHandler) GetByProductID(ctx *gin.Context) {
 }
 
 // @Summary Update Inventory Quantity
-// @Description Update the quantity of a product in inventory
+// @Description Update the quantity of a product in the inventory of a warehouse (the default warehouse when none is given)
 // @Tags Inventory
 // @Accept json
 // @Produce json
@@ -115,3 +129,20 @@ func (h *InventoryHandler) UpdateQuantity(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
 }
+
+// parseWarehouseIDQuery reads the optional warehouse_id query parameter, answering the request
+// with BAD_REQUEST when it is not a number
+func parseWarehouseIDQuery(ctx *gin.Context) (*int, bool) {
+	warehouseIDStr := ctx.Query("warehouse_id")
+	if warehouseIDStr == "" {
+		return nil, true
+	}
+
+	warehouseID, err := strconv.Atoi(warehouseIDStr)
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "warehouse_id")
+		ctx.JSON(statusCode, errResponse)
+		return nil, false
+	}
+	return &warehouseID, true
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/controller/http/middleware"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	"github.com/pna/management-app-backend/internal/utils/validation"
+)
+
+type InventoryLotHandler struct {
+	inventoryLotService service.InventoryLotService
+}
+
+func NewInventoryLotHandler(inventoryLotService service.InventoryLotService) *InventoryLotHandler {
+	return &InventoryLotHandler{
+		inventoryLotService: inventoryLotService,
+	}
+}
+
+// @Summary Get All Inventory Lots
+// @Description Retrieve the lots still in stock, earliest expiry first, optionally for one product or warehouse
+// @Tags Inventory
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param product_id query int false "Product ID"
+// @Param warehouse_id query int false "Warehouse ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllInventoryLotsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /inventory/lots [get]
+func (h *InventoryLotHandler) GetAll(ctx *gin.Context) {
+	productID := 0
+	if productIDStr := ctx.Query("product_id"); productIDStr != "" {
+		id, err := strconv.Atoi(productIDStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "product_id")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		productID = id
+	}
+
+	warehouseID, ok := parseWarehouseIDQuery(ctx)
+	if !ok {
+		return
+	}
+	warehouseFilter := 0
+	if warehouseID != nil {
+		warehouseFilter = *warehouseID
+	}
+
+	response, errCode := h.inventoryLotService.GetAll(ctx, productID, warehouseFilter)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Write Off Inventory Lot
+// @Description Take stock out of one lot, typically an expired lot that is no longer issued FEFO
+// @Tags Inventory
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param lotId path int true "Lot ID"
+// @Param request body model.WriteOffInventoryLotRequest true "Write-off request"
+// @Success 200 {object} httpcommon.HttpResponse[model.InventoryLotResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /inventory/lots/{lotId}/write-off [post]
+func (h *InventoryLotHandler) WriteOff(ctx *gin.Context) {
+	lotID, err := strconv.Atoi(ctx.Param("lotId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "lotId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.WriteOffInventoryLotRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	response, errCode := h.inventoryLotService.WriteOff(ctx, lotID, request, userID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
@@ -22,11 +22,12 @@ func NewInventoryReceiptHandler(inventoryReceiptService service.InventoryReceipt
 }
 
 // @Summary Create Inventory Receipt
-// @Description Create a new inventory receipt with items
+// @Description Create a new inventory receipt with items. Items given in an alternate unit (unit_id) are converted to the product's base unit, unit_cost included. Items with purchase_order_item_id are booked against that purchase order line: the received quantity may not exceed what is still open, unit_cost defaults to the ordered price, the receipt takes the order's supplier and the order moves to PARTIALLY_RECEIVED or RECEIVED
 // @Tags Inventory Receipts
 // @Accept json
 // @Produce json
 // @Param  Authorization header string true "Authorization: Bearer"
+// @Param  Idempotency-Key header string false "Key that makes retries return the original response instead of creating another receipt"
 // @Param request body model.CreateInventoryReceiptRequest true "Inventory receipt information"
 // @Success 201 {object} httpcommon.HttpResponse[model.InventoryReceiptResponse]
 // @Failure 400 {object} httpcommon.HttpResponse[any]
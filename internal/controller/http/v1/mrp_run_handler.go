@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/controller/http/middleware"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	"github.com/pna/management-app-backend/internal/utils/validation"
+)
+
+type MrpRunHandler struct {
+	mrpRunService service.MrpRunService
+}
+
+func NewMrpRunHandler(mrpRunService service.MrpRunService) *MrpRunHandler {
+	return &MrpRunHandler{
+		mrpRunService: mrpRunService,
+	}
+}
+
+// @Summary Create MRP Run
+// @Description Explode the demand of all open (PENDING) orders and/or the given demand list through the BOMs, net it against current inventory level by level and save the per-material shortage and suggested purchase quantities as a new run. Open orders use the BOM versions in effect at their order date. Without open orders, stock they reserved is not counted as available
+// @Tags MRP
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateMrpRunRequest true "Demand to plan"
+// @Success 201 {object} httpcommon.HttpResponse[model.MrpRunResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /mrp-runs [post]
+func (h *MrpRunHandler) Create(ctx *gin.Context) {
+	var request model.CreateMrpRunRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	response, errCode := h.mrpRunService.Create(ctx, request, userID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All MRP Runs
+// @Description Retrieve saved MRP runs, newest first, without their items
+// @Tags MRP
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllMrpRunsResponse]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /mrp-runs [get]
+func (h *MrpRunHandler) GetAll(ctx *gin.Context) {
+	response, errCode := h.mrpRunService.GetAll(ctx)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get MRP Run
+// @Description Retrieve a saved MRP run with its demand and per-material results
+// @Tags MRP
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param runId path int true "MRP Run ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOneMrpRunResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /mrp-runs/{runId} [get]
+func (h *MrpRunHandler) GetOne(ctx *gin.Context) {
+	runID, err := strconv.Atoi(ctx.Param("runId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "runId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.mrpRunService.GetOne(ctx, runID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Export MRP Run
+// @Description Download the per-material results of a saved MRP run as a CSV file
+// @Tags MRP
+// @Produce text/csv
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param runId path int true "MRP Run ID"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /mrp-runs/{runId}/export.csv [get]
+func (h *MrpRunHandler) Export(ctx *gin.Context) {
+	runID, err := strconv.Atoi(ctx.Param("runId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "runId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	content, fileName, errCode := h.mrpRunService.ExportCSV(ctx, runID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	ctx.Data(http.StatusOK, "text/csv; charset=utf-8", content)
+}
+
+// @Summary Compare MRP Runs
+// @Description Line up the shortage and suggested purchase of two saved MRP runs per product
+// @Tags MRP
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param from_run_id query int true "Old MRP Run ID"
+// @Param to_run_id query int true "New MRP Run ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.MrpRunComparisonResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /mrp-runs/compare [get]
+func (h *MrpRunHandler) Compare(ctx *gin.Context) {
+	fromRunID, err := strconv.Atoi(ctx.Query("from_run_id"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "from_run_id")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+	toRunID, err := strconv.Atoi(ctx.Query("to_run_id"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "to_run_id")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.mrpRunService.Compare(ctx, fromRunID, toRunID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
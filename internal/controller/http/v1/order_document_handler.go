@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type OrderDocumentHandler struct {
+	orderDocumentService service.OrderDocumentService
+}
+
+func NewOrderDocumentHandler(orderDocumentService service.OrderDocumentService) *OrderDocumentHandler {
+	return &OrderDocumentHandler{
+		orderDocumentService: orderDocumentService,
+	}
+}
+
+// @Summary Get Order Invoice PDF
+// @Description Render a printable invoice for an order
+// @Tags Orders
+// @Produce application/pdf
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/invoice.pdf [get]
+func (h *OrderDocumentHandler) GetInvoicePDF(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	content, fileName, errCode := h.orderDocumentService.GenerateInvoicePDF(ctx, orderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `inline; filename="`+fileName+`"`)
+	ctx.Data(http.StatusOK, "application/pdf", content)
+}
+
+// @Summary Get Order Delivery Note PDF
+// @Description Render a printable delivery note for the driver
+// @Tags Orders
+// @Produce application/pdf
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Success 200 {file} file
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/delivery-note.pdf [get]
+func (h *OrderDocumentHandler) GetDeliveryNotePDF(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	content, fileName, errCode := h.orderDocumentService.GenerateDeliveryNotePDF(ctx, orderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `inline; filename="`+fileName+`"`)
+	ctx.Data(http.StatusOK, "application/pdf", content)
+}
@@ -24,12 +24,34 @@ func NewOrderHandler(orderService service.OrderService) *OrderHandler {
 	}
 }
 
+// writeOrderErrorResponse writes the error response, using the detailed inventory shortage
+// message from the service when there is one
+func writeOrderErrorResponse(ctx *gin.Context, errorCode string) {
+	// Check for detailed error message from service
+	detailedMessage, exists := ctx.Get("detailed_error_message")
+	if exists && errorCode == error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED {
+		// Use detailed message for inventory shortage
+		statusCode := http.StatusBadRequest
+		errResponse := httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: detailedMessage.(string),
+			Field:   "",
+			Code:    errorCode,
+		})
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
+	ctx.JSON(statusCode, errResponse)
+}
+
 // @Summary Create Order
-// @Description Create a new order
+// @Description Create a new order. Finished goods in stock are used first and only the shortfall is exploded through the BOM. Pending orders reserve their materials, stock is issued when the order is delivered. Items may be sold in an alternate unit (unit_id), stock is moved in the product's base unit
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Authorization: Bearer"
+// @Param Idempotency-Key header string false "Key that makes retries return the original response instead of creating another order"
 // @Param request body model.CreateOrderRequest true "Order creation information"
 // @Success 201 {object} httpcommon.HttpResponse[model.OrderResponse]
 // @Failure 400 {object} httpcommon.HttpResponse[any]
@@ -47,21 +69,7 @@ func (h *OrderHandler) CreateOrder(ctx *gin.Context) {
 
 	response, errorCode := h.orderService.CreateOrder(ctx, request, userID)
 	if errorCode != "" {
-		// Check for detailed error message from service
-		detailedMessage, exists := ctx.Get("detailed_error_message")
-		if exists && errorCode == error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED {
-			// Use detailed message for inventory shortage
-			statusCode := http.StatusBadRequest
-			errResponse := httpcommon.NewErrorResponse(httpcommon.Error{
-				Message: detailedMessage.(string),
-				Field:   "",
-				Code:    errorCode,
-			})
-			ctx.JSON(statusCode, errResponse)
-		} else {
-			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
-			ctx.JSON(statusCode, errResponse)
-		}
+		writeOrderErrorResponse(ctx, errorCode)
 		return
 	}
 
@@ -100,7 +108,7 @@ func (h *OrderHandler) GetOneOrder(ctx *gin.Context) {
 }
 
 // @Summary Update Order
-// @Description Update an existing order
+// @Description Update an existing order. Moving a pending order to DELIVERED issues its reserved materials
 // @Tags Orders
 // @Accept json
 // @Produce json
@@ -125,7 +133,85 @@ func (h *OrderHandler) Update(ctx *gin.Context) {
 	}
 
 	request.ID = orderID
-	errCode := h.orderService.Update(ctx, request)
+	userID := middleware.GetUserIdHelper(ctx)
+	errCode := h.orderService.Update(ctx, request, userID)
+	if errCode != "" {
+		writeOrderErrorResponse(ctx, errCode)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Update Order Items
+// @Description Replace the items of an existing order and adjust inventory for the difference
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Param request body model.UpdateOrderItemsRequest true "New order items"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/items [put]
+func (h *OrderHandler) UpdateItems(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateOrderItemsRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	errCode := h.orderService.UpdateItems(ctx, orderID, request, userID)
+	if errCode != "" {
+		writeOrderErrorResponse(ctx, errCode)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Cancel Order
+// @Description Cancel an order not yet delivered and without payments, releasing its reservations and restocking the materials it consumed
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Param request body model.CancelOrderRequest false "Cancellation information"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/cancel [post]
+func (h *OrderHandler) CancelOrder(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	// The request body is optional
+	var request model.CancelOrderRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+			return
+		}
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	errCode := h.orderService.CancelOrder(ctx, orderID, request, userID)
 	if errCode != "" {
 		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
 		ctx.JSON(statusCode, errResponse)
@@ -135,6 +221,35 @@ func (h *OrderHandler) Update(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
 }
 
+// @Summary Get Order Status History
+// @Description Retrieve the status changes of an order, oldest first
+// @Tags Orders
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOrderStatusHistoriesResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/status-history [get]
+func (h *OrderHandler) GetStatusHistories(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.orderService.GetStatusHistories(ctx, orderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
 // @Summary Get All Orders
 // @Description Retrieve all orders with optional filters and sorting
 // @Tags Orders
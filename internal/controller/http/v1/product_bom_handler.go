@@ -3,6 +3,7 @@ package v1
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/pna/management-app-backend/internal/utils/validation"
 
@@ -23,8 +24,26 @@ func NewProductBomHandler(bomService service.ProductBomService) *ProductBomHandl
 	}
 }
 
+// writeBomErrorResponse writes the error response, reporting the offending path when the BOM
+// would contain a cycle
+func writeBomErrorResponse(ctx *gin.Context, errorCode string) {
+	detailedMessage, exists := ctx.Get("detailed_error_message")
+	if exists && errorCode == error_utils.ErrorCode.BOM_CYCLE_DETECTED {
+		errResponse := httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: detailedMessage.(string),
+			Field:   "components",
+			Code:    errorCode,
+		})
+		ctx.JSON(http.StatusBadRequest, errResponse)
+		return
+	}
+
+	statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
+	ctx.JSON(statusCode, errResponse)
+}
+
 // @Summary Create Product BOM
-// @Description Create a Bill of Materials (BOM) for a product with multiple components
+// @Description Create a Bill of Materials (BOM) for a product with multiple components, saved as a new BOM version effective from effective_from (default now). Self-references and cycles are rejected with the offending path. Component quantities may be fractional (up to 3 decimals), scrap_percent is the share of the component lost in production
 // @Tags BOMs
 // @Accept json
 // @Produce json
@@ -43,8 +62,7 @@ func (h *ProductBomHandler) CreateProductBom(ctx *gin.Context) {
 
 	result, errorCode := h.bomService.Create(ctx, request)
 	if errorCode != "" {
-		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
-		ctx.JSON(statusCode, errResponse)
+		writeBomErrorResponse(ctx, errorCode)
 		return
 	}
 
@@ -52,7 +70,7 @@ func (h *ProductBomHandler) CreateProductBom(ctx *gin.Context) {
 }
 
 // @Summary Update Product BOM
-// @Description Update a complete Bill of Materials (BOM) for a product, replacing all existing components
+// @Description Update a complete Bill of Materials (BOM) for a product by saving the new components as a new BOM version effective from effective_from (default now). The previous version ends then and stays available for orders dated before. Self-references and cycles are rejected with the offending path. Component quantities may be fractional (up to 3 decimals), scrap_percent is the share of the component lost in production
 // @Tags BOMs
 // @Accept json
 // @Produce json
@@ -71,8 +89,7 @@ func (h *ProductBomHandler) UpdateProductBom(ctx *gin.Context) {
 
 	result, errorCode := h.bomService.Update(ctx, request)
 	if errorCode != "" {
-		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
-		ctx.JSON(statusCode, errResponse)
+		writeBomErrorResponse(ctx, errorCode)
 		return
 	}
 
@@ -80,7 +97,7 @@ func (h *ProductBomHandler) UpdateProductBom(ctx *gin.Context) {
 }
 
 // @Summary Get All Product BOMs
-// @Description Retrieve all Bills of Materials (BOMs) in the system, grouped by parent product
+// @Description Retrieve the Bills of Materials (BOMs) in effect now, grouped by parent product
 // @Tags BOMs
 // @Produce json
 // @Param Authorization header string true "Authorization: Bearer"
@@ -99,7 +116,7 @@ func (h *ProductBomHandler) GetAllProductBoms(ctx *gin.Context) {
 }
 
 // @Summary Get BOM by Parent Product ID
-// @Description Retrieve the complete Bill of Materials (BOM) for a specific parent product
+// @Description Retrieve the Bill of Materials (BOM) version in effect now for a specific parent product
 // @Tags BOMs
 // @Produce json
 // @Param Authorization header string true "Authorization: Bearer"
@@ -129,7 +146,7 @@ func (h *ProductBomHandler) GetProductBomByParentID(ctx *gin.Context) {
 }
 
 // @Summary Get BOMs by Component Product ID
-// @Description Find all Bills of Materials (BOMs) that use a specific product as a component/ingredient
+// @Description Find all Bills of Materials (BOMs) in effect now that use a specific product as a component/ingredient
 // @Tags BOMs
 // @Produce json
 // @Param Authorization header string true "Authorization: Bearer"
@@ -157,8 +174,50 @@ func (h *ProductBomHandler) GetProductBomsByComponentID(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(result))
 }
 
+// @Summary Get Component Where-Used
+// @Description List every product that uses the component at any BOM level, with the cumulative quantity of the component per unit of each product (net and with scrap and yield loss), and the open (PENDING) orders that need it
+// @Tags BOMs
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer"
+// @Param componentProductId path int true "Component Product ID"
+// @Param date query string false "Use the BOM versions in effect on this date, YYYY-MM-DD (default: now)"
+// @Success 200 {object} httpcommon.HttpResponse[model.WhereUsedResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /boms/component/{componentProductId}/where-used [get]
+func (h *ProductBomHandler) GetComponentWhereUsed(ctx *gin.Context) {
+	componentProductID, err := strconv.Atoi(ctx.Param("componentProductId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "componentProductId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	date := time.Now()
+	if dateStr := ctx.Query("date"); dateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "date format should be YYYY-MM-DD")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		// Versions that start during the day count as in effect on it
+		date = parsedDate.Add(24*time.Hour - time.Second)
+	}
+
+	result, errorCode := h.bomService.GetWhereUsed(ctx, componentProductID, date)
+	if errorCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(result))
+}
+
 // @Summary Delete Product BOM
-// @Description Delete the complete Bill of Materials (BOM) for a specific parent product
+// @Description End the Bill of Materials (BOM) of a specific parent product now. Its versions are kept for orders dated before, scheduled versions are turned off
 // @Tags BOMs
 // @Produce json
 // @Param Authorization header string true "Authorization: Bearer"
@@ -189,7 +248,7 @@ func (h *ProductBomHandler) DeleteProductBom(ctx *gin.Context) {
 }
 
 // @Summary Calculate Material Requirements
-// @Description Calculate the total raw material requirements for producing a specified quantity of a product
+// @Description Calculate the total raw material requirements for producing a specified quantity of a product. using the BOM versions in effect at date (default now). gross_quantity includes component scrap and product yield loss, net_quantity is the plain BOM quantity. required_quantity is the exact gross quantity, stock_quantity is rounded according to BOM_STOCK_ROUNDING
 // @Tags BOMs
 // @Accept json
 // @Produce json
@@ -215,3 +274,186 @@ func (h *ProductBomHandler) CalculateMaterialRequirements(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(result))
 }
+
+// @Summary Get BOM Tree
+// @Description Retrieve the full multi-level BOM structure of a product with level, quantity per parent, extended quantity and unit name. Extended quantities and the cost include component scrap and product yield loss. The cost is rolled up from the leaf materials' cost so it can be compared with the product's manually entered cost
+// @Tags BOMs
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer"
+// @Param parentProductId path int true "Parent Product ID"
+// @Param quantity query int false "Quantity of the parent product (default: 1)"
+// @Param date query string false "Use the BOM versions in effect on this date, YYYY-MM-DD (default: now)"
+// @Success 200 {object} httpcommon.HttpResponse[model.BomTreeResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /boms/parent/{parentProductId}/tree [get]
+func (h *ProductBomHandler) GetBomTree(ctx *gin.Context) {
+	parentProductID, err := strconv.Atoi(ctx.Param("parentProductId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "parentProductId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	quantity := 1
+	if quantityStr := ctx.Query("quantity"); quantityStr != "" {
+		quantity, err = strconv.Atoi(quantityStr)
+		if err != nil || quantity <= 0 {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "quantity")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+	}
+
+	date := time.Now()
+	if dateStr := ctx.Query("date"); dateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "date format should be YYYY-MM-DD")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		// Versions that start during the day count as in effect on it
+		date = parsedDate.Add(24*time.Hour - time.Second)
+	}
+
+	result, errorCode := h.bomService.GetTree(ctx, parentProductID, quantity, date)
+	if errorCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(result))
+}
+
+// @Summary Get BOM Versions
+// @Description List all BOM versions of a parent product, newest first
+// @Tags BOMs
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer"
+// @Param parentProductId path int true "Parent Product ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetBomVersionsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /boms/parent/{parentProductId}/versions [get]
+func (h *ProductBomHandler) GetBomVersions(ctx *gin.Context) {
+	parentProductIDParam := ctx.Param("parentProductId")
+	parentProductID, err := strconv.Atoi(parentProductIDParam)
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "parentProductId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	result, errorCode := h.bomService.GetVersions(ctx, parentProductID)
+	if errorCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(result))
+}
+
+// @Summary Get BOM Version
+// @Description Retrieve one BOM version with its components
+// @Tags BOMs
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer"
+// @Param versionId path int true "BOM Version ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOneProductBomResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /boms/versions/{versionId} [get]
+func (h *ProductBomHandler) GetBomVersion(ctx *gin.Context) {
+	versionID, err := strconv.Atoi(ctx.Param("versionId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "versionId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	result, errorCode := h.bomService.GetVersion(ctx, versionID)
+	if errorCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(result))
+}
+
+// @Summary Update BOM Version Status
+// @Description Turn a BOM version on or off. Inactive versions are skipped when BOMs are exploded. Turning a version back on is rejected when it would create a cycle
+// @Tags BOMs
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer"
+// @Param versionId path int true "BOM Version ID"
+// @Param request body model.UpdateBomVersionStatusRequest true "New status"
+// @Success 200 {object} httpcommon.HttpResponse[model.BomVersionResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /boms/versions/{versionId}/status [put]
+func (h *ProductBomHandler) UpdateBomVersionStatus(ctx *gin.Context) {
+	versionID, err := strconv.Atoi(ctx.Param("versionId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "versionId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateBomVersionStatusRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	result, errorCode := h.bomService.UpdateVersionStatus(ctx, versionID, request)
+	if errorCode != "" {
+		writeBomErrorResponse(ctx, errorCode)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(result))
+}
+
+// @Summary Diff BOM Versions
+// @Description List the components added, removed or changed in quantity or scrap percent between two versions of the same BOM
+// @Tags BOMs
+// @Produce json
+// @Param Authorization header string true "Authorization: Bearer"
+// @Param from_version_id query int true "Old BOM Version ID"
+// @Param to_version_id query int true "New BOM Version ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.BomVersionDiffResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /boms/versions/diff [get]
+func (h *ProductBomHandler) DiffBomVersions(ctx *gin.Context) {
+	fromVersionID, err := strconv.Atoi(ctx.Query("from_version_id"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "from_version_id")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+	toVersionID, err := strconv.Atoi(ctx.Query("to_version_id"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "to_version_id")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	result, errorCode := h.bomService.DiffVersions(ctx, fromVersionID, toVersionID)
+	if errorCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errorCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(result))
+}
@@ -0,0 +1,50 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type ProductCostHistoryHandler struct {
+	productCostHistoryService service.ProductCostHistoryService
+}
+
+func NewProductCostHistoryHandler(productCostHistoryService service.ProductCostHistoryService) *ProductCostHistoryHandler {
+	return &ProductCostHistoryHandler{
+		productCostHistoryService: productCostHistoryService,
+	}
+}
+
+// @Summary Get Product Cost Histories
+// @Description Retrieve how the moving weighted-average cost of a product changed, latest first
+// @Tags Products
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param productId path int true "Product ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllProductCostHistoriesResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /products/{productId}/cost-histories [get]
+func (h *ProductCostHistoryHandler) GetAll(ctx *gin.Context) {
+	productID, err := strconv.Atoi(ctx.Param("productId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "productId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.productCostHistoryService.GetAll(ctx, productID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
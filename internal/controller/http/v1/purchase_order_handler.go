@@ -0,0 +1,191 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/controller/http/middleware"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	"github.com/pna/management-app-backend/internal/utils/validation"
+)
+
+type PurchaseOrderHandler struct {
+	purchaseOrderService service.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(purchaseOrderService service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{
+		purchaseOrderService: purchaseOrderService,
+	}
+}
+
+// @Summary Create Purchase Order
+// @Description Create a draft purchase order from a supplier. Items given in an alternate unit (unit_id) are converted to the product's base unit, unit_cost included
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreatePurchaseOrderRequest true "Purchase order information"
+// @Success 201 {object} httpcommon.HttpResponse[model.PurchaseOrderResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders [post]
+func (h *PurchaseOrderHandler) Create(ctx *gin.Context) {
+	var request model.CreatePurchaseOrderRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	response, errCode := h.purchaseOrderService.Create(ctx, request, userID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Purchase Orders
+// @Description Retrieve purchase orders, optionally filtered by supplier and status
+// @Tags Purchase Orders
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param supplier_id query int false "Supplier ID"
+// @Param status query string false "Status (DRAFT, ORDERED, PARTIALLY_RECEIVED, RECEIVED, CANCELLED)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllPurchaseOrdersResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders [get]
+func (h *PurchaseOrderHandler) GetAll(ctx *gin.Context) {
+	supplierIDStr := ctx.Query("supplier_id")
+	status := ctx.Query("status")
+
+	supplierID := 0
+	if supplierIDStr != "" {
+		id, err := strconv.Atoi(supplierIDStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "supplier_id")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		supplierID = id
+	}
+
+	response, errCode := h.purchaseOrderService.GetAll(ctx, supplierID, status)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Purchase Order
+// @Description Retrieve a purchase order with its items and the quantities received so far
+// @Tags Purchase Orders
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param purchaseOrderId path int true "Purchase Order ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOnePurchaseOrderResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders/{purchaseOrderId} [get]
+func (h *PurchaseOrderHandler) GetOne(ctx *gin.Context) {
+	purchaseOrderID, err := strconv.Atoi(ctx.Param("purchaseOrderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "purchaseOrderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.purchaseOrderService.GetOne(ctx, purchaseOrderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Update Purchase Order
+// @Description Update a draft purchase order and replace its items
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param purchaseOrderId path int true "Purchase Order ID"
+// @Param request body model.UpdatePurchaseOrderRequest true "Purchase order information"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders/{purchaseOrderId} [put]
+func (h *PurchaseOrderHandler) Update(ctx *gin.Context) {
+	purchaseOrderID, err := strconv.Atoi(ctx.Param("purchaseOrderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "purchaseOrderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdatePurchaseOrderRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.purchaseOrderService.Update(ctx, purchaseOrderID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Update Purchase Order Status
+// @Description Send a draft purchase order to the supplier (ORDERED) or cancel it. Cancelling a partially received order closes the lines that are still open
+// @Tags Purchase Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param purchaseOrderId path int true "Purchase Order ID"
+// @Param request body model.UpdatePurchaseOrderStatusRequest true "New status"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /purchase-orders/{purchaseOrderId}/status [put]
+func (h *PurchaseOrderHandler) UpdateStatus(ctx *gin.Context) {
+	purchaseOrderID, err := strconv.Atoi(ctx.Param("purchaseOrderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "purchaseOrderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdatePurchaseOrderStatusRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.purchaseOrderService.UpdateStatus(ctx, purchaseOrderID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
@@ -0,0 +1,230 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/controller/http/middleware"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	"github.com/pna/management-app-backend/internal/utils/validation"
+)
+
+type QuotationHandler struct {
+	quotationService service.QuotationService
+}
+
+func NewQuotationHandler(quotationService service.QuotationService) *QuotationHandler {
+	return &QuotationHandler{
+		quotationService: quotationService,
+	}
+}
+
+// @Summary Create Quotation
+// @Description Create a price quotation for a customer. Quotations do not reserve or issue inventory
+// @Tags Quotations
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateQuotationRequest true "Quotation information"
+// @Success 201 {object} httpcommon.HttpResponse[model.QuotationResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /quotations [post]
+func (h *QuotationHandler) Create(ctx *gin.Context) {
+	var request model.CreateQuotationRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	response, errCode := h.quotationService.Create(ctx, request, userID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Quotations
+// @Description Retrieve quotations, optionally filtered by customer and status. Open quotations past their validity are reported as EXPIRED
+// @Tags Quotations
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param customer_id query int false "Customer ID"
+// @Param status query string false "Status (DRAFT, SENT, ACCEPTED, EXPIRED)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllQuotationsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /quotations [get]
+func (h *QuotationHandler) GetAll(ctx *gin.Context) {
+	customerIDStr := ctx.Query("customer_id")
+	status := ctx.Query("status")
+
+	customerID := 0
+	if customerIDStr != "" {
+		id, err := strconv.Atoi(customerIDStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "customer_id")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		customerID = id
+	}
+
+	response, errCode := h.quotationService.GetAll(ctx, customerID, status)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Quotation
+// @Description Retrieve a quotation with its items
+// @Tags Quotations
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param quotationId path int true "Quotation ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOneQuotationResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /quotations/{quotationId} [get]
+func (h *QuotationHandler) GetOne(ctx *gin.Context) {
+	quotationID, err := strconv.Atoi(ctx.Param("quotationId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "quotationId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.quotationService.GetOne(ctx, quotationID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Update Quotation
+// @Description Update a draft or sent quotation and replace its items
+// @Tags Quotations
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param quotationId path int true "Quotation ID"
+// @Param request body model.UpdateQuotationRequest true "Quotation information"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /quotations/{quotationId} [put]
+func (h *QuotationHandler) Update(ctx *gin.Context) {
+	quotationID, err := strconv.Atoi(ctx.Param("quotationId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "quotationId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateQuotationRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.quotationService.Update(ctx, quotationID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Update Quotation Status
+// @Description Move a quotation to SENT, ACCEPTED or EXPIRED
+// @Tags Quotations
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param quotationId path int true "Quotation ID"
+// @Param request body model.UpdateQuotationStatusRequest true "New status"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /quotations/{quotationId}/status [put]
+func (h *QuotationHandler) UpdateStatus(ctx *gin.Context) {
+	quotationID, err := strconv.Atoi(ctx.Param("quotationId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "quotationId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateQuotationStatusRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	errCode := h.quotationService.UpdateStatus(ctx, quotationID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
+
+// @Summary Convert Quotation To Order
+// @Description Create an order from a quotation. Inventory is checked and issued only at this point; only a sent quotation within its validity can be converted, and only once
+// @Tags Quotations
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param quotationId path int true "Quotation ID"
+// @Param request body model.ConvertQuotationRequest false "Order information"
+// @Success 201 {object} httpcommon.HttpResponse[model.OrderResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /quotations/{quotationId}/convert [post]
+func (h *QuotationHandler) Convert(ctx *gin.Context) {
+	quotationID, err := strconv.Atoi(ctx.Param("quotationId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "quotationId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.ConvertQuotationRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+			return
+		}
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	response, errCode := h.quotationService.Convert(ctx, quotationID, request, userID)
+	if errCode != "" {
+		writeOrderErrorResponse(ctx, errCode)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
@@ -0,0 +1,183 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type ReportHandler struct {
+	reportService service.ReportService
+}
+
+func NewReportHandler(reportService service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// @Summary Get Receivables Aging
+// @Description Group unpaid order balances by customer into 0-30, 31-60, 61-90 and 90+ day buckets based on the order date
+// @Tags Reports
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param as_of_date query string false "Report date in YYYY-MM-DD format (default: today)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetReceivablesAgingResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /reports/receivables [get]
+func (h *ReportHandler) GetReceivablesAging(ctx *gin.Context) {
+	now := time.Now()
+	asOfDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if asOfDateStr := ctx.Query("as_of_date"); asOfDateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", asOfDateStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "as_of_date format should be YYYY-MM-DD")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		asOfDate = parsedDate
+	}
+
+	response, errCode := h.reportService.GetReceivablesAging(ctx, asOfDate)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Customer Statement
+// @Description List a customer's orders and payments with a running balance
+// @Tags Reports
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param customerId path int true "Customer ID"
+// @Param from_date query string false "From date in YYYY-MM-DD format"
+// @Param to_date query string false "To date in YYYY-MM-DD format"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetCustomerStatementResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /reports/receivables/customers/{customerId}/statement [get]
+func (h *ReportHandler) GetCustomerStatement(ctx *gin.Context) {
+	customerID, err := strconv.Atoi(ctx.Param("customerId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "customerId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var fromDate *time.Time
+	var toDate *time.Time
+
+	if fromDateStr := ctx.Query("from_date"); fromDateStr != "" {
+		if parsedDate, err := time.Parse("2006-01-02", fromDateStr); err == nil {
+			fromDate = &parsedDate
+		} else {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "from_date format should be YYYY-MM-DD")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+	}
+
+	if toDateStr := ctx.Query("to_date"); toDateStr != "" {
+		if parsedDate, err := time.Parse("2006-01-02", toDateStr); err == nil {
+			toDate = &parsedDate
+		} else {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "to_date format should be YYYY-MM-DD")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+	}
+
+	response, errCode := h.reportService.GetCustomerStatement(ctx, customerID, fromDate, toDate)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Lot Recall
+// @Description List every order and customer that received a lot, with what is still in stock, in all warehouses
+// @Tags Reports
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param lot_number query string true "Lot number"
+// @Param product_id query int false "Product ID, when several products share the lot number"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetLotRecallResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /reports/lot-recall [get]
+func (h *ReportHandler) GetLotRecall(ctx *gin.Context) {
+	lotNumber := ctx.Query("lot_number")
+	if lotNumber == "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "lot_number")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	productID := 0
+	if productIDStr := ctx.Query("product_id"); productIDStr != "" {
+		id, err := strconv.Atoi(productIDStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "product_id")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		productID = id
+	}
+
+	response, errCode := h.reportService.GetLotRecall(ctx, lotNumber, productID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Stock Valuation
+// @Description Value the stock on hand at the end of a day, rebuilt from the inventory histories, with totals per category and operation type. Costs follow the valuation method of the deployment (INVENTORY_VALUATION_METHOD).
+// @Tags Reports
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param as_of query string false "Report date in YYYY-MM-DD format (default: today)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetStockValuationResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /reports/stock-valuation [get]
+func (h *ReportHandler) GetStockValuation(ctx *gin.Context) {
+	now := time.Now()
+	asOfDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if asOfStr := ctx.Query("as_of"); asOfStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", asOfStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "as_of format should be YYYY-MM-DD")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		asOfDate = parsedDate
+	}
+
+	response, errCode := h.reportService.GetStockValuation(ctx, asOfDate)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
@@ -22,7 +22,23 @@ func MapRoutes(router *gin.Engine,
 	statisticsHandler *StatisticsHandler,
 	productImageHandler *ProductImageHandler,
 	orderHandler *OrderHandler,
+	paymentHandler *PaymentHandler,
+	reportHandler *ReportHandler,
+	salesReturnHandler *SalesReturnHandler,
+	orderDocumentHandler *OrderDocumentHandler,
+	quotationHandler *QuotationHandler,
+	workOrderHandler *WorkOrderHandler,
+	unitConversionHandler *UnitConversionHandler,
+	mrpRunHandler *MrpRunHandler,
+	supplierHandler *SupplierHandler,
+	purchaseOrderHandler *PurchaseOrderHandler,
+	warehouseHandler *WarehouseHandler,
+	stockTransferHandler *StockTransferHandler,
+	inventoryLotHandler *InventoryLotHandler,
+	productCostHistoryHandler *ProductCostHistoryHandler,
+	inventoryCostLayerHandler *InventoryCostLayerHandler,
 	authMiddleware *middleware.AuthMiddleware,
+	idempotencyMiddleware *middleware.IdempotencyMiddleware,
 ) {
 	// Apply CORS middleware to all routes
 	router.Use(middleware.CorsMiddleware())
@@ -50,6 +66,7 @@ func MapRoutes(router *gin.Engine,
 			products.GET("/:productId/inventories", authMiddleware.VerifyAccessToken, inventoryHandler.GetByProductID)
 			products.PUT("/:productId/inventories/quantity", authMiddleware.VerifyAccessToken, inventoryHandler.UpdateQuantity)
 			products.GET("/:productId/inventories/histories", authMiddleware.VerifyAccessToken, inventoryHistoryHandler.GetAll)
+			products.GET("/:productId/cost-histories", authMiddleware.VerifyAccessToken, productCostHistoryHandler.GetAll)
 			products.POST("/:productId/images/upload-url", authMiddleware.VerifyAccessToken, productImageHandler.GenerateSignedUploadURL)
 			products.DELETE("/:productId/images/:imageId", authMiddleware.VerifyAccessToken, productImageHandler.DeleteImage)
 		}
@@ -60,7 +77,13 @@ func MapRoutes(router *gin.Engine,
 			boms.GET("", authMiddleware.VerifyAccessToken, productBomHandler.GetAllProductBoms)
 			boms.GET("/parent/:parentProductId", authMiddleware.VerifyAccessToken, productBomHandler.GetProductBomByParentID)
 			boms.GET("/component/:componentProductId", authMiddleware.VerifyAccessToken, productBomHandler.GetProductBomsByComponentID)
+			boms.GET("/component/:componentProductId/where-used", authMiddleware.VerifyAccessToken, productBomHandler.GetComponentWhereUsed)
 			boms.DELETE("/parent/:parentProductId", authMiddleware.VerifyAccessToken, productBomHandler.DeleteProductBom)
+			boms.GET("/parent/:parentProductId/tree", authMiddleware.VerifyAccessToken, productBomHandler.GetBomTree)
+			boms.GET("/parent/:parentProductId/versions", authMiddleware.VerifyAccessToken, productBomHandler.GetBomVersions)
+			boms.GET("/versions/diff", authMiddleware.VerifyAccessToken, productBomHandler.DiffBomVersions)
+			boms.GET("/versions/:versionId", authMiddleware.VerifyAccessToken, productBomHandler.GetBomVersion)
+			boms.PUT("/versions/:versionId/status", authMiddleware.VerifyAccessToken, productBomHandler.UpdateBomVersionStatus)
 			boms.POST("/explosion", authMiddleware.VerifyAccessToken, productBomHandler.CalculateMaterialRequirements)
 		}
 		categories := v1.Group("/categories")
@@ -79,6 +102,14 @@ func MapRoutes(router *gin.Engine,
 			units.GET("/:unitId", authMiddleware.VerifyAccessToken, unitOfMeasureHandler.GetOne)
 			units.GET("/code/:code", authMiddleware.VerifyAccessToken, unitOfMeasureHandler.GetByCode)
 		}
+		unitConversions := v1.Group("/unit-conversions")
+		{
+			unitConversions.POST("", authMiddleware.VerifyAccessToken, unitConversionHandler.Create)
+			unitConversions.GET("", authMiddleware.VerifyAccessToken, unitConversionHandler.GetAll)
+			unitConversions.GET("/:conversionId", authMiddleware.VerifyAccessToken, unitConversionHandler.GetOne)
+			unitConversions.PUT("/:conversionId", authMiddleware.VerifyAccessToken, unitConversionHandler.Update)
+			unitConversions.DELETE("/:conversionId", authMiddleware.VerifyAccessToken, unitConversionHandler.Delete)
+		}
 		customers := v1.Group("/customers")
 		{
 			customers.POST("", authMiddleware.VerifyAccessToken, customerHandler.Create)
@@ -86,13 +117,44 @@ func MapRoutes(router *gin.Engine,
 			customers.GET("", authMiddleware.VerifyAccessToken, customerHandler.GetAll)
 			customers.GET("/:customerId", authMiddleware.VerifyAccessToken, customerHandler.GetOne)
 		}
+		suppliers := v1.Group("/suppliers")
+		{
+			suppliers.POST("", authMiddleware.VerifyAccessToken, supplierHandler.Create)
+			suppliers.PUT("/:supplierId", authMiddleware.VerifyAccessToken, supplierHandler.Update)
+			suppliers.GET("", authMiddleware.VerifyAccessToken, supplierHandler.GetAll)
+			suppliers.GET("/:supplierId", authMiddleware.VerifyAccessToken, supplierHandler.GetOne)
+		}
+		purchaseOrders := v1.Group("/purchase-orders")
+		{
+			purchaseOrders.POST("", authMiddleware.VerifyAccessToken, purchaseOrderHandler.Create)
+			purchaseOrders.GET("", authMiddleware.VerifyAccessToken, purchaseOrderHandler.GetAll)
+			purchaseOrders.GET("/:purchaseOrderId", authMiddleware.VerifyAccessToken, purchaseOrderHandler.GetOne)
+			purchaseOrders.PUT("/:purchaseOrderId", authMiddleware.VerifyAccessToken, purchaseOrderHandler.Update)
+			purchaseOrders.PUT("/:purchaseOrderId/status", authMiddleware.VerifyAccessToken, purchaseOrderHandler.UpdateStatus)
+		}
+		warehouses := v1.Group("/warehouses")
+		{
+			warehouses.POST("", authMiddleware.VerifyAccessToken, warehouseHandler.Create)
+			warehouses.PUT("/:warehouseId", authMiddleware.VerifyAccessToken, warehouseHandler.Update)
+			warehouses.GET("", authMiddleware.VerifyAccessToken, warehouseHandler.GetAll)
+			warehouses.GET("/:warehouseId", authMiddleware.VerifyAccessToken, warehouseHandler.GetOne)
+		}
+		stockTransfers := v1.Group("/stock-transfers")
+		{
+			stockTransfers.POST("", authMiddleware.VerifyAccessToken, idempotencyMiddleware.Deduplicate, stockTransferHandler.Create)
+			stockTransfers.GET("", authMiddleware.VerifyAccessToken, stockTransferHandler.GetAll)
+			stockTransfers.GET("/:stockTransferId", authMiddleware.VerifyAccessToken, stockTransferHandler.GetOne)
+		}
 		inventory := v1.Group("/inventory")
 		{
 			inventory.GET("", authMiddleware.VerifyAccessToken, inventoryHandler.GetAll)
+			inventory.GET("/lots", authMiddleware.VerifyAccessToken, inventoryLotHandler.GetAll)
+			inventory.POST("/lots/:lotId/write-off", authMiddleware.VerifyAccessToken, idempotencyMiddleware.Deduplicate, inventoryLotHandler.WriteOff)
+			inventory.GET("/valuation", authMiddleware.VerifyAccessToken, inventoryCostLayerHandler.GetValuation)
 		}
 		inventoryReceipts := v1.Group("/inventory-receipts")
 		{
-			inventoryReceipts.POST("", authMiddleware.VerifyAccessToken, inventoryReceiptHandler.Create)
+			inventoryReceipts.POST("", authMiddleware.VerifyAccessToken, idempotencyMiddleware.Deduplicate, inventoryReceiptHandler.Create)
 			inventoryReceipts.GET("", authMiddleware.VerifyAccessToken, inventoryReceiptHandler.GetAll)
 			inventoryReceipts.GET("/:receiptCode", authMiddleware.VerifyAccessToken, inventoryReceiptHandler.GetOne)
 		}
@@ -102,11 +164,55 @@ func MapRoutes(router *gin.Engine,
 		}
 		orders := v1.Group("/orders")
 		{
-			orders.POST("", authMiddleware.VerifyAccessToken, orderHandler.CreateOrder)
+			orders.POST("", authMiddleware.VerifyAccessToken, idempotencyMiddleware.Deduplicate, orderHandler.CreateOrder)
 			orders.GET("/:orderId", authMiddleware.VerifyAccessToken, orderHandler.GetOneOrder)
 			orders.PUT("/:orderId", authMiddleware.VerifyAccessToken, orderHandler.Update)
+			orders.PUT("/:orderId/items", authMiddleware.VerifyAccessToken, orderHandler.UpdateItems)
+			orders.POST("/:orderId/cancel", authMiddleware.VerifyAccessToken, orderHandler.CancelOrder)
+			orders.GET("/:orderId/invoice.pdf", authMiddleware.VerifyAccessToken, orderDocumentHandler.GetInvoicePDF)
+			orders.GET("/:orderId/delivery-note.pdf", authMiddleware.VerifyAccessToken, orderDocumentHandler.GetDeliveryNotePDF)
+			orders.GET("/:orderId/status-history", authMiddleware.VerifyAccessToken, orderHandler.GetStatusHistories)
+			orders.POST("/:orderId/payments", authMiddleware.VerifyAccessToken, paymentHandler.Create)
+			orders.GET("/:orderId/payments", authMiddleware.VerifyAccessToken, paymentHandler.GetAllByOrderID)
+			orders.POST("/:orderId/returns", authMiddleware.VerifyAccessToken, salesReturnHandler.Create)
+			orders.GET("/:orderId/returns", authMiddleware.VerifyAccessToken, salesReturnHandler.GetAllByOrderID)
 			orders.GET("", authMiddleware.VerifyAccessToken, orderHandler.GetAll)
 		}
+		salesReturns := v1.Group("/sales-returns")
+		{
+			salesReturns.GET("/:returnId", authMiddleware.VerifyAccessToken, salesReturnHandler.GetOne)
+		}
+		quotations := v1.Group("/quotations")
+		{
+			quotations.POST("", authMiddleware.VerifyAccessToken, quotationHandler.Create)
+			quotations.GET("", authMiddleware.VerifyAccessToken, quotationHandler.GetAll)
+			quotations.GET("/:quotationId", authMiddleware.VerifyAccessToken, quotationHandler.GetOne)
+			quotations.PUT("/:quotationId", authMiddleware.VerifyAccessToken, quotationHandler.Update)
+			quotations.PUT("/:quotationId/status", authMiddleware.VerifyAccessToken, quotationHandler.UpdateStatus)
+			quotations.POST("/:quotationId/convert", authMiddleware.VerifyAccessToken, quotationHandler.Convert)
+		}
+		workOrders := v1.Group("/work-orders")
+		{
+			workOrders.POST("", authMiddleware.VerifyAccessToken, workOrderHandler.Create)
+			workOrders.GET("", authMiddleware.VerifyAccessToken, workOrderHandler.GetAll)
+			workOrders.GET("/:workOrderId", authMiddleware.VerifyAccessToken, workOrderHandler.GetOne)
+			workOrders.PUT("/:workOrderId/status", authMiddleware.VerifyAccessToken, workOrderHandler.UpdateStatus)
+		}
+		mrpRuns := v1.Group("/mrp-runs")
+		{
+			mrpRuns.POST("", authMiddleware.VerifyAccessToken, mrpRunHandler.Create)
+			mrpRuns.GET("", authMiddleware.VerifyAccessToken, mrpRunHandler.GetAll)
+			mrpRuns.GET("/compare", authMiddleware.VerifyAccessToken, mrpRunHandler.Compare)
+			mrpRuns.GET("/:runId", authMiddleware.VerifyAccessToken, mrpRunHandler.GetOne)
+			mrpRuns.GET("/:runId/export.csv", authMiddleware.VerifyAccessToken, mrpRunHandler.Export)
+		}
+		reports := v1.Group("/reports")
+		{
+			reports.GET("/receivables", authMiddleware.VerifyAccessToken, reportHandler.GetReceivablesAging)
+			reports.GET("/receivables/customers/:customerId/statement", authMiddleware.VerifyAccessToken, reportHandler.GetCustomerStatement)
+			reports.GET("/lot-recall", authMiddleware.VerifyAccessToken, reportHandler.GetLotRecall)
+			reports.GET("/stock-valuation", authMiddleware.VerifyAccessToken, reportHandler.GetStockValuation)
+		}
 	}
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/controller/http/middleware"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	"github.com/pna/management-app-backend/internal/utils/validation"
+)
+
+type SalesReturnHandler struct {
+	salesReturnService service.SalesReturnService
+}
+
+func NewSalesReturnHandler(salesReturnService service.SalesReturnService) *SalesReturnHandler {
+	return &SalesReturnHandler{
+		salesReturnService: salesReturnService,
+	}
+}
+
+// @Summary Create Sales Return
+// @Description Record goods coming back from a customer. Each item is restocked as the product, restocked as its BOM materials, or written off; the credit reduces the order's receivable
+// @Tags Sales Returns
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Param request body model.CreateSalesReturnRequest true "Sales return information"
+// @Success 201 {object} httpcommon.HttpResponse[model.SalesReturnResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/returns [post]
+func (h *SalesReturnHandler) Create(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.CreateSalesReturnRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	response, errCode := h.salesReturnService.Create(ctx, orderID, request, userID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Order Sales Returns
+// @Description Retrieve the sales returns recorded against an order
+// @Tags Sales Returns
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param orderId path int true "Order ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllSalesReturnsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /orders/{orderId}/returns [get]
+func (h *SalesReturnHandler) GetAllByOrderID(ctx *gin.Context) {
+	orderID, err := strconv.Atoi(ctx.Param("orderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "orderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.salesReturnService.GetAllByOrderID(ctx, orderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Sales Return
+// @Description Retrieve a sales return with its items
+// @Tags Sales Returns
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param returnId path int true "Sales Return ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOneSalesReturnResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /sales-returns/{returnId} [get]
+func (h *SalesReturnHandler) GetOne(ctx *gin.Context) {
+	returnID, err := strconv.Atoi(ctx.Param("returnId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "returnId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.salesReturnService.GetOne(ctx, returnID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
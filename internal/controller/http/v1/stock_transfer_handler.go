@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/controller/http/middleware"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	"github.com/pna/management-app-backend/internal/utils/validation"
+)
+
+type StockTransferHandler struct {
+	stockTransferService service.StockTransferService
+}
+
+func NewStockTransferHandler(stockTransferService service.StockTransferService) *StockTransferHandler {
+	return &StockTransferHandler{
+		stockTransferService: stockTransferService,
+	}
+}
+
+// @Summary Create Stock Transfer
+// @Description Move stock from one warehouse to another, its code (CK00001) is generated. Stock reserved for orders in the source warehouse cannot be moved
+// @Tags Stock Transfers
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateStockTransferRequest true "Stock transfer information"
+// @Success 201 {object} httpcommon.HttpResponse[model.StockTransferResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stock-transfers [post]
+func (h *StockTransferHandler) Create(ctx *gin.Context) {
+	var request model.CreateStockTransferRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	response, errCode := h.stockTransferService.Create(ctx, request, userID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Stock Transfers
+// @Description Retrieve all stock transfers, optionally only those leaving or entering a warehouse
+// @Tags Stock Transfers
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param warehouse_id query int false "Warehouse ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllStockTransfersResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stock-transfers [get]
+func (h *StockTransferHandler) GetAll(ctx *gin.Context) {
+	warehouseID, ok := parseWarehouseIDQuery(ctx)
+	if !ok {
+		return
+	}
+
+	filter := 0
+	if warehouseID != nil {
+		filter = *warehouseID
+	}
+
+	response, errCode := h.stockTransferService.GetAll(ctx, filter)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Stock Transfer by ID
+// @Description Retrieve a stock transfer with its items
+// @Tags Stock Transfers
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param stockTransferId path int true "Stock Transfer ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOneStockTransferResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /stock-transfers/{stockTransferId} [get]
+func (h *StockTransferHandler) GetOne(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("stockTransferId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "stockTransferId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.stockTransferService.GetOne(ctx, id)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	"github.com/pna/management-app-backend/internal/utils/validation"
+)
+
+type SupplierHandler struct {
+	supplierService service.SupplierService
+}
+
+func NewSupplierHandler(supplierService service.SupplierService) *SupplierHandler {
+	return &SupplierHandler{
+		supplierService: supplierService,
+	}
+}
+
+// @Summary Create Supplier
+// @Description Create a new supplier, its code (NCC00001) is generated
+// @Tags Suppliers
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateSupplierRequest true "Supplier information"
+// @Success 201 {object} httpcommon.HttpResponse[model.SupplierResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /suppliers [post]
+func (h *SupplierHandler) Create(ctx *gin.Context) {
+	var request model.CreateSupplierRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.supplierService.Create(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Update Supplier
+// @Description Update an existing supplier
+// @Tags Suppliers
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param supplierId path int true "Supplier ID"
+// @Param request body model.UpdateSupplierRequest true "Updated supplier information"
+// @Success 200 {object} httpcommon.HttpResponse[model.SupplierResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /suppliers/{supplierId} [put]
+func (h *SupplierHandler) Update(ctx *gin.Context) {
+	supplierID, err := strconv.Atoi(ctx.Param("supplierId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "supplierId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateSupplierRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.supplierService.Update(ctx, supplierID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Suppliers
+// @Description Retrieve all suppliers
+// @Tags Suppliers
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllSuppliersResponse]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /suppliers [get]
+func (h *SupplierHandler) GetAll(ctx *gin.Context) {
+	response, errCode := h.supplierService.GetAll(ctx)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Supplier by ID
+// @Description Retrieve a supplier by its ID
+// @Tags Suppliers
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param supplierId path int true "Supplier ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOneSupplierResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /suppliers/{supplierId} [get]
+func (h *SupplierHandler) GetOne(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("supplierId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "supplierId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.supplierService.GetOne(ctx, id)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
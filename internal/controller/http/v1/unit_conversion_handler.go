@@ -0,0 +1,181 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	"github.com/pna/management-app-backend/internal/utils/validation"
+)
+
+type UnitConversionHandler struct {
+	unitConversionService service.UnitConversionService
+}
+
+func NewUnitConversionHandler(unitConversionService service.UnitConversionService) *UnitConversionHandler {
+	return &UnitConversionHandler{
+		unitConversionService: unitConversionService,
+	}
+}
+
+// @Summary Create Unit Conversion
+// @Description Define how many to_unit one from_unit is, either for all products or for one product (e.g. 1 THUNG = 24 CAI for product X). Conversions work in both directions and can be chained
+// @Tags Unit Conversions
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateUnitConversionRequest true "Unit conversion information"
+// @Success 201 {object} httpcommon.HttpResponse[model.UnitConversionResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 409 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /unit-conversions [post]
+func (h *UnitConversionHandler) Create(ctx *gin.Context) {
+	var request model.CreateUnitConversionRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.unitConversionService.Create(ctx, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Unit Conversions
+// @Description Retrieve unit conversions, optionally only those of one product
+// @Tags Unit Conversions
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param product_id query int false "Product ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllUnitConversionsResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /unit-conversions [get]
+func (h *UnitConversionHandler) GetAll(ctx *gin.Context) {
+	productIDStr := ctx.Query("product_id")
+
+	productID := 0
+	if productIDStr != "" {
+		id, err := strconv.Atoi(productIDStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "product_id")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		productID = id
+	}
+
+	response, errCode := h.unitConversionService.GetAll(ctx, productID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Unit Conversion
+// @Description Retrieve a unit conversion
+// @Tags Unit Conversions
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param conversionId path int true "Unit Conversion ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOneUnitConversionResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /unit-conversions/{conversionId} [get]
+func (h *UnitConversionHandler) GetOne(ctx *gin.Context) {
+	conversionID, err := strconv.Atoi(ctx.Param("conversionId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "conversionId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.unitConversionService.GetOne(ctx, conversionID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Update Unit Conversion
+// @Description Update the units and factor of a unit conversion
+// @Tags Unit Conversions
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param conversionId path int true "Unit Conversion ID"
+// @Param request body model.UpdateUnitConversionRequest true "Unit conversion information"
+// @Success 200 {object} httpcommon.HttpResponse[model.UnitConversionResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 409 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /unit-conversions/{conversionId} [put]
+func (h *UnitConversionHandler) Update(ctx *gin.Context) {
+	conversionID, err := strconv.Atoi(ctx.Param("conversionId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "conversionId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateUnitConversionRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	response, errCode := h.unitConversionService.Update(ctx, conversionID, request)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Delete Unit Conversion
+// @Description Delete a unit conversion
+// @Tags Unit Conversions
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param conversionId path int true "Unit Conversion ID"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /unit-conversions/{conversionId} [delete]
+func (h *UnitConversionHandler) Delete(ctx *gin.Context) {
+	conversionID, err := strconv.Atoi(ctx.Param("conversionId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "conversionId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	errCode := h.unitConversionService.Delete(ctx, conversionID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/controller/http/middleware"
+	httpcommon "github.com/pna/management-app-backend/internal/domain/http_common"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	"github.com/pna/management-app-backend/internal/utils/validation"
+)
+
+type WorkOrderHandler struct {
+	workOrderService service.WorkOrderService
+}
+
+func NewWorkOrderHandler(workOrderService service.WorkOrderService) *WorkOrderHandler {
+	return &WorkOrderHandler{
+		workOrderService: workOrderService,
+	}
+}
+
+// @Summary Create Work Order
+// @Description Plan a production run for a MANUFACTURING or PACKAGING product. Set multi_level to also produce missing sub-assemblies from their own BOM
+// @Tags Work Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param request body model.CreateWorkOrderRequest true "Work order information"
+// @Success 201 {object} httpcommon.HttpResponse[model.WorkOrderResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /work-orders [post]
+func (h *WorkOrderHandler) Create(ctx *gin.Context) {
+	var request model.CreateWorkOrderRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	response, errCode := h.workOrderService.Create(ctx, request, userID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get All Work Orders
+// @Description Retrieve work orders, optionally filtered by product and status
+// @Tags Work Orders
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param product_id query int false "Product ID"
+// @Param status query string false "Status (PLANNED, IN_PROGRESS, DONE)"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetAllWorkOrdersResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /work-orders [get]
+func (h *WorkOrderHandler) GetAll(ctx *gin.Context) {
+	productIDStr := ctx.Query("product_id")
+	status := ctx.Query("status")
+
+	productID := 0
+	if productIDStr != "" {
+		id, err := strconv.Atoi(productIDStr)
+		if err != nil {
+			statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "product_id")
+			ctx.JSON(statusCode, errResponse)
+			return
+		}
+		productID = id
+	}
+
+	response, errCode := h.workOrderService.GetAll(ctx, productID, status)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Get Work Order
+// @Description Retrieve a work order with the components it consumed
+// @Tags Work Orders
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param workOrderId path int true "Work Order ID"
+// @Success 200 {object} httpcommon.HttpResponse[model.GetOneWorkOrderResponse]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /work-orders/{workOrderId} [get]
+func (h *WorkOrderHandler) GetOne(ctx *gin.Context) {
+	workOrderID, err := strconv.Atoi(ctx.Param("workOrderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "workOrderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	response, errCode := h.workOrderService.GetOne(ctx, workOrderID)
+	if errCode != "" {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(errCode, "")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse(response))
+}
+
+// @Summary Update Work Order Status
+// @Description Move a work order forward. IN_PROGRESS issues the BOM components from inventory, DONE adds the produced quantity to the product's inventory
+// @Tags Work Orders
+// @Accept json
+// @Produce json
+// @Param  Authorization header string true "Authorization: Bearer"
+// @Param workOrderId path int true "Work Order ID"
+// @Param request body model.UpdateWorkOrderStatusRequest true "New status"
+// @Success 200 {object} httpcommon.HttpResponse[any]
+// @Failure 400 {object} httpcommon.HttpResponse[any]
+// @Failure 404 {object} httpcommon.HttpResponse[any]
+// @Failure 500 {object} httpcommon.HttpResponse[any]
+// @Router /work-orders/{workOrderId}/status [put]
+func (h *WorkOrderHandler) UpdateStatus(ctx *gin.Context) {
+	workOrderID, err := strconv.Atoi(ctx.Param("workOrderId"))
+	if err != nil {
+		statusCode, errResponse := error_utils.ErrorCodeToHttpResponse(error_utils.ErrorCode.BAD_REQUEST, "workOrderId")
+		ctx.JSON(statusCode, errResponse)
+		return
+	}
+
+	var request model.UpdateWorkOrderStatusRequest
+	if err := validation.BindJsonAndValidate(ctx, &request); err != nil {
+		return
+	}
+
+	userID := middleware.GetUserIdHelper(ctx)
+
+	errCode := h.workOrderService.UpdateStatus(ctx, workOrderID, request, userID)
+	if errCode != "" {
+		writeOrderErrorResponse(ctx, errCode)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, httpcommon.NewSuccessResponse[any](nil))
+}
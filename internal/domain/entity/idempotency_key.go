@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+type IdempotencyKey struct {
+	ID                 int       `db:"id"`
+	UserID             int       `db:"user_id"`              // Người gửi yêu cầu
+	IdempotencyKey     string    `db:"idempotency_key"`      // Giá trị header Idempotency-Key
+	RequestHash        string    `db:"request_hash"`         // Dấu vân tay của yêu cầu
+	Status             string    `db:"status"`               // Trạng thái xử lý
+	ResponseStatusCode *int      `db:"response_status_code"` // HTTP status của phản hồi gốc
+	ResponseBody       *string   `db:"response_body"`        // Nội dung phản hồi gốc
+	CreatedAt          time.Time `db:"created_at"`
+	ExpiresAt          time.Time `db:"expires_at"` // Hết hạn lưu giữ
+}
+
+type idempotencyKeyStatus struct {
+	PROCESSING string
+	COMPLETED  string
+}
+
+var IdempotencyKeyStatus = idempotencyKeyStatus{
+	PROCESSING: "PROCESSING", // Yêu cầu gốc đang được xử lý
+	COMPLETED:  "COMPLETED",  // Đã lưu phản hồi, các lần gửi lại sẽ nhận phản hồi này
+}
@@ -1,8 +1,9 @@
 package entity
 
 type Inventory struct {
-	ID        int    `db:"id"`
-	ProductID int    `db:"product_id"`
-	Quantity  int    `db:"quantity"`
-	Version   string `db:"version"`
+	ID          int    `db:"id"`
+	ProductID   int    `db:"product_id"`
+	WarehouseID int    `db:"warehouse_id"` // Kho
+	Quantity    int    `db:"quantity"`
+	Version     string `db:"version"`
 }
@@ -0,0 +1,65 @@
+package entity
+
+import "time"
+
+type InventoryCostLayer struct {
+	ID                     int       `db:"id"`
+	ProductID              int       `db:"product_id"`                // Sản phẩm
+	SourceType             string    `db:"source_type"`               // Nguồn nhập
+	SourceID               *int      `db:"source_id"`                 // Chứng từ nhập
+	InventoryReceiptItemID *int      `db:"inventory_receipt_item_id"` // Dòng phiếu nhập tạo lớp giá
+	UnitCost               float64   `db:"unit_cost"`                 // Đơn giá theo đơn vị cơ bản (VND)
+	Quantity               int       `db:"quantity"`                  // Số lượng nhập (theo đơn vị cơ bản)
+	RemainingQuantity      int       `db:"remaining_quantity"`        // Số lượng còn lại chưa xuất
+	ReceivedAt             time.Time `db:"received_at"`               // Thời điểm nhập, thứ tự xuất FIFO
+	CreatedAt              time.Time `db:"created_at"`
+}
+
+type InventoryCostLayerMovement struct {
+	ID            int       `db:"id"`
+	CostLayerID   int       `db:"cost_layer_id"`  // Lớp giá
+	ProductID     int       `db:"product_id"`     // Sản phẩm
+	ReferenceType string    `db:"reference_type"` // Chứng từ
+	ReferenceID   *int      `db:"reference_id"`   // ID chứng từ
+	Quantity      int       `db:"quantity"`       // Số lượng (âm = xuất khỏi lớp, dương = trả lại lớp)
+	UnitCost      float64   `db:"unit_cost"`      // Đơn giá của lớp
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+type inventoryCostLayerSourceType struct {
+	OPENING_BALANCE      string
+	INVENTORY_RECEIPT    string
+	INVENTORY_ADJUSTMENT string
+	WORK_ORDER           string
+	RETURN               string
+}
+
+var InventoryCostLayerSourceType = inventoryCostLayerSourceType{
+	OPENING_BALANCE:      "OPENING_BALANCE",      // Tồn đầu kỳ khi bắt đầu theo dõi lớp giá
+	INVENTORY_RECEIPT:    "INVENTORY_RECEIPT",    // Nhập kho theo phiếu nhập
+	INVENTORY_ADJUSTMENT: "INVENTORY_ADJUSTMENT", // Điều chỉnh tăng tồn kho
+	WORK_ORDER:           "WORK_ORDER",           // Nhập thành phẩm từ lệnh sản xuất
+	RETURN:               "RETURN",               // Hàng trả lại vượt quá phần đã xuất từ lớp giá
+}
+
+type inventoryCostLayerReferenceType struct {
+	ORDER                string
+	WORK_ORDER           string
+	INVENTORY_ADJUSTMENT string
+}
+
+var InventoryCostLayerReferenceType = inventoryCostLayerReferenceType{
+	ORDER:                "ORDER",                // Xuất bán và hàng bán trả lại của đơn hàng
+	WORK_ORDER:           "WORK_ORDER",           // Xuất nguyên liệu cho lệnh sản xuất
+	INVENTORY_ADJUSTMENT: "INVENTORY_ADJUSTMENT", // Điều chỉnh giảm tồn kho
+}
+
+type inventoryValuationMethod struct {
+	AVERAGE string
+	FIFO    string
+}
+
+var InventoryValuationMethod = inventoryValuationMethod{
+	AVERAGE: "AVERAGE", // Bình quân gia quyền di động
+	FIFO:    "FIFO",    // Nhập trước xuất trước
+}
@@ -5,10 +5,28 @@ import "time"
 type InventoryHistory struct {
 	ID            int       `db:"id"`
 	ProductID     int       `db:"product_id"`
+	WarehouseID   *int      `db:"warehouse_id"` // Kho phát sinh biến động
 	Quantity      int       `db:"quantity"`
 	FinalQuantity int       `db:"final_quantity"`
 	ImporterName  string    `db:"importer_name"`
 	ImportedAt    time.Time `db:"imported_at"`
 	Note          string    `db:"note"`
 	ReferenceID   *int      `db:"reference_id"`
+	ReferenceType *string   `db:"reference_type"` // Loại tham chiếu của ReferenceID (ORDER, INVENTORY_RECEIPT, ...)
+}
+
+type inventoryHistoryReferenceType struct {
+	ORDER             string
+	INVENTORY_RECEIPT string
+	SALES_RETURN      string
+	WORK_ORDER        string
+	STOCK_TRANSFER    string
+}
+
+var InventoryHistoryReferenceType = inventoryHistoryReferenceType{
+	ORDER:             "ORDER",
+	INVENTORY_RECEIPT: "INVENTORY_RECEIPT",
+	SALES_RETURN:      "SALES_RETURN",
+	WORK_ORDER:        "WORK_ORDER",
+	STOCK_TRANSFER:    "STOCK_TRANSFER",
 }
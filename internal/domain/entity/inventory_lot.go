@@ -0,0 +1,62 @@
+package entity
+
+import "time"
+
+// InventoryLot is the balance of one lot of a product in a warehouse. Stock received without a
+// lot number is not tracked here, so the lots of a product add up to at most its inventory.
+type InventoryLot struct {
+	ID          int        `db:"id"`
+	ProductID   int        `db:"product_id"`   // Sản phẩm
+	WarehouseID int        `db:"warehouse_id"` // Kho
+	LotNumber   string     `db:"lot_number"`   // Số lô
+	ExpiryDate  *time.Time `db:"expiry_date"`  // Hạn sử dụng
+	Quantity    int        `db:"quantity"`     // Tồn kho của lô (theo đơn vị cơ bản)
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+// OrderItemLot is one entry of the ledger of lots drawn by orders. The quantity an order holds
+// from a lot is the sum of its entries.
+type OrderItemLot struct {
+	ID             int       `db:"id"`
+	OrderID        int       `db:"order_id"`         // Đơn hàng
+	OrderItemID    *int      `db:"order_item_id"`    // Dòng đơn hàng (nil khi là nguyên liệu của thành phẩm)
+	ProductID      int       `db:"product_id"`       // Sản phẩm xuất kho
+	InventoryLotID int       `db:"inventory_lot_id"` // Lô xuất kho
+	Quantity       int       `db:"quantity"`         // Số lượng (dương = xuất, âm = hoàn lại lô)
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// WorkOrderLot is the quantity of a lot a work order consumed as a component
+type WorkOrderLot struct {
+	ID             int       `db:"id"`
+	WorkOrderID    int       `db:"work_order_id"`    // Lệnh sản xuất
+	ProductID      int       `db:"product_id"`       // Thành phần xuất kho
+	InventoryLotID int       `db:"inventory_lot_id"` // Lô xuất kho
+	Quantity       int       `db:"quantity"`         // Số lượng đã tiêu hao từ lô (theo đơn vị cơ bản)
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// LotConsumption is the quantity of a lot a work order consumed, with the lot its finished goods
+// were received into
+type LotConsumption struct {
+	InventoryLotID  int    `db:"inventory_lot_id"`
+	WorkOrderID     int    `db:"work_order_id"`
+	WorkOrderCode   string `db:"work_order_code"`
+	WorkOrderStatus string `db:"work_order_status"`
+	ProductID       int    `db:"product_id"`
+	Quantity        int    `db:"quantity"`
+	FinishedLotID   *int   `db:"finished_lot_id"`
+}
+
+// LotShipment is the net quantity of a lot an order item received, with the order and customer
+type LotShipment struct {
+	InventoryLotID int       `db:"inventory_lot_id"`
+	OrderID        int       `db:"order_id"`
+	OrderCode      string    `db:"order_code"`
+	OrderDate      time.Time `db:"order_date"`
+	CustomerID     int       `db:"customer_id"`
+	OrderItemID    *int      `db:"order_item_id"`
+	ProductID      int       `db:"product_id"`
+	Quantity       int       `db:"quantity"`
+}
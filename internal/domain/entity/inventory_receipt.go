@@ -6,6 +6,8 @@ type InventoryReceipt struct {
 	ID          int       `db:"id"`
 	Code        string    `db:"code"`
 	UserID      int       `db:"user_id"`
+	SupplierID  *int      `db:"supplier_id"`  // Nhà cung cấp
+	WarehouseID int       `db:"warehouse_id"` // Kho nhập hàng
 	ReceiptDate time.Time `db:"receipt_date"`
 	Notes       *string   `db:"notes"`
 	TotalItems  int       `db:"total_items"`
@@ -3,12 +3,16 @@ package entity
 import "time"
 
 type InventoryReceiptItem struct {
-	ID                 int       `db:"id"`
-	InventoryReceiptID int       `db:"inventory_receipt_id"`
-	ProductID          int       `db:"product_id"`
-	Quantity           int       `db:"quantity"`
-	UnitCost           *float64  `db:"unit_cost"`
-	Notes              *string   `db:"notes"`
-	CreatedAt          time.Time `db:"created_at"`
-	UpdatedAt          time.Time `db:"updated_at"`
+	ID                  int        `db:"id"`
+	InventoryReceiptID  int        `db:"inventory_receipt_id"`
+	ProductID           int        `db:"product_id"`
+	PurchaseOrderItemID *int       `db:"purchase_order_item_id"` // Dòng đơn mua hàng được nhận
+	Quantity            int        `db:"quantity"`
+	UnitCost            *float64   `db:"unit_cost"`
+	LotNumber           *string    `db:"lot_number"`       // Số lô
+	ExpiryDate          *time.Time `db:"expiry_date"`      // Hạn sử dụng
+	InventoryLotID      *int       `db:"inventory_lot_id"` // Lô được nhập vào
+	Notes               *string    `db:"notes"`
+	CreatedAt           time.Time  `db:"created_at"`
+	UpdatedAt           time.Time  `db:"updated_at"`
 }
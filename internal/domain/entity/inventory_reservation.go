@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// InventoryReservation is one entry of the reservation ledger. The reserved quantity of a
+// product is the sum of its entries.
+type InventoryReservation struct {
+	ID            int       `db:"id"`
+	ProductID     int       `db:"product_id"`      // Nguyên vật liệu được giữ chỗ
+	WarehouseID   int       `db:"warehouse_id"`    // Kho giữ chỗ
+	OrderID       int       `db:"order_id"`        // Đơn hàng giữ chỗ
+	Quantity      int       `db:"quantity"`        // Số lượng thay đổi (dương = giữ chỗ, âm = nhả hoặc xuất kho)
+	EntryType     string    `db:"entry_type"`      // Loại bút toán
+	Note          *string   `db:"note"`            // Ghi chú
+	CreatedByName string    `db:"created_by_name"` // Tên người thực hiện
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+type inventoryReservationEntryType struct {
+	RESERVE string
+	RELEASE string
+	ISSUE   string
+}
+
+var InventoryReservationEntryType = inventoryReservationEntryType{
+	RESERVE: "RESERVE", // Giữ chỗ khi tạo hoặc tăng đơn hàng
+	RELEASE: "RELEASE", // Nhả chỗ khi giảm hoặc hủy đơn hàng
+	ISSUE:   "ISSUE",   // Chuyển thành xuất kho khi giao hàng
+}
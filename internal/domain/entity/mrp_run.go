@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+type MrpRun struct {
+	ID                int       `db:"id"`
+	Code              string    `db:"code"`                // Mã kế hoạch (MRP00001)
+	IncludeOpenOrders bool      `db:"include_open_orders"` // Tính cả nhu cầu của các đơn hàng đang chờ giao
+	Note              *string   `db:"note"`                // Ghi chú
+	TotalMaterials    int       `db:"total_materials"`     // Số sản phẩm trong kế hoạch
+	ShortageMaterials int       `db:"shortage_materials"`  // Số nguyên liệu cần mua thêm
+	CreatedBy         *int      `db:"created_by"`          // Người chạy kế hoạch
+	CreatedByName     string    `db:"created_by_name"`     // Tên người chạy kế hoạch
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+type MrpRunDemand struct {
+	ID        int  `db:"id"`
+	MrpRunID  int  `db:"mrp_run_id"` // Kế hoạch
+	ProductID int  `db:"product_id"` // Sản phẩm có nhu cầu
+	OrderID   *int `db:"order_id"`   // Đơn hàng phát sinh nhu cầu (nil = nhập tay)
+	Quantity  int  `db:"quantity"`   // Số lượng theo đơn vị cơ bản
+}
+
+type MrpRunItem struct {
+	ID                int     `db:"id"`
+	MrpRunID          int     `db:"mrp_run_id"`         // Kế hoạch
+	ProductID         int     `db:"product_id"`         // Sản phẩm/nguyên liệu
+	GrossRequirement  float64 `db:"gross_requirement"`  // Tổng nhu cầu trước khi trừ tồn kho
+	AvailableStock    int     `db:"available_stock"`    // Tồn kho dùng được cho kế hoạch
+	NetRequirement    float64 `db:"net_requirement"`    // Phần thiếu sau khi trừ tồn kho
+	PlannedProduction int     `db:"planned_production"` // Số lượng cần sản xuất (sản phẩm có BOM)
+	SuggestedPurchase int     `db:"suggested_purchase"` // Số lượng đề xuất mua (nguyên liệu không có BOM)
+}
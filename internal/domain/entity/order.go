@@ -6,6 +6,7 @@ type Order struct {
 	ID                 int       `db:"id"`
 	Code               string    `db:"code"`                 // Mã đơn hàng (DH00001)
 	CustomerID         int       `db:"customer_id"`          // Khách hàng
+	WarehouseID        int       `db:"warehouse_id"`         // Kho xuất hàng
 	OrderDate          time.Time `db:"order_date"`           // Ngày đặt hàng
 	Note               *string   `db:"note"`                 // Ghi chú
 	TotalOriginalCost  int       `db:"total_original_cost"`  // Tổng giá vốn sản phẩm
@@ -21,6 +22,7 @@ type orderDeliveryStatus struct {
 	DELIVERED string
 	UNPAID    string
 	COMPLETED string
+	CANCELLED string
 }
 
 var OrderDeliveryStatus = orderDeliveryStatus{
@@ -28,4 +30,5 @@ var OrderDeliveryStatus = orderDeliveryStatus{
 	DELIVERED: "DELIVERED",
 	UNPAID:    "UNPAID",
 	COMPLETED: "COMPLETED",
+	CANCELLED: "CANCELLED",
 }
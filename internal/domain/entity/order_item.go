@@ -1,12 +1,14 @@
 package entity
 
 type OrderItem struct {
-	ID              int `db:"id"`
-	OrderID         int `db:"order_id"`         // Đơn hàng
-	ProductID       int `db:"product_id"`       // Sản phẩm (có thể là direct product hoặc parent product từ BOM)
-	Quantity        int `db:"quantity"`         // Số lượng
-	SellingPrice    int `db:"selling_price"`    // Giá bán
-	OriginalPrice   int `db:"original_price"`   // Giá vốn
-	DiscountPercent int `db:"discount_percent"` // Chiết khấu
-	FinalAmount     int `db:"final_amount"`     // Tổng tiền
+	ID              int  `db:"id"`
+	OrderID         int  `db:"order_id"`         // Đơn hàng
+	ProductID       int  `db:"product_id"`       // Sản phẩm (có thể là direct product hoặc parent product từ BOM)
+	Quantity        int  `db:"quantity"`         // Số lượng (theo đơn vị bán)
+	UnitID          *int `db:"unit_id"`          // Đơn vị bán (nil = đơn vị cơ bản của sản phẩm)
+	BaseQuantity    int  `db:"base_quantity"`    // Số lượng quy đổi theo đơn vị cơ bản, dùng cho tồn kho
+	SellingPrice    int  `db:"selling_price"`    // Giá bán
+	OriginalPrice   int  `db:"original_price"`   // Giá vốn
+	DiscountPercent int  `db:"discount_percent"` // Chiết khấu
+	FinalAmount     int  `db:"final_amount"`     // Tổng tiền
 }
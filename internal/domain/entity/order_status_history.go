@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type OrderStatusHistory struct {
+	ID            int       `db:"id"`
+	OrderID       int       `db:"order_id"`        // Đơn hàng
+	FromStatus    *string   `db:"from_status"`     // Trạng thái trước (nil khi tạo đơn)
+	ToStatus      string    `db:"to_status"`       // Trạng thái mới
+	ChangedBy     *int      `db:"changed_by"`      // Người thay đổi
+	ChangedByName string    `db:"changed_by_name"` // Tên người thay đổi
+	Reason        *string   `db:"reason"`          // Lý do thay đổi
+	ChangedAt     time.Time `db:"changed_at"`      // Thời gian thay đổi
+}
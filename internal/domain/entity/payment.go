@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+type Payment struct {
+	ID              int       `db:"id"`
+	OrderID         int       `db:"order_id"`         // Đơn hàng
+	Amount          int       `db:"amount"`           // Số tiền thanh toán
+	PaymentMethod   string    `db:"payment_method"`   // Hình thức thanh toán
+	PaymentDate     time.Time `db:"payment_date"`     // Ngày thanh toán
+	ReferenceNumber *string   `db:"reference_number"` // Mã giao dịch / số chứng từ
+	Note            *string   `db:"note"`             // Ghi chú
+	CreatedBy       *int      `db:"created_by"`       // Người ghi nhận
+	CreatedByName   string    `db:"created_by_name"`  // Tên người ghi nhận
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+type paymentMethod struct {
+	CASH          string
+	BANK_TRANSFER string
+	OTHER         string
+}
+
+var PaymentMethod = paymentMethod{
+	CASH:          "CASH",
+	BANK_TRANSFER: "BANK_TRANSFER",
+	OTHER:         "OTHER",
+}
+
+type orderPaymentStatus struct {
+	UNPAID         string
+	PARTIALLY_PAID string
+	PAID           string
+}
+
+// OrderPaymentStatus is derived from the payments recorded against an order, it is not stored
+var OrderPaymentStatus = orderPaymentStatus{
+	UNPAID:         "UNPAID",
+	PARTIALLY_PAID: "PARTIALLY_PAID",
+	PAID:           "PAID",
+}
@@ -9,4 +9,5 @@ type Product struct {
 	UnitID        *int    `db:"unit_id"`        // ID đơn vị tính
 	Description   string  `db:"description"`    // Mô tả chi tiết sản phẩm
 	OperationType string  `db:"operation_type"` // Loại sản phẩm: MANUFACTURING hoặc PACKAGING
+	YieldPercent  float64 `db:"yield_percent"`  // Hiệu suất sản xuất (%), nguyên liệu được chia cho tỷ lệ này
 }
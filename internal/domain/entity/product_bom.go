@@ -4,9 +4,35 @@ import "time"
 
 type ProductBom struct {
 	ID                 int       `db:"id"`
+	BomVersionID       int       `db:"bom_version_id"`       // ID phiên bản BOM
 	ParentProductID    int       `db:"parent_product_id"`    // ID sản phẩm thành phẩm
 	ComponentProductID int       `db:"component_product_id"` // ID sản phẩm nguyên liệu
-	Quantity           int       `db:"quantity"`             // Số lượng nguyên liệu cần thiết
+	Quantity           float64   `db:"quantity"`             // Số lượng nguyên liệu cần thiết (tối đa 3 chữ số thập phân)
+	ScrapPercent       float64   `db:"scrap_percent"`        // Tỷ lệ hao hụt của nguyên liệu (%)
 	CreatedAt          time.Time `db:"created_at"`           // Thời gian tạo
 	UpdatedAt          time.Time `db:"updated_at"`           // Thời gian cập nhật
 }
+
+type ProductBomVersion struct {
+	ID              int        `db:"id"`
+	ParentProductID int        `db:"parent_product_id"` // ID sản phẩm thành phẩm
+	VersionNumber   int        `db:"version_number"`    // Số phiên bản, tăng dần theo sản phẩm
+	EffectiveFrom   time.Time  `db:"effective_from"`    // Thời điểm bắt đầu có hiệu lực
+	EffectiveTo     *time.Time `db:"effective_to"`      // Thời điểm hết hiệu lực (nil = chưa hết hiệu lực)
+	IsActive        bool       `db:"is_active"`         // Phiên bản bị tắt sẽ không được dùng khi tính nguyên liệu
+	Note            *string    `db:"note"`              // Ghi chú thay đổi
+	CreatedAt       time.Time  `db:"created_at"`        // Thời gian tạo
+	UpdatedAt       time.Time  `db:"updated_at"`        // Thời gian cập nhật
+}
+
+type bomChangeType struct {
+	ADDED   string
+	REMOVED string
+	CHANGED string
+}
+
+var BomChangeType = bomChangeType{
+	ADDED:   "ADDED",   // Nguyên liệu mới có ở phiên bản sau
+	REMOVED: "REMOVED", // Nguyên liệu bị bỏ ở phiên bản sau
+	CHANGED: "CHANGED", // Nguyên liệu đổi số lượng
+}
@@ -0,0 +1,27 @@
+package entity
+
+import "time"
+
+type ProductCostHistory struct {
+	ID               int       `db:"id"`
+	ProductID        int       `db:"product_id"`        // Sản phẩm
+	PreviousCost     float64   `db:"previous_cost"`     // Giá vốn trước thay đổi (VND)
+	NewCost          float64   `db:"new_cost"`          // Giá vốn sau thay đổi (VND)
+	OnHandQuantity   int       `db:"on_hand_quantity"`  // Tồn kho (mọi kho) trước khi nhập
+	ReceivedQuantity int       `db:"received_quantity"` // Số lượng nhập (theo đơn vị cơ bản)
+	UnitCost         *float64  `db:"unit_cost"`         // Đơn giá nhập theo đơn vị cơ bản
+	ReferenceType    string    `db:"reference_type"`    // Nguồn thay đổi
+	ReferenceID      *int      `db:"reference_id"`      // Chứng từ gây thay đổi
+	CreatedByName    *string   `db:"created_by_name"`   // Người thực hiện
+	CreatedAt        time.Time `db:"created_at"`
+}
+
+type productCostHistoryReferenceType struct {
+	INVENTORY_RECEIPT string
+	MANUAL            string
+}
+
+var ProductCostHistoryReferenceType = productCostHistoryReferenceType{
+	INVENTORY_RECEIPT: "INVENTORY_RECEIPT", // Nhập kho theo phiếu nhập
+	MANUAL:            "MANUAL",            // Sửa giá vốn trực tiếp trên sản phẩm
+}
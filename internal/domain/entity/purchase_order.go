@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+type PurchaseOrder struct {
+	ID           int        `db:"id"`
+	Code         string     `db:"code"`          // Mã đơn mua hàng (MH00001)
+	SupplierID   int        `db:"supplier_id"`   // Nhà cung cấp
+	OrderDate    time.Time  `db:"order_date"`    // Ngày đặt mua
+	ExpectedDate *time.Time `db:"expected_date"` // Ngày dự kiến nhận hàng
+	Status       string     `db:"status"`        // Trạng thái đơn mua hàng
+	Note         *string    `db:"note"`          // Ghi chú
+	CreatedBy    *int       `db:"created_by"`    // Người tạo
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    time.Time  `db:"updated_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID               int        `db:"id"`
+	PurchaseOrderID  int        `db:"purchase_order_id"` // Đơn mua hàng
+	ProductID        int        `db:"product_id"`        // Sản phẩm
+	Quantity         int        `db:"quantity"`          // Số lượng đặt (theo đơn vị cơ bản)
+	ReceivedQuantity int        `db:"received_quantity"` // Số lượng đã nhận
+	UnitCost         *float64   `db:"unit_cost"`         // Đơn giá theo đơn vị cơ bản
+	ExpectedDate     *time.Time `db:"expected_date"`     // Ngày dự kiến nhận dòng hàng
+	Note             *string    `db:"note"`              // Ghi chú
+}
+
+type purchaseOrderStatus struct {
+	DRAFT              string
+	ORDERED            string
+	PARTIALLY_RECEIVED string
+	RECEIVED           string
+	CANCELLED          string
+}
+
+var PurchaseOrderStatus = purchaseOrderStatus{
+	DRAFT:              "DRAFT",
+	ORDERED:            "ORDERED",
+	PARTIALLY_RECEIVED: "PARTIALLY_RECEIVED",
+	RECEIVED:           "RECEIVED",
+	CANCELLED:          "CANCELLED",
+}
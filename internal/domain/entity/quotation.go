@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+type Quotation struct {
+	ID                 int       `db:"id"`
+	Code               string    `db:"code"`                 // Mã báo giá (BG00001)
+	CustomerID         int       `db:"customer_id"`          // Khách hàng
+	QuotationDate      time.Time `db:"quotation_date"`       // Ngày báo giá
+	ValidFrom          time.Time `db:"valid_from"`           // Hiệu lực từ ngày
+	ValidUntil         time.Time `db:"valid_until"`          // Hiệu lực đến ngày
+	Note               *string   `db:"note"`                 // Ghi chú
+	AdditionalCost     int       `db:"additional_cost"`      // Chi phí phát sinh
+	AdditionalCostNote *string   `db:"additional_cost_note"` // Ghi chú chi phí phát sinh
+	TaxPercent         int       `db:"tax_percent"`          // Thuế suất
+	Status             string    `db:"status"`               // Trạng thái báo giá
+	OrderID            *int      `db:"order_id"`             // Đơn hàng được tạo từ báo giá
+	CreatedBy          *int      `db:"created_by"`           // Người tạo
+	CreatedAt          time.Time `db:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
+
+type QuotationItem struct {
+	ID              int  `db:"id"`
+	QuotationID     int  `db:"quotation_id"`     // Báo giá
+	ProductID       int  `db:"product_id"`       // Sản phẩm
+	Quantity        int  `db:"quantity"`         // Số lượng
+	UnitID          *int `db:"unit_id"`          // Đơn vị bán (nil = đơn vị cơ bản)
+	SellingPrice    int  `db:"selling_price"`    // Giá bán
+	OriginalPrice   int  `db:"original_price"`   // Giá vốn
+	DiscountPercent int  `db:"discount_percent"` // Chiết khấu
+	FinalAmount     int  `db:"final_amount"`     // Tổng tiền
+}
+
+type quotationStatus struct {
+	DRAFT    string
+	SENT     string
+	ACCEPTED string
+	EXPIRED  string
+}
+
+var QuotationStatus = quotationStatus{
+	DRAFT:    "DRAFT",
+	SENT:     "SENT",
+	ACCEPTED: "ACCEPTED",
+	EXPIRED:  "EXPIRED",
+}
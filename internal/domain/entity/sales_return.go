@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+type SalesReturn struct {
+	ID            int       `db:"id"`
+	Code          string    `db:"code"`            // Mã phiếu trả hàng (TH00001)
+	OrderID       int       `db:"order_id"`        // Đơn hàng
+	ReturnDate    time.Time `db:"return_date"`     // Ngày trả hàng
+	Reason        *string   `db:"reason"`          // Lý do trả hàng
+	CreditAmount  int       `db:"credit_amount"`   // Số tiền ghi giảm công nợ (đã gồm thuế)
+	CreatedBy     *int      `db:"created_by"`      // Người ghi nhận
+	CreatedByName string    `db:"created_by_name"` // Tên người ghi nhận
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+type SalesReturnItem struct {
+	ID            int     `db:"id"`
+	SalesReturnID int     `db:"sales_return_id"` // Phiếu trả hàng
+	OrderItemID   int     `db:"order_item_id"`   // Dòng đơn hàng được trả
+	ProductID     int     `db:"product_id"`      // Sản phẩm
+	Quantity      int     `db:"quantity"`        // Số lượng trả
+	Disposition   string  `db:"disposition"`     // Cách xử lý hàng trả
+	Reason        *string `db:"reason"`          // Lý do trả
+	CreditAmount  int     `db:"credit_amount"`   // Số tiền ghi giảm (chưa gồm thuế)
+}
+
+type salesReturnDisposition struct {
+	RESTOCK_PRODUCT   string
+	RESTOCK_MATERIALS string
+	WRITE_OFF         string
+}
+
+var SalesReturnDisposition = salesReturnDisposition{
+	RESTOCK_PRODUCT:   "RESTOCK_PRODUCT",   // Nhập lại kho thành phẩm
+	RESTOCK_MATERIALS: "RESTOCK_MATERIALS", // Tháo ra, nhập lại kho nguyên vật liệu theo BOM
+	WRITE_OFF:         "WRITE_OFF",         // Hàng hỏng, không nhập kho
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+type StockTransfer struct {
+	ID              int       `db:"id"`
+	Code            string    `db:"code"`              // Mã phiếu chuyển kho (CK00001)
+	FromWarehouseID int       `db:"from_warehouse_id"` // Kho xuất
+	ToWarehouseID   int       `db:"to_warehouse_id"`   // Kho nhận
+	TransferDate    time.Time `db:"transfer_date"`     // Ngày chuyển kho
+	Note            *string   `db:"note"`              // Ghi chú
+	CreatedBy       *int      `db:"created_by"`        // Người tạo
+	CreatedByName   string    `db:"created_by_name"`   // Tên người tạo
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+type StockTransferItem struct {
+	ID              int     `db:"id"`
+	StockTransferID int     `db:"stock_transfer_id"` // Phiếu chuyển kho
+	ProductID       int     `db:"product_id"`        // Sản phẩm
+	Quantity        int     `db:"quantity"`          // Số lượng chuyển (theo đơn vị cơ bản)
+	Note            *string `db:"note"`              // Ghi chú
+}
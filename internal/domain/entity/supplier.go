@@ -0,0 +1,11 @@
+package entity
+
+type Supplier struct {
+	ID      int     `db:"id"`
+	Code    string  `db:"code"` // Mã nhà cung cấp (NCC00001)
+	Name    string  `db:"name"`
+	Phone   string  `db:"phone"`
+	Email   *string `db:"email"`
+	Address string  `db:"address"`
+	TaxCode *string `db:"tax_code"` // Mã số thuế
+}
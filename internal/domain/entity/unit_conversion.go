@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type UnitConversion struct {
+	ID         int       `db:"id"`
+	ProductID  *int      `db:"product_id"`   // Sản phẩm áp dụng (nil = áp dụng cho mọi sản phẩm)
+	FromUnitID int       `db:"from_unit_id"` // Đơn vị quy đổi
+	ToUnitID   int       `db:"to_unit_id"`   // Đơn vị đích
+	Factor     float64   `db:"factor"`       // Hệ số: 1 from_unit = factor to_unit
+	CreatedAt  time.Time `db:"created_at"`   // Thời gian tạo
+	UpdatedAt  time.Time `db:"updated_at"`   // Thời gian cập nhật
+}
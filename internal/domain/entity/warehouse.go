@@ -0,0 +1,9 @@
+package entity
+
+type Warehouse struct {
+	ID        int     `db:"id"`
+	Code      string  `db:"code"`       // Mã kho (KHO00001)
+	Name      string  `db:"name"`       // Tên kho
+	Address   *string `db:"address"`    // Địa chỉ
+	IsDefault bool    `db:"is_default"` // Kho mặc định khi chứng từ không chỉ định kho
+}
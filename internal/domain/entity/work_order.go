@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type WorkOrder struct {
+	ID            int        `db:"id"`
+	Code          string     `db:"code"`            // Mã lệnh sản xuất (SX00001)
+	ProductID     int        `db:"product_id"`      // Thành phẩm cần sản xuất
+	WarehouseID   int        `db:"warehouse_id"`    // Kho xuất nguyên liệu và nhập thành phẩm
+	Quantity      int        `db:"quantity"`        // Số lượng sản xuất
+	MultiLevel    bool       `db:"multi_level"`     // Sản xuất cả bán thành phẩm còn thiếu theo BOM nhiều cấp
+	Status        string     `db:"status"`          // Trạng thái lệnh sản xuất
+	PlannedDate   *time.Time `db:"planned_date"`    // Ngày dự kiến sản xuất
+	Note          *string    `db:"note"`            // Ghi chú
+	StartedAt     *time.Time `db:"started_at"`      // Thời điểm bắt đầu (xuất nguyên liệu)
+	CompletedAt   *time.Time `db:"completed_at"`    // Thời điểm hoàn thành (nhập thành phẩm)
+	FinishedLotID *int       `db:"finished_lot_id"` // Lô thành phẩm nhập kho
+	CreatedBy     *int       `db:"created_by"`      // Người tạo
+	CreatedByName string     `db:"created_by_name"` // Tên người tạo
+	CreatedAt     time.Time  `db:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"`
+}
+
+type WorkOrderItem struct {
+	ID          int     `db:"id"`
+	WorkOrderID int     `db:"work_order_id"` // Lệnh sản xuất
+	ProductID   int     `db:"product_id"`    // Thành phần đã xuất kho
+	Quantity    int     `db:"quantity"`      // Số lượng đã tiêu hao
+	UnitCost    float64 `db:"unit_cost"`     // Giá vốn theo đơn vị cơ bản, theo phương pháp tính giá kho
+}
+
+type workOrderStatus struct {
+	PLANNED     string
+	IN_PROGRESS string
+	DONE        string
+}
+
+var WorkOrderStatus = workOrderStatus{
+	PLANNED:     "PLANNED",     // Đã lên kế hoạch, chưa xuất nguyên liệu
+	IN_PROGRESS: "IN_PROGRESS", // Đang sản xuất, nguyên liệu đã xuất kho
+	DONE:        "DONE",        // Hoàn thành, thành phẩm đã nhập kho
+}
@@ -0,0 +1,10 @@
+package model
+
+// IdempotentRequest is the outcome of claiming an Idempotency-Key. When Replayed is set the
+// stored response must be returned instead of running the request again.
+type IdempotentRequest struct {
+	ID                 int
+	Replayed           bool
+	ResponseStatusCode int
+	ResponseBody       []byte
+}
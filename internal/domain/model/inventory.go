@@ -1,24 +1,32 @@
 package model
 
 type UpdateInventoryQuantityRequest struct {
-	Quantity int    `json:"quantity" binding:"required"`
-	Note     string `json:"note"`
-	Version  string `json:"version" binding:"required"`
+	WarehouseID *int   `json:"warehouse_id"` // Kho điều chỉnh (bỏ trống = kho mặc định)
+	Quantity    int    `json:"quantity" binding:"required"`
+	Note        string `json:"note"`
+	Version     string `json:"version" binding:"required"`
 }
 
 type InventoryResponse struct {
-	ID        int    `json:"id"`
-	ProductID int    `json:"product_id"`
-	Quantity  int    `json:"quantity"`
-	Version   string `json:"version"`
+	ID                int    `json:"id"`
+	ProductID         int    `json:"product_id"`
+	WarehouseID       int    `json:"warehouse_id"`       // Kho
+	Quantity          int    `json:"quantity"`           // Tồn kho thực tế
+	ReservedQuantity  int    `json:"reserved_quantity"`  // Đang giữ chỗ cho đơn hàng chờ giao
+	AvailableQuantity int    `json:"available_quantity"` // Có thể nhận đơn (tồn kho - giữ chỗ)
+	Version           string `json:"version"`
 }
 
 type InventoryWithProductResponse struct {
-	ID        int         `json:"id"`
-	ProductID int         `json:"product_id"`
-	Quantity  int         `json:"quantity"`
-	Version   string      `json:"version"`
-	Product   ProductInfo `json:"product"`
+	ID                int         `json:"id"`
+	ProductID         int         `json:"product_id"`
+	WarehouseID       int         `json:"warehouse_id"`       // Kho
+	WarehouseName     string      `json:"warehouse_name"`     // Tên kho
+	Quantity          int         `json:"quantity"`           // Tồn kho thực tế
+	ReservedQuantity  int         `json:"reserved_quantity"`  // Đang giữ chỗ cho đơn hàng chờ giao
+	AvailableQuantity int         `json:"available_quantity"` // Có thể nhận đơn (tồn kho - giữ chỗ)
+	Version           string      `json:"version"`
+	Product           ProductInfo `json:"product"`
 }
 
 type ProductInfo struct {
@@ -27,6 +35,18 @@ type ProductInfo struct {
 	Cost float64 `json:"cost"`
 }
 
+// InventoryWarehouseSummary is the stock of one warehouse summed over its products
+type InventoryWarehouseSummary struct {
+	WarehouseID       int    `json:"warehouse_id"`
+	WarehouseCode     string `json:"warehouse_code"`     // Mã kho
+	WarehouseName     string `json:"warehouse_name"`     // Tên kho
+	ProductCount      int    `json:"product_count"`      // Số sản phẩm đang có tồn kho
+	Quantity          int    `json:"quantity"`           // Tổng tồn kho thực tế
+	ReservedQuantity  int    `json:"reserved_quantity"`  // Tổng đang giữ chỗ
+	AvailableQuantity int    `json:"available_quantity"` // Tổng có thể nhận đơn
+}
+
 type GetAllInventoryResponse struct {
 	Inventories []InventoryWithProductResponse `json:"inventories"`
+	Warehouses  []InventoryWarehouseSummary    `json:"warehouses"` // Tổng hợp theo kho
 }
@@ -4,6 +4,7 @@ import "time"
 
 type CreateInventoryHistoryRequest struct {
 	ProductID    int    `json:"product_id" binding:"required"`
+	WarehouseID  *int   `json:"warehouse_id"` // Kho phát sinh biến động
 	Quantity     int    `json:"quantity" binding:"required"`
 	ImporterName string `json:"importer_name" binding:"required"`
 	Note         string `json:"note"`
@@ -12,12 +13,14 @@ type CreateInventoryHistoryRequest struct {
 type InventoryHistoryResponse struct {
 	ID            int       `json:"id"`
 	ProductID     int       `json:"product_id"`
+	WarehouseID   *int      `json:"warehouse_id"` // Kho phát sinh biến động
 	Quantity      int       `json:"quantity"`
 	FinalQuantity int       `json:"final_quantity"`
 	ImporterName  string    `json:"importer_name"`
 	ImportedAt    time.Time `json:"imported_at"`
 	Note          string    `json:"note"`
 	ReferenceID   *int      `json:"reference_id,omitempty"`
+	ReferenceType *string   `json:"reference_type,omitempty"`
 }
 
 type GetAllInventoryHistoriesResponse struct {
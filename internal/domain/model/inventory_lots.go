@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+type InventoryLotResponse struct {
+	ID            int        `json:"id"`
+	ProductID     int        `json:"product_id"`
+	ProductName   string     `json:"product_name"`
+	WarehouseID   int        `json:"warehouse_id"`
+	WarehouseName string     `json:"warehouse_name"`
+	LotNumber     string     `json:"lot_number"`  // Số lô
+	ExpiryDate    *time.Time `json:"expiry_date"` // Hạn sử dụng
+	Quantity      int        `json:"quantity"`    // Tồn kho của lô (theo đơn vị cơ bản)
+	IsExpired     bool       `json:"is_expired"`  // Lô đã hết hạn
+}
+
+type WriteOffInventoryLotRequest struct {
+	Quantity int    `json:"quantity" binding:"required,gt=0"` // Số lượng hủy (theo đơn vị cơ bản)
+	Note     string `json:"note"`                             // Lý do hủy
+}
+
+type GetAllInventoryLotsResponse struct {
+	Lots []InventoryLotResponse `json:"lots"`
+}
@@ -3,34 +3,46 @@ package model
 import "time"
 
 type InventoryReceiptItemRequest struct {
-	ProductID int      `json:"product_id" binding:"required"`
-	Quantity  int      `json:"quantity" binding:"required"`
-	UnitCost  *float64 `json:"unit_cost"`
-	Notes     *string  `json:"notes"`
+	ProductID           int        `json:"product_id" binding:"required"`
+	PurchaseOrderItemID *int       `json:"purchase_order_item_id"` // Dòng đơn mua hàng được nhận (bỏ trống = nhập ngoài đơn)
+	Quantity            int        `json:"quantity" binding:"required"`
+	UnitID              *int       `json:"unit_id"`     // Đơn vị nhập (bỏ trống = đơn vị cơ bản của sản phẩm)
+	UnitCost            *float64   `json:"unit_cost"`   // Đơn giá theo đơn vị nhập
+	LotNumber           *string    `json:"lot_number"`  // Số lô (bắt buộc với hóa chất)
+	ExpiryDate          *time.Time `json:"expiry_date"` // Hạn sử dụng của lô (bắt buộc với hóa chất)
+	Notes               *string    `json:"notes"`
 }
 
 type CreateInventoryReceiptRequest struct {
 	UserID      int                           `json:"user_id" binding:"required"`
+	SupplierID  *int                          `json:"supplier_id"`  // Nhà cung cấp (bỏ trống = lấy theo đơn mua hàng nếu có)
+	WarehouseID *int                          `json:"warehouse_id"` // Kho nhập hàng (bỏ trống = kho mặc định)
 	ReceiptDate time.Time                     `json:"receipt_date"`
 	Notes       *string                       `json:"notes"`
 	Items       []InventoryReceiptItemRequest `json:"items" binding:"required,dive"`
 }
 
 type InventoryReceiptItemResponse struct {
-	ID                 int       `json:"id"`
-	InventoryReceiptID int       `json:"inventory_receipt_id"`
-	ProductID          int       `json:"product_id"`
-	Quantity           int       `json:"quantity"`
-	UnitCost           *float64  `json:"unit_cost"`
-	Notes              *string   `json:"notes"`
-	CreatedAt          time.Time `json:"created_at"`
-	UpdatedAt          time.Time `json:"updated_at"`
+	ID                  int        `json:"id"`
+	InventoryReceiptID  int        `json:"inventory_receipt_id"`
+	ProductID           int        `json:"product_id"`
+	PurchaseOrderItemID *int       `json:"purchase_order_item_id"` // Dòng đơn mua hàng được nhận
+	Quantity            int        `json:"quantity"`
+	UnitCost            *float64   `json:"unit_cost"`
+	LotNumber           *string    `json:"lot_number"`       // Số lô
+	ExpiryDate          *time.Time `json:"expiry_date"`      // Hạn sử dụng
+	InventoryLotID      *int       `json:"inventory_lot_id"` // Lô được nhập vào
+	Notes               *string    `json:"notes"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
 }
 
 type InventoryReceiptResponse struct {
 	ID          int                            `json:"id"`
 	Code        string                         `json:"code"`
 	UserID      int                            `json:"user_id"`
+	SupplierID  *int                           `json:"supplier_id"`  // Nhà cung cấp
+	WarehouseID int                            `json:"warehouse_id"` // Kho nhập hàng
 	ReceiptDate time.Time                      `json:"receipt_date"`
 	Notes       *string                        `json:"notes"`
 	TotalItems  int                            `json:"total_items"`
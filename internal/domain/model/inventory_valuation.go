@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+type InventoryCostLayerResponse struct {
+	ID                int       `json:"id"`
+	SourceType        string    `json:"source_type"`        // Nguồn nhập: OPENING_BALANCE, INVENTORY_RECEIPT, INVENTORY_ADJUSTMENT, WORK_ORDER hoặc RETURN
+	SourceID          *int      `json:"source_id"`          // Chứng từ nhập
+	ReceivedAt        time.Time `json:"received_at"`        // Thời điểm nhập
+	UnitCost          float64   `json:"unit_cost"`          // Đơn giá theo đơn vị cơ bản (VND)
+	Quantity          int       `json:"quantity"`           // Số lượng nhập
+	RemainingQuantity int       `json:"remaining_quantity"` // Số lượng còn lại
+	Value             float64   `json:"value"`              // Giá trị còn lại (VND)
+}
+
+type ProductValuationResponse struct {
+	ProductID      int                          `json:"product_id"`
+	ProductCode    string                       `json:"product_code"`
+	ProductName    string                       `json:"product_name"`
+	OnHandQuantity int                          `json:"on_hand_quantity"` // Tồn kho (mọi kho)
+	AverageCost    float64                      `json:"average_cost"`     // Giá vốn bình quân (VND)
+	AverageValue   float64                      `json:"average_value"`    // Giá trị tồn theo bình quân (VND)
+	LayerQuantity  int                          `json:"layer_quantity"`   // Số lượng còn trong các lớp giá FIFO
+	FifoValue      float64                      `json:"fifo_value"`       // Giá trị tồn theo FIFO (VND)
+	Value          float64                      `json:"value"`            // Giá trị tồn theo phương pháp đang áp dụng (VND)
+	Layers         []InventoryCostLayerResponse `json:"layers"`           // Các lớp giá còn tồn, cũ nhất trước
+}
+
+type GetInventoryValuationResponse struct {
+	Method     string                     `json:"method"`      // Phương pháp tính giá: AVERAGE hoặc FIFO
+	TotalValue float64                    `json:"total_value"` // Tổng giá trị tồn kho (VND)
+	Products   []ProductValuationResponse `json:"products"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+type MrpDemandRequest struct {
+	ProductID int  `json:"product_id" binding:"required"`    // Sản phẩm có nhu cầu
+	Quantity  int  `json:"quantity" binding:"required,gt=0"` // Số lượng cần
+	UnitID    *int `json:"unit_id"`                          // Đơn vị của số lượng (bỏ trống = đơn vị cơ bản)
+}
+
+type CreateMrpRunRequest struct {
+	IncludeOpenOrders bool               `json:"include_open_orders"`    // Tính cả nhu cầu của các đơn hàng đang chờ giao (PENDING)
+	Demands           []MrpDemandRequest `json:"demands" binding:"dive"` // Nhu cầu nhập tay, cộng thêm vào nhu cầu từ đơn hàng
+	Note              *string            `json:"note"`                   // Ghi chú
+}
+
+type MrpRunDemandResponse struct {
+	ProductID   int     `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	OrderID     *int    `json:"order_id"`   // Đơn hàng phát sinh nhu cầu (nil = nhập tay)
+	OrderCode   *string `json:"order_code"` // Mã đơn hàng
+	Quantity    int     `json:"quantity"`   // Số lượng theo đơn vị cơ bản
+}
+
+type MrpRunItemResponse struct {
+	ProductID         int     `json:"product_id"`
+	ProductCode       string  `json:"product_code"`
+	ProductName       string  `json:"product_name"`
+	OperationType     string  `json:"operation_type"`
+	UnitName          string  `json:"unit_name"`
+	GrossRequirement  float64 `json:"gross_requirement"`  // Tổng nhu cầu trước khi trừ tồn kho
+	AvailableStock    int     `json:"available_stock"`    // Tồn kho dùng được (trừ phần giữ chỗ của đơn hàng khi không tính đơn hàng)
+	NetRequirement    float64 `json:"net_requirement"`    // Phần thiếu sau khi trừ tồn kho
+	PlannedProduction int     `json:"planned_production"` // Số lượng cần sản xuất (sản phẩm có BOM)
+	SuggestedPurchase int     `json:"suggested_purchase"` // Số lượng đề xuất mua (nguyên liệu không có BOM)
+}
+
+type MrpRunResponse struct {
+	ID                int                    `json:"id"`
+	Code              string                 `json:"code"`
+	IncludeOpenOrders bool                   `json:"include_open_orders"`
+	Note              *string                `json:"note"`
+	TotalMaterials    int                    `json:"total_materials"`    // Số sản phẩm trong kế hoạch
+	ShortageMaterials int                    `json:"shortage_materials"` // Số nguyên liệu cần mua thêm
+	CreatedBy         *int                   `json:"created_by"`
+	CreatedByName     string                 `json:"created_by_name"`
+	CreatedAt         time.Time              `json:"created_at"`
+	Demands           []MrpRunDemandResponse `json:"demands,omitempty"` // Nhu cầu đầu vào
+	Items             []MrpRunItemResponse   `json:"items,omitempty"`   // Kết quả theo từng sản phẩm
+}
+
+type GetAllMrpRunsResponse struct {
+	MrpRuns []MrpRunResponse `json:"mrp_runs"`
+}
+
+type GetOneMrpRunResponse struct {
+	MrpRun MrpRunResponse `json:"mrp_run"`
+}
+
+// One product compared between two MRP runs
+type MrpRunComparisonItem struct {
+	ProductID             int     `json:"product_id"`
+	ProductName           string  `json:"product_name"`
+	FromNetRequirement    float64 `json:"from_net_requirement"`    // Phần thiếu ở lần chạy cũ
+	ToNetRequirement      float64 `json:"to_net_requirement"`      // Phần thiếu ở lần chạy mới
+	FromSuggestedPurchase int     `json:"from_suggested_purchase"` // Đề xuất mua ở lần chạy cũ
+	ToSuggestedPurchase   int     `json:"to_suggested_purchase"`   // Đề xuất mua ở lần chạy mới
+	SuggestedPurchaseDiff int     `json:"suggested_purchase_diff"` // Chênh lệch đề xuất mua (mới - cũ)
+}
+
+type MrpRunComparisonResponse struct {
+	FromRun MrpRunResponse         `json:"from_run"`
+	ToRun   MrpRunResponse         `json:"to_run"`
+	Items   []MrpRunComparisonItem `json:"items"` // Các sản phẩm có trong ít nhất một lần chạy
+}
@@ -3,22 +3,23 @@ package model
 import "time"
 
 type CreateOrderRequest struct {
-	CustomerID         int                      `json:"customer_id" binding:"required"` // Khách hàng
-	OrderDate          time.Time                `json:"order_date" binding:"required"`  // Ngày đặt hàng
-	Note               *string                  `json:"note"`                           // Ghi chú
-	AdditionalCost     int                      `json:"additional_cost"`                // Chi phí phát sinh
-	AdditionalCostNote *string                  `json:"additional_cost_note"`           // Ghi chú chi phí phát sinh
-	TaxPercent         int                      `json:"tax_percent"`                    // Thuế suất
-	DeliveryStatus     string                   `json:"delivery_status"`                // Trạng thái giao hàng
-	Items              []CreateOrderItemRequest `json:"items" binding:"required,dive"`  // Danh sách sản phẩm trong đơn hàng
+	CustomerID         int                      `json:"customer_id" binding:"required"`                                               // Khách hàng
+	WarehouseID        *int                     `json:"warehouse_id"`                                                                 // Kho xuất hàng (bỏ trống = kho mặc định)
+	OrderDate          time.Time                `json:"order_date" binding:"required"`                                                // Ngày đặt hàng
+	Note               *string                  `json:"note"`                                                                         // Ghi chú
+	AdditionalCost     int                      `json:"additional_cost"`                                                              // Chi phí phát sinh
+	AdditionalCostNote *string                  `json:"additional_cost_note"`                                                         // Ghi chú chi phí phát sinh
+	TaxPercent         int                      `json:"tax_percent"`                                                                  // Thuế suất
+	DeliveryStatus     string                   `json:"delivery_status" binding:"omitempty,oneof=PENDING DELIVERED UNPAID COMPLETED"` // Trạng thái giao hàng (mặc định PENDING)
+	Items              []CreateOrderItemRequest `json:"items" binding:"required,dive"`                                                // Danh sách sản phẩm trong đơn hàng
 }
 
 type CreateOrderItemRequest struct {
-	ProductID       int `json:"product_id" binding:"required"`     // Sản phẩm (một order item chỉ có thể là 1 product)
-	Quantity        int `json:"quantity" binding:"required"`       // Số lượng
-	SellingPrice    int `json:"selling_price" binding:"required"`  // Giá bán
-	OriginalPrice   int `json:"original_price" binding:"required"` // Giá vốn
-	DiscountPercent int `json:"discount_percent"`                  // Chiết khấu
+	ProductID       int  `json:"product_id" binding:"required"`    // Sản phẩm (một order item chỉ có thể là 1 product)
+	Quantity        int  `json:"quantity" binding:"required"`      // Số lượng (theo đơn vị bán)
+	UnitID          *int `json:"unit_id"`                          // Đơn vị bán (bỏ trống = đơn vị cơ bản của sản phẩm)
+	SellingPrice    int  `json:"selling_price" binding:"required"` // Giá bán theo đơn vị bán
+	DiscountPercent int  `json:"discount_percent"`                 // Chiết khấu
 }
 
 type OrderResponse struct {
@@ -30,11 +31,17 @@ type OrderResponse struct {
 	AdditionalCostNote *string             `json:"additional_cost_note"`
 	TaxPercent         int                 `json:"tax_percent"`
 	DeliveryStatus     string              `json:"delivery_status"`
+	WarehouseID        int                 `json:"warehouse_id"` // Kho xuất hàng
 	Customer           CustomerResponse    `json:"customer"`
 	OrderItems         []OrderItemResponse `json:"order_items,omitempty"`
 	Images             []OrderImage        `json:"images,omitempty"`
 	TotalAmount        *int                `json:"total_amount,omitempty"`
-	ProductCount       *int                `json:"product_count,omitempty"`
+	// Payment fields, TotalAmount is the amount due
+	ReturnedAmount    *int   `json:"returned_amount,omitempty"`    // Số tiền hàng trả lại (ghi giảm công nợ)
+	PaidAmount        *int   `json:"paid_amount,omitempty"`        // Số tiền đã thu
+	OutstandingAmount *int   `json:"outstanding_amount,omitempty"` // Số tiền còn nợ
+	PaymentStatus     string `json:"payment_status,omitempty"`     // Trạng thái thanh toán
+	ProductCount      *int   `json:"product_count,omitempty"`
 	// Profit/Loss fields for total order
 	TotalProfitLoss           *int     `json:"total_profit_loss,omitempty"`            // Total profit/loss for the order
 	TotalProfitLossPercentage *float64 `json:"total_profit_loss_percentage,omitempty"` // Total profit/loss percentage for the order
@@ -47,13 +54,24 @@ type OrderItemResponse struct {
 	OrderID         int    `json:"order_id"`
 	ProductID       int    `json:"product_id"`
 	Quantity        int    `json:"quantity"`
+	UnitID          *int   `json:"unit_id"`       // Đơn vị bán (nil = đơn vị cơ bản)
+	UnitName        string `json:"unit_name"`     // Tên đơn vị bán
+	BaseQuantity    int    `json:"base_quantity"` // Số lượng theo đơn vị cơ bản
 	SellingPrice    int    `json:"selling_price"`
 	DiscountPercent int    `json:"discount_percent"`
 	FinalAmount     *int   `json:"final_amount"`
 	// Profit/Loss fields
-	OriginalPrice        *int     `json:"original_price,omitempty"`         // Product's original price
-	ProfitLoss           *int     `json:"profit_loss,omitempty"`            // Profit/Loss amount for this item
-	ProfitLossPercentage *float64 `json:"profit_loss_percentage,omitempty"` // Profit/Loss percentage for this item
+	OriginalPrice        *int                   `json:"original_price,omitempty"`         // Product's original price
+	ProfitLoss           *int                   `json:"profit_loss,omitempty"`            // Profit/Loss amount for this item
+	ProfitLossPercentage *float64               `json:"profit_loss_percentage,omitempty"` // Profit/Loss percentage for this item
+	Lots                 []OrderItemLotResponse `json:"lots,omitempty"`                   // Các lô đã xuất cho dòng hàng
+}
+
+type OrderItemLotResponse struct {
+	InventoryLotID int        `json:"inventory_lot_id"`
+	LotNumber      string     `json:"lot_number"`  // Số lô
+	ExpiryDate     *time.Time `json:"expiry_date"` // Hạn sử dụng
+	Quantity       int        `json:"quantity"`    // Số lượng xuất từ lô (theo đơn vị cơ bản)
 }
 
 type GetOneOrderResponse struct {
@@ -69,6 +87,39 @@ type UpdateOrderRequest struct {
 	AdditionalCostNote *string   `json:"additional_cost_note"`  // Ghi chú cho chi phí phát sinh
 	TaxPercent         *int      `json:"tax_percent"`           // Phần trăm thuế (%)
 	DeliveryStatus     *string   `json:"delivery_status"`       // Trạng thái giao hàng
+	StatusReason       *string   `json:"status_reason"`         // Lý do thay đổi trạng thái
+}
+
+type UpdateOrderItemsRequest struct {
+	Items []UpdateOrderItemRequest `json:"items" binding:"required,min=1,dive"` // Danh sách sản phẩm mới của đơn hàng (thay thế toàn bộ)
+}
+
+type UpdateOrderItemRequest struct {
+	ID              *int `json:"id"`                               // ID order item hiện có (bỏ trống để thêm mới)
+	ProductID       int  `json:"product_id" binding:"required"`    // Sản phẩm
+	Quantity        int  `json:"quantity" binding:"required,gt=0"` // Số lượng (theo đơn vị bán)
+	UnitID          *int `json:"unit_id"`                          // Đơn vị bán (bỏ trống = đơn vị cơ bản của sản phẩm)
+	SellingPrice    int  `json:"selling_price" binding:"required"` // Giá bán theo đơn vị bán
+	DiscountPercent int  `json:"discount_percent"`                 // Chiết khấu
+}
+
+type CancelOrderRequest struct {
+	Reason *string `json:"reason"` // Lý do hủy đơn hàng
+}
+
+type OrderStatusHistoryResponse struct {
+	ID            int       `json:"id"`
+	OrderID       int       `json:"order_id"`
+	FromStatus    *string   `json:"from_status"`     // Trạng thái trước (null khi tạo đơn)
+	ToStatus      string    `json:"to_status"`       // Trạng thái mới
+	ChangedBy     *int      `json:"changed_by"`      // ID người thay đổi
+	ChangedByName string    `json:"changed_by_name"` // Tên người thay đổi
+	Reason        *string   `json:"reason"`          // Lý do thay đổi
+	ChangedAt     time.Time `json:"changed_at"`      // Thời gian thay đổi
+}
+
+type GetOrderStatusHistoriesResponse struct {
+	StatusHistories []OrderStatusHistoryResponse `json:"status_histories"`
 }
 
 type GetAllOrdersResponse struct {
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+type CreatePaymentRequest struct {
+	Amount          int        `json:"amount" binding:"required,gt=0"`                                   // Số tiền thanh toán
+	PaymentMethod   string     `json:"payment_method" binding:"required,oneof=CASH BANK_TRANSFER OTHER"` // Hình thức thanh toán
+	PaymentDate     *time.Time `json:"payment_date"`                                                     // Ngày thanh toán (mặc định là hiện tại)
+	ReferenceNumber *string    `json:"reference_number"`                                                 // Mã giao dịch / số chứng từ
+	Note            *string    `json:"note"`                                                             // Ghi chú
+}
+
+type PaymentResponse struct {
+	ID              int       `json:"id"`
+	OrderID         int       `json:"order_id"`
+	Amount          int       `json:"amount"`
+	PaymentMethod   string    `json:"payment_method"`
+	PaymentDate     time.Time `json:"payment_date"`
+	ReferenceNumber *string   `json:"reference_number"`
+	Note            *string   `json:"note"`
+	CreatedBy       *int      `json:"created_by"`
+	CreatedByName   string    `json:"created_by_name"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type GetOrderPaymentsResponse struct {
+	Payments          []PaymentResponse `json:"payments"`
+	AmountDue         int               `json:"amount_due"`         // Số tiền phải thu
+	ReturnedAmount    int               `json:"returned_amount"`    // Số tiền hàng trả lại (ghi giảm công nợ)
+	PaidAmount        int               `json:"paid_amount"`        // Số tiền đã thu
+	OutstandingAmount int               `json:"outstanding_amount"` // Số tiền còn nợ
+	PaymentStatus     string            `json:"payment_status"`     // Trạng thái thanh toán
+}
@@ -1,16 +1,21 @@
 package model
 
+import "time"
+
 // Component for BOM - represents one component needed
 type BomComponent struct {
-	ComponentProductID int `json:"component_product_id" binding:"required"` // ID sản phẩm nguyên liệu
-	Quantity           int `json:"quantity" binding:"required"`             // Số lượng nguyên liệu cần thiết
+	ComponentProductID int     `json:"component_product_id" binding:"required"` // ID sản phẩm nguyên liệu
+	Quantity           float64 `json:"quantity" binding:"required,gt=0"`        // Số lượng nguyên liệu cần thiết, cho phép số lẻ (VD: 0.5)
+	UnitID             *int    `json:"unit_id"`                                 // Đơn vị của số lượng (bỏ trống = đơn vị cơ bản của nguyên liệu)
+	ScrapPercent       float64 `json:"scrap_percent" binding:"gte=0,lt=100"`    // Tỉ lệ hao hụt của nguyên liệu (%)
 }
 
 // Component with full product info for response
 type BomComponentResponse struct {
 	ID                 int             `json:"id"`                          // ID của BOM entry
 	ComponentProductID int             `json:"component_product_id"`        // ID sản phẩm nguyên liệu
-	Quantity           int             `json:"quantity"`                    // Số lượng nguyên liệu cần thiết
+	Quantity           float64         `json:"quantity"`                    // Số lượng nguyên liệu cần thiết
+	ScrapPercent       float64         `json:"scrap_percent"`               // Tỉ lệ hao hụt của nguyên liệu (%)
 	ComponentProduct   *ProductBomInfo `json:"component_product,omitempty"` // Thông tin sản phẩm nguyên liệu
 }
 
@@ -18,18 +23,23 @@ type BomComponentResponse struct {
 type CreateProductBomRequest struct {
 	ParentProductID int            `json:"parent_product_id" binding:"required"` // ID sản phẩm thành phẩm
 	Components      []BomComponent `json:"components" binding:"required,dive"`   // Danh sách nguyên liệu cần thiết
+	EffectiveFrom   *time.Time     `json:"effective_from"`                       // Thời điểm phiên bản có hiệu lực (bỏ trống = ngay bây giờ)
+	Note            *string        `json:"note"`                                 // Ghi chú phiên bản
 }
 
-// Update entire BOM (replace all components)
+// Update entire BOM (replace all components with a new version)
 type UpdateProductBomRequest struct {
 	ParentProductID int            `json:"parent_product_id" binding:"required"` // ID sản phẩm thành phẩm
 	Components      []BomComponent `json:"components" binding:"required,dive"`   // Danh sách nguyên liệu cần thiết mới
+	EffectiveFrom   *time.Time     `json:"effective_from"`                       // Thời điểm phiên bản mới có hiệu lực (bỏ trống = ngay bây giờ)
+	Note            *string        `json:"note"`                                 // Ghi chú thay đổi
 }
 
 // BOM response - one product with all its components
 type ProductBomResponse struct {
 	ParentProductID int                    `json:"parent_product_id"`        // ID sản phẩm thành phẩm
 	ParentProduct   *ProductBomInfo        `json:"parent_product,omitempty"` // Thông tin sản phẩm thành phẩm
+	Version         *BomVersionResponse    `json:"version,omitempty"`        // Phiên bản BOM
 	Components      []BomComponentResponse `json:"components"`               // Danh sách nguyên liệu
 	TotalComponents int                    `json:"total_components"`         // Tổng số loại nguyên liệu
 }
@@ -42,6 +52,47 @@ type GetOneProductBomResponse struct {
 	Bom ProductBomResponse `json:"bom"`
 }
 
+// BOM version without its components
+type BomVersionResponse struct {
+	ID              int        `json:"id"`                     // ID phiên bản
+	ParentProductID int        `json:"parent_product_id"`      // ID sản phẩm thành phẩm
+	VersionNumber   int        `json:"version_number"`         // Số phiên bản
+	EffectiveFrom   time.Time  `json:"effective_from"`         // Thời điểm bắt đầu có hiệu lực
+	EffectiveTo     *time.Time `json:"effective_to,omitempty"` // Thời điểm hết hiệu lực
+	IsActive        bool       `json:"is_active"`              // Còn được dùng khi tính nguyên liệu hay không
+	Note            *string    `json:"note,omitempty"`         // Ghi chú thay đổi
+	CreatedAt       time.Time  `json:"created_at"`             // Thời gian tạo
+}
+
+type GetBomVersionsResponse struct {
+	Versions []BomVersionResponse `json:"versions"`
+}
+
+// Turn a BOM version on or off
+type UpdateBomVersionStatusRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"` // Bật/tắt phiên bản
+}
+
+// One component that differs between two BOM versions
+type BomComponentChange struct {
+	ComponentProductID int             `json:"component_product_id"`        // ID sản phẩm nguyên liệu
+	ComponentProduct   *ProductBomInfo `json:"component_product,omitempty"` // Thông tin sản phẩm nguyên liệu
+	ChangeType         string          `json:"change_type"`                 // ADDED, REMOVED hoặc CHANGED
+	FromQuantity       float64         `json:"from_quantity"`               // Số lượng ở phiên bản cũ (0 nếu mới thêm)
+	ToQuantity         float64         `json:"to_quantity"`                 // Số lượng ở phiên bản mới (0 nếu đã bỏ)
+	FromScrapPercent   float64         `json:"from_scrap_percent"`          // Tỉ lệ hao hụt ở phiên bản cũ
+	ToScrapPercent     float64         `json:"to_scrap_percent"`            // Tỉ lệ hao hụt ở phiên bản mới
+}
+
+// Differences between two versions of the same BOM
+type BomVersionDiffResponse struct {
+	ParentProductID     int                  `json:"parent_product_id"`    // ID sản phẩm thành phẩm
+	FromVersion         BomVersionResponse   `json:"from_version"`         // Phiên bản gốc
+	ToVersion           BomVersionResponse   `json:"to_version"`           // Phiên bản so sánh
+	Changes             []BomComponentChange `json:"changes"`              // Các nguyên liệu thêm, bỏ hoặc đổi số lượng
+	UnchangedComponents int                  `json:"unchanged_components"` // Số nguyên liệu giữ nguyên
+}
+
 type ProductBomInfo struct {
 	ID           int     `json:"id"`            // ID sản phẩm
 	Name         string  `json:"name"`          // Tên sản phẩm
@@ -52,15 +103,19 @@ type ProductBomInfo struct {
 
 // Material requirement calculation request
 type CalculateMaterialRequirementsRequest struct {
-	ParentProductID int `json:"parent_product_id" binding:"required"` // ID sản phẩm thành phẩm
-	Quantity        int `json:"quantity" binding:"required,gt=0"`     // Số lượng sản phẩm cần sản xuất
+	ParentProductID int        `json:"parent_product_id" binding:"required"` // ID sản phẩm thành phẩm
+	Quantity        int        `json:"quantity" binding:"required,gt=0"`     // Số lượng sản phẩm cần sản xuất
+	Date            *time.Time `json:"date"`                                 // Ngày áp dụng phiên bản BOM (bỏ trống = hiện tại)
 }
 
 // Raw material requirement
 type MaterialRequirement struct {
 	ProductID        int             `json:"product_id"`        // ID nguyên liệu
 	Product          *ProductBomInfo `json:"product"`           // Thông tin nguyên liệu
-	RequiredQuantity int             `json:"required_quantity"` // Tổng số lượng cần thiết
+	RequiredQuantity float64         `json:"required_quantity"` // Tổng số lượng cần thiết (chính xác, chưa làm tròn, đã gồm hao hụt)
+	GrossQuantity    float64         `json:"gross_quantity"`    // Số lượng gộp, đã gồm tỉ lệ hao hụt và hiệu suất
+	NetQuantity      float64         `json:"net_quantity"`      // Số lượng tịnh theo định mức BOM, chưa gồm hao hụt
+	StockQuantity    int             `json:"stock_quantity"`    // Số lượng xuất kho (theo số lượng gộp) sau khi làm tròn theo BOM_STOCK_ROUNDING
 }
 
 // Material requirements calculation response
@@ -68,6 +123,78 @@ type MaterialRequirementsResponse struct {
 	ParentProductID      int                   `json:"parent_product_id"`     // ID sản phẩm thành phẩm
 	ParentProduct        *ProductBomInfo       `json:"parent_product"`        // Thông tin sản phẩm thành phẩm
 	RequestedQuantity    int                   `json:"requested_quantity"`    // Số lượng sản phẩm được yêu cầu
+	EffectiveDate        time.Time             `json:"effective_date"`        // Ngày áp dụng phiên bản BOM
 	MaterialRequirements []MaterialRequirement `json:"material_requirements"` // Danh sách nguyên liệu và số lượng cần thiết
 	TotalMaterials       int                   `json:"total_materials"`       // Tổng số loại nguyên liệu
 }
+
+// One product in the multi-level BOM tree
+type BomTreeNode struct {
+	ProductID         int           `json:"product_id"`          // ID sản phẩm
+	ProductName       string        `json:"product_name"`        // Tên sản phẩm
+	OperationType     string        `json:"operation_type"`      // Loại sản phẩm
+	UnitName          string        `json:"unit_name"`           // Tên đơn vị tính
+	Level             int           `json:"level"`               // Cấp trong cây BOM (0 = thành phẩm gốc)
+	BomVersionID      *int          `json:"bom_version_id"`      // Phiên bản BOM được dùng (nil nếu là nguyên liệu lá)
+	QuantityPerParent float64       `json:"quantity_per_parent"` // Số lượng cho 1 đơn vị sản phẩm cha theo định mức
+	ScrapPercent      float64       `json:"scrap_percent"`       // Tỉ lệ hao hụt của thành phần (%)
+	YieldPercent      float64       `json:"yield_percent"`       // Hiệu suất sản xuất của sản phẩm (%)
+	GrossPerParent    float64       `json:"gross_per_parent"`    // Số lượng cho 1 đơn vị sản phẩm cha, đã gồm hao hụt của cha
+	ExtendedQuantity  float64       `json:"extended_quantity"`   // Tổng số lượng (đã gồm hao hụt) cho số lượng gốc được yêu cầu
+	Cost              float64       `json:"cost"`                // Giá vốn nhập tay của sản phẩm (products.cost)
+	RolledUpUnitCost  float64       `json:"rolled_up_unit_cost"` // Giá vốn 1 đơn vị tính từ nguyên liệu lá
+	ExtendedCost      float64       `json:"extended_cost"`       // Giá vốn tính từ nguyên liệu lá cho số lượng mở rộng
+	Components        []BomTreeNode `json:"components"`          // Các thành phần ở cấp dưới
+}
+
+// Multi-level BOM tree with the cost rolled up from the leaf materials
+type BomTreeResponse struct {
+	ParentProductID  int         `json:"parent_product_id"`   // ID sản phẩm thành phẩm
+	Quantity         int         `json:"quantity"`            // Số lượng thành phẩm được tính
+	EffectiveDate    time.Time   `json:"effective_date"`      // Ngày áp dụng phiên bản BOM
+	Tree             BomTreeNode `json:"tree"`                // Cây BOM
+	TotalLevels      int         `json:"total_levels"`        // Số cấp của cây BOM
+	ManualUnitCost   float64     `json:"manual_unit_cost"`    // Giá vốn nhập tay của thành phẩm
+	RolledUpUnitCost float64     `json:"rolled_up_unit_cost"` // Giá vốn 1 đơn vị tính từ nguyên liệu lá
+	CostDifference   float64     `json:"cost_difference"`     // Giá vốn nhập tay trừ giá vốn tính được
+}
+
+// One product that uses the component, directly or through sub-assemblies
+type WhereUsedProduct struct {
+	ProductID            int             `json:"product_id"`              // ID sản phẩm sử dụng nguyên liệu
+	Product              *ProductBomInfo `json:"product,omitempty"`       // Thông tin sản phẩm
+	Level                int             `json:"level"`                   // Số cấp gần nhất từ nguyên liệu lên sản phẩm (1 = dùng trực tiếp)
+	IsTopLevel           bool            `json:"is_top_level"`            // Thành phẩm cao nhất, không nằm trong BOM nào khác
+	QuantityPerUnit      float64         `json:"quantity_per_unit"`       // Số lượng nguyên liệu cho 1 đơn vị sản phẩm, cộng dồn qua mọi cấp
+	GrossQuantityPerUnit float64         `json:"gross_quantity_per_unit"` // Như trên, đã gồm tỉ lệ hao hụt và hiệu suất
+	Paths                []string        `json:"paths"`                   // Các đường đi từ sản phẩm xuống nguyên liệu
+}
+
+// One line of an open order that needs the component
+type WhereUsedOrderItem struct {
+	ProductID         int     `json:"product_id"`         // ID sản phẩm trong đơn
+	ProductName       string  `json:"product_name"`       // Tên sản phẩm
+	Quantity          int     `json:"quantity"`           // Số lượng đặt (theo đơn vị cơ bản)
+	ComponentQuantity float64 `json:"component_quantity"` // Số lượng nguyên liệu cần cho dòng này (đã gồm hao hụt)
+}
+
+// Open order affected by a change of the component
+type WhereUsedOrder struct {
+	OrderID           int                  `json:"order_id"`           // ID đơn hàng
+	OrderCode         string               `json:"order_code"`         // Mã đơn hàng
+	OrderDate         time.Time            `json:"order_date"`         // Ngày đặt hàng
+	CustomerID        int                  `json:"customer_id"`        // Khách hàng
+	Items             []WhereUsedOrderItem `json:"items"`              // Các dòng cần nguyên liệu
+	ComponentQuantity float64              `json:"component_quantity"` // Tổng số lượng nguyên liệu cho đơn hàng
+}
+
+// Recursive where-used report of a component
+type WhereUsedResponse struct {
+	ComponentProductID     int                `json:"component_product_id"`        // ID nguyên liệu
+	ComponentProduct       *ProductBomInfo    `json:"component_product,omitempty"` // Thông tin nguyên liệu
+	EffectiveDate          time.Time          `json:"effective_date"`              // Ngày áp dụng phiên bản BOM
+	UsedIn                 []WhereUsedProduct `json:"used_in"`                     // Các sản phẩm dùng nguyên liệu ở mọi cấp
+	TopLevelProducts       int                `json:"top_level_products"`          // Số thành phẩm cao nhất bị ảnh hưởng
+	AffectedOrders         []WhereUsedOrder   `json:"affected_orders"`             // Đơn hàng chưa giao cần nguyên liệu
+	TotalComponentQuantity float64            `json:"total_component_quantity"`    // Tổng số lượng nguyên liệu cho các đơn hàng chưa giao
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+type ProductCostHistoryResponse struct {
+	ID               int       `json:"id"`
+	ProductID        int       `json:"product_id"`
+	PreviousCost     float64   `json:"previous_cost"`     // Giá vốn trước thay đổi (VND)
+	NewCost          float64   `json:"new_cost"`          // Giá vốn sau thay đổi (VND)
+	OnHandQuantity   int       `json:"on_hand_quantity"`  // Tồn kho (mọi kho) trước khi nhập
+	ReceivedQuantity int       `json:"received_quantity"` // Số lượng nhập (theo đơn vị cơ bản)
+	UnitCost         *float64  `json:"unit_cost"`         // Đơn giá nhập theo đơn vị cơ bản
+	ReferenceType    string    `json:"reference_type"`    // Nguồn thay đổi: INVENTORY_RECEIPT hoặc MANUAL
+	ReferenceID      *int      `json:"reference_id"`      // Chứng từ gây thay đổi
+	CreatedByName    *string   `json:"created_by_name"`   // Người thực hiện
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type GetAllProductCostHistoriesResponse struct {
+	CostHistories []ProductCostHistoryResponse `json:"cost_histories"`
+}
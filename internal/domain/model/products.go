@@ -1,22 +1,24 @@
 package model
 
 type CreateProductRequest struct {
-	Name          string  `json:"name" binding:"required"`                                                  // Tên sản phẩm
-	Cost          float64 `json:"cost"`                                                                     // Giá vốn của sản phẩm (VND)
-	CategoryID    *int    `json:"category_id"`                                                              // ID danh mục sản phẩm
-	UnitID        *int    `json:"unit_id"`                                                                  // ID đơn vị tính
-	Description   string  `json:"description"`                                                              // Mô tả chi tiết sản phẩm
-	OperationType string  `json:"operation_type" binding:"required,oneof=MANUFACTURING PACKAGING PURCHASE"` // Loại sản phẩm: MANUFACTURING, PACKAGING hoặc PURCHASE
+	Name          string   `json:"name" binding:"required"`                                                  // Tên sản phẩm
+	Cost          float64  `json:"cost"`                                                                     // Giá vốn của sản phẩm (VND)
+	CategoryID    *int     `json:"category_id"`                                                              // ID danh mục sản phẩm
+	UnitID        *int     `json:"unit_id"`                                                                  // ID đơn vị tính
+	Description   string   `json:"description"`                                                              // Mô tả chi tiết sản phẩm
+	OperationType string   `json:"operation_type" binding:"required,oneof=MANUFACTURING PACKAGING PURCHASE"` // Loại sản phẩm: MANUFACTURING, PACKAGING hoặc PURCHASE
+	YieldPercent  *float64 `json:"yield_percent" binding:"omitempty,gt=0,lte=100"`                           // Hiệu suất sản xuất (%), bỏ trống = 100
 }
 
 type UpdateProductRequest struct {
-	ID            int     `json:"id" binding:"required"`
-	Name          string  `json:"name" binding:"required"`                                                  // Tên sản phẩm
-	Cost          float64 `json:"cost"`                                                                     // Giá vốn của sản phẩm (VND)
-	CategoryID    *int    `json:"category_id"`                                                              // ID danh mục sản phẩm
-	UnitID        *int    `json:"unit_id"`                                                                  // ID đơn vị tính
-	Description   string  `json:"description"`                                                              // Mô tả chi tiết sản phẩm
-	OperationType string  `json:"operation_type" binding:"required,oneof=MANUFACTURING PACKAGING PURCHASE"` // Loại sản phẩm: MANUFACTURING, PACKAGING hoặc PURCHASE
+	ID            int      `json:"id" binding:"required"`
+	Name          string   `json:"name" binding:"required"`                                                  // Tên sản phẩm
+	Cost          *float64 `json:"cost" binding:"omitempty,gte=0"`                                           // Giá vốn bình quân (VND), bỏ trống = giữ nguyên, chỉ nhập tay khi cần điều chỉnh
+	CategoryID    *int     `json:"category_id"`                                                              // ID danh mục sản phẩm
+	UnitID        *int     `json:"unit_id"`                                                                  // ID đơn vị tính
+	Description   string   `json:"description"`                                                              // Mô tả chi tiết sản phẩm
+	OperationType string   `json:"operation_type" binding:"required,oneof=MANUFACTURING PACKAGING PURCHASE"` // Loại sản phẩm: MANUFACTURING, PACKAGING hoặc PURCHASE
+	YieldPercent  *float64 `json:"yield_percent" binding:"omitempty,gt=0,lte=100"`                           // Hiệu suất sản xuất (%), bỏ trống = giữ nguyên
 }
 
 type ProductResponse struct {
@@ -28,6 +30,7 @@ type ProductResponse struct {
 	UnitID        *int                     `json:"unit_id"`            // ID đơn vị tính
 	Description   string                   `json:"description"`        // Mô tả chi tiết sản phẩm
 	OperationType string                   `json:"operation_type"`     // Loại sản phẩm: MANUFACTURING, PACKAGING hoặc PURCHASE
+	YieldPercent  float64                  `json:"yield_percent"`      // Hiệu suất sản xuất (%)
 	Category      *ProductCategoryResponse `json:"category,omitempty"` // Thông tin danh mục sản phẩm
 	Unit          *UnitOfMeasureResponse   `json:"unit,omitempty"`     // Thông tin đơn vị tính
 	Inventory     *InventoryInfo           `json:"inventory,omitempty"`
@@ -44,14 +47,14 @@ type ProductBOMInfo struct {
 
 // Information about where this product is used as component
 type ProductBOMUsage struct {
-	ParentProductID   int    `json:"parent_product_id"`   // ID sản phẩm thành phẩm
-	ParentProductName string `json:"parent_product_name"` // Tên sản phẩm thành phẩm
-	Quantity          int    `json:"quantity"`            // Số lượng cần thiết
+	ParentProductID   int     `json:"parent_product_id"`   // ID sản phẩm thành phẩm
+	ParentProductName string  `json:"parent_product_name"` // Tên sản phẩm thành phẩm
+	Quantity          float64 `json:"quantity"`            // Số lượng cần thiết
 }
 
 type InventoryInfo struct {
-	Quantity int    `json:"quantity"` // Số lượng tồn kho
-	Version  string `json:"version"`  // Version để optimistic lock
+	Quantity int    `json:"quantity"` // Số lượng tồn kho (tất cả các kho)
+	Version  string `json:"version"`  // Version tồn kho của kho mặc định để optimistic lock
 }
 
 type GetAllProductsResponse struct {
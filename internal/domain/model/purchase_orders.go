@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+type PurchaseOrderItemRequest struct {
+	ProductID    int        `json:"product_id" binding:"required"`       // Sản phẩm
+	Quantity     int        `json:"quantity" binding:"required,gt=0"`    // Số lượng đặt (theo đơn vị đặt)
+	UnitID       *int       `json:"unit_id"`                             // Đơn vị đặt (bỏ trống = đơn vị cơ bản của sản phẩm)
+	UnitCost     *float64   `json:"unit_cost" binding:"omitempty,gte=0"` // Đơn giá theo đơn vị đặt
+	ExpectedDate *time.Time `json:"expected_date"`                       // Ngày dự kiến nhận dòng hàng (bỏ trống = theo đơn)
+	Note         *string    `json:"note"`                                // Ghi chú
+}
+
+type CreatePurchaseOrderRequest struct {
+	SupplierID   int                        `json:"supplier_id" binding:"required"`      // Nhà cung cấp
+	OrderDate    *time.Time                 `json:"order_date"`                          // Ngày đặt mua (mặc định là hiện tại)
+	ExpectedDate *time.Time                 `json:"expected_date"`                       // Ngày dự kiến nhận hàng
+	Note         *string                    `json:"note"`                                // Ghi chú
+	Items        []PurchaseOrderItemRequest `json:"items" binding:"required,min=1,dive"` // Danh sách sản phẩm đặt mua
+}
+
+type UpdatePurchaseOrderRequest struct {
+	SupplierID   int                        `json:"supplier_id" binding:"required"`      // Nhà cung cấp
+	OrderDate    *time.Time                 `json:"order_date"`                          // Ngày đặt mua
+	ExpectedDate *time.Time                 `json:"expected_date"`                       // Ngày dự kiến nhận hàng
+	Note         *string                    `json:"note"`                                // Ghi chú
+	Items        []PurchaseOrderItemRequest `json:"items" binding:"required,min=1,dive"` // Danh sách sản phẩm (thay thế toàn bộ)
+}
+
+type UpdatePurchaseOrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=ORDERED CANCELLED"` // Trạng thái mới
+}
+
+type PurchaseOrderItemResponse struct {
+	ID                int        `json:"id"`
+	ProductID         int        `json:"product_id"`
+	ProductName       string     `json:"product_name"`
+	Quantity          int        `json:"quantity"`           // Số lượng đặt (theo đơn vị cơ bản)
+	ReceivedQuantity  int        `json:"received_quantity"`  // Số lượng đã nhận
+	RemainingQuantity int        `json:"remaining_quantity"` // Số lượng còn chờ nhận
+	UnitCost          *float64   `json:"unit_cost"`          // Đơn giá theo đơn vị cơ bản
+	ExpectedDate      *time.Time `json:"expected_date"`      // Ngày dự kiến nhận dòng hàng
+	Note              *string    `json:"note"`
+}
+
+type PurchaseOrderResponse struct {
+	ID           int                         `json:"id"`
+	Code         string                      `json:"code"`
+	Supplier     SupplierResponse            `json:"supplier"`
+	OrderDate    time.Time                   `json:"order_date"`
+	ExpectedDate *time.Time                  `json:"expected_date"`
+	Status       string                      `json:"status"`
+	Note         *string                     `json:"note"`
+	TotalAmount  float64                     `json:"total_amount"` // Tổng tiền theo đơn giá đặt mua
+	Items        []PurchaseOrderItemResponse `json:"items,omitempty"`
+	CreatedBy    *int                        `json:"created_by"`
+	CreatedAt    time.Time                   `json:"created_at"`
+	UpdatedAt    time.Time                   `json:"updated_at"`
+}
+
+type GetAllPurchaseOrdersResponse struct {
+	PurchaseOrders []PurchaseOrderResponse `json:"purchase_orders"`
+}
+
+type GetOnePurchaseOrderResponse struct {
+	PurchaseOrder PurchaseOrderResponse `json:"purchase_order"`
+}
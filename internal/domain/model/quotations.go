@@ -0,0 +1,85 @@
+package model
+
+import "time"
+
+type CreateQuotationRequest struct {
+	CustomerID         int                    `json:"customer_id" binding:"required"`      // Khách hàng
+	QuotationDate      *time.Time             `json:"quotation_date"`                      // Ngày báo giá (mặc định là hiện tại)
+	ValidFrom          time.Time              `json:"valid_from" binding:"required"`       // Hiệu lực từ ngày
+	ValidUntil         time.Time              `json:"valid_until" binding:"required"`      // Hiệu lực đến ngày
+	Note               *string                `json:"note"`                                // Ghi chú
+	AdditionalCost     int                    `json:"additional_cost"`                     // Chi phí phát sinh
+	AdditionalCostNote *string                `json:"additional_cost_note"`                // Ghi chú chi phí phát sinh
+	TaxPercent         int                    `json:"tax_percent"`                         // Thuế suất
+	Items              []QuotationItemRequest `json:"items" binding:"required,min=1,dive"` // Danh sách sản phẩm
+}
+
+type UpdateQuotationRequest struct {
+	CustomerID         int                    `json:"customer_id" binding:"required"`      // Khách hàng
+	QuotationDate      *time.Time             `json:"quotation_date"`                      // Ngày báo giá
+	ValidFrom          time.Time              `json:"valid_from" binding:"required"`       // Hiệu lực từ ngày
+	ValidUntil         time.Time              `json:"valid_until" binding:"required"`      // Hiệu lực đến ngày
+	Note               *string                `json:"note"`                                // Ghi chú
+	AdditionalCost     int                    `json:"additional_cost"`                     // Chi phí phát sinh
+	AdditionalCostNote *string                `json:"additional_cost_note"`                // Ghi chú chi phí phát sinh
+	TaxPercent         int                    `json:"tax_percent"`                         // Thuế suất
+	Items              []QuotationItemRequest `json:"items" binding:"required,min=1,dive"` // Danh sách sản phẩm (thay thế toàn bộ)
+}
+
+type QuotationItemRequest struct {
+	ProductID       int  `json:"product_id" binding:"required"`            // Sản phẩm
+	Quantity        int  `json:"quantity" binding:"required,gt=0"`         // Số lượng (theo đơn vị bán)
+	UnitID          *int `json:"unit_id"`                                  // Đơn vị bán (bỏ trống = đơn vị cơ bản của sản phẩm)
+	SellingPrice    int  `json:"selling_price" binding:"gte=0"`            // Giá bán theo đơn vị bán
+	DiscountPercent int  `json:"discount_percent" binding:"gte=0,lte=100"` // Chiết khấu
+}
+
+type UpdateQuotationStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=SENT ACCEPTED EXPIRED"` // Trạng thái mới
+}
+
+type ConvertQuotationRequest struct {
+	OrderDate   *time.Time `json:"order_date"`   // Ngày đặt hàng (mặc định là hiện tại)
+	Note        *string    `json:"note"`         // Ghi chú đơn hàng (mặc định lấy ghi chú báo giá)
+	WarehouseID *int       `json:"warehouse_id"` // Kho xuất hàng (bỏ trống = kho mặc định)
+}
+
+type QuotationItemResponse struct {
+	ID              int    `json:"id"`
+	ProductID       int    `json:"product_id"`
+	ProductName     string `json:"product_name"`
+	Quantity        int    `json:"quantity"`
+	UnitID          *int   `json:"unit_id"`   // Đơn vị bán (nil = đơn vị cơ bản)
+	UnitName        string `json:"unit_name"` // Tên đơn vị bán
+	SellingPrice    int    `json:"selling_price"`
+	OriginalPrice   int    `json:"original_price"`
+	DiscountPercent int    `json:"discount_percent"`
+	FinalAmount     int    `json:"final_amount"`
+}
+
+type QuotationResponse struct {
+	ID                 int                     `json:"id"`
+	Code               string                  `json:"code"`
+	Customer           CustomerResponse        `json:"customer"`
+	QuotationDate      time.Time               `json:"quotation_date"`
+	ValidFrom          time.Time               `json:"valid_from"`
+	ValidUntil         time.Time               `json:"valid_until"`
+	Note               *string                 `json:"note"`
+	AdditionalCost     int                     `json:"additional_cost"`
+	AdditionalCostNote *string                 `json:"additional_cost_note"`
+	TaxPercent         int                     `json:"tax_percent"`
+	Status             string                  `json:"status"`
+	OrderID            *int                    `json:"order_id"`     // Đơn hàng được tạo từ báo giá
+	TotalAmount        int                     `json:"total_amount"` // Tổng tiền (hàng + chi phí phát sinh + thuế)
+	Items              []QuotationItemResponse `json:"items,omitempty"`
+	CreatedAt          time.Time               `json:"created_at"`
+	UpdatedAt          time.Time               `json:"updated_at"`
+}
+
+type GetAllQuotationsResponse struct {
+	Quotations []QuotationResponse `json:"quotations"`
+}
+
+type GetOneQuotationResponse struct {
+	Quotation QuotationResponse `json:"quotation"`
+}
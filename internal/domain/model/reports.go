@@ -0,0 +1,130 @@
+package model
+
+import "time"
+
+type ReceivableAgingBuckets struct {
+	Days0To30  int `json:"days_0_30"`  // Nợ từ 0-30 ngày
+	Days31To60 int `json:"days_31_60"` // Nợ từ 31-60 ngày
+	Days61To90 int `json:"days_61_90"` // Nợ từ 61-90 ngày
+	Over90Days int `json:"over_90"`    // Nợ trên 90 ngày
+	Total      int `json:"total"`      // Tổng nợ
+}
+
+type CustomerReceivableResponse struct {
+	Customer         CustomerResponse       `json:"customer"`
+	UnpaidOrderCount int                    `json:"unpaid_order_count"` // Số đơn hàng còn nợ
+	Buckets          ReceivableAgingBuckets `json:"buckets"`
+}
+
+type GetReceivablesAgingResponse struct {
+	AsOfDate  time.Time                    `json:"as_of_date"` // Ngày chốt số liệu
+	Customers []CustomerReceivableResponse `json:"customers"`
+	Totals    ReceivableAgingBuckets       `json:"totals"`
+}
+
+type CustomerStatementEntry struct {
+	Date          time.Time `json:"date"`
+	EntryType     string    `json:"entry_type"`                // ORDER, PAYMENT hoặc SALES_RETURN
+	OrderID       int       `json:"order_id"`                  // Đơn hàng liên quan
+	OrderCode     string    `json:"order_code"`                // Mã đơn hàng
+	PaymentID     *int      `json:"payment_id,omitempty"`      // Phiếu thu (chỉ với PAYMENT)
+	PaymentMethod *string   `json:"payment_method,omitempty"`  // Hình thức thanh toán (chỉ với PAYMENT)
+	SalesReturnID *int      `json:"sales_return_id,omitempty"` // Phiếu trả hàng (chỉ với SALES_RETURN)
+	Description   string    `json:"description"`               // Diễn giải
+	Debit         int       `json:"debit"`                     // Phát sinh nợ
+	Credit        int       `json:"credit"`                    // Phát sinh có
+	Balance       int       `json:"balance"`                   // Số dư lũy kế
+}
+
+type GetCustomerStatementResponse struct {
+	Customer       CustomerResponse         `json:"customer"`
+	FromDate       *time.Time               `json:"from_date,omitempty"`
+	ToDate         *time.Time               `json:"to_date,omitempty"`
+	OpeningBalance int                      `json:"opening_balance"` // Số dư đầu kỳ
+	Entries        []CustomerStatementEntry `json:"entries"`
+	ClosingBalance int                      `json:"closing_balance"` // Số dư cuối kỳ
+}
+
+type customerStatementEntryType struct {
+	ORDER        string
+	PAYMENT      string
+	SALES_RETURN string
+}
+
+var CustomerStatementEntryType = customerStatementEntryType{
+	ORDER:        "ORDER",
+	PAYMENT:      "PAYMENT",
+	SALES_RETURN: "SALES_RETURN",
+}
+
+type LotRecallShipment struct {
+	OrderID     int              `json:"order_id"`
+	OrderCode   string           `json:"order_code"` // Mã đơn hàng
+	OrderDate   time.Time        `json:"order_date"`
+	Customer    CustomerResponse `json:"customer"`
+	OrderItemID *int             `json:"order_item_id"` // Dòng đơn hàng (nil khi lô là nguyên liệu của thành phẩm)
+	ProductID   int              `json:"product_id"`
+	ProductName string           `json:"product_name"`
+	WarehouseID int              `json:"warehouse_id"` // Kho xuất lô
+	LotNumber   string           `json:"lot_number"`
+	Quantity    int              `json:"quantity"` // Số lượng khách đã nhận từ lô (theo đơn vị cơ bản)
+}
+
+type LotRecallWorkOrder struct {
+	WorkOrderID          int    `json:"work_order_id"`
+	WorkOrderCode        string `json:"work_order_code"` // Mã lệnh sản xuất
+	Status               string `json:"status"`
+	ComponentProductID   int    `json:"component_product_id"`
+	ComponentProductName string `json:"component_product_name"`
+	ComponentLotNumber   string `json:"component_lot_number"` // Lô nguyên liệu đã tiêu hao
+	Quantity             int    `json:"quantity"`             // Số lượng đã tiêu hao từ lô (theo đơn vị cơ bản)
+	FinishedLotID        *int   `json:"finished_lot_id"`      // Lô thành phẩm (nil khi chưa hoàn thành)
+}
+
+type GetLotRecallResponse struct {
+	LotNumber       string                 `json:"lot_number"`
+	Lots            []InventoryLotResponse `json:"lots"`             // Lô trong các kho và tồn hiện tại
+	WorkOrders      []LotRecallWorkOrder   `json:"work_orders"`      // Các lệnh sản xuất đã dùng lô làm nguyên liệu
+	ProducedLots    []InventoryLotResponse `json:"produced_lots"`    // Lô thành phẩm làm từ lô
+	Shipments       []LotRecallShipment    `json:"shipments"`        // Các đơn hàng đã nhận lô hoặc thành phẩm làm từ lô
+	Customers       []CustomerResponse     `json:"customers"`        // Khách hàng cần thu hồi
+	ShippedQuantity int                    `json:"shipped_quantity"` // Tổng số lượng đã xuất cho khách
+	OnHandQuantity  int                    `json:"on_hand_quantity"` // Tổng số lượng còn trong kho, kể cả thành phẩm làm từ lô
+}
+
+type StockValuationProduct struct {
+	ProductID         int     `json:"product_id"`
+	ProductCode       string  `json:"product_code"`
+	ProductName       string  `json:"product_name"`
+	CategoryID        *int    `json:"category_id"`
+	CategoryName      string  `json:"category_name"`
+	OperationType     string  `json:"operation_type"`     // MANUFACTURING, PACKAGING hoặc PURCHASE
+	Quantity          int     `json:"quantity"`           // Tồn kho tại ngày chốt (mọi kho, theo đơn vị cơ bản)
+	UnitCost          float64 `json:"unit_cost"`          // Đơn giá áp dụng tại ngày chốt (VND)
+	Value             float64 `json:"value"`              // Giá trị tồn (VND)
+	UncoveredQuantity int     `json:"uncovered_quantity"` // Số lượng không còn lớp giá FIFO, tính theo giá bình quân
+	UncoveredValue    float64 `json:"uncovered_value"`    // Giá trị phần không có lớp giá FIFO (VND)
+}
+
+type StockValuationCategoryTotal struct {
+	CategoryID   *int    `json:"category_id"` // nil = chưa phân loại
+	CategoryName string  `json:"category_name"`
+	Quantity     int     `json:"quantity"`
+	Value        float64 `json:"value"`
+}
+
+type StockValuationOperationTypeTotal struct {
+	OperationType string  `json:"operation_type"`
+	Quantity      int     `json:"quantity"`
+	Value         float64 `json:"value"`
+}
+
+type GetStockValuationResponse struct {
+	AsOfDate       time.Time                          `json:"as_of_date"`      // Ngày chốt số liệu
+	Method         string                             `json:"method"`          // Phương pháp tính giá: AVERAGE hoặc FIFO
+	Products       []StockValuationProduct            `json:"products"`        // Sản phẩm còn tồn tại ngày chốt
+	Categories     []StockValuationCategoryTotal      `json:"categories"`      // Tổng theo danh mục
+	OperationTypes []StockValuationOperationTypeTotal `json:"operation_types"` // Tổng theo loại sản phẩm
+	TotalValue     float64                            `json:"total_value"`     // Tổng giá trị tồn kho (VND)
+	UncoveredValue float64                            `json:"uncovered_value"` // Phần giá trị tính theo giá bình quân vì không có lớp giá FIFO (VND)
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+type CreateSalesReturnRequest struct {
+	ReturnDate *time.Time                     `json:"return_date"`                         // Ngày trả hàng (mặc định là hiện tại)
+	Reason     *string                        `json:"reason"`                              // Lý do trả hàng
+	Items      []CreateSalesReturnItemRequest `json:"items" binding:"required,min=1,dive"` // Danh sách hàng trả
+}
+
+type CreateSalesReturnItemRequest struct {
+	OrderItemID int     `json:"order_item_id" binding:"required"`                                                 // Dòng đơn hàng được trả
+	Quantity    int     `json:"quantity" binding:"required,gt=0"`                                                 // Số lượng trả
+	Disposition string  `json:"disposition" binding:"required,oneof=RESTOCK_PRODUCT RESTOCK_MATERIALS WRITE_OFF"` // Cách xử lý hàng trả
+	Reason      *string `json:"reason"`                                                                           // Lý do trả
+}
+
+type SalesReturnItemResponse struct {
+	ID            int     `json:"id"`
+	SalesReturnID int     `json:"sales_return_id"`
+	OrderItemID   int     `json:"order_item_id"`
+	ProductID     int     `json:"product_id"`
+	ProductName   string  `json:"product_name"`
+	Quantity      int     `json:"quantity"`
+	Disposition   string  `json:"disposition"`
+	Reason        *string `json:"reason"`
+	CreditAmount  int     `json:"credit_amount"` // Số tiền ghi giảm (chưa gồm thuế)
+}
+
+type SalesReturnResponse struct {
+	ID            int                       `json:"id"`
+	Code          string                    `json:"code"`
+	OrderID       int                       `json:"order_id"`
+	ReturnDate    time.Time                 `json:"return_date"`
+	Reason        *string                   `json:"reason"`
+	CreditAmount  int                       `json:"credit_amount"` // Số tiền ghi giảm công nợ (đã gồm thuế)
+	CreatedBy     *int                      `json:"created_by"`
+	CreatedByName string                    `json:"created_by_name"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	Items         []SalesReturnItemResponse `json:"items,omitempty"`
+}
+
+type GetAllSalesReturnsResponse struct {
+	SalesReturns []SalesReturnResponse `json:"sales_returns"`
+}
+
+type GetOneSalesReturnResponse struct {
+	SalesReturn SalesReturnResponse `json:"sales_return"`
+}
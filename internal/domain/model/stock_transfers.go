@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+type StockTransferItemRequest struct {
+	ProductID int     `json:"product_id" binding:"required"`    // Sản phẩm
+	Quantity  int     `json:"quantity" binding:"required,gt=0"` // Số lượng chuyển (theo đơn vị chuyển)
+	UnitID    *int    `json:"unit_id"`                          // Đơn vị chuyển (bỏ trống = đơn vị cơ bản của sản phẩm)
+	Note      *string `json:"note"`                             // Ghi chú
+}
+
+type CreateStockTransferRequest struct {
+	FromWarehouseID int                        `json:"from_warehouse_id" binding:"required"` // Kho xuất
+	ToWarehouseID   int                        `json:"to_warehouse_id" binding:"required"`   // Kho nhận
+	TransferDate    *time.Time                 `json:"transfer_date"`                        // Ngày chuyển kho (mặc định là hiện tại)
+	Note            *string                    `json:"note"`                                 // Ghi chú
+	Items           []StockTransferItemRequest `json:"items" binding:"required,min=1,dive"`  // Danh sách sản phẩm chuyển kho
+}
+
+type StockTransferItemResponse struct {
+	ID          int     `json:"id"`
+	ProductID   int     `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"` // Số lượng chuyển (theo đơn vị cơ bản)
+	Note        *string `json:"note"`
+}
+
+type StockTransferResponse struct {
+	ID                int                         `json:"id"`
+	Code              string                      `json:"code"` // Mã phiếu chuyển kho (CK00001)
+	FromWarehouseID   int                         `json:"from_warehouse_id"`
+	FromWarehouseName string                      `json:"from_warehouse_name"` // Tên kho xuất
+	ToWarehouseID     int                         `json:"to_warehouse_id"`
+	ToWarehouseName   string                      `json:"to_warehouse_name"` // Tên kho nhận
+	TransferDate      time.Time                   `json:"transfer_date"`
+	Note              *string                     `json:"note"`
+	CreatedBy         *int                        `json:"created_by"`
+	CreatedByName     string                      `json:"created_by_name"`
+	CreatedAt         time.Time                   `json:"created_at"`
+	Items             []StockTransferItemResponse `json:"items,omitempty"`
+}
+
+type GetAllStockTransfersResponse struct {
+	StockTransfers []StockTransferResponse `json:"stock_transfers"`
+}
+
+type GetOneStockTransferResponse struct {
+	StockTransfer StockTransferResponse `json:"stock_transfer"`
+}
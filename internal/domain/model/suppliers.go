@@ -0,0 +1,35 @@
+package model
+
+type CreateSupplierRequest struct {
+	Name    string  `json:"name" binding:"required"`    // Tên nhà cung cấp
+	Phone   string  `json:"phone" binding:"required"`   // Số điện thoại
+	Email   *string `json:"email"`                      // Email
+	Address string  `json:"address" binding:"required"` // Địa chỉ
+	TaxCode *string `json:"tax_code"`                   // Mã số thuế
+}
+
+type UpdateSupplierRequest struct {
+	Name    string  `json:"name"`     // Tên nhà cung cấp
+	Phone   string  `json:"phone"`    // Số điện thoại
+	Email   *string `json:"email"`    // Email
+	Address string  `json:"address"`  // Địa chỉ
+	TaxCode *string `json:"tax_code"` // Mã số thuế
+}
+
+type SupplierResponse struct {
+	ID      int     `json:"id"`
+	Code    string  `json:"code"`     // Mã nhà cung cấp (NCC00001)
+	Name    string  `json:"name"`     // Tên nhà cung cấp
+	Phone   string  `json:"phone"`    // Số điện thoại
+	Email   *string `json:"email"`    // Email
+	Address string  `json:"address"`  // Địa chỉ
+	TaxCode *string `json:"tax_code"` // Mã số thuế
+}
+
+type GetAllSuppliersResponse struct {
+	Suppliers []SupplierResponse `json:"suppliers"`
+}
+
+type GetOneSupplierResponse struct {
+	Supplier SupplierResponse `json:"supplier"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+type CreateUnitConversionRequest struct {
+	ProductID  *int    `json:"product_id"`                      // Sản phẩm áp dụng (bỏ trống = áp dụng cho mọi sản phẩm)
+	FromUnitID int     `json:"from_unit_id" binding:"required"` // Đơn vị quy đổi (VD: THUNG)
+	ToUnitID   int     `json:"to_unit_id" binding:"required"`   // Đơn vị đích (VD: CAI)
+	Factor     float64 `json:"factor" binding:"required,gt=0"`  // Hệ số: 1 from_unit = factor to_unit (VD: 24)
+}
+
+type UpdateUnitConversionRequest struct {
+	FromUnitID int     `json:"from_unit_id" binding:"required"` // Đơn vị quy đổi
+	ToUnitID   int     `json:"to_unit_id" binding:"required"`   // Đơn vị đích
+	Factor     float64 `json:"factor" binding:"required,gt=0"`  // Hệ số: 1 from_unit = factor to_unit
+}
+
+type UnitConversionResponse struct {
+	ID           int       `json:"id"`
+	ProductID    *int      `json:"product_id"`   // nil = áp dụng cho mọi sản phẩm
+	ProductName  *string   `json:"product_name"` // Tên sản phẩm áp dụng
+	FromUnitID   int       `json:"from_unit_id"`
+	FromUnitName string    `json:"from_unit_name"`
+	ToUnitID     int       `json:"to_unit_id"`
+	ToUnitName   string    `json:"to_unit_name"`
+	Factor       float64   `json:"factor"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type GetAllUnitConversionsResponse struct {
+	Conversions []UnitConversionResponse `json:"conversions"`
+}
+
+type GetOneUnitConversionResponse struct {
+	Conversion UnitConversionResponse `json:"conversion"`
+}
@@ -0,0 +1,29 @@
+package model
+
+type CreateWarehouseRequest struct {
+	Name      string  `json:"name" binding:"required"` // Tên kho
+	Address   *string `json:"address"`                 // Địa chỉ
+	IsDefault bool    `json:"is_default"`              // Đặt làm kho mặc định
+}
+
+type UpdateWarehouseRequest struct {
+	Name      string  `json:"name"`       // Tên kho
+	Address   *string `json:"address"`    // Địa chỉ
+	IsDefault *bool   `json:"is_default"` // Đặt làm kho mặc định (chỉ nhận true, kho mặc định luôn phải có)
+}
+
+type WarehouseResponse struct {
+	ID        int     `json:"id"`
+	Code      string  `json:"code"`       // Mã kho (KHO00001)
+	Name      string  `json:"name"`       // Tên kho
+	Address   *string `json:"address"`    // Địa chỉ
+	IsDefault bool    `json:"is_default"` // Kho mặc định khi chứng từ không chỉ định kho
+}
+
+type GetAllWarehousesResponse struct {
+	Warehouses []WarehouseResponse `json:"warehouses"`
+}
+
+type GetOneWarehouseResponse struct {
+	Warehouse WarehouseResponse `json:"warehouse"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+type CreateWorkOrderRequest struct {
+	ProductID   int        `json:"product_id" binding:"required"`    // Thành phẩm cần sản xuất (MANUFACTURING hoặc PACKAGING)
+	WarehouseID *int       `json:"warehouse_id"`                     // Kho xuất nguyên liệu và nhập thành phẩm (bỏ trống = kho mặc định)
+	Quantity    int        `json:"quantity" binding:"required,gt=0"` // Số lượng sản xuất
+	MultiLevel  bool       `json:"multi_level"`                      // Sản xuất cả bán thành phẩm còn thiếu theo BOM nhiều cấp
+	PlannedDate *time.Time `json:"planned_date"`                     // Ngày dự kiến sản xuất
+	Note        *string    `json:"note"`                             // Ghi chú
+}
+
+type UpdateWorkOrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=IN_PROGRESS DONE"` // Trạng thái mới
+}
+
+type WorkOrderItemResponse struct {
+	ID          int     `json:"id"`
+	ProductID   int     `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`  // Số lượng đã tiêu hao
+	UnitCost    float64 `json:"unit_cost"` // Giá vốn theo đơn vị cơ bản, theo phương pháp tính giá kho
+}
+
+type WorkOrderResponse struct {
+	ID            int                     `json:"id"`
+	Code          string                  `json:"code"`
+	ProductID     int                     `json:"product_id"`
+	ProductName   string                  `json:"product_name"`
+	WarehouseID   int                     `json:"warehouse_id"` // Kho xuất nguyên liệu và nhập thành phẩm
+	Quantity      int                     `json:"quantity"`
+	MultiLevel    bool                    `json:"multi_level"`
+	Status        string                  `json:"status"`
+	PlannedDate   *time.Time              `json:"planned_date"`
+	Note          *string                 `json:"note"`
+	StartedAt     *time.Time              `json:"started_at"`
+	CompletedAt   *time.Time              `json:"completed_at"`
+	FinishedLotID *int                    `json:"finished_lot_id"` // Lô thành phẩm nhập kho
+	CreatedBy     *int                    `json:"created_by"`
+	CreatedByName string                  `json:"created_by_name"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+	Items         []WorkOrderItemResponse `json:"items,omitempty"` // Thành phần đã xuất kho khi bắt đầu sản xuất
+}
+
+type GetAllWorkOrdersResponse struct {
+	WorkOrders []WorkOrderResponse `json:"work_orders"`
+}
+
+type GetOneWorkOrderResponse struct {
+	WorkOrder WorkOrderResponse `json:"work_order"`
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type IdempotencyKeyRepository interface {
+	CreateCommand(ctx context.Context, idempotencyKey *entity.IdempotencyKey, tx *sqlx.Tx) error
+	GetOneByUserIDAndKeyQuery(ctx context.Context, userID int, key string, tx *sqlx.Tx) (*entity.IdempotencyKey, error)
+	CompleteCommand(ctx context.Context, id int, responseStatusCode int, responseBody string, tx *sqlx.Tx) error
+	DeleteCommand(ctx context.Context, id int, tx *sqlx.Tx) error
+	DeleteExpiredCommand(ctx context.Context, now time.Time, tx *sqlx.Tx) error
+	DeleteStaleCommand(ctx context.Context, id int, now time.Time, leaseStartedBefore time.Time, tx *sqlx.Tx) (bool, error)
+}
@@ -0,0 +1,136 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type IdempotencyKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewIdempotencyKeyRepository(db database.Db) repository.IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: db}
+}
+
+func (repo *IdempotencyKeyRepository) CreateCommand(ctx context.Context, idempotencyKey *entity.IdempotencyKey, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO idempotency_keys(user_id, idempotency_key, request_hash, status, created_at, expires_at)
+					VALUES (:user_id, :idempotency_key, :request_hash, :status, :created_at, :expires_at)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, idempotencyKey)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, idempotencyKey)
+	}
+
+	if err != nil {
+		// The key is already taken by an earlier request of the same user
+		if strings.Contains(err.Error(), "unique_user_idempotency_key") {
+			return &error_utils.ConstraintViolationError{Message: "Idempotency key already exists"}
+		}
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	idempotencyKey.ID = int(lastID)
+	return nil
+}
+
+func (repo *IdempotencyKeyRepository) GetOneByUserIDAndKeyQuery(ctx context.Context, userID int, key string, tx *sqlx.Tx) (*entity.IdempotencyKey, error) {
+	var idempotencyKey entity.IdempotencyKey
+	query := "SELECT * FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &idempotencyKey, query, userID, key)
+	} else {
+		err = repo.db.GetContext(ctx, &idempotencyKey, query, userID, key)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &idempotencyKey, nil
+}
+
+func (repo *IdempotencyKeyRepository) CompleteCommand(ctx context.Context, id int, responseStatusCode int, responseBody string, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE idempotency_keys SET status = ?, response_status_code = ?, response_body = ? WHERE id = ?`
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, updateQuery, entity.IdempotencyKeyStatus.COMPLETED, responseStatusCode, responseBody, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, updateQuery, entity.IdempotencyKeyStatus.COMPLETED, responseStatusCode, responseBody, id)
+	}
+
+	return err
+}
+
+func (repo *IdempotencyKeyRepository) DeleteCommand(ctx context.Context, id int, tx *sqlx.Tx) error {
+	deleteQuery := "DELETE FROM idempotency_keys WHERE id = ?"
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, deleteQuery, id)
+	} else {
+		_, err = repo.db.ExecContext(ctx, deleteQuery, id)
+	}
+
+	return err
+}
+
+// DeleteExpiredCommand removes the keys whose retention window has passed
+func (repo *IdempotencyKeyRepository) DeleteExpiredCommand(ctx context.Context, now time.Time, tx *sqlx.Tx) error {
+	deleteQuery := "DELETE FROM idempotency_keys WHERE expires_at < ?"
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, deleteQuery, now)
+	} else {
+		_, err = repo.db.ExecContext(ctx, deleteQuery, now)
+	}
+
+	return err
+}
+
+// DeleteStaleCommand removes the key if it has expired or its request has been processing since
+// before leaseStartedBefore. It returns false when the key is still live.
+func (repo *IdempotencyKeyRepository) DeleteStaleCommand(ctx context.Context, id int, now time.Time, leaseStartedBefore time.Time, tx *sqlx.Tx) (bool, error) {
+	deleteQuery := `DELETE FROM idempotency_keys
+					WHERE id = ? AND (expires_at < ? OR (status = ? AND created_at < ?))`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, deleteQuery, id, now, entity.IdempotencyKeyStatus.PROCESSING, leaseStartedBefore)
+	} else {
+		result, err = repo.db.ExecContext(ctx, deleteQuery, id, now, entity.IdempotencyKeyStatus.PROCESSING, leaseStartedBefore)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
@@ -0,0 +1,66 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type InventoryCostLayerMovementRepository struct {
+	db *sqlx.DB
+}
+
+func NewInventoryCostLayerMovementRepository(db database.Db) repository.InventoryCostLayerMovementRepository {
+	return &InventoryCostLayerMovementRepository{db: db}
+}
+
+func (repo *InventoryCostLayerMovementRepository) CreateCommand(ctx context.Context, movement *entity.InventoryCostLayerMovement, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO inventory_cost_layer_movements(cost_layer_id, product_id, reference_type, reference_id, quantity, unit_cost)
+					VALUES (:cost_layer_id, :product_id, :reference_type, :reference_id, :quantity, :unit_cost)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, movement)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, movement)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	movement.ID = int(lastID)
+	return nil
+}
+
+func (repo *InventoryCostLayerMovementRepository) GetAllByReferenceQuery(ctx context.Context, referenceType string, referenceID int, tx *sqlx.Tx) ([]entity.InventoryCostLayerMovement, error) {
+	var movements []entity.InventoryCostLayerMovement
+	query := "SELECT * FROM inventory_cost_layer_movements WHERE reference_type = ? AND reference_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &movements, query, referenceType, referenceID)
+	} else {
+		err = repo.db.SelectContext(ctx, &movements, query, referenceType, referenceID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if movements == nil {
+		return []entity.InventoryCostLayerMovement{}, nil
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,174 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+// fifoOrder lists layers oldest first
+const fifoOrder = " ORDER BY received_at, id"
+
+type InventoryCostLayerRepository struct {
+	db *sqlx.DB
+}
+
+func NewInventoryCostLayerRepository(db database.Db) repository.InventoryCostLayerRepository {
+	return &InventoryCostLayerRepository{db: db}
+}
+
+// CreateCommand stamps the layer with the database clock, the one its movements are stamped with,
+// unless it already carries a received time
+func (repo *InventoryCostLayerRepository) CreateCommand(ctx context.Context, layer *entity.InventoryCostLayer, tx *sqlx.Tx) error {
+	receivedAt := ":received_at"
+	if layer.ReceivedAt.IsZero() {
+		receivedAt = "CURRENT_TIMESTAMP"
+	}
+	insertQuery := `INSERT INTO inventory_cost_layers(product_id, source_type, source_id, inventory_receipt_item_id, unit_cost, quantity, remaining_quantity, received_at)
+					VALUES (:product_id, :source_type, :source_id, :inventory_receipt_item_id, :unit_cost, :quantity, :remaining_quantity, ` + receivedAt + `)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, layer)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, layer)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	layer.ID = int(lastID)
+	return nil
+}
+
+// GetAllWithFiltersQuery returns the layers in FIFO order. A zero productID does not filter.
+func (repo *InventoryCostLayerRepository) GetAllWithFiltersQuery(ctx context.Context, productID int, openOnly bool, tx *sqlx.Tx) ([]entity.InventoryCostLayer, error) {
+	var layers []entity.InventoryCostLayer
+	query := "SELECT * FROM inventory_cost_layers WHERE 1=1"
+	var args []interface{}
+
+	if productID > 0 {
+		query += " AND product_id = ?"
+		args = append(args, productID)
+	}
+	if openOnly {
+		query += " AND remaining_quantity > 0"
+	}
+	query += " ORDER BY product_id, received_at, id"
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &layers, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &layers, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if layers == nil {
+		return []entity.InventoryCostLayer{}, nil
+	}
+	return layers, nil
+}
+
+// GetOpenForUpdateQuery locks the layers of the product that still hold stock, oldest first
+func (repo *InventoryCostLayerRepository) GetOpenForUpdateQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.InventoryCostLayer, error) {
+	var layers []entity.InventoryCostLayer
+	query := "SELECT * FROM inventory_cost_layers WHERE product_id = ? AND remaining_quantity > 0" + fifoOrder + " FOR UPDATE"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &layers, query, productID)
+	} else {
+		err = repo.db.SelectContext(ctx, &layers, query, productID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if layers == nil {
+		return []entity.InventoryCostLayer{}, nil
+	}
+	return layers, nil
+}
+
+func (repo *InventoryCostLayerRepository) GetManyByIDsForUpdateQuery(ctx context.Context, ids []int, tx *sqlx.Tx) ([]entity.InventoryCostLayer, error) {
+	if len(ids) == 0 {
+		return []entity.InventoryCostLayer{}, nil
+	}
+	query, args, err := sqlx.In("SELECT * FROM inventory_cost_layers WHERE id IN (?)"+fifoOrder+" FOR UPDATE", ids)
+	if err != nil {
+		return nil, err
+	}
+	query = repo.db.Rebind(query)
+
+	var layers []entity.InventoryCostLayer
+	if tx != nil {
+		err = tx.SelectContext(ctx, &layers, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &layers, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if layers == nil {
+		return []entity.InventoryCostLayer{}, nil
+	}
+	return layers, nil
+}
+
+// AddRemainingQuantityCommand adds the signed quantity to what is left in the layer
+func (repo *InventoryCostLayerRepository) AddRemainingQuantityCommand(ctx context.Context, id int, quantity int, tx *sqlx.Tx) error {
+	updateQuery := "UPDATE inventory_cost_layers SET remaining_quantity = remaining_quantity + ? WHERE id = ?"
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, quantity, id)
+		return err
+	}
+
+	_, err := repo.db.ExecContext(ctx, updateQuery, quantity, id)
+	return err
+}
+
+// GetRemainingBeforeQuery replays the layers received before the cutoff with their movements before
+// the cutoff. RemainingQuantity is what each layer still held at that time; layers that were used
+// up are left out. Layers come newest first per product, the order stock on hand is made up of.
+func (repo *InventoryCostLayerRepository) GetRemainingBeforeQuery(ctx context.Context, cutoff time.Time, tx *sqlx.Tx) ([]entity.InventoryCostLayer, error) {
+	var layers []entity.InventoryCostLayer
+	query := `SELECT l.id, l.product_id, l.source_type, l.source_id, l.inventory_receipt_item_id, l.unit_cost, l.quantity,
+			         l.quantity + COALESCE(m.quantity, 0) AS remaining_quantity, l.received_at, l.created_at
+			  FROM inventory_cost_layers l
+			  LEFT JOIN (
+			    SELECT cost_layer_id, SUM(quantity) AS quantity
+			    FROM inventory_cost_layer_movements
+			    WHERE created_at < ?
+			    GROUP BY cost_layer_id
+			  ) m ON m.cost_layer_id = l.id
+			  WHERE l.received_at < ? AND l.quantity + COALESCE(m.quantity, 0) > 0
+			  ORDER BY l.product_id, l.received_at DESC, l.id DESC`
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &layers, query, cutoff, cutoff)
+	} else {
+		err = repo.db.SelectContext(ctx, &layers, query, cutoff, cutoff)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if layers == nil {
+		return []entity.InventoryCostLayer{}, nil
+	}
+	return layers, nil
+}
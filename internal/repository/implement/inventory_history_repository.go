@@ -3,6 +3,7 @@ package repositoryimplement
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/management-app-backend/internal/database"
@@ -40,8 +41,30 @@ func (repo *InventoryHistoryRepository) GetAllByProductIDQuery(ctx context.Conte
 	return inventoryHistories, nil
 }
 
+func (repo *InventoryHistoryRepository) GetAllByReferenceQuery(ctx context.Context, referenceType string, referenceID int, tx *sqlx.Tx) ([]entity.InventoryHistory, error) {
+	var inventoryHistories []entity.InventoryHistory
+	query := "SELECT * FROM inventory_histories WHERE reference_type = ? AND reference_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &inventoryHistories, query, referenceType, referenceID)
+	} else {
+		err = repo.db.SelectContext(ctx, &inventoryHistories, query, referenceType, referenceID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if inventoryHistories == nil {
+		return []entity.InventoryHistory{}, nil
+	}
+
+	return inventoryHistories, nil
+}
+
 func (repo *InventoryHistoryRepository) CreateCommand(ctx context.Context, inventoryHistory *entity.InventoryHistory, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO inventory_histories(product_id, quantity, final_quantity, importer_name, imported_at, note, reference_id) VALUES (:product_id, :quantity, :final_quantity, :importer_name, :imported_at, :note, :reference_id)`
+	insertQuery := `INSERT INTO inventory_histories(product_id, warehouse_id, quantity, final_quantity, importer_name, imported_at, note, reference_id, reference_type) VALUES (:product_id, :warehouse_id, :quantity, :final_quantity, :importer_name, :imported_at, :note, :reference_id, :reference_type)`
 
 	var result sql.Result
 	var err error
@@ -66,3 +89,41 @@ func (repo *InventoryHistoryRepository) CreateCommand(ctx context.Context, inven
 	inventoryHistory.ID = int(lastID)
 	return nil
 }
+
+// GetQuantitiesBeforeQuery rebuilds the on-hand quantity of every product just before the cutoff by
+// taking the changes recorded from the cutoff on back off the current inventory, so that stock that
+// never moved (and has no history) is counted as well
+func (repo *InventoryHistoryRepository) GetQuantitiesBeforeQuery(ctx context.Context, cutoff time.Time, tx *sqlx.Tx) (map[int]int, error) {
+	query := `SELECT i.product_id, i.quantity - COALESCE(h.quantity, 0) AS quantity
+			  FROM (
+			    SELECT product_id, SUM(quantity) AS quantity
+			    FROM inventory
+			    GROUP BY product_id
+			  ) i
+			  LEFT JOIN (
+			    SELECT product_id, SUM(quantity) AS quantity
+			    FROM inventory_histories
+			    WHERE imported_at >= ?
+			    GROUP BY product_id
+			  ) h ON h.product_id = i.product_id`
+
+	var rows []struct {
+		ProductID int `db:"product_id"`
+		Quantity  int `db:"quantity"`
+	}
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, cutoff)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, cutoff)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[int]int, len(rows))
+	for _, row := range rows {
+		quantities[row.ProductID] = row.Quantity
+	}
+	return quantities, nil
+}
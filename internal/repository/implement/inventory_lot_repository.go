@@ -0,0 +1,211 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+// fefoOrder lists lots earliest expiry first, lots without an expiry date last
+const fefoOrder = " ORDER BY expiry_date IS NULL, expiry_date, id"
+
+type InventoryLotRepository struct {
+	db *sqlx.DB
+}
+
+func NewInventoryLotRepository(db database.Db) repository.InventoryLotRepository {
+	return &InventoryLotRepository{db: db}
+}
+
+func (repo *InventoryLotRepository) CreateCommand(ctx context.Context, lot *entity.InventoryLot, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO inventory_lots(product_id, warehouse_id, lot_number, expiry_date, quantity)
+					VALUES (:product_id, :warehouse_id, :lot_number, :expiry_date, :quantity)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, lot)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, lot)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	lot.ID = int(lastID)
+	return nil
+}
+
+func (repo *InventoryLotRepository) GetOneByLotNumberQuery(ctx context.Context, productID int, warehouseID int, lotNumber string, tx *sqlx.Tx) (*entity.InventoryLot, error) {
+	var lot entity.InventoryLot
+	query := "SELECT * FROM inventory_lots WHERE product_id = ? AND warehouse_id = ? AND lot_number = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &lot, query, productID, warehouseID, lotNumber)
+	} else {
+		err = repo.db.GetContext(ctx, &lot, query, productID, warehouseID, lotNumber)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &lot, nil
+}
+
+// GetAllWithFiltersQuery returns the lots in FEFO order. A zero productID or warehouseID and an
+// empty lotNumber do not filter.
+func (repo *InventoryLotRepository) GetAllWithFiltersQuery(ctx context.Context, productID int, warehouseID int, lotNumber string, inStockOnly bool, tx *sqlx.Tx) ([]entity.InventoryLot, error) {
+	var lots []entity.InventoryLot
+	query := "SELECT * FROM inventory_lots WHERE 1=1"
+	var args []interface{}
+
+	if productID > 0 {
+		query += " AND product_id = ?"
+		args = append(args, productID)
+	}
+	if warehouseID > 0 {
+		query += " AND warehouse_id = ?"
+		args = append(args, warehouseID)
+	}
+	if lotNumber != "" {
+		query += " AND lot_number = ?"
+		args = append(args, lotNumber)
+	}
+	if inStockOnly {
+		query += " AND quantity > 0"
+	}
+	query += fefoOrder
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &lots, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &lots, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lots == nil {
+		return []entity.InventoryLot{}, nil
+	}
+	return lots, nil
+}
+
+// GetAvailableForUpdateQuery locks the lots of the product in the warehouse that still hold
+// stock and have not expired, in FEFO order
+func (repo *InventoryLotRepository) GetAvailableForUpdateQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) ([]entity.InventoryLot, error) {
+	var lots []entity.InventoryLot
+	query := "SELECT * FROM inventory_lots WHERE product_id = ? AND warehouse_id = ? AND quantity > 0 AND (expiry_date IS NULL OR expiry_date >= CURDATE())" + fefoOrder + " FOR UPDATE"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &lots, query, productID, warehouseID)
+	} else {
+		err = repo.db.SelectContext(ctx, &lots, query, productID, warehouseID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lots == nil {
+		return []entity.InventoryLot{}, nil
+	}
+	return lots, nil
+}
+
+func (repo *InventoryLotRepository) GetManyByIDsQuery(ctx context.Context, ids []int, tx *sqlx.Tx) ([]entity.InventoryLot, error) {
+	return repo.selectManyByIDs(ctx, "SELECT * FROM inventory_lots WHERE id IN (?)"+fefoOrder, ids, tx)
+}
+
+func (repo *InventoryLotRepository) GetManyByIDsForUpdateQuery(ctx context.Context, ids []int, tx *sqlx.Tx) ([]entity.InventoryLot, error) {
+	return repo.selectManyByIDs(ctx, "SELECT * FROM inventory_lots WHERE id IN (?)"+fefoOrder+" FOR UPDATE", ids, tx)
+}
+
+func (repo *InventoryLotRepository) selectManyByIDs(ctx context.Context, query string, ids []int, tx *sqlx.Tx) ([]entity.InventoryLot, error) {
+	if len(ids) == 0 {
+		return []entity.InventoryLot{}, nil
+	}
+	query, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return nil, err
+	}
+	query = repo.db.Rebind(query)
+
+	var lots []entity.InventoryLot
+	if tx != nil {
+		err = tx.SelectContext(ctx, &lots, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &lots, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lots == nil {
+		return []entity.InventoryLot{}, nil
+	}
+	return lots, nil
+}
+
+// AddQuantityCommand adds the signed quantity to the lot balance
+func (repo *InventoryLotRepository) AddQuantityCommand(ctx context.Context, id int, quantity int, tx *sqlx.Tx) error {
+	updateQuery := "UPDATE inventory_lots SET quantity = quantity + ? WHERE id = ?"
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, quantity, id)
+		return err
+	}
+
+	_, err := repo.db.ExecContext(ctx, updateQuery, quantity, id)
+	return err
+}
+
+// GetTotalQuantityQuery returns the stock of the product in the warehouse booked to lots, expired
+// lots included
+func (repo *InventoryLotRepository) GetTotalQuantityQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) (int, error) {
+	var total int
+	query := "SELECT COALESCE(SUM(quantity), 0) FROM inventory_lots WHERE product_id = ? AND warehouse_id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &total, query, productID, warehouseID)
+	} else {
+		err = repo.db.GetContext(ctx, &total, query, productID, warehouseID)
+	}
+	return total, err
+}
+
+func (repo *InventoryLotRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.InventoryLot, error) {
+	var lot entity.InventoryLot
+	query := "SELECT * FROM inventory_lots WHERE id = ? FOR UPDATE"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &lot, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &lot, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &lot, nil
+}
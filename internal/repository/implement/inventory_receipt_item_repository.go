@@ -19,8 +19,8 @@ func NewInventoryReceiptItemRepository(db database.Db) repository.InventoryRecei
 }
 
 func (repo *InventoryReceiptItemRepository) CreateCommand(ctx context.Context, item *entity.InventoryReceiptItem, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO inventory_receipt_items(inventory_receipt_id, product_id, quantity, unit_cost, notes) 
-					VALUES (:inventory_receipt_id, :product_id, :quantity, :unit_cost, :notes)`
+	insertQuery := `INSERT INTO inventory_receipt_items(inventory_receipt_id, product_id, purchase_order_item_id, quantity, unit_cost, lot_number, expiry_date, inventory_lot_id, notes) 
+					VALUES (:inventory_receipt_id, :product_id, :purchase_order_item_id, :quantity, :unit_cost, :lot_number, :expiry_date, :inventory_lot_id, :notes)`
 
 	if tx != nil {
 		result, err := tx.NamedExecContext(ctx, insertQuery, item)
@@ -124,8 +124,8 @@ func (repo *InventoryReceiptItemRepository) GetByProductIDQuery(ctx context.Cont
 
 func (repo *InventoryReceiptItemRepository) UpdateCommand(ctx context.Context, item *entity.InventoryReceiptItem, tx *sqlx.Tx) error {
 	updateQuery := `UPDATE inventory_receipt_items SET inventory_receipt_id = :inventory_receipt_id, 
-					product_id = :product_id, quantity = :quantity, unit_cost = :unit_cost, 
-					notes = :notes WHERE id = :id`
+					product_id = :product_id, purchase_order_item_id = :purchase_order_item_id, quantity = :quantity, unit_cost = :unit_cost, 
+					lot_number = :lot_number, expiry_date = :expiry_date, inventory_lot_id = :inventory_lot_id, notes = :notes WHERE id = :id`
 
 	if tx != nil {
 		_, err := tx.NamedExecContext(ctx, updateQuery, item)
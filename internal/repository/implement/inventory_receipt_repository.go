@@ -22,8 +22,8 @@ func NewInventoryReceiptRepository(db database.Db) repository.InventoryReceiptRe
 
 func (repo *InventoryReceiptRepository) CreateCommand(ctx context.Context, receipt *entity.InventoryReceipt, tx *sqlx.Tx) error {
 	// First insert without code (code will be generated after getting ID)
-	insertQuery := `INSERT INTO inventory_receipts(code, user_id, receipt_date, notes, total_items) 
-					VALUES ('TEMP', :user_id, :receipt_date, :notes, :total_items)`
+	insertQuery := `INSERT INTO inventory_receipts(code, user_id, supplier_id, warehouse_id, receipt_date, notes, total_items) 
+					VALUES ('TEMP', :user_id, :supplier_id, :warehouse_id, :receipt_date, :notes, :total_items)`
 
 	var result sql.Result
 	var err error
@@ -140,7 +140,7 @@ func (repo *InventoryReceiptRepository) GetByUserIDQuery(ctx context.Context, us
 }
 
 func (repo *InventoryReceiptRepository) UpdateCommand(ctx context.Context, receipt *entity.InventoryReceipt, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE inventory_receipts SET code = :code, user_id = :user_id, receipt_date = :receipt_date, 
+	updateQuery := `UPDATE inventory_receipts SET code = :code, user_id = :user_id, supplier_id = :supplier_id, warehouse_id = :warehouse_id, receipt_date = :receipt_date, 
 					notes = :notes, total_items = :total_items WHERE id = :id`
 
 	if tx != nil {
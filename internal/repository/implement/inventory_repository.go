@@ -21,7 +21,7 @@ func NewInventoryRepository(db database.Db) repository.InventoryRepository {
 }
 
 func (repo *InventoryRepository) CreateCommand(ctx context.Context, inventory *entity.Inventory, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO inventory(product_id, quantity, version) VALUES (:product_id, :quantity, :version)`
+	insertQuery := `INSERT INTO inventory(product_id, warehouse_id, quantity, version) VALUES (:product_id, :warehouse_id, :quantity, :version)`
 
 	if tx != nil {
 		_, err := tx.NamedExecContext(ctx, insertQuery, inventory)
@@ -31,33 +31,44 @@ func (repo *InventoryRepository) CreateCommand(ctx context.Context, inventory *e
 	return err
 }
 
-func (repo *InventoryRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Inventory, error) {
+// GetAllQuery returns the inventories of one warehouse, or of all warehouses when warehouseID is nil
+func (repo *InventoryRepository) GetAllQuery(ctx context.Context, warehouseID *int, tx *sqlx.Tx) ([]entity.Inventory, error) {
 	var inventories []entity.Inventory
-	query := "SELECT * FROM inventory ORDER BY id"
+	query := "SELECT * FROM inventory"
+	var args []interface{}
+	if warehouseID != nil {
+		query += " WHERE warehouse_id = ?"
+		args = append(args, *warehouseID)
+	}
+	query += " ORDER BY id"
 	var err error
 
 	if tx != nil {
-		err = tx.SelectContext(ctx, &inventories, query)
+		err = tx.SelectContext(ctx, &inventories, query, args...)
 	} else {
-		err = repo.db.SelectContext(ctx, &inventories, query)
+		err = repo.db.SelectContext(ctx, &inventories, query, args...)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	if inventories == nil {
+		return []entity.Inventory{}, nil
+	}
+
 	return inventories, nil
 }
 
-func (repo *InventoryRepository) GetOneByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) (*entity.Inventory, error) {
+func (repo *InventoryRepository) GetOneByProductIDQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) (*entity.Inventory, error) {
 	var inventory entity.Inventory
-	query := "SELECT * FROM inventory WHERE product_id = ?"
+	query := "SELECT * FROM inventory WHERE product_id = ? AND warehouse_id = ?"
 	var err error
 
 	if tx != nil {
-		err = tx.GetContext(ctx, &inventory, query, productID)
+		err = tx.GetContext(ctx, &inventory, query, productID, warehouseID)
 	} else {
-		err = repo.db.GetContext(ctx, &inventory, query, productID)
+		err = repo.db.GetContext(ctx, &inventory, query, productID, warehouseID)
 	}
 
 	if err != nil {
@@ -70,14 +81,48 @@ func (repo *InventoryRepository) GetOneByProductIDQuery(ctx context.Context, pro
 	return &inventory, nil
 }
 
-func (repo *InventoryRepository) UpdateQuantityCommand(ctx context.Context, productID int, quantity int, version string, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE inventory SET quantity = quantity + ?, version = ? WHERE product_id = ?`
+// GetTotalQuantitiesByProductIDsQuery returns the on-hand quantity of the given products summed over all warehouses
+func (repo *InventoryRepository) GetTotalQuantitiesByProductIDsQuery(ctx context.Context, productIDs []int, tx *sqlx.Tx) (map[int]int, error) {
+	if len(productIDs) == 0 {
+		return map[int]int{}, nil
+	}
+	query, args, err := sqlx.In(`SELECT product_id, SUM(quantity) AS quantity
+			  FROM inventory
+			  WHERE product_id IN (?)
+			  GROUP BY product_id`, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	query = repo.db.Rebind(query)
+
+	var rows []struct {
+		ProductID int `db:"product_id"`
+		Quantity  int `db:"quantity"`
+	}
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[int]int, len(rows))
+	for _, row := range rows {
+		quantities[row.ProductID] = row.Quantity
+	}
+	return quantities, nil
+}
+
+func (repo *InventoryRepository) UpdateQuantityCommand(ctx context.Context, productID int, warehouseID int, quantity int, version string, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE inventory SET quantity = quantity + ?, version = ? WHERE product_id = ? AND warehouse_id = ?`
 
 	var err error
 	if tx != nil {
-		_, err = tx.ExecContext(ctx, updateQuery, quantity, version, productID)
+		_, err = tx.ExecContext(ctx, updateQuery, quantity, version, productID, warehouseID)
 	} else {
-		_, err = repo.db.ExecContext(ctx, updateQuery, quantity, version, productID)
+		_, err = repo.db.ExecContext(ctx, updateQuery, quantity, version, productID, warehouseID)
 	}
 
 	if err != nil {
@@ -112,15 +157,15 @@ func (repo *InventoryRepository) GetOneByIDForUpdateQuery(ctx context.Context, p
 	return &inventory, nil
 }
 
-func (repo *InventoryRepository) UpdateQuantityWithVersionCommand(ctx context.Context, productID int, quantity int, expectedVersion string, newVersion string, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE inventory SET quantity = quantity + ?, version = ? WHERE product_id = ? AND version = ?`
+func (repo *InventoryRepository) UpdateQuantityWithVersionCommand(ctx context.Context, productID int, warehouseID int, quantity int, expectedVersion string, newVersion string, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE inventory SET quantity = quantity + ?, version = ? WHERE product_id = ? AND warehouse_id = ? AND version = ?`
 
 	var result sql.Result
 	var err error
 	if tx != nil {
-		result, err = tx.ExecContext(ctx, updateQuery, quantity, newVersion, productID, expectedVersion)
+		result, err = tx.ExecContext(ctx, updateQuery, quantity, newVersion, productID, warehouseID, expectedVersion)
 	} else {
-		result, err = repo.db.ExecContext(ctx, updateQuery, quantity, newVersion, productID, expectedVersion)
+		result, err = repo.db.ExecContext(ctx, updateQuery, quantity, newVersion, productID, warehouseID, expectedVersion)
 	}
 
 	if err != nil {
@@ -165,16 +210,21 @@ func (repo *InventoryRepository) SelectManyForUpdate(ctx context.Context, ids []
 	return inventories, nil
 }
 
-func (repo *InventoryRepository) GetInventoryIDsByProductIDsQuery(ctx context.Context, productIDs []int, tx *sqlx.Tx) ([]int, error) {
+func (repo *InventoryRepository) GetInventoryIDsByProductIDsQuery(ctx context.Context, productIDs []int, warehouseID int, tx *sqlx.Tx) ([]int, error) {
 	if len(productIDs) == 0 {
 		return []int{}, nil
 	}
-	query, args, err := sqlx.In("SELECT id FROM inventory WHERE product_id IN (?)", productIDs)
+	query, args, err := sqlx.In("SELECT id FROM inventory WHERE product_id IN (?) AND warehouse_id = ?", productIDs, warehouseID)
 	if err != nil {
 		return nil, err
 	}
 	query = repo.db.Rebind(query)
-	rows, err := repo.db.QueryxContext(ctx, query, args...)
+	var rows *sqlx.Rows
+	if tx != nil {
+		rows, err = tx.QueryxContext(ctx, query, args...)
+	} else {
+		rows, err = repo.db.QueryxContext(ctx, query, args...)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,141 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type InventoryReservationRepository struct {
+	db *sqlx.DB
+}
+
+func NewInventoryReservationRepository(db database.Db) repository.InventoryReservationRepository {
+	return &InventoryReservationRepository{db: db}
+}
+
+func (repo *InventoryReservationRepository) CreateCommand(ctx context.Context, reservation *entity.InventoryReservation, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO inventory_reservations(product_id, warehouse_id, order_id, quantity, entry_type, note, created_by_name)
+					VALUES (:product_id, :warehouse_id, :order_id, :quantity, :entry_type, :note, :created_by_name)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, reservation)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, reservation)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	reservation.ID = int(lastID)
+	return nil
+}
+
+func (repo *InventoryReservationRepository) GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.InventoryReservation, error) {
+	var reservations []entity.InventoryReservation
+	query := "SELECT * FROM inventory_reservations WHERE order_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &reservations, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &reservations, query, orderID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reservations == nil {
+		return []entity.InventoryReservation{}, nil
+	}
+
+	return reservations, nil
+}
+
+// GetReservedQuantitiesByOrderIDQuery returns the quantity an order still holds per product
+func (repo *InventoryReservationRepository) GetReservedQuantitiesByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (map[int]int, error) {
+	query := `SELECT product_id, SUM(quantity) AS quantity
+			  FROM inventory_reservations
+			  WHERE order_id = ?
+			  GROUP BY product_id
+			  HAVING SUM(quantity) <> 0`
+
+	return repo.selectReservedQuantities(ctx, query, []interface{}{orderID}, tx)
+}
+
+// GetReservedQuantitiesByProductIDsQuery returns the quantity held by all orders for the given
+// products, in one warehouse or in all of them when warehouseID is nil
+func (repo *InventoryReservationRepository) GetReservedQuantitiesByProductIDsQuery(ctx context.Context, productIDs []int, warehouseID *int, tx *sqlx.Tx) (map[int]int, error) {
+	if len(productIDs) == 0 {
+		return map[int]int{}, nil
+	}
+	query := `SELECT product_id, SUM(quantity) AS quantity
+			  FROM inventory_reservations
+			  WHERE product_id IN (?)`
+	args := []interface{}{productIDs}
+	if warehouseID != nil {
+		query += " AND warehouse_id = ?"
+		args = append(args, *warehouseID)
+	}
+	query += " GROUP BY product_id"
+
+	query, args, err := sqlx.In(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.selectReservedQuantities(ctx, repo.db.Rebind(query), args, tx)
+}
+
+// GetAllReservedQuantitiesQuery returns the quantity held by all orders per product, in one
+// warehouse or in all of them when warehouseID is nil
+func (repo *InventoryReservationRepository) GetAllReservedQuantitiesQuery(ctx context.Context, warehouseID *int, tx *sqlx.Tx) (map[int]int, error) {
+	query := `SELECT product_id, SUM(quantity) AS quantity
+			  FROM inventory_reservations`
+	var args []interface{}
+	if warehouseID != nil {
+		query += " WHERE warehouse_id = ?"
+		args = append(args, *warehouseID)
+	}
+	query += " GROUP BY product_id"
+
+	return repo.selectReservedQuantities(ctx, query, args, tx)
+}
+
+func (repo *InventoryReservationRepository) selectReservedQuantities(ctx context.Context, query string, args []interface{}, tx *sqlx.Tx) (map[int]int, error) {
+	var rows []struct {
+		ProductID int `db:"product_id"`
+		Quantity  int `db:"quantity"`
+	}
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, args...)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	reservedQuantities := make(map[int]int, len(rows))
+	for _, row := range rows {
+		reservedQuantities[row.ProductID] = row.Quantity
+	}
+
+	return reservedQuantities, nil
+}
@@ -0,0 +1,66 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type MrpRunDemandRepository struct {
+	db *sqlx.DB
+}
+
+func NewMrpRunDemandRepository(db database.Db) repository.MrpRunDemandRepository {
+	return &MrpRunDemandRepository{db: db}
+}
+
+func (repo *MrpRunDemandRepository) CreateCommand(ctx context.Context, demand *entity.MrpRunDemand, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO mrp_run_demands(mrp_run_id, product_id, order_id, quantity)
+					VALUES (:mrp_run_id, :product_id, :order_id, :quantity)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, demand)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, demand)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	demand.ID = int(lastID)
+	return nil
+}
+
+func (repo *MrpRunDemandRepository) GetAllByMrpRunIDQuery(ctx context.Context, mrpRunID int, tx *sqlx.Tx) ([]entity.MrpRunDemand, error) {
+	var demands []entity.MrpRunDemand
+	query := "SELECT * FROM mrp_run_demands WHERE mrp_run_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &demands, query, mrpRunID)
+	} else {
+		err = repo.db.SelectContext(ctx, &demands, query, mrpRunID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if demands == nil {
+		return []entity.MrpRunDemand{}, nil
+	}
+
+	return demands, nil
+}
@@ -0,0 +1,66 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type MrpRunItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewMrpRunItemRepository(db database.Db) repository.MrpRunItemRepository {
+	return &MrpRunItemRepository{db: db}
+}
+
+func (repo *MrpRunItemRepository) CreateCommand(ctx context.Context, item *entity.MrpRunItem, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO mrp_run_items(mrp_run_id, product_id, gross_requirement, available_stock, net_requirement, planned_production, suggested_purchase)
+					VALUES (:mrp_run_id, :product_id, :gross_requirement, :available_stock, :net_requirement, :planned_production, :suggested_purchase)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, item)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, item)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	item.ID = int(lastID)
+	return nil
+}
+
+func (repo *MrpRunItemRepository) GetAllByMrpRunIDQuery(ctx context.Context, mrpRunID int, tx *sqlx.Tx) ([]entity.MrpRunItem, error) {
+	var items []entity.MrpRunItem
+	query := "SELECT * FROM mrp_run_items WHERE mrp_run_id = ? ORDER BY product_id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &items, query, mrpRunID)
+	} else {
+		err = repo.db.SelectContext(ctx, &items, query, mrpRunID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		return []entity.MrpRunItem{}, nil
+	}
+
+	return items, nil
+}
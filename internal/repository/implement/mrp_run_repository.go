@@ -0,0 +1,117 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type MrpRunRepository struct {
+	db *sqlx.DB
+}
+
+func NewMrpRunRepository(db database.Db) repository.MrpRunRepository {
+	return &MrpRunRepository{db: db}
+}
+
+func (repo *MrpRunRepository) CreateCommand(ctx context.Context, run *entity.MrpRun, tx *sqlx.Tx) error {
+	// First insert without code (code will be generated after getting ID)
+	insertQuery := `INSERT INTO mrp_runs(code, include_open_orders, note, total_materials, shortage_materials, created_by, created_by_name)
+					VALUES ('TEMP', :include_open_orders, :note, :total_materials, :shortage_materials, :created_by, :created_by_name)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, run)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, run)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	run.ID = int(id)
+
+	// Generate code based on ID (MRP + 5-digit format)
+	code := fmt.Sprintf("MRP%05d", run.ID)
+	run.Code = code
+
+	// Update the record with the generated code
+	updateCodeQuery := `UPDATE mrp_runs SET code = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, updateCodeQuery, code, run.ID)
+	} else {
+		_, err = repo.db.ExecContext(ctx, updateCodeQuery, code, run.ID)
+	}
+
+	return err
+}
+
+func (repo *MrpRunRepository) UpdateCommand(ctx context.Context, run *entity.MrpRun, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE mrp_runs SET note = :note, total_materials = :total_materials, shortage_materials = :shortage_materials WHERE id = :id`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, updateQuery, run)
+	} else {
+		_, err = repo.db.NamedExecContext(ctx, updateQuery, run)
+	}
+	return err
+}
+
+func (repo *MrpRunRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.MrpRun, error) {
+	var run entity.MrpRun
+	query := "SELECT * FROM mrp_runs WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &run, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &run, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+func (repo *MrpRunRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.MrpRun, error) {
+	var runs []entity.MrpRun
+	query := "SELECT * FROM mrp_runs ORDER BY id DESC"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &runs, query)
+	} else {
+		err = repo.db.SelectContext(ctx, &runs, query)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if runs == nil {
+		return []entity.MrpRun{}, nil
+	}
+
+	return runs, nil
+}
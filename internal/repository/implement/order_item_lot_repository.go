@@ -0,0 +1,100 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type OrderItemLotRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderItemLotRepository(db database.Db) repository.OrderItemLotRepository {
+	return &OrderItemLotRepository{db: db}
+}
+
+func (repo *OrderItemLotRepository) CreateCommand(ctx context.Context, orderItemLot *entity.OrderItemLot, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO order_item_lots(order_id, order_item_id, product_id, inventory_lot_id, quantity)
+					VALUES (:order_id, :order_item_id, :product_id, :inventory_lot_id, :quantity)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, orderItemLot)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, orderItemLot)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	orderItemLot.ID = int(lastID)
+	return nil
+}
+
+func (repo *OrderItemLotRepository) GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.OrderItemLot, error) {
+	var orderItemLots []entity.OrderItemLot
+	query := "SELECT * FROM order_item_lots WHERE order_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &orderItemLots, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &orderItemLots, query, orderID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if orderItemLots == nil {
+		return []entity.OrderItemLot{}, nil
+	}
+
+	return orderItemLots, nil
+}
+
+// GetShipmentsByLotIDsQuery returns the net quantity of the lots each order item still holds,
+// leaving out what has been put back into the lots
+func (repo *OrderItemLotRepository) GetShipmentsByLotIDsQuery(ctx context.Context, lotIDs []int, tx *sqlx.Tx) ([]entity.LotShipment, error) {
+	if len(lotIDs) == 0 {
+		return []entity.LotShipment{}, nil
+	}
+	query, args, err := sqlx.In(`SELECT oil.inventory_lot_id, o.id AS order_id, o.code AS order_code, o.order_date, o.customer_id,
+					oil.order_item_id, oil.product_id, SUM(oil.quantity) AS quantity
+			  FROM order_item_lots oil
+			  JOIN orders o ON o.id = oil.order_id
+			  WHERE oil.inventory_lot_id IN (?)
+			  GROUP BY oil.inventory_lot_id, o.id, o.code, o.order_date, o.customer_id, oil.order_item_id, oil.product_id
+			  HAVING SUM(oil.quantity) > 0
+			  ORDER BY o.order_date, o.id`, lotIDs)
+	if err != nil {
+		return nil, err
+	}
+	query = repo.db.Rebind(query)
+
+	var shipments []entity.LotShipment
+	if tx != nil {
+		err = tx.SelectContext(ctx, &shipments, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &shipments, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if shipments == nil {
+		return []entity.LotShipment{}, nil
+	}
+	return shipments, nil
+}
@@ -19,8 +19,8 @@ func NewOrderItemRepository(db database.Db) repository.OrderItemRepository {
 }
 
 func (repo *OrderItemRepository) CreateCommand(ctx context.Context, item *entity.OrderItem, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO order_items(order_id, product_id, quantity, selling_price, original_price, discount_percent, final_amount)
-					VALUES (:order_id, :product_id, :quantity, :selling_price, :original_price, :discount_percent, :final_amount)`
+	insertQuery := `INSERT INTO order_items(order_id, product_id, quantity, unit_id, base_quantity, selling_price, original_price, discount_percent, final_amount)
+					VALUES (:order_id, :product_id, :quantity, :unit_id, :base_quantity, :selling_price, :original_price, :discount_percent, :final_amount)`
 
 	var err error
 
@@ -100,7 +100,7 @@ func (repo *OrderItemRepository) GetAllByOrderIDQuery(ctx context.Context, order
 
 func (repo *OrderItemRepository) UpdateCommand(ctx context.Context, item *entity.OrderItem, tx *sqlx.Tx) error {
 	updateQuery := `UPDATE order_items SET order_id = :order_id, product_id = :product_id, 
-					quantity = :quantity, selling_price = :selling_price, original_price = :original_price, 
+					quantity = :quantity, unit_id = :unit_id, base_quantity = :base_quantity, selling_price = :selling_price, original_price = :original_price, 
 					discount_percent = :discount_percent, final_amount = :final_amount WHERE id = :id`
 
 	if tx != nil {
@@ -64,10 +64,31 @@ func (repo *OrderRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sq
 	return &order, nil
 }
 
+func (repo *OrderRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Order, error) {
+	var order entity.Order
+	query := "SELECT * FROM orders WHERE id = ? FOR UPDATE"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &order, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &order, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
 func (repo *OrderRepository) CreateCommand(ctx context.Context, order *entity.Order, tx *sqlx.Tx) error {
 	// First insert without code (code will be generated after getting ID)
-	insertQuery := `INSERT INTO orders(code, customer_id, order_date, note, total_original_cost, total_sales_revenue, additional_cost, additional_cost_note, tax_percent, delivery_status) 
-					VALUES ('TEMP', :customer_id, :order_date, :note, :total_original_cost, :total_sales_revenue, :additional_cost, :additional_cost_note, :tax_percent, :delivery_status)`
+	insertQuery := `INSERT INTO orders(code, customer_id, warehouse_id, order_date, note, total_original_cost, total_sales_revenue, additional_cost, additional_cost_note, tax_percent, delivery_status) 
+					VALUES ('TEMP', :customer_id, :warehouse_id, :order_date, :note, :total_original_cost, :total_sales_revenue, :additional_cost, :additional_cost_note, :tax_percent, :delivery_status)`
 
 	var result sql.Result
 	var err error
@@ -141,6 +162,25 @@ func (repo *OrderRepository) GetByCustomerIDQuery(ctx context.Context, customerI
 	return orders, nil
 }
 
+func (repo *OrderRepository) GetAllByDeliveryStatusQuery(ctx context.Context, deliveryStatus string, tx *sqlx.Tx) ([]entity.Order, error) {
+	var orders []entity.Order
+	query := "SELECT * FROM orders WHERE delivery_status = ? ORDER BY order_date, id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &orders, query, deliveryStatus)
+	} else {
+		err = repo.db.SelectContext(ctx, &orders, query, deliveryStatus)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if orders == nil {
+		return []entity.Order{}, nil
+	}
+	return orders, nil
+}
+
 func (repo *OrderRepository) GetAllWithFiltersQuery(ctx context.Context, customerID int, sortBy string, fromDate *time.Time, toDate *time.Time, tx *sqlx.Tx) ([]entity.Order, error) {
 	var orders []entity.Order
 	query := "SELECT * FROM orders WHERE 1=1"
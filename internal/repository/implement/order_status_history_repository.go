@@ -0,0 +1,66 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type OrderStatusHistoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewOrderStatusHistoryRepository(db database.Db) repository.OrderStatusHistoryRepository {
+	return &OrderStatusHistoryRepository{db: db}
+}
+
+func (repo *OrderStatusHistoryRepository) CreateCommand(ctx context.Context, history *entity.OrderStatusHistory, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO order_status_histories(order_id, from_status, to_status, changed_by, changed_by_name, reason, changed_at)
+					VALUES (:order_id, :from_status, :to_status, :changed_by, :changed_by_name, :reason, :changed_at)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, history)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, history)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	history.ID = int(lastID)
+	return nil
+}
+
+func (repo *OrderStatusHistoryRepository) GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.OrderStatusHistory, error) {
+	var histories []entity.OrderStatusHistory
+	query := "SELECT * FROM order_status_histories WHERE order_id = ? ORDER BY changed_at, id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &histories, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &histories, query, orderID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if histories == nil {
+		return []entity.OrderStatusHistory{}, nil
+	}
+
+	return histories, nil
+}
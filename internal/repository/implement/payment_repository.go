@@ -0,0 +1,84 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type PaymentRepository struct {
+	db *sqlx.DB
+}
+
+func NewPaymentRepository(db database.Db) repository.PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+func (repo *PaymentRepository) CreateCommand(ctx context.Context, payment *entity.Payment, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO payments(order_id, amount, payment_method, payment_date, reference_number, note, created_by, created_by_name)
+					VALUES (:order_id, :amount, :payment_method, :payment_date, :reference_number, :note, :created_by, :created_by_name)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, payment)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, payment)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	payment.ID = int(lastID)
+	return nil
+}
+
+func (repo *PaymentRepository) GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.Payment, error) {
+	var payments []entity.Payment
+	query := "SELECT * FROM payments WHERE order_id = ? ORDER BY payment_date, id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &payments, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &payments, query, orderID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if payments == nil {
+		return []entity.Payment{}, nil
+	}
+
+	return payments, nil
+}
+
+func (repo *PaymentRepository) GetTotalAmountByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (int, error) {
+	var total int
+	query := "SELECT COALESCE(SUM(amount), 0) FROM payments WHERE order_id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &total, query, orderID)
+	} else {
+		err = repo.db.GetContext(ctx, &total, query, orderID)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
@@ -3,6 +3,7 @@ package repositoryimplement
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/management-app-backend/internal/database"
@@ -62,15 +63,31 @@ func (repo *ProductBomRepository) GetOneByIDQuery(ctx context.Context, id int, t
 	return &bom, nil
 }
 
-func (repo *ProductBomRepository) GetByParentProductIDQuery(ctx context.Context, parentProductID int, tx *sqlx.Tx) ([]entity.ProductBom, error) {
+// effectiveBomVersionIDsQuery selects, per parent product, the active version in effect at the
+// date: the latest one that started on or before it and has not ended yet. Dates before the
+// first version fall back to the earliest one. It takes the date three times.
+const effectiveBomVersionIDsQuery = `
+	SELECT id FROM (
+		SELECT id, ROW_NUMBER() OVER (
+			PARTITION BY parent_product_id
+			ORDER BY effective_from <= ? DESC,
+				CASE WHEN effective_from <= ? THEN effective_from END DESC,
+				effective_from, version_number DESC
+		) AS version_rank
+		FROM product_bom_versions
+		WHERE is_active = TRUE AND (effective_to IS NULL OR effective_to > ?)
+	) ranked_versions
+	WHERE version_rank = 1`
+
+func (repo *ProductBomRepository) GetEffectiveQuery(ctx context.Context, date time.Time, tx *sqlx.Tx) ([]entity.ProductBom, error) {
 	var boms []entity.ProductBom
-	query := "SELECT * FROM product_boms WHERE parent_product_id = ? ORDER BY id"
+	query := "SELECT * FROM product_boms WHERE bom_version_id IN (" + effectiveBomVersionIDsQuery + ") ORDER BY id"
 	var err error
 
 	if tx != nil {
-		err = tx.SelectContext(ctx, &boms, query, parentProductID)
+		err = tx.SelectContext(ctx, &boms, query, date, date, date)
 	} else {
-		err = repo.db.SelectContext(ctx, &boms, query, parentProductID)
+		err = repo.db.SelectContext(ctx, &boms, query, date, date, date)
 	}
 
 	if err != nil {
@@ -84,15 +101,59 @@ func (repo *ProductBomRepository) GetByParentProductIDQuery(ctx context.Context,
 	return boms, nil
 }
 
-func (repo *ProductBomRepository) GetByComponentProductIDQuery(ctx context.Context, componentProductID int, tx *sqlx.Tx) ([]entity.ProductBom, error) {
+func (repo *ProductBomRepository) GetEffectiveByParentProductIDQuery(ctx context.Context, parentProductID int, date time.Time, tx *sqlx.Tx) ([]entity.ProductBom, error) {
 	var boms []entity.ProductBom
-	query := "SELECT * FROM product_boms WHERE component_product_id = ? ORDER BY id"
+	query := "SELECT * FROM product_boms WHERE parent_product_id = ? AND bom_version_id IN (" + effectiveBomVersionIDsQuery + ") ORDER BY id"
 	var err error
 
 	if tx != nil {
-		err = tx.SelectContext(ctx, &boms, query, componentProductID)
+		err = tx.SelectContext(ctx, &boms, query, parentProductID, date, date, date)
 	} else {
-		err = repo.db.SelectContext(ctx, &boms, query, componentProductID)
+		err = repo.db.SelectContext(ctx, &boms, query, parentProductID, date, date, date)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if boms == nil {
+		return []entity.ProductBom{}, nil
+	}
+
+	return boms, nil
+}
+
+func (repo *ProductBomRepository) GetEffectiveByComponentProductIDQuery(ctx context.Context, componentProductID int, date time.Time, tx *sqlx.Tx) ([]entity.ProductBom, error) {
+	var boms []entity.ProductBom
+	query := "SELECT * FROM product_boms WHERE component_product_id = ? AND bom_version_id IN (" + effectiveBomVersionIDsQuery + ") ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &boms, query, componentProductID, date, date, date)
+	} else {
+		err = repo.db.SelectContext(ctx, &boms, query, componentProductID, date, date, date)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if boms == nil {
+		return []entity.ProductBom{}, nil
+	}
+
+	return boms, nil
+}
+
+func (repo *ProductBomRepository) GetByBomVersionIDQuery(ctx context.Context, bomVersionID int, tx *sqlx.Tx) ([]entity.ProductBom, error) {
+	var boms []entity.ProductBom
+	query := "SELECT * FROM product_boms WHERE bom_version_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &boms, query, bomVersionID)
+	} else {
+		err = repo.db.SelectContext(ctx, &boms, query, bomVersionID)
 	}
 
 	if err != nil {
@@ -107,7 +168,7 @@ func (repo *ProductBomRepository) GetByComponentProductIDQuery(ctx context.Conte
 }
 
 func (repo *ProductBomRepository) CreateCommand(ctx context.Context, bom *entity.ProductBom, tx *sqlx.Tx) error {
-	insertQuery := `INSERT INTO product_boms(parent_product_id, component_product_id, quantity) VALUES (:parent_product_id, :component_product_id, :quantity)`
+	insertQuery := `INSERT INTO product_boms(bom_version_id, parent_product_id, component_product_id, quantity, scrap_percent) VALUES (:bom_version_id, :parent_product_id, :component_product_id, :quantity, :scrap_percent)`
 
 	var result sql.Result
 	var err error
@@ -134,7 +195,7 @@ func (repo *ProductBomRepository) CreateCommand(ctx context.Context, bom *entity
 }
 
 func (repo *ProductBomRepository) UpdateCommand(ctx context.Context, bom *entity.ProductBom, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE product_boms SET parent_product_id = :parent_product_id, component_product_id = :component_product_id, quantity = :quantity WHERE id = :id`
+	updateQuery := `UPDATE product_boms SET parent_product_id = :parent_product_id, component_product_id = :component_product_id, quantity = :quantity, scrap_percent = :scrap_percent WHERE id = :id`
 
 	if tx != nil {
 		_, err := tx.NamedExecContext(ctx, updateQuery, bom)
@@ -154,3 +215,28 @@ func (repo *ProductBomRepository) DeleteCommand(ctx context.Context, id int, tx
 	_, err := repo.db.ExecContext(ctx, deleteQuery, id)
 	return err
 }
+
+// bomGraphLockTimeoutSeconds is how long a BOM write waits for the one in progress
+const bomGraphLockTimeoutSeconds = 10
+
+// LockGraphCommand takes the named lock that serializes changes to the BOM graph. The lock is
+// held on its own connection, so it outlives the caller's transaction until release is called
+func (repo *ProductBomRepository) LockGraphCommand(ctx context.Context) (func(), bool, error) {
+	conn, err := repo.db.Connx(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired sql.NullInt64
+	err = conn.GetContext(ctx, &acquired, "SELECT GET_LOCK('bom_graph', ?)", bomGraphLockTimeoutSeconds)
+	if err != nil || acquired.Int64 != 1 {
+		conn.Close()
+		return nil, false, err
+	}
+
+	release := func() {
+		conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK('bom_graph')")
+		conn.Close()
+	}
+	return release, true, nil
+}
@@ -0,0 +1,159 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type ProductBomVersionRepository struct {
+	db *sqlx.DB
+}
+
+func NewProductBomVersionRepository(db database.Db) repository.ProductBomVersionRepository {
+	return &ProductBomVersionRepository{db: db}
+}
+
+func (repo *ProductBomVersionRepository) CreateCommand(ctx context.Context, version *entity.ProductBomVersion, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO product_bom_versions(parent_product_id, version_number, effective_from, effective_to, is_active, note)
+					VALUES (:parent_product_id, :version_number, :effective_from, :effective_to, :is_active, :note)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, version)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, version)
+	}
+
+	if err != nil {
+		if strings.Contains(err.Error(), "unique_parent_version") {
+			return &error_utils.ConstraintViolationError{Message: "BOM version already exists"}
+		}
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	version.ID = int(lastID)
+	return nil
+}
+
+func (repo *ProductBomVersionRepository) UpdateCommand(ctx context.Context, version *entity.ProductBomVersion, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE product_bom_versions SET effective_to = :effective_to, is_active = :is_active, note = :note WHERE id = :id`
+
+	if tx != nil {
+		_, err := tx.NamedExecContext(ctx, updateQuery, version)
+		return err
+	}
+	_, err := repo.db.NamedExecContext(ctx, updateQuery, version)
+	return err
+}
+
+func (repo *ProductBomVersionRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.ProductBomVersion, error) {
+	var version entity.ProductBomVersion
+	query := "SELECT * FROM product_bom_versions WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &version, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &version, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+func (repo *ProductBomVersionRepository) GetAllByParentProductIDQuery(ctx context.Context, parentProductID int, tx *sqlx.Tx) ([]entity.ProductBomVersion, error) {
+	var versions []entity.ProductBomVersion
+	query := "SELECT * FROM product_bom_versions WHERE parent_product_id = ? ORDER BY version_number DESC"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &versions, query, parentProductID)
+	} else {
+		err = repo.db.SelectContext(ctx, &versions, query, parentProductID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if versions == nil {
+		return []entity.ProductBomVersion{}, nil
+	}
+
+	return versions, nil
+}
+
+func (repo *ProductBomVersionRepository) GetEffectiveByParentProductIDQuery(ctx context.Context, parentProductID int, date time.Time, tx *sqlx.Tx) (*entity.ProductBomVersion, error) {
+	var version entity.ProductBomVersion
+	query := "SELECT * FROM product_bom_versions WHERE parent_product_id = ? AND id IN (" + effectiveBomVersionIDsQuery + ")"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &version, query, parentProductID, date, date, date)
+	} else {
+		err = repo.db.GetContext(ctx, &version, query, parentProductID, date, date, date)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &version, nil
+}
+
+// GetActiveStartsBetweenQuery returns the distinct times after from and before to, each bound
+// being optional, at which an active version of any product takes effect
+func (repo *ProductBomVersionRepository) GetActiveStartsBetweenQuery(ctx context.Context, from *time.Time, to *time.Time, tx *sqlx.Tx) ([]time.Time, error) {
+	var starts []time.Time
+	query := "SELECT DISTINCT effective_from FROM product_bom_versions WHERE is_active = TRUE"
+	args := []interface{}{}
+	if from != nil {
+		query += " AND effective_from > ?"
+		args = append(args, *from)
+	}
+	if to != nil {
+		query += " AND effective_from < ?"
+		args = append(args, *to)
+	}
+	query += " ORDER BY effective_from"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &starts, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &starts, query, args...)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if starts == nil {
+		return []time.Time{}, nil
+	}
+
+	return starts, nil
+}
@@ -0,0 +1,106 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type ProductCostHistoryRepository struct {
+	db *sqlx.DB
+}
+
+func NewProductCostHistoryRepository(db database.Db) repository.ProductCostHistoryRepository {
+	return &ProductCostHistoryRepository{db: db}
+}
+
+func (repo *ProductCostHistoryRepository) CreateCommand(ctx context.Context, history *entity.ProductCostHistory, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO product_cost_histories(product_id, previous_cost, new_cost, on_hand_quantity, received_quantity, unit_cost, reference_type, reference_id, created_by_name)
+					VALUES (:product_id, :previous_cost, :new_cost, :on_hand_quantity, :received_quantity, :unit_cost, :reference_type, :reference_id, :created_by_name)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, history)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, history)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	history.ID = int(lastID)
+	return nil
+}
+
+func (repo *ProductCostHistoryRepository) GetAllByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.ProductCostHistory, error) {
+	var histories []entity.ProductCostHistory
+	query := "SELECT * FROM product_cost_histories WHERE product_id = ? ORDER BY created_at DESC, id DESC"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &histories, query, productID)
+	} else {
+		err = repo.db.SelectContext(ctx, &histories, query, productID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if histories == nil {
+		return []entity.ProductCostHistory{}, nil
+	}
+
+	return histories, nil
+}
+
+// GetCostsBeforeQuery returns the cost each product with a cost history had just before the cutoff:
+// the new cost of its last change before the cutoff or, when it only changed later, the previous
+// cost of its first change
+func (repo *ProductCostHistoryRepository) GetCostsBeforeQuery(ctx context.Context, cutoff time.Time, tx *sqlx.Tx) (map[int]float64, error) {
+	query := `SELECT product_id, cost
+			  FROM (
+			    SELECT product_id,
+			           IF(created_at < ?, new_cost, previous_cost) AS cost,
+			           ROW_NUMBER() OVER (
+			             PARTITION BY product_id
+			             ORDER BY created_at < ? DESC,
+			                      IF(created_at < ?, created_at, NULL) DESC,
+			                      created_at, id
+			           ) AS row_num
+			    FROM product_cost_histories
+			  ) costs
+			  WHERE row_num = 1`
+
+	var rows []struct {
+		ProductID int     `db:"product_id"`
+		Cost      float64 `db:"cost"`
+	}
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, cutoff, cutoff, cutoff)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, cutoff, cutoff, cutoff)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	costs := make(map[int]float64, len(rows))
+	for _, row := range rows {
+		costs[row.ProductID] = row.Cost
+	}
+	return costs, nil
+}
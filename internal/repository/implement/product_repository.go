@@ -87,10 +87,31 @@ func (repo *ProductRepository) GetOneByIDQuery(ctx context.Context, id int, tx *
 	return &product, nil
 }
 
+func (repo *ProductRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Product, error) {
+	var product entity.Product
+	query := "SELECT * FROM products WHERE id = ? FOR UPDATE"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &product, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &product, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &product, nil
+}
+
 func (repo *ProductRepository) CreateCommand(ctx context.Context, product *entity.Product, tx *sqlx.Tx) error {
 	// First insert without code (code will be generated after getting ID)
-	insertQuery := `INSERT INTO products(code, name, cost, category_id, unit_id, description, operation_type) 
-					VALUES ('TEMP', :name, :cost, :category_id, :unit_id, :description, :operation_type)`
+	insertQuery := `INSERT INTO products(code, name, cost, category_id, unit_id, description, operation_type, yield_percent) 
+					VALUES ('TEMP', :name, :cost, :category_id, :unit_id, :description, :operation_type, :yield_percent)`
 
 	var result sql.Result
 	var err error
@@ -129,7 +150,7 @@ func (repo *ProductRepository) CreateCommand(ctx context.Context, product *entit
 }
 
 func (repo *ProductRepository) UpdateCommand(ctx context.Context, product *entity.Product, tx *sqlx.Tx) error {
-	updateQuery := `UPDATE products SET name = :name, cost = :cost, category_id = :category_id, unit_id = :unit_id, description = :description, operation_type = :operation_type WHERE id = :id`
+	updateQuery := `UPDATE products SET name = :name, cost = :cost, category_id = :category_id, unit_id = :unit_id, description = :description, operation_type = :operation_type, yield_percent = :yield_percent WHERE id = :id`
 
 	if tx != nil {
 		_, err := tx.NamedExecContext(ctx, updateQuery, product)
@@ -138,3 +159,14 @@ func (repo *ProductRepository) UpdateCommand(ctx context.Context, product *entit
 	_, err := repo.db.NamedExecContext(ctx, updateQuery, product)
 	return err
 }
+
+func (repo *ProductRepository) UpdateCostCommand(ctx context.Context, id int, cost float64, tx *sqlx.Tx) error {
+	updateQuery := "UPDATE products SET cost = ? WHERE id = ?"
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, cost, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, updateQuery, cost, id)
+	return err
+}
@@ -0,0 +1,122 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type PurchaseOrderItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewPurchaseOrderItemRepository(db database.Db) repository.PurchaseOrderItemRepository {
+	return &PurchaseOrderItemRepository{db: db}
+}
+
+func (repo *PurchaseOrderItemRepository) CreateCommand(ctx context.Context, item *entity.PurchaseOrderItem, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO purchase_order_items(purchase_order_id, product_id, quantity, received_quantity, unit_cost, expected_date, note)
+					VALUES (:purchase_order_id, :product_id, :quantity, :received_quantity, :unit_cost, :expected_date, :note)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, item)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, item)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	item.ID = int(lastID)
+	return nil
+}
+
+func (repo *PurchaseOrderItemRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrderItem, error) {
+	var item entity.PurchaseOrderItem
+	query := "SELECT * FROM purchase_order_items WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &item, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &item, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (repo *PurchaseOrderItemRepository) GetAllByPurchaseOrderIDQuery(ctx context.Context, purchaseOrderID int, tx *sqlx.Tx) ([]entity.PurchaseOrderItem, error) {
+	var items []entity.PurchaseOrderItem
+	query := "SELECT * FROM purchase_order_items WHERE purchase_order_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &items, query, purchaseOrderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &items, query, purchaseOrderID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		return []entity.PurchaseOrderItem{}, nil
+	}
+
+	return items, nil
+}
+
+func (repo *PurchaseOrderItemRepository) DeleteByPurchaseOrderIDCommand(ctx context.Context, purchaseOrderID int, tx *sqlx.Tx) error {
+	deleteQuery := "DELETE FROM purchase_order_items WHERE purchase_order_id = ?"
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, deleteQuery, purchaseOrderID)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, deleteQuery, purchaseOrderID)
+	return err
+}
+
+// AddReceivedQuantityCommand adds a delivery to the line's received quantity. It returns false
+// when the line would receive more than was ordered.
+func (repo *PurchaseOrderItemRepository) AddReceivedQuantityCommand(ctx context.Context, id int, quantity int, tx *sqlx.Tx) (bool, error) {
+	updateQuery := `UPDATE purchase_order_items SET received_quantity = received_quantity + ? WHERE id = ? AND received_quantity + ? <= quantity`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, updateQuery, quantity, id, quantity)
+	} else {
+		result, err = repo.db.ExecContext(ctx, updateQuery, quantity, id, quantity)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
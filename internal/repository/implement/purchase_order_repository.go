@@ -0,0 +1,149 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type PurchaseOrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewPurchaseOrderRepository(db database.Db) repository.PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+func (repo *PurchaseOrderRepository) CreateCommand(ctx context.Context, purchaseOrder *entity.PurchaseOrder, tx *sqlx.Tx) error {
+	// First insert without code (code will be generated after getting ID)
+	insertQuery := `INSERT INTO purchase_orders(code, supplier_id, order_date, expected_date, status, note, created_by)
+					VALUES ('TEMP', :supplier_id, :order_date, :expected_date, :status, :note, :created_by)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, purchaseOrder)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, purchaseOrder)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	purchaseOrder.ID = int(id)
+
+	// Generate code based on ID (MH + 5-digit format)
+	code := fmt.Sprintf("MH%05d", purchaseOrder.ID)
+	purchaseOrder.Code = code
+
+	// Update the record with the generated code
+	updateCodeQuery := `UPDATE purchase_orders SET code = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, updateCodeQuery, code, purchaseOrder.ID)
+	} else {
+		_, err = repo.db.ExecContext(ctx, updateCodeQuery, code, purchaseOrder.ID)
+	}
+
+	return err
+}
+
+func (repo *PurchaseOrderRepository) UpdateCommand(ctx context.Context, purchaseOrder *entity.PurchaseOrder, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE purchase_orders SET supplier_id = :supplier_id, order_date = :order_date, expected_date = :expected_date,
+					status = :status, note = :note WHERE id = :id`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, updateQuery, purchaseOrder)
+	} else {
+		_, err = repo.db.NamedExecContext(ctx, updateQuery, purchaseOrder)
+	}
+	return err
+}
+
+func (repo *PurchaseOrderRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrder, error) {
+	var purchaseOrder entity.PurchaseOrder
+	query := "SELECT * FROM purchase_orders WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &purchaseOrder, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &purchaseOrder, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &purchaseOrder, nil
+}
+
+func (repo *PurchaseOrderRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrder, error) {
+	var purchaseOrder entity.PurchaseOrder
+	query := "SELECT * FROM purchase_orders WHERE id = ? FOR UPDATE"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &purchaseOrder, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &purchaseOrder, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &purchaseOrder, nil
+}
+
+func (repo *PurchaseOrderRepository) GetAllWithFiltersQuery(ctx context.Context, supplierID int, status string, tx *sqlx.Tx) ([]entity.PurchaseOrder, error) {
+	var purchaseOrders []entity.PurchaseOrder
+	query := "SELECT * FROM purchase_orders WHERE 1=1"
+	var args []interface{}
+
+	// Add supplier filter
+	if supplierID > 0 {
+		query += " AND supplier_id = ?"
+		args = append(args, supplierID)
+	}
+	// Add status filter
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY id DESC"
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &purchaseOrders, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &purchaseOrders, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if purchaseOrders == nil {
+		return []entity.PurchaseOrder{}, nil
+	}
+	return purchaseOrders, nil
+}
@@ -0,0 +1,77 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type QuotationItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewQuotationItemRepository(db database.Db) repository.QuotationItemRepository {
+	return &QuotationItemRepository{db: db}
+}
+
+func (repo *QuotationItemRepository) CreateCommand(ctx context.Context, item *entity.QuotationItem, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO quotation_items(quotation_id, product_id, quantity, unit_id, selling_price, original_price, discount_percent, final_amount)
+					VALUES (:quotation_id, :product_id, :quantity, :unit_id, :selling_price, :original_price, :discount_percent, :final_amount)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, item)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, item)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	item.ID = int(lastID)
+	return nil
+}
+
+func (repo *QuotationItemRepository) GetAllByQuotationIDQuery(ctx context.Context, quotationID int, tx *sqlx.Tx) ([]entity.QuotationItem, error) {
+	var items []entity.QuotationItem
+	query := "SELECT * FROM quotation_items WHERE quotation_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &items, query, quotationID)
+	} else {
+		err = repo.db.SelectContext(ctx, &items, query, quotationID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		return []entity.QuotationItem{}, nil
+	}
+
+	return items, nil
+}
+
+func (repo *QuotationItemRepository) DeleteByQuotationIDCommand(ctx context.Context, quotationID int, tx *sqlx.Tx) error {
+	deleteQuery := "DELETE FROM quotation_items WHERE quotation_id = ?"
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, deleteQuery, quotationID)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, deleteQuery, quotationID)
+	return err
+}
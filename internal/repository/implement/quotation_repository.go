@@ -0,0 +1,168 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type QuotationRepository struct {
+	db *sqlx.DB
+}
+
+func NewQuotationRepository(db database.Db) repository.QuotationRepository {
+	return &QuotationRepository{db: db}
+}
+
+func (repo *QuotationRepository) CreateCommand(ctx context.Context, quotation *entity.Quotation, tx *sqlx.Tx) error {
+	// First insert without code (code will be generated after getting ID)
+	insertQuery := `INSERT INTO quotations(code, customer_id, quotation_date, valid_from, valid_until, note, additional_cost, additional_cost_note, tax_percent, status, created_by)
+					VALUES ('TEMP', :customer_id, :quotation_date, :valid_from, :valid_until, :note, :additional_cost, :additional_cost_note, :tax_percent, :status, :created_by)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, quotation)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, quotation)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	quotation.ID = int(id)
+
+	// Generate code based on ID (BG + 5-digit format)
+	code := fmt.Sprintf("BG%05d", quotation.ID)
+	quotation.Code = code
+
+	// Update the record with the generated code
+	updateCodeQuery := `UPDATE quotations SET code = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, updateCodeQuery, code, quotation.ID)
+	} else {
+		_, err = repo.db.ExecContext(ctx, updateCodeQuery, code, quotation.ID)
+	}
+
+	return err
+}
+
+func (repo *QuotationRepository) UpdateCommand(ctx context.Context, quotation *entity.Quotation, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE quotations SET customer_id = :customer_id, quotation_date = :quotation_date, valid_from = :valid_from,
+					valid_until = :valid_until, note = :note, additional_cost = :additional_cost,
+					additional_cost_note = :additional_cost_note, tax_percent = :tax_percent, status = :status WHERE id = :id`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, updateQuery, quotation)
+	} else {
+		_, err = repo.db.NamedExecContext(ctx, updateQuery, quotation)
+	}
+	return err
+}
+
+func (repo *QuotationRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Quotation, error) {
+	var quotation entity.Quotation
+	query := "SELECT * FROM quotations WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &quotation, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &quotation, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &quotation, nil
+}
+
+func (repo *QuotationRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Quotation, error) {
+	var quotation entity.Quotation
+	query := "SELECT * FROM quotations WHERE id = ? FOR UPDATE"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &quotation, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &quotation, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &quotation, nil
+}
+
+func (repo *QuotationRepository) GetAllWithFiltersQuery(ctx context.Context, customerID int, tx *sqlx.Tx) ([]entity.Quotation, error) {
+	var quotations []entity.Quotation
+	query := "SELECT * FROM quotations WHERE 1=1"
+	var args []interface{}
+
+	// Add customer filter
+	if customerID > 0 {
+		query += " AND customer_id = ?"
+		args = append(args, customerID)
+	}
+	query += " ORDER BY id DESC"
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &quotations, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &quotations, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if quotations == nil {
+		return []entity.Quotation{}, nil
+	}
+	return quotations, nil
+}
+
+// MarkConvertedCommand links the quotation to the order created from it and accepts it.
+// It returns false when the quotation had already been converted.
+func (repo *QuotationRepository) MarkConvertedCommand(ctx context.Context, id int, orderID int, tx *sqlx.Tx) (bool, error) {
+	updateQuery := `UPDATE quotations SET order_id = ?, status = ? WHERE id = ? AND order_id IS NULL`
+
+	var result sql.Result
+	var err error
+	if tx != nil {
+		result, err = tx.ExecContext(ctx, updateQuery, orderID, entity.QuotationStatus.ACCEPTED, id)
+	} else {
+		result, err = repo.db.ExecContext(ctx, updateQuery, orderID, entity.QuotationStatus.ACCEPTED, id)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
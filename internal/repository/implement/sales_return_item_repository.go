@@ -0,0 +1,97 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type SalesReturnItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewSalesReturnItemRepository(db database.Db) repository.SalesReturnItemRepository {
+	return &SalesReturnItemRepository{db: db}
+}
+
+func (repo *SalesReturnItemRepository) CreateCommand(ctx context.Context, item *entity.SalesReturnItem, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO sales_return_items(sales_return_id, order_item_id, product_id, quantity, disposition, reason, credit_amount)
+					VALUES (:sales_return_id, :order_item_id, :product_id, :quantity, :disposition, :reason, :credit_amount)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, item)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, item)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	item.ID = int(lastID)
+	return nil
+}
+
+func (repo *SalesReturnItemRepository) GetAllBySalesReturnIDQuery(ctx context.Context, salesReturnID int, tx *sqlx.Tx) ([]entity.SalesReturnItem, error) {
+	var items []entity.SalesReturnItem
+	query := "SELECT * FROM sales_return_items WHERE sales_return_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &items, query, salesReturnID)
+	} else {
+		err = repo.db.SelectContext(ctx, &items, query, salesReturnID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		return []entity.SalesReturnItem{}, nil
+	}
+
+	return items, nil
+}
+
+// GetReturnedQuantitiesByOrderIDQuery returns the quantity already returned per order item of an order
+func (repo *SalesReturnItemRepository) GetReturnedQuantitiesByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (map[int]int, error) {
+	var rows []struct {
+		OrderItemID int `db:"order_item_id"`
+		Quantity    int `db:"quantity"`
+	}
+	query := `SELECT sri.order_item_id, SUM(sri.quantity) AS quantity
+			  FROM sales_return_items sri
+			  JOIN sales_returns sr ON sr.id = sri.sales_return_id
+			  WHERE sr.order_id = ?
+			  GROUP BY sri.order_item_id`
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &rows, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &rows, query, orderID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	returnedQuantities := make(map[int]int, len(rows))
+	for _, row := range rows {
+		returnedQuantities[row.OrderItemID] = row.Quantity
+	}
+
+	return returnedQuantities, nil
+}
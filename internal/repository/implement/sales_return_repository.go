@@ -0,0 +1,123 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type SalesReturnRepository struct {
+	db *sqlx.DB
+}
+
+func NewSalesReturnRepository(db database.Db) repository.SalesReturnRepository {
+	return &SalesReturnRepository{db: db}
+}
+
+func (repo *SalesReturnRepository) CreateCommand(ctx context.Context, salesReturn *entity.SalesReturn, tx *sqlx.Tx) error {
+	// First insert without code (code will be generated after getting ID)
+	insertQuery := `INSERT INTO sales_returns(code, order_id, return_date, reason, credit_amount, created_by, created_by_name)
+					VALUES ('TEMP', :order_id, :return_date, :reason, :credit_amount, :created_by, :created_by_name)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, salesReturn)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, salesReturn)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	salesReturn.ID = int(id)
+
+	// Generate code based on ID (TH + 5-digit format)
+	code := fmt.Sprintf("TH%05d", salesReturn.ID)
+	salesReturn.Code = code
+
+	// Update the record with the generated code
+	updateCodeQuery := `UPDATE sales_returns SET code = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, updateCodeQuery, code, salesReturn.ID)
+	} else {
+		_, err = repo.db.ExecContext(ctx, updateCodeQuery, code, salesReturn.ID)
+	}
+
+	return err
+}
+
+func (repo *SalesReturnRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.SalesReturn, error) {
+	var salesReturn entity.SalesReturn
+	query := "SELECT * FROM sales_returns WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &salesReturn, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &salesReturn, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &salesReturn, nil
+}
+
+func (repo *SalesReturnRepository) GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.SalesReturn, error) {
+	var salesReturns []entity.SalesReturn
+	query := "SELECT * FROM sales_returns WHERE order_id = ? ORDER BY return_date, id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &salesReturns, query, orderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &salesReturns, query, orderID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if salesReturns == nil {
+		return []entity.SalesReturn{}, nil
+	}
+
+	return salesReturns, nil
+}
+
+func (repo *SalesReturnRepository) GetTotalCreditAmountByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (int, error) {
+	var total int
+	query := "SELECT COALESCE(SUM(credit_amount), 0) FROM sales_returns WHERE order_id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &total, query, orderID)
+	} else {
+		err = repo.db.GetContext(ctx, &total, query, orderID)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
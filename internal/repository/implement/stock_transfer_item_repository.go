@@ -0,0 +1,66 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type StockTransferItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewStockTransferItemRepository(db database.Db) repository.StockTransferItemRepository {
+	return &StockTransferItemRepository{db: db}
+}
+
+func (repo *StockTransferItemRepository) CreateCommand(ctx context.Context, item *entity.StockTransferItem, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO stock_transfer_items(stock_transfer_id, product_id, quantity, note)
+					VALUES (:stock_transfer_id, :product_id, :quantity, :note)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, item)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, item)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	item.ID = int(lastID)
+	return nil
+}
+
+func (repo *StockTransferItemRepository) GetAllByStockTransferIDQuery(ctx context.Context, stockTransferID int, tx *sqlx.Tx) ([]entity.StockTransferItem, error) {
+	var items []entity.StockTransferItem
+	query := "SELECT * FROM stock_transfer_items WHERE stock_transfer_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &items, query, stockTransferID)
+	} else {
+		err = repo.db.SelectContext(ctx, &items, query, stockTransferID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		return []entity.StockTransferItem{}, nil
+	}
+
+	return items, nil
+}
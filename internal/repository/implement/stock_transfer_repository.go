@@ -0,0 +1,111 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type StockTransferRepository struct {
+	db *sqlx.DB
+}
+
+func NewStockTransferRepository(db database.Db) repository.StockTransferRepository {
+	return &StockTransferRepository{db: db}
+}
+
+func (repo *StockTransferRepository) CreateCommand(ctx context.Context, stockTransfer *entity.StockTransfer, tx *sqlx.Tx) error {
+	// First insert without code (code will be generated after getting ID)
+	insertQuery := `INSERT INTO stock_transfers(code, from_warehouse_id, to_warehouse_id, transfer_date, note, created_by, created_by_name)
+					VALUES ('TEMP', :from_warehouse_id, :to_warehouse_id, :transfer_date, :note, :created_by, :created_by_name)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, stockTransfer)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, stockTransfer)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	stockTransfer.ID = int(id)
+
+	// Generate code based on ID (CK + 5-digit format)
+	code := fmt.Sprintf("CK%05d", stockTransfer.ID)
+	stockTransfer.Code = code
+
+	// Update the record with the generated code
+	updateCodeQuery := `UPDATE stock_transfers SET code = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, updateCodeQuery, code, stockTransfer.ID)
+	} else {
+		_, err = repo.db.ExecContext(ctx, updateCodeQuery, code, stockTransfer.ID)
+	}
+
+	return err
+}
+
+func (repo *StockTransferRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.StockTransfer, error) {
+	var stockTransfer entity.StockTransfer
+	query := "SELECT * FROM stock_transfers WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &stockTransfer, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &stockTransfer, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &stockTransfer, nil
+}
+
+// GetAllWithFiltersQuery returns the transfers leaving or entering the warehouse, all of them when warehouseID is 0
+func (repo *StockTransferRepository) GetAllWithFiltersQuery(ctx context.Context, warehouseID int, tx *sqlx.Tx) ([]entity.StockTransfer, error) {
+	var stockTransfers []entity.StockTransfer
+	query := "SELECT * FROM stock_transfers WHERE 1=1"
+	var args []interface{}
+
+	// Add warehouse filter
+	if warehouseID > 0 {
+		query += " AND (from_warehouse_id = ? OR to_warehouse_id = ?)"
+		args = append(args, warehouseID, warehouseID)
+	}
+	query += " ORDER BY id DESC"
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &stockTransfers, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &stockTransfers, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stockTransfers == nil {
+		return []entity.StockTransfer{}, nil
+	}
+	return stockTransfers, nil
+}
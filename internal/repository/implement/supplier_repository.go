@@ -0,0 +1,115 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type SupplierRepository struct {
+	db *sqlx.DB
+}
+
+func NewSupplierRepository(db database.Db) repository.SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+func (repo *SupplierRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Supplier, error) {
+	var suppliers []entity.Supplier
+	query := "SELECT * FROM suppliers ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &suppliers, query)
+	} else {
+		err = repo.db.SelectContext(ctx, &suppliers, query)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if suppliers == nil {
+		return []entity.Supplier{}, nil
+	}
+
+	return suppliers, nil
+}
+
+func (repo *SupplierRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Supplier, error) {
+	var supplier entity.Supplier
+	query := "SELECT * FROM suppliers WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &supplier, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &supplier, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &supplier, nil
+}
+
+func (repo *SupplierRepository) CreateCommand(ctx context.Context, supplier *entity.Supplier, tx *sqlx.Tx) error {
+	// First insert without code (code will be generated after getting ID)
+	insertQuery := `INSERT INTO suppliers(code, name, phone, email, address, tax_code) VALUES ('TEMP', :name, :phone, :email, :address, :tax_code)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, supplier)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, supplier)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	supplier.ID = int(id)
+
+	// Generate code based on ID (NCC + 5-digit format)
+	code := fmt.Sprintf("NCC%05d", supplier.ID)
+	supplier.Code = code
+
+	// Update the record with the generated code
+	updateCodeQuery := `UPDATE suppliers SET code = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, updateCodeQuery, code, supplier.ID)
+	} else {
+		_, err = repo.db.ExecContext(ctx, updateCodeQuery, code, supplier.ID)
+	}
+
+	return err
+}
+
+func (repo *SupplierRepository) UpdateCommand(ctx context.Context, supplier *entity.Supplier, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE suppliers SET name = :name, phone = :phone, email = :email, address = :address, tax_code = :tax_code WHERE id = :id`
+
+	if tx != nil {
+		_, err := tx.NamedExecContext(ctx, updateQuery, supplier)
+		return err
+	}
+	_, err := repo.db.NamedExecContext(ctx, updateQuery, supplier)
+	return err
+}
@@ -0,0 +1,171 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type UnitConversionRepository struct {
+	db *sqlx.DB
+}
+
+func NewUnitConversionRepository(db database.Db) repository.UnitConversionRepository {
+	return &UnitConversionRepository{db: db}
+}
+
+func (repo *UnitConversionRepository) CreateCommand(ctx context.Context, conversion *entity.UnitConversion, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO unit_conversions(product_id, from_unit_id, to_unit_id, factor)
+					VALUES (:product_id, :from_unit_id, :to_unit_id, :factor)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, conversion)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, conversion)
+	}
+
+	if err != nil {
+		if strings.Contains(err.Error(), "unique_product_unit_conversion") {
+			return &error_utils.ConstraintViolationError{Message: "Unit conversion already exists"}
+		}
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	conversion.ID = int(lastID)
+	return nil
+}
+
+func (repo *UnitConversionRepository) UpdateCommand(ctx context.Context, conversion *entity.UnitConversion, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE unit_conversions SET from_unit_id = :from_unit_id, to_unit_id = :to_unit_id, factor = :factor WHERE id = :id`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, updateQuery, conversion)
+	} else {
+		_, err = repo.db.NamedExecContext(ctx, updateQuery, conversion)
+	}
+
+	if err != nil && strings.Contains(err.Error(), "unique_product_unit_conversion") {
+		return &error_utils.ConstraintViolationError{Message: "Unit conversion already exists"}
+	}
+	return err
+}
+
+func (repo *UnitConversionRepository) DeleteCommand(ctx context.Context, id int, tx *sqlx.Tx) error {
+	deleteQuery := "DELETE FROM unit_conversions WHERE id = ?"
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, deleteQuery, id)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, deleteQuery, id)
+	return err
+}
+
+func (repo *UnitConversionRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.UnitConversion, error) {
+	var conversion entity.UnitConversion
+	query := "SELECT * FROM unit_conversions WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &conversion, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &conversion, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &conversion, nil
+}
+
+func (repo *UnitConversionRepository) GetOneByUnitsQuery(ctx context.Context, productID *int, fromUnitID int, toUnitID int, tx *sqlx.Tx) (*entity.UnitConversion, error) {
+	var conversion entity.UnitConversion
+	// <=> also matches the global conversions, which have no product
+	query := "SELECT * FROM unit_conversions WHERE product_id <=> ? AND from_unit_id = ? AND to_unit_id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &conversion, query, productID, fromUnitID, toUnitID)
+	} else {
+		err = repo.db.GetContext(ctx, &conversion, query, productID, fromUnitID, toUnitID)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &conversion, nil
+}
+
+func (repo *UnitConversionRepository) GetAllWithFiltersQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.UnitConversion, error) {
+	var conversions []entity.UnitConversion
+	query := "SELECT * FROM unit_conversions"
+	var args []interface{}
+
+	if productID > 0 {
+		query += " WHERE product_id = ?"
+		args = append(args, productID)
+	}
+	query += " ORDER BY id"
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &conversions, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &conversions, query, args...)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if conversions == nil {
+		return []entity.UnitConversion{}, nil
+	}
+
+	return conversions, nil
+}
+
+func (repo *UnitConversionRepository) GetAllApplicableByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.UnitConversion, error) {
+	var conversions []entity.UnitConversion
+	// Global conversions first so that the product's own conversions can override them
+	query := "SELECT * FROM unit_conversions WHERE product_id = ? OR product_id IS NULL ORDER BY product_id IS NOT NULL, id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &conversions, query, productID)
+	} else {
+		err = repo.db.SelectContext(ctx, &conversions, query, productID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if conversions == nil {
+		return []entity.UnitConversion{}, nil
+	}
+
+	return conversions, nil
+}
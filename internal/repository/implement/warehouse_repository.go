@@ -0,0 +1,149 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type WarehouseRepository struct {
+	db *sqlx.DB
+}
+
+func NewWarehouseRepository(db database.Db) repository.WarehouseRepository {
+	return &WarehouseRepository{db: db}
+}
+
+func (repo *WarehouseRepository) GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Warehouse, error) {
+	var warehouses []entity.Warehouse
+	query := "SELECT * FROM warehouses ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &warehouses, query)
+	} else {
+		err = repo.db.SelectContext(ctx, &warehouses, query)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if warehouses == nil {
+		return []entity.Warehouse{}, nil
+	}
+
+	return warehouses, nil
+}
+
+func (repo *WarehouseRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Warehouse, error) {
+	var warehouse entity.Warehouse
+	query := "SELECT * FROM warehouses WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &warehouse, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &warehouse, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &warehouse, nil
+}
+
+// GetDefaultQuery returns the warehouse documents fall back to when they do not name one
+func (repo *WarehouseRepository) GetDefaultQuery(ctx context.Context, tx *sqlx.Tx) (*entity.Warehouse, error) {
+	var warehouse entity.Warehouse
+	query := "SELECT * FROM warehouses WHERE is_default = 1 ORDER BY id LIMIT 1"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &warehouse, query)
+	} else {
+		err = repo.db.GetContext(ctx, &warehouse, query)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &warehouse, nil
+}
+
+func (repo *WarehouseRepository) CreateCommand(ctx context.Context, warehouse *entity.Warehouse, tx *sqlx.Tx) error {
+	// First insert without code (code will be generated after getting ID)
+	insertQuery := `INSERT INTO warehouses(code, name, address, is_default) VALUES ('TEMP', :name, :address, :is_default)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, warehouse)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, warehouse)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	warehouse.ID = int(id)
+
+	// Generate code based on ID (KHO + 5-digit format)
+	code := fmt.Sprintf("KHO%05d", warehouse.ID)
+	warehouse.Code = code
+
+	// Update the record with the generated code
+	updateCodeQuery := `UPDATE warehouses SET code = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, updateCodeQuery, code, warehouse.ID)
+	} else {
+		_, err = repo.db.ExecContext(ctx, updateCodeQuery, code, warehouse.ID)
+	}
+
+	return err
+}
+
+func (repo *WarehouseRepository) UpdateCommand(ctx context.Context, warehouse *entity.Warehouse, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE warehouses SET name = :name, address = :address, is_default = :is_default WHERE id = :id`
+
+	if tx != nil {
+		_, err := tx.NamedExecContext(ctx, updateQuery, warehouse)
+		return err
+	}
+	_, err := repo.db.NamedExecContext(ctx, updateQuery, warehouse)
+	return err
+}
+
+// ClearDefaultCommand removes the default flag from every warehouse but the given one
+func (repo *WarehouseRepository) ClearDefaultCommand(ctx context.Context, exceptID int, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE warehouses SET is_default = 0 WHERE id <> ? AND is_default = 1`
+
+	if tx != nil {
+		_, err := tx.ExecContext(ctx, updateQuery, exceptID)
+		return err
+	}
+	_, err := repo.db.ExecContext(ctx, updateQuery, exceptID)
+	return err
+}
@@ -0,0 +1,66 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type WorkOrderItemRepository struct {
+	db *sqlx.DB
+}
+
+func NewWorkOrderItemRepository(db database.Db) repository.WorkOrderItemRepository {
+	return &WorkOrderItemRepository{db: db}
+}
+
+func (repo *WorkOrderItemRepository) CreateCommand(ctx context.Context, item *entity.WorkOrderItem, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO work_order_items(work_order_id, product_id, quantity, unit_cost)
+					VALUES (:work_order_id, :product_id, :quantity, :unit_cost)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, item)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, item)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	item.ID = int(lastID)
+	return nil
+}
+
+func (repo *WorkOrderItemRepository) GetAllByWorkOrderIDQuery(ctx context.Context, workOrderID int, tx *sqlx.Tx) ([]entity.WorkOrderItem, error) {
+	var items []entity.WorkOrderItem
+	query := "SELECT * FROM work_order_items WHERE work_order_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &items, query, workOrderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &items, query, workOrderID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		return []entity.WorkOrderItem{}, nil
+	}
+
+	return items, nil
+}
@@ -0,0 +1,99 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+)
+
+type WorkOrderLotRepository struct {
+	db *sqlx.DB
+}
+
+func NewWorkOrderLotRepository(db database.Db) repository.WorkOrderLotRepository {
+	return &WorkOrderLotRepository{db: db}
+}
+
+func (repo *WorkOrderLotRepository) CreateCommand(ctx context.Context, workOrderLot *entity.WorkOrderLot, tx *sqlx.Tx) error {
+	insertQuery := `INSERT INTO work_order_lots(work_order_id, product_id, inventory_lot_id, quantity)
+					VALUES (:work_order_id, :product_id, :inventory_lot_id, :quantity)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, workOrderLot)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, workOrderLot)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	workOrderLot.ID = int(lastID)
+	return nil
+}
+
+func (repo *WorkOrderLotRepository) GetAllByWorkOrderIDQuery(ctx context.Context, workOrderID int, tx *sqlx.Tx) ([]entity.WorkOrderLot, error) {
+	var workOrderLots []entity.WorkOrderLot
+	query := "SELECT * FROM work_order_lots WHERE work_order_id = ? ORDER BY id"
+	var err error
+
+	if tx != nil {
+		err = tx.SelectContext(ctx, &workOrderLots, query, workOrderID)
+	} else {
+		err = repo.db.SelectContext(ctx, &workOrderLots, query, workOrderID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if workOrderLots == nil {
+		return []entity.WorkOrderLot{}, nil
+	}
+
+	return workOrderLots, nil
+}
+
+// GetConsumptionsByLotIDsQuery returns the quantity of the lots each work order consumed, with the
+// lot its finished goods went into (nil while the work order is still in progress)
+func (repo *WorkOrderLotRepository) GetConsumptionsByLotIDsQuery(ctx context.Context, lotIDs []int, tx *sqlx.Tx) ([]entity.LotConsumption, error) {
+	if len(lotIDs) == 0 {
+		return []entity.LotConsumption{}, nil
+	}
+	query, args, err := sqlx.In(`SELECT wol.inventory_lot_id, wo.id AS work_order_id, wo.code AS work_order_code,
+					wo.status AS work_order_status, wol.product_id, SUM(wol.quantity) AS quantity, wo.finished_lot_id
+			  FROM work_order_lots wol
+			  JOIN work_orders wo ON wo.id = wol.work_order_id
+			  WHERE wol.inventory_lot_id IN (?)
+			  GROUP BY wol.inventory_lot_id, wo.id, wo.code, wo.status, wol.product_id, wo.finished_lot_id
+			  ORDER BY wo.id`, lotIDs)
+	if err != nil {
+		return nil, err
+	}
+	query = repo.db.Rebind(query)
+
+	var consumptions []entity.LotConsumption
+	if tx != nil {
+		err = tx.SelectContext(ctx, &consumptions, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &consumptions, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if consumptions == nil {
+		return []entity.LotConsumption{}, nil
+	}
+	return consumptions, nil
+}
@@ -0,0 +1,149 @@
+package repositoryimplement
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/database"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+)
+
+type WorkOrderRepository struct {
+	db *sqlx.DB
+}
+
+func NewWorkOrderRepository(db database.Db) repository.WorkOrderRepository {
+	return &WorkOrderRepository{db: db}
+}
+
+func (repo *WorkOrderRepository) CreateCommand(ctx context.Context, workOrder *entity.WorkOrder, tx *sqlx.Tx) error {
+	// First insert without code (code will be generated after getting ID)
+	insertQuery := `INSERT INTO work_orders(code, product_id, warehouse_id, quantity, multi_level, status, planned_date, note, created_by, created_by_name)
+					VALUES ('TEMP', :product_id, :warehouse_id, :quantity, :multi_level, :status, :planned_date, :note, :created_by, :created_by_name)`
+
+	var result sql.Result
+	var err error
+
+	if tx != nil {
+		result, err = tx.NamedExecContext(ctx, insertQuery, workOrder)
+	} else {
+		result, err = repo.db.NamedExecContext(ctx, insertQuery, workOrder)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	workOrder.ID = int(id)
+
+	// Generate code based on ID (SX + 5-digit format)
+	code := fmt.Sprintf("SX%05d", workOrder.ID)
+	workOrder.Code = code
+
+	// Update the record with the generated code
+	updateCodeQuery := `UPDATE work_orders SET code = ? WHERE id = ?`
+
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, updateCodeQuery, code, workOrder.ID)
+	} else {
+		_, err = repo.db.ExecContext(ctx, updateCodeQuery, code, workOrder.ID)
+	}
+
+	return err
+}
+
+func (repo *WorkOrderRepository) UpdateCommand(ctx context.Context, workOrder *entity.WorkOrder, tx *sqlx.Tx) error {
+	updateQuery := `UPDATE work_orders SET status = :status, planned_date = :planned_date, note = :note,
+					started_at = :started_at, completed_at = :completed_at, finished_lot_id = :finished_lot_id WHERE id = :id`
+
+	var err error
+	if tx != nil {
+		_, err = tx.NamedExecContext(ctx, updateQuery, workOrder)
+	} else {
+		_, err = repo.db.NamedExecContext(ctx, updateQuery, workOrder)
+	}
+	return err
+}
+
+func (repo *WorkOrderRepository) GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.WorkOrder, error) {
+	var workOrder entity.WorkOrder
+	query := "SELECT * FROM work_orders WHERE id = ?"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &workOrder, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &workOrder, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &workOrder, nil
+}
+
+func (repo *WorkOrderRepository) GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.WorkOrder, error) {
+	var workOrder entity.WorkOrder
+	query := "SELECT * FROM work_orders WHERE id = ? FOR UPDATE"
+	var err error
+
+	if tx != nil {
+		err = tx.GetContext(ctx, &workOrder, query, id)
+	} else {
+		err = repo.db.GetContext(ctx, &workOrder, query, id)
+	}
+
+	if err != nil {
+		if err.Error() == error_utils.SystemErrorMessage.SqlxNoRow {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &workOrder, nil
+}
+
+func (repo *WorkOrderRepository) GetAllWithFiltersQuery(ctx context.Context, productID int, status string, tx *sqlx.Tx) ([]entity.WorkOrder, error) {
+	var workOrders []entity.WorkOrder
+	query := "SELECT * FROM work_orders WHERE 1=1"
+	var args []interface{}
+
+	// Add product filter
+	if productID > 0 {
+		query += " AND product_id = ?"
+		args = append(args, productID)
+	}
+	// Add status filter
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY id DESC"
+
+	var err error
+	if tx != nil {
+		err = tx.SelectContext(ctx, &workOrders, query, args...)
+	} else {
+		err = repo.db.SelectContext(ctx, &workOrders, query, args...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if workOrders == nil {
+		return []entity.WorkOrder{}, nil
+	}
+	return workOrders, nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type InventoryCostLayerMovementRepository interface {
+	CreateCommand(ctx context.Context, movement *entity.InventoryCostLayerMovement, tx *sqlx.Tx) error
+	GetAllByReferenceQuery(ctx context.Context, referenceType string, referenceID int, tx *sqlx.Tx) ([]entity.InventoryCostLayerMovement, error)
+}
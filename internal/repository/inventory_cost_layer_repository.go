@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type InventoryCostLayerRepository interface {
+	CreateCommand(ctx context.Context, layer *entity.InventoryCostLayer, tx *sqlx.Tx) error
+	GetAllWithFiltersQuery(ctx context.Context, productID int, openOnly bool, tx *sqlx.Tx) ([]entity.InventoryCostLayer, error)
+	GetOpenForUpdateQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.InventoryCostLayer, error)
+	GetManyByIDsForUpdateQuery(ctx context.Context, ids []int, tx *sqlx.Tx) ([]entity.InventoryCostLayer, error)
+	AddRemainingQuantityCommand(ctx context.Context, id int, quantity int, tx *sqlx.Tx) error
+	GetRemainingBeforeQuery(ctx context.Context, cutoff time.Time, tx *sqlx.Tx) ([]entity.InventoryCostLayer, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/management-app-backend/internal/domain/entity"
@@ -9,5 +10,7 @@ import (
 
 type InventoryHistoryRepository interface {
 	GetAllByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.InventoryHistory, error)
+	GetAllByReferenceQuery(ctx context.Context, referenceType string, referenceID int, tx *sqlx.Tx) ([]entity.InventoryHistory, error)
 	CreateCommand(ctx context.Context, inventoryHistory *entity.InventoryHistory, tx *sqlx.Tx) error
+	GetQuantitiesBeforeQuery(ctx context.Context, cutoff time.Time, tx *sqlx.Tx) (map[int]int, error)
 }
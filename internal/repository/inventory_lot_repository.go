@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type InventoryLotRepository interface {
+	CreateCommand(ctx context.Context, lot *entity.InventoryLot, tx *sqlx.Tx) error
+	GetOneByLotNumberQuery(ctx context.Context, productID int, warehouseID int, lotNumber string, tx *sqlx.Tx) (*entity.InventoryLot, error)
+	GetAllWithFiltersQuery(ctx context.Context, productID int, warehouseID int, lotNumber string, inStockOnly bool, tx *sqlx.Tx) ([]entity.InventoryLot, error)
+	GetAvailableForUpdateQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) ([]entity.InventoryLot, error)
+	GetManyByIDsQuery(ctx context.Context, ids []int, tx *sqlx.Tx) ([]entity.InventoryLot, error)
+	GetManyByIDsForUpdateQuery(ctx context.Context, ids []int, tx *sqlx.Tx) ([]entity.InventoryLot, error)
+	AddQuantityCommand(ctx context.Context, id int, quantity int, tx *sqlx.Tx) error
+	GetTotalQuantityQuery(ctx context.Context, productID int, warehouseID int, tx *sqlx.Tx) (int, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.InventoryLot, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type InventoryReservationRepository interface {
+	CreateCommand(ctx context.Context, reservation *entity.InventoryReservation, tx *sqlx.Tx) error
+	GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.InventoryReservation, error)
+	GetReservedQuantitiesByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (map[int]int, error)
+	GetReservedQuantitiesByProductIDsQuery(ctx context.Context, productIDs []int, warehouseID *int, tx *sqlx.Tx) (map[int]int, error)
+	GetAllReservedQuantitiesQuery(ctx context.Context, warehouseID *int, tx *sqlx.Tx) (map[int]int, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type MrpRunDemandRepository interface {
+	CreateCommand(ctx context.Context, demand *entity.MrpRunDemand, tx *sqlx.Tx) error
+	GetAllByMrpRunIDQuery(ctx context.Context, mrpRunID int, tx *sqlx.Tx) ([]entity.MrpRunDemand, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type MrpRunRepository interface {
+	CreateCommand(ctx context.Context, run *entity.MrpRun, tx *sqlx.Tx) error
+	UpdateCommand(ctx context.Context, run *entity.MrpRun, tx *sqlx.Tx) error
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.MrpRun, error)
+	GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.MrpRun, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type OrderItemLotRepository interface {
+	CreateCommand(ctx context.Context, orderItemLot *entity.OrderItemLot, tx *sqlx.Tx) error
+	GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.OrderItemLot, error)
+	GetShipmentsByLotIDsQuery(ctx context.Context, lotIDs []int, tx *sqlx.Tx) ([]entity.LotShipment, error)
+}
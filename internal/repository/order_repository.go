@@ -11,8 +11,10 @@ import (
 type OrderRepository interface {
 	GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Order, error)
 	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Order, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Order, error)
 	CreateCommand(ctx context.Context, order *entity.Order, tx *sqlx.Tx) error
 	UpdateCommand(ctx context.Context, order *entity.Order, tx *sqlx.Tx) error
 	GetByCustomerIDQuery(ctx context.Context, customerID int, tx *sqlx.Tx) ([]entity.Order, error)
+	GetAllByDeliveryStatusQuery(ctx context.Context, deliveryStatus string, tx *sqlx.Tx) ([]entity.Order, error)
 	GetAllWithFiltersQuery(ctx context.Context, customerID int, sortBy string, fromDate *time.Time, toDate *time.Time, tx *sqlx.Tx) ([]entity.Order, error)
 }
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type OrderStatusHistoryRepository interface {
+	CreateCommand(ctx context.Context, history *entity.OrderStatusHistory, tx *sqlx.Tx) error
+	GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.OrderStatusHistory, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type PaymentRepository interface {
+	CreateCommand(ctx context.Context, payment *entity.Payment, tx *sqlx.Tx) error
+	GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.Payment, error)
+	GetTotalAmountByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (int, error)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/pna/management-app-backend/internal/domain/entity"
@@ -10,9 +11,12 @@ import (
 type ProductBomRepository interface {
 	GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.ProductBom, error)
 	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.ProductBom, error)
-	GetByParentProductIDQuery(ctx context.Context, parentProductID int, tx *sqlx.Tx) ([]entity.ProductBom, error)
-	GetByComponentProductIDQuery(ctx context.Context, componentProductID int, tx *sqlx.Tx) ([]entity.ProductBom, error)
+	GetEffectiveQuery(ctx context.Context, date time.Time, tx *sqlx.Tx) ([]entity.ProductBom, error)
+	GetEffectiveByParentProductIDQuery(ctx context.Context, parentProductID int, date time.Time, tx *sqlx.Tx) ([]entity.ProductBom, error)
+	GetEffectiveByComponentProductIDQuery(ctx context.Context, componentProductID int, date time.Time, tx *sqlx.Tx) ([]entity.ProductBom, error)
+	GetByBomVersionIDQuery(ctx context.Context, bomVersionID int, tx *sqlx.Tx) ([]entity.ProductBom, error)
 	CreateCommand(ctx context.Context, bom *entity.ProductBom, tx *sqlx.Tx) error
 	UpdateCommand(ctx context.Context, bom *entity.ProductBom, tx *sqlx.Tx) error
 	DeleteCommand(ctx context.Context, id int, tx *sqlx.Tx) error
+	LockGraphCommand(ctx context.Context) (release func(), acquired bool, err error)
 }
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type ProductBomVersionRepository interface {
+	CreateCommand(ctx context.Context, version *entity.ProductBomVersion, tx *sqlx.Tx) error
+	UpdateCommand(ctx context.Context, version *entity.ProductBomVersion, tx *sqlx.Tx) error
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.ProductBomVersion, error)
+	GetAllByParentProductIDQuery(ctx context.Context, parentProductID int, tx *sqlx.Tx) ([]entity.ProductBomVersion, error)
+	GetEffectiveByParentProductIDQuery(ctx context.Context, parentProductID int, date time.Time, tx *sqlx.Tx) (*entity.ProductBomVersion, error)
+	GetActiveStartsBetweenQuery(ctx context.Context, from *time.Time, to *time.Time, tx *sqlx.Tx) ([]time.Time, error)
+}
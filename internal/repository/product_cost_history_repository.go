@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type ProductCostHistoryRepository interface {
+	CreateCommand(ctx context.Context, history *entity.ProductCostHistory, tx *sqlx.Tx) error
+	GetAllByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.ProductCostHistory, error)
+	GetCostsBeforeQuery(ctx context.Context, cutoff time.Time, tx *sqlx.Tx) (map[int]float64, error)
+}
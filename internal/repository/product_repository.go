@@ -10,6 +10,8 @@ import (
 type ProductRepository interface {
 	GetAllQuery(ctx context.Context, categoryFilter string, operationTypeFilter string, tx *sqlx.Tx) ([]entity.Product, error)
 	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Product, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Product, error)
 	CreateCommand(ctx context.Context, product *entity.Product, tx *sqlx.Tx) error
 	UpdateCommand(ctx context.Context, product *entity.Product, tx *sqlx.Tx) error
+	UpdateCostCommand(ctx context.Context, id int, cost float64, tx *sqlx.Tx) error
 }
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type PurchaseOrderItemRepository interface {
+	CreateCommand(ctx context.Context, item *entity.PurchaseOrderItem, tx *sqlx.Tx) error
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrderItem, error)
+	GetAllByPurchaseOrderIDQuery(ctx context.Context, purchaseOrderID int, tx *sqlx.Tx) ([]entity.PurchaseOrderItem, error)
+	DeleteByPurchaseOrderIDCommand(ctx context.Context, purchaseOrderID int, tx *sqlx.Tx) error
+	AddReceivedQuantityCommand(ctx context.Context, id int, quantity int, tx *sqlx.Tx) (bool, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type PurchaseOrderRepository interface {
+	CreateCommand(ctx context.Context, purchaseOrder *entity.PurchaseOrder, tx *sqlx.Tx) error
+	UpdateCommand(ctx context.Context, purchaseOrder *entity.PurchaseOrder, tx *sqlx.Tx) error
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrder, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.PurchaseOrder, error)
+	GetAllWithFiltersQuery(ctx context.Context, supplierID int, status string, tx *sqlx.Tx) ([]entity.PurchaseOrder, error)
+}
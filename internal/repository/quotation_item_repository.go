@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type QuotationItemRepository interface {
+	CreateCommand(ctx context.Context, item *entity.QuotationItem, tx *sqlx.Tx) error
+	GetAllByQuotationIDQuery(ctx context.Context, quotationID int, tx *sqlx.Tx) ([]entity.QuotationItem, error)
+	DeleteByQuotationIDCommand(ctx context.Context, quotationID int, tx *sqlx.Tx) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type QuotationRepository interface {
+	CreateCommand(ctx context.Context, quotation *entity.Quotation, tx *sqlx.Tx) error
+	UpdateCommand(ctx context.Context, quotation *entity.Quotation, tx *sqlx.Tx) error
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Quotation, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Quotation, error)
+	GetAllWithFiltersQuery(ctx context.Context, customerID int, tx *sqlx.Tx) ([]entity.Quotation, error)
+	MarkConvertedCommand(ctx context.Context, id int, orderID int, tx *sqlx.Tx) (bool, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type SalesReturnItemRepository interface {
+	CreateCommand(ctx context.Context, item *entity.SalesReturnItem, tx *sqlx.Tx) error
+	GetAllBySalesReturnIDQuery(ctx context.Context, salesReturnID int, tx *sqlx.Tx) ([]entity.SalesReturnItem, error)
+	GetReturnedQuantitiesByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (map[int]int, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type SalesReturnRepository interface {
+	CreateCommand(ctx context.Context, salesReturn *entity.SalesReturn, tx *sqlx.Tx) error
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.SalesReturn, error)
+	GetAllByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) ([]entity.SalesReturn, error)
+	GetTotalCreditAmountByOrderIDQuery(ctx context.Context, orderID int, tx *sqlx.Tx) (int, error)
+}
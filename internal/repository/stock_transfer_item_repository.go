@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type StockTransferItemRepository interface {
+	CreateCommand(ctx context.Context, item *entity.StockTransferItem, tx *sqlx.Tx) error
+	GetAllByStockTransferIDQuery(ctx context.Context, stockTransferID int, tx *sqlx.Tx) ([]entity.StockTransferItem, error)
+}
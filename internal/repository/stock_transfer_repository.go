@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type StockTransferRepository interface {
+	CreateCommand(ctx context.Context, stockTransfer *entity.StockTransfer, tx *sqlx.Tx) error
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.StockTransfer, error)
+	GetAllWithFiltersQuery(ctx context.Context, warehouseID int, tx *sqlx.Tx) ([]entity.StockTransfer, error)
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type SupplierRepository interface {
+	GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Supplier, error)
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Supplier, error)
+	CreateCommand(ctx context.Context, supplier *entity.Supplier, tx *sqlx.Tx) error
+	UpdateCommand(ctx context.Context, supplier *entity.Supplier, tx *sqlx.Tx) error
+}
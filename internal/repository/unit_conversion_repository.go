@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type UnitConversionRepository interface {
+	CreateCommand(ctx context.Context, conversion *entity.UnitConversion, tx *sqlx.Tx) error
+	UpdateCommand(ctx context.Context, conversion *entity.UnitConversion, tx *sqlx.Tx) error
+	DeleteCommand(ctx context.Context, id int, tx *sqlx.Tx) error
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.UnitConversion, error)
+	GetOneByUnitsQuery(ctx context.Context, productID *int, fromUnitID int, toUnitID int, tx *sqlx.Tx) (*entity.UnitConversion, error)
+	GetAllWithFiltersQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.UnitConversion, error)
+	GetAllApplicableByProductIDQuery(ctx context.Context, productID int, tx *sqlx.Tx) ([]entity.UnitConversion, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type WarehouseRepository interface {
+	GetAllQuery(ctx context.Context, tx *sqlx.Tx) ([]entity.Warehouse, error)
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.Warehouse, error)
+	GetDefaultQuery(ctx context.Context, tx *sqlx.Tx) (*entity.Warehouse, error)
+	CreateCommand(ctx context.Context, warehouse *entity.Warehouse, tx *sqlx.Tx) error
+	UpdateCommand(ctx context.Context, warehouse *entity.Warehouse, tx *sqlx.Tx) error
+	ClearDefaultCommand(ctx context.Context, exceptID int, tx *sqlx.Tx) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type WorkOrderItemRepository interface {
+	CreateCommand(ctx context.Context, item *entity.WorkOrderItem, tx *sqlx.Tx) error
+	GetAllByWorkOrderIDQuery(ctx context.Context, workOrderID int, tx *sqlx.Tx) ([]entity.WorkOrderItem, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type WorkOrderLotRepository interface {
+	CreateCommand(ctx context.Context, workOrderLot *entity.WorkOrderLot, tx *sqlx.Tx) error
+	GetAllByWorkOrderIDQuery(ctx context.Context, workOrderID int, tx *sqlx.Tx) ([]entity.WorkOrderLot, error)
+	GetConsumptionsByLotIDsQuery(ctx context.Context, lotIDs []int, tx *sqlx.Tx) ([]entity.LotConsumption, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+)
+
+type WorkOrderRepository interface {
+	CreateCommand(ctx context.Context, workOrder *entity.WorkOrder, tx *sqlx.Tx) error
+	UpdateCommand(ctx context.Context, workOrder *entity.WorkOrder, tx *sqlx.Tx) error
+	GetOneByIDQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.WorkOrder, error)
+	GetOneByIDForUpdateQuery(ctx context.Context, id int, tx *sqlx.Tx) (*entity.WorkOrder, error)
+	GetAllWithFiltersQuery(ctx context.Context, productID int, status string, tx *sqlx.Tx) ([]entity.WorkOrder, error)
+}
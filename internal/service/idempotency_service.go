@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type IdempotencyService interface {
+	Begin(ctx context.Context, userID int, key string, requestHash string) (*model.IdempotentRequest, string)
+	Complete(ctx context.Context, id int, responseStatusCode int, responseBody []byte) string
+	Release(ctx context.Context, id int) string
+}
@@ -0,0 +1,175 @@
+package serviceimplement
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultIdempotencyKeyRetentionHours is how long a stored response can be replayed
+const defaultIdempotencyKeyRetentionHours = 24
+
+// idempotencyProcessingLease is how long a request holds its key while processing. A key still
+// PROCESSING after that belongs to a request that died, a retry may run the request again.
+const idempotencyProcessingLease = 5 * time.Minute
+
+// idempotencySweepInterval is how often expired keys are swept out of the table
+const idempotencySweepInterval = time.Hour
+
+type IdempotencyService struct {
+	idempotencyKeyRepo repository.IdempotencyKeyRepository
+	retention          time.Duration
+	lastSweep          atomic.Int64 // Unix seconds of the last sweep of expired keys
+}
+
+func NewIdempotencyService(idempotencyKeyRepo repository.IdempotencyKeyRepository) service.IdempotencyService {
+	// Get configuration from environment variables
+	retentionHours := defaultIdempotencyKeyRetentionHours
+	if value := os.Getenv("IDEMPOTENCY_KEY_RETENTION_HOURS"); value != "" {
+		if hours, err := strconv.Atoi(value); err == nil && hours > 0 {
+			retentionHours = hours
+		} else {
+			log.Warn("IdempotencyService: invalid IDEMPOTENCY_KEY_RETENTION_HOURS, using default")
+		}
+	}
+
+	return &IdempotencyService{
+		idempotencyKeyRepo: idempotencyKeyRepo,
+		retention:          time.Duration(retentionHours) * time.Hour,
+	}
+}
+
+// sweepExpiredKeys deletes the keys past their retention window, at most once per sweep interval.
+// Begin does not depend on it, an expired key is taken over when it is reused.
+func (s *IdempotencyService) sweepExpiredKeys(ctx context.Context, now time.Time) {
+	lastSweep := s.lastSweep.Load()
+	if now.Sub(time.Unix(lastSweep, 0)) < idempotencySweepInterval || !s.lastSweep.CompareAndSwap(lastSweep, now.Unix()) {
+		return
+	}
+	if err := s.idempotencyKeyRepo.DeleteExpiredCommand(ctx, now, nil); err != nil {
+		log.Error("IdempotencyService.sweepExpiredKeys Error when delete expired keys: " + err.Error())
+	}
+}
+
+// Begin claims the key for the user. The first request with a key gets a fresh record to
+// complete or release; later requests with the same fingerprint get the stored response. A key
+// that has expired, or whose request has held it past the processing lease, is claimed anew.
+func (s *IdempotencyService) Begin(ctx context.Context, userID int, key string, requestHash string) (*model.IdempotentRequest, string) {
+	now := time.Now()
+	s.sweepExpiredKeys(ctx, now)
+
+	idempotencyKey := &entity.IdempotencyKey{
+		UserID:         userID,
+		IdempotencyKey: key,
+		RequestHash:    requestHash,
+		Status:         entity.IdempotencyKeyStatus.PROCESSING,
+		CreatedAt:      now, // Set here rather than by MySQL, the lease is checked against this clock
+		ExpiresAt:      now.Add(s.retention),
+	}
+
+	// The insert is not wrapped in a transaction so that concurrent duplicates see it right away
+	created, errCode := s.createKey(ctx, idempotencyKey)
+	if errCode != "" {
+		return nil, errCode
+	}
+	if created {
+		return &model.IdempotentRequest{ID: idempotencyKey.ID}, ""
+	}
+
+	existing, err := s.idempotencyKeyRepo.GetOneByUserIDAndKeyQuery(ctx, userID, key, nil)
+	if err != nil {
+		log.Error("IdempotencyService.Begin Error when get idempotency key: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if existing == nil {
+		// The original request failed and released the key in the meantime
+		return nil, error_utils.ErrorCode.IDEMPOTENCY_REQUEST_IN_PROGRESS
+	}
+
+	expired := existing.ExpiresAt.Before(now)
+	if !expired && existing.RequestHash != requestHash {
+		return nil, error_utils.ErrorCode.IDEMPOTENCY_KEY_REUSED
+	}
+	leaseExpired := existing.Status == entity.IdempotencyKeyStatus.PROCESSING && existing.CreatedAt.Before(now.Add(-idempotencyProcessingLease))
+	if expired || leaseExpired {
+		// Replace the stale record with a new one, so that a late Complete or Release of the
+		// request that held it no longer touches the key
+		deleted, err := s.idempotencyKeyRepo.DeleteStaleCommand(ctx, existing.ID, now, now.Add(-idempotencyProcessingLease), nil)
+		if err != nil {
+			log.Error("IdempotencyService.Begin Error when delete stale idempotency key: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if deleted {
+			created, errCode = s.createKey(ctx, idempotencyKey)
+			if errCode != "" {
+				return nil, errCode
+			}
+			if created {
+				return &model.IdempotentRequest{ID: idempotencyKey.ID}, ""
+			}
+		}
+		// Another retry claimed the key first
+		return nil, error_utils.ErrorCode.IDEMPOTENCY_REQUEST_IN_PROGRESS
+	}
+	if existing.Status != entity.IdempotencyKeyStatus.COMPLETED || existing.ResponseStatusCode == nil {
+		return nil, error_utils.ErrorCode.IDEMPOTENCY_REQUEST_IN_PROGRESS
+	}
+
+	request := &model.IdempotentRequest{
+		ID:                 existing.ID,
+		Replayed:           true,
+		ResponseStatusCode: *existing.ResponseStatusCode,
+	}
+	if existing.ResponseBody != nil {
+		request.ResponseBody = []byte(*existing.ResponseBody)
+	}
+
+	return request, ""
+}
+
+// createKey inserts the key, returning false when the user already holds it
+func (s *IdempotencyService) createKey(ctx context.Context, idempotencyKey *entity.IdempotencyKey) (bool, string) {
+	err := s.idempotencyKeyRepo.CreateCommand(ctx, idempotencyKey, nil)
+	if err == nil {
+		return true, ""
+	}
+
+	var constraintViolationError *error_utils.ConstraintViolationError
+	if !errors.As(err, &constraintViolationError) {
+		log.Error("IdempotencyService.createKey Error when create idempotency key: " + err.Error())
+		return false, error_utils.ErrorCode.DB_DOWN
+	}
+	return false, ""
+}
+
+// Complete stores the response so that retries with the same key get it back
+func (s *IdempotencyService) Complete(ctx context.Context, id int, responseStatusCode int, responseBody []byte) string {
+	err := s.idempotencyKeyRepo.CompleteCommand(ctx, id, responseStatusCode, string(responseBody), nil)
+	if err != nil {
+		log.Error("IdempotencyService.Complete Error when complete idempotency key: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+// Release frees the key after a failed request so that the client can retry with it
+func (s *IdempotencyService) Release(ctx context.Context, id int) string {
+	err := s.idempotencyKeyRepo.DeleteCommand(ctx, id, nil)
+	if err != nil {
+		log.Error("IdempotencyService.Release Error when delete idempotency key: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
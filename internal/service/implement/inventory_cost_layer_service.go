@@ -0,0 +1,284 @@
+package serviceimplement
+
+import (
+	"context"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// valuationMethodFromEnv reads INVENTORY_VALUATION_METHOD, AVERAGE (default) or FIFO. FIFO layers and
+// the moving-average product cost are both kept whichever is selected; the setting picks which one
+// the cost of sales and stock figures use: the cost of order lines and the order's total cost, the
+// cost of the components a work order consumes, the inventory valuation and the stock valuation
+// report. Finished goods always open their FIFO layer at the layer cost of their components.
+func valuationMethodFromEnv() string {
+	switch value := os.Getenv("INVENTORY_VALUATION_METHOD"); value {
+	case "", entity.InventoryValuationMethod.AVERAGE:
+		return entity.InventoryValuationMethod.AVERAGE
+	case entity.InventoryValuationMethod.FIFO:
+		return entity.InventoryValuationMethod.FIFO
+	default:
+		log.Warn("Invalid INVENTORY_VALUATION_METHOD " + value + ", using AVERAGE")
+		return entity.InventoryValuationMethod.AVERAGE
+	}
+}
+
+// costLayerLedger keeps the FIFO cost layers of products in step with their stock. Every inflow
+// opens a layer, every outflow takes from the oldest layers and records what it took so that a
+// reversal can put it back. The caller must hold the lock on the inventory it moves.
+type costLayerLedger struct {
+	layerRepo    repository.InventoryCostLayerRepository
+	movementRepo repository.InventoryCostLayerMovementRepository
+}
+
+func newCostLayerLedger(layerRepo repository.InventoryCostLayerRepository, movementRepo repository.InventoryCostLayerMovementRepository) *costLayerLedger {
+	return &costLayerLedger{layerRepo: layerRepo, movementRepo: movementRepo}
+}
+
+// add opens a layer holding the whole received quantity
+func (l *costLayerLedger) add(ctx context.Context, layer *entity.InventoryCostLayer, tx *sqlx.Tx) error {
+	if layer.Quantity <= 0 {
+		return nil
+	}
+	layer.UnitCost = roundCost(layer.UnitCost)
+	layer.RemainingQuantity = layer.Quantity
+	return l.layerRepo.CreateCommand(ctx, layer, tx)
+}
+
+// consume takes up to quantity of the product from its layers oldest first and returns the cost of
+// what it took. Stock the layers do not cover (sold before layers were kept) is left unvalued.
+func (l *costLayerLedger) consume(ctx context.Context, productID int, quantity int, referenceType string, referenceID *int, tx *sqlx.Tx) (float64, error) {
+	if quantity <= 0 {
+		return 0, nil
+	}
+	layers, err := l.layerRepo.GetOpenForUpdateQuery(ctx, productID, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	var value float64
+	for _, layer := range layers {
+		if quantity <= 0 {
+			break
+		}
+		taken := min(layer.RemainingQuantity, quantity)
+		if err := l.layerRepo.AddRemainingQuantityCommand(ctx, layer.ID, -taken, tx); err != nil {
+			return 0, err
+		}
+		movement := &entity.InventoryCostLayerMovement{
+			CostLayerID:   layer.ID,
+			ProductID:     productID,
+			ReferenceType: referenceType,
+			ReferenceID:   referenceID,
+			Quantity:      -taken,
+			UnitCost:      layer.UnitCost,
+		}
+		if err := l.movementRepo.CreateCommand(ctx, movement, tx); err != nil {
+			return 0, err
+		}
+		value += float64(taken) * layer.UnitCost
+		quantity -= taken
+	}
+	return value, nil
+}
+
+// restore puts stock coming back to the document into the layers it was taken from, newest first.
+// What the document did not take from a layer opens a new layer at fallbackCost.
+func (l *costLayerLedger) restore(ctx context.Context, productID int, quantity int, referenceType string, referenceID int, fallbackCost float64, tx *sqlx.Tx) error {
+	if quantity <= 0 {
+		return nil
+	}
+	movements, err := l.movementRepo.GetAllByReferenceQuery(ctx, referenceType, referenceID, tx)
+	if err != nil {
+		return err
+	}
+
+	// Net quantity the document still holds per layer
+	held := make(map[int]int)
+	var layerIDs []int
+	for _, movement := range movements {
+		if movement.ProductID != productID {
+			continue
+		}
+		if _, exists := held[movement.CostLayerID]; !exists {
+			layerIDs = append(layerIDs, movement.CostLayerID)
+		}
+		held[movement.CostLayerID] -= movement.Quantity
+	}
+
+	layers, err := l.layerRepo.GetManyByIDsForUpdateQuery(ctx, layerIDs, tx)
+	if err != nil {
+		return err
+	}
+
+	// Undo FIFO: the newest layer taken from gets its stock back first
+	for i := len(layers) - 1; i >= 0 && quantity > 0; i-- {
+		layer := layers[i]
+		returned := min(held[layer.ID], layer.Quantity-layer.RemainingQuantity, quantity)
+		if returned <= 0 {
+			continue
+		}
+		if err := l.layerRepo.AddRemainingQuantityCommand(ctx, layer.ID, returned, tx); err != nil {
+			return err
+		}
+		movement := &entity.InventoryCostLayerMovement{
+			CostLayerID:   layer.ID,
+			ProductID:     productID,
+			ReferenceType: referenceType,
+			ReferenceID:   &referenceID,
+			Quantity:      returned,
+			UnitCost:      layer.UnitCost,
+		}
+		if err := l.movementRepo.CreateCommand(ctx, movement, tx); err != nil {
+			return err
+		}
+		quantity -= returned
+	}
+
+	return l.add(ctx, &entity.InventoryCostLayer{
+		ProductID:  productID,
+		SourceType: entity.InventoryCostLayerSourceType.RETURN,
+		SourceID:   &referenceID,
+		UnitCost:   fallbackCost,
+		Quantity:   quantity,
+	}, tx)
+}
+
+// consumedByProduct returns per product the quantity the document still holds from the layers and
+// what it cost
+func (l *costLayerLedger) consumedByProduct(ctx context.Context, referenceType string, referenceID int, tx *sqlx.Tx) (map[int]int, map[int]float64, error) {
+	movements, err := l.movementRepo.GetAllByReferenceQuery(ctx, referenceType, referenceID, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	quantities := make(map[int]int)
+	values := make(map[int]float64)
+	for _, movement := range movements {
+		quantities[movement.ProductID] -= movement.Quantity
+		values[movement.ProductID] -= float64(movement.Quantity) * movement.UnitCost
+	}
+	return quantities, values, nil
+}
+
+// fifoIssueValue is the FIFO cost of an issued quantity: the value of the layers it took, with what
+// the layers did not cover (stock from before layers were kept) at the moving-average cost
+func fifoIssueValue(quantity int, layerQuantity int, layerValue float64, averageCost float64) float64 {
+	return layerValue + float64(max(quantity-layerQuantity, 0))*averageCost
+}
+
+type InventoryCostLayerService struct {
+	inventoryCostLayerRepository repository.InventoryCostLayerRepository
+	inventoryRepository          repository.InventoryRepository
+	productRepository            repository.ProductRepository
+	valuationMethod              string
+}
+
+func NewInventoryCostLayerService(
+	inventoryCostLayerRepository repository.InventoryCostLayerRepository,
+	inventoryRepository repository.InventoryRepository,
+	productRepository repository.ProductRepository,
+) service.InventoryCostLayerService {
+	return &InventoryCostLayerService{
+		inventoryCostLayerRepository: inventoryCostLayerRepository,
+		inventoryRepository:          inventoryRepository,
+		productRepository:            productRepository,
+		valuationMethod:              valuationMethodFromEnv(),
+	}
+}
+
+func (s *InventoryCostLayerService) GetValuation(ctx *gin.Context, productID int) (*model.GetInventoryValuationResponse, string) {
+	var products []entity.Product
+	if productID > 0 {
+		product, err := s.productRepository.GetOneByIDQuery(ctx, productID, nil)
+		if err != nil {
+			log.Error("InventoryCostLayerService.GetValuation Error when get product: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if product == nil {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+		products = append(products, *product)
+	} else {
+		var err error
+		products, err = s.productRepository.GetAllQuery(ctx, "", "", nil)
+		if err != nil {
+			log.Error("InventoryCostLayerService.GetValuation Error when get products: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	productIDs := make([]int, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+	}
+	onHandQuantities, err := s.inventoryRepository.GetTotalQuantitiesByProductIDsQuery(ctx, productIDs, nil)
+	if err != nil {
+		log.Error("InventoryCostLayerService.GetValuation Error when get inventory quantities: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	layers, err := s.inventoryCostLayerRepository.GetAllWithFiltersQuery(ctx, productID, true, nil)
+	if err != nil {
+		log.Error("InventoryCostLayerService.GetValuation Error when get cost layers: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	productLayers := make(map[int][]entity.InventoryCostLayer)
+	for _, layer := range layers {
+		productLayers[layer.ProductID] = append(productLayers[layer.ProductID], layer)
+	}
+
+	response := &model.GetInventoryValuationResponse{
+		Method:   s.valuationMethod,
+		Products: []model.ProductValuationResponse{},
+	}
+	for _, product := range products {
+		onHand := onHandQuantities[product.ID]
+		if onHand == 0 && len(productLayers[product.ID]) == 0 {
+			continue
+		}
+
+		productValuation := model.ProductValuationResponse{
+			ProductID:      product.ID,
+			ProductCode:    product.Code,
+			ProductName:    product.Name,
+			OnHandQuantity: onHand,
+			AverageCost:    product.Cost,
+			AverageValue:   roundCost(float64(onHand) * product.Cost),
+			Layers:         make([]model.InventoryCostLayerResponse, 0, len(productLayers[product.ID])),
+		}
+		for _, layer := range productLayers[product.ID] {
+			layerValue := roundCost(float64(layer.RemainingQuantity) * layer.UnitCost)
+			productValuation.Layers = append(productValuation.Layers, model.InventoryCostLayerResponse{
+				ID:                layer.ID,
+				SourceType:        layer.SourceType,
+				SourceID:          layer.SourceID,
+				ReceivedAt:        layer.ReceivedAt,
+				UnitCost:          layer.UnitCost,
+				Quantity:          layer.Quantity,
+				RemainingQuantity: layer.RemainingQuantity,
+				Value:             layerValue,
+			})
+			productValuation.LayerQuantity += layer.RemainingQuantity
+			productValuation.FifoValue += layerValue
+		}
+		productValuation.FifoValue = roundCost(productValuation.FifoValue)
+
+		productValuation.Value = productValuation.AverageValue
+		if s.valuationMethod == entity.InventoryValuationMethod.FIFO {
+			productValuation.Value = productValuation.FifoValue
+		}
+		response.TotalValue += productValuation.Value
+		response.Products = append(response.Products, productValuation)
+	}
+	response.TotalValue = roundCost(response.TotalValue)
+
+	return response, ""
+}
@@ -36,12 +36,14 @@ func (s *InventoryHistoryService) GetAll(ctx *gin.Context, productID int) (*mode
 		inventoryHistoryResponses[i] = model.InventoryHistoryResponse{
 			ID:            inventoryHistory.ID,
 			ProductID:     inventoryHistory.ProductID,
+			WarehouseID:   inventoryHistory.WarehouseID,
 			Quantity:      inventoryHistory.Quantity,
 			FinalQuantity: inventoryHistory.FinalQuantity,
 			ImporterName:  inventoryHistory.ImporterName,
 			ImportedAt:    inventoryHistory.ImportedAt,
 			Note:          inventoryHistory.Note,
 			ReferenceID:   inventoryHistory.ReferenceID,
+			ReferenceType: inventoryHistory.ReferenceType,
 		}
 	}
 
@@ -54,6 +56,7 @@ func (s *InventoryHistoryService) Create(ctx *gin.Context, request model.CreateI
 	// Create inventory history entity
 	inventoryHistory := &entity.InventoryHistory{
 		ProductID:    request.ProductID,
+		WarehouseID:  request.WarehouseID,
 		Quantity:     request.Quantity,
 		ImporterName: request.ImporterName,
 		ImportedAt:   time.Now(),
@@ -71,11 +74,13 @@ func (s *InventoryHistoryService) Create(ctx *gin.Context, request model.CreateI
 	return &model.InventoryHistoryResponse{
 		ID:            inventoryHistory.ID,
 		ProductID:     inventoryHistory.ProductID,
+		WarehouseID:   inventoryHistory.WarehouseID,
 		Quantity:      inventoryHistory.Quantity,
 		FinalQuantity: inventoryHistory.FinalQuantity,
 		ImporterName:  inventoryHistory.ImporterName,
 		ImportedAt:    inventoryHistory.ImportedAt,
 		Note:          inventoryHistory.Note,
 		ReferenceID:   inventoryHistory.ReferenceID,
+		ReferenceType: inventoryHistory.ReferenceType,
 	}, ""
 }
@@ -0,0 +1,450 @@
+package serviceimplement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// lotTrackedCategoryCode is the category whose products must be received with a lot number and an expiry date
+const lotTrackedCategoryCode = "HOA_CHAT"
+
+type InventoryLotService struct {
+	inventoryLotRepository     repository.InventoryLotRepository
+	productRepository          repository.ProductRepository
+	warehouseRepository        repository.WarehouseRepository
+	inventoryRepository        repository.InventoryRepository
+	inventoryHistoryRepository repository.InventoryHistoryRepository
+	userRepository             repository.UserRepository
+	costLayers                 *costLayerLedger
+	unitOfWork                 repository.UnitOfWork
+}
+
+func NewInventoryLotService(
+	inventoryLotRepository repository.InventoryLotRepository,
+	productRepository repository.ProductRepository,
+	warehouseRepository repository.WarehouseRepository,
+	inventoryRepository repository.InventoryRepository,
+	inventoryHistoryRepository repository.InventoryHistoryRepository,
+	userRepository repository.UserRepository,
+	inventoryCostLayerRepository repository.InventoryCostLayerRepository,
+	inventoryCostLayerMovementRepository repository.InventoryCostLayerMovementRepository,
+	unitOfWork repository.UnitOfWork,
+) service.InventoryLotService {
+	return &InventoryLotService{
+		inventoryLotRepository:     inventoryLotRepository,
+		productRepository:          productRepository,
+		warehouseRepository:        warehouseRepository,
+		inventoryRepository:        inventoryRepository,
+		inventoryHistoryRepository: inventoryHistoryRepository,
+		userRepository:             userRepository,
+		costLayers:                 newCostLayerLedger(inventoryCostLayerRepository, inventoryCostLayerMovementRepository),
+		unitOfWork:                 unitOfWork,
+	}
+}
+
+// lotDraw is the quantity taken from one lot
+type lotDraw struct {
+	lot      entity.InventoryLot
+	quantity int
+}
+
+// isLotTracked tells whether the product belongs to the category that is tracked by lot
+func isLotTracked(ctx context.Context, categoryRepo repository.ProductCategoryRepository, product *entity.Product, tx *sqlx.Tx) (bool, error) {
+	if product.CategoryID == nil {
+		return false, nil
+	}
+	category, err := categoryRepo.GetOneByIDQuery(ctx, *product.CategoryID, tx)
+	if err != nil {
+		return false, err
+	}
+	return category != nil && category.Code == lotTrackedCategoryCode, nil
+}
+
+// sameExpiryDate compares expiry dates by calendar day, the column has no time part
+func sameExpiryDate(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Format(time.DateOnly) == b.Format(time.DateOnly)
+}
+
+// receiveLot adds the quantity to the lot of the product in the warehouse, creating the lot on
+// its first receipt. A lot keeps the expiry date it was first received with.
+func receiveLot(ctx context.Context, lotRepo repository.InventoryLotRepository, productID int, warehouseID int, lotNumber string, expiryDate *time.Time, quantity int, tx *sqlx.Tx) (*entity.InventoryLot, string) {
+	lot, err := lotRepo.GetOneByLotNumberQuery(ctx, productID, warehouseID, lotNumber, tx)
+	if err != nil {
+		log.Error("receiveLot Error when get lot: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if lot == nil {
+		lot = &entity.InventoryLot{
+			ProductID:   productID,
+			WarehouseID: warehouseID,
+			LotNumber:   lotNumber,
+			ExpiryDate:  expiryDate,
+			Quantity:    quantity,
+		}
+		if err := lotRepo.CreateCommand(ctx, lot, tx); err != nil {
+			log.Error("receiveLot Error when create lot: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		return lot, ""
+	}
+
+	if !sameExpiryDate(lot.ExpiryDate, expiryDate) {
+		return nil, error_utils.ErrorCode.LOT_EXPIRY_MISMATCH
+	}
+	if err := lotRepo.AddQuantityCommand(ctx, lot.ID, quantity, tx); err != nil {
+		log.Error("receiveLot Error when update lot: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	lot.Quantity += quantity
+	return lot, ""
+}
+
+// errInsufficientUnexpiredStock is returned when only expired lots are left to cover an issue
+var errInsufficientUnexpiredStock = errors.New("unexpired stock cannot cover the issue")
+
+// drawLotsFEFO takes quantity of the product from its unexpired lots in the warehouse, earliest
+// expiry first. What the lots cannot cover comes from stock received without a lot number; expired
+// lots are never issued, they are written off with writeOffLot. onHand is the inventory quantity
+// before the issue, the caller must hold the lock on the inventory.
+func drawLotsFEFO(ctx context.Context, lotRepo repository.InventoryLotRepository, productID int, warehouseID int, onHand int, quantity int, tx *sqlx.Tx) ([]lotDraw, error) {
+	lotTotal, err := lotRepo.GetTotalQuantityQuery(ctx, productID, warehouseID, tx)
+	if err != nil {
+		return nil, err
+	}
+	untracked := max(onHand-lotTotal, 0)
+
+	lots, err := lotRepo.GetAvailableForUpdateQuery(ctx, productID, warehouseID, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var draws []lotDraw
+	for _, lot := range lots {
+		if quantity <= 0 {
+			break
+		}
+		taken := min(lot.Quantity, quantity)
+		if err := lotRepo.AddQuantityCommand(ctx, lot.ID, -taken, tx); err != nil {
+			return nil, err
+		}
+		draws = append(draws, lotDraw{lot: lot, quantity: taken})
+		quantity -= taken
+	}
+	if quantity > untracked {
+		return nil, errInsufficientUnexpiredStock
+	}
+	return draws, nil
+}
+
+// drawOrderLots takes stock issued for the order from the product's lots FEFO and records the
+// lots drawn against the order item selling the product, or against the order alone when the
+// product is a material of what was sold
+func drawOrderLots(ctx context.Context, lotRepo repository.InventoryLotRepository, orderItemLotRepo repository.OrderItemLotRepository, orderItemRepo repository.OrderItemRepository, order *entity.Order, productID int, onHand int, quantity int, tx *sqlx.Tx) error {
+	draws, err := drawLotsFEFO(ctx, lotRepo, productID, order.WarehouseID, onHand, quantity, tx)
+	if err != nil || len(draws) == 0 {
+		return err
+	}
+
+	orderItems, err := orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		return err
+	}
+	var orderItemID *int
+	for _, item := range orderItems {
+		if item.ProductID == productID {
+			orderItemID = &item.ID
+			break
+		}
+	}
+
+	for _, draw := range draws {
+		orderItemLot := &entity.OrderItemLot{
+			OrderID:        order.ID,
+			OrderItemID:    orderItemID,
+			ProductID:      productID,
+			InventoryLotID: draw.lot.ID,
+			Quantity:       draw.quantity,
+		}
+		if err := orderItemLotRepo.CreateCommand(ctx, orderItemLot, tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returnOrderLots puts stock coming back from the order into the lots the order drew it from,
+// latest expiry first. What the order did not draw from a lot goes back untracked.
+func returnOrderLots(ctx context.Context, lotRepo repository.InventoryLotRepository, orderItemLotRepo repository.OrderItemLotRepository, orderID int, productID int, quantity int, tx *sqlx.Tx) error {
+	entries, err := orderItemLotRepo.GetAllByOrderIDQuery(ctx, orderID, tx)
+	if err != nil {
+		return err
+	}
+
+	// Net quantity the order holds per lot and order item
+	type holding struct {
+		lotID       int
+		orderItemID *int
+		quantity    int
+	}
+	var holdings []*holding
+	holdingIndex := make(map[[2]int]*holding)
+	var lotIDs []int
+	for _, entry := range entries {
+		if entry.ProductID != productID {
+			continue
+		}
+		key := [2]int{entry.InventoryLotID, 0}
+		if entry.OrderItemID != nil {
+			key[1] = *entry.OrderItemID
+		}
+		h, exists := holdingIndex[key]
+		if !exists {
+			h = &holding{lotID: entry.InventoryLotID, orderItemID: entry.OrderItemID}
+			holdingIndex[key] = h
+			holdings = append(holdings, h)
+			lotIDs = append(lotIDs, entry.InventoryLotID)
+		}
+		h.quantity += entry.Quantity
+	}
+	if len(holdings) == 0 {
+		return nil
+	}
+
+	lots, err := lotRepo.GetManyByIDsForUpdateQuery(ctx, lotIDs, tx)
+	if err != nil {
+		return err
+	}
+
+	// Undo FEFO: the latest expiring lot drawn goes back first
+	for i := len(lots) - 1; i >= 0 && quantity > 0; i-- {
+		for _, h := range holdings {
+			if h.lotID != lots[i].ID || h.quantity <= 0 || quantity <= 0 {
+				continue
+			}
+			returned := min(h.quantity, quantity)
+			if err := lotRepo.AddQuantityCommand(ctx, h.lotID, returned, tx); err != nil {
+				return err
+			}
+			orderItemLot := &entity.OrderItemLot{
+				OrderID:        orderID,
+				OrderItemID:    h.orderItemID,
+				ProductID:      productID,
+				InventoryLotID: h.lotID,
+				Quantity:       -returned,
+			}
+			if err := orderItemLotRepo.CreateCommand(ctx, orderItemLot, tx); err != nil {
+				return err
+			}
+			h.quantity -= returned
+			quantity -= returned
+		}
+	}
+	return nil
+}
+
+// getNetOrderItemLots sums the lot ledger of the order per order item and lot, dropping what
+// has been fully put back
+func getNetOrderItemLots(entries []entity.OrderItemLot) map[int]map[int]int {
+	netLots := make(map[int]map[int]int) // orderItemID -> lotID -> quantity
+	for _, entry := range entries {
+		if entry.OrderItemID == nil {
+			continue
+		}
+		if netLots[*entry.OrderItemID] == nil {
+			netLots[*entry.OrderItemID] = make(map[int]int)
+		}
+		netLots[*entry.OrderItemID][entry.InventoryLotID] += entry.Quantity
+	}
+	for orderItemID, lots := range netLots {
+		for lotID, quantity := range lots {
+			if quantity <= 0 {
+				delete(lots, lotID)
+			}
+		}
+		if len(lots) == 0 {
+			delete(netLots, orderItemID)
+		}
+	}
+	return netLots
+}
+
+// toInventoryLotResponses adds the product and warehouse names to the lots
+func toInventoryLotResponses(ctx context.Context, productRepo repository.ProductRepository, warehouseRepo repository.WarehouseRepository, lots []entity.InventoryLot) ([]model.InventoryLotResponse, string) {
+	warehouses, err := warehouseRepo.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("toInventoryLotResponses Error when get warehouses: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	warehouseNames := make(map[int]string, len(warehouses))
+	for _, warehouse := range warehouses {
+		warehouseNames[warehouse.ID] = warehouse.Name
+	}
+
+	today := time.Now().Format(time.DateOnly)
+	productNames := make(map[int]string)
+	lotResponses := make([]model.InventoryLotResponse, len(lots))
+	for i, lot := range lots {
+		productName, exists := productNames[lot.ProductID]
+		if !exists {
+			product, err := productRepo.GetOneByIDQuery(ctx, lot.ProductID, nil)
+			if err != nil {
+				log.Error("toInventoryLotResponses Error when get product: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			if product != nil {
+				productName = product.Name
+			}
+			productNames[lot.ProductID] = productName
+		}
+
+		lotResponses[i] = model.InventoryLotResponse{
+			ID:            lot.ID,
+			ProductID:     lot.ProductID,
+			ProductName:   productName,
+			WarehouseID:   lot.WarehouseID,
+			WarehouseName: warehouseNames[lot.WarehouseID],
+			LotNumber:     lot.LotNumber,
+			ExpiryDate:    lot.ExpiryDate,
+			Quantity:      lot.Quantity,
+			IsExpired:     lot.ExpiryDate != nil && lot.ExpiryDate.Format(time.DateOnly) < today,
+		}
+	}
+	return lotResponses, ""
+}
+
+func (s *InventoryLotService) GetAll(ctx *gin.Context, productID int, warehouseID int) (*model.GetAllInventoryLotsResponse, string) {
+	lots, err := s.inventoryLotRepository.GetAllWithFiltersQuery(ctx, productID, warehouseID, "", true, nil)
+	if err != nil {
+		log.Error("InventoryLotService.GetAll Error when get lots: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	lotResponses, errCode := toInventoryLotResponses(ctx, s.productRepository, s.warehouseRepository, lots)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	return &model.GetAllInventoryLotsResponse{
+		Lots: lotResponses,
+	}, ""
+}
+
+// WriteOff takes stock out of one lot, the way expired lots that FEFO no longer issues are disposed of
+func (s *InventoryLotService) WriteOff(ctx *gin.Context, lotID int, request model.WriteOffInventoryLotRequest, userID int) (*model.InventoryLotResponse, string) {
+	user, err := s.userRepository.FindByIDQuery(ctx, userID, nil)
+	if err != nil {
+		log.Error("InventoryLotService.WriteOff Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("InventoryLotService.WriteOff Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("InventoryLotService.WriteOff Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	lots, err := s.inventoryLotRepository.GetManyByIDsQuery(ctx, []int{lotID}, tx)
+	if err != nil {
+		log.Error("InventoryLotService.WriteOff Error when get lot: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if len(lots) == 0 {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// The inventory is locked before the lot, as everywhere else
+	inventoryMap, errCode := lockInventories(ctx, s.inventoryRepository, []int{lots[0].ProductID}, lots[0].WarehouseID, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+	inventory := inventoryMap[lots[0].ProductID]
+
+	lot, err := s.inventoryLotRepository.GetOneByIDForUpdateQuery(ctx, lotID, tx)
+	if err != nil {
+		log.Error("InventoryLotService.WriteOff Error when lock lot: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if lot == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if request.Quantity > lot.Quantity || request.Quantity > inventory.Quantity {
+		return nil, error_utils.ErrorCode.LOT_QUANTITY_EXCEEDED
+	}
+
+	err = s.inventoryRepository.UpdateQuantityCommand(ctx, lot.ProductID, lot.WarehouseID, -request.Quantity, uuid.New().String(), tx)
+	if err != nil {
+		log.Error("InventoryLotService.WriteOff Error when update inventory: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if err := s.inventoryLotRepository.AddQuantityCommand(ctx, lot.ID, -request.Quantity, tx); err != nil {
+		log.Error("InventoryLotService.WriteOff Error when update lot: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	lot.Quantity -= request.Quantity
+
+	// The stock written off leaves the oldest cost layers, like any other write-off
+	_, err = s.costLayers.consume(ctx, lot.ProductID, request.Quantity, entity.InventoryCostLayerReferenceType.INVENTORY_ADJUSTMENT, nil, tx)
+	if err != nil {
+		log.Error("InventoryLotService.WriteOff Error when update cost layers: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	note := fmt.Sprintf("Hủy hàng lô %s", lot.LotNumber)
+	if request.Note != "" {
+		note += fmt.Sprintf(" - %s", request.Note)
+	}
+	inventoryHistory := &entity.InventoryHistory{
+		ProductID:     lot.ProductID,
+		WarehouseID:   &lot.WarehouseID,
+		Quantity:      -request.Quantity,
+		FinalQuantity: inventory.Quantity - request.Quantity,
+		ImporterName:  user.Username,
+		ImportedAt:    time.Now(),
+		Note:          note,
+	}
+	err = s.inventoryHistoryRepository.CreateCommand(ctx, inventoryHistory, tx)
+	if err != nil {
+		log.Error("InventoryLotService.WriteOff Error when create inventory history: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("InventoryLotService.WriteOff Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	lotResponses, errCode := toInventoryLotResponses(ctx, s.productRepository, s.warehouseRepository, []entity.InventoryLot{*lot})
+	if errCode != "" {
+		return nil, errCode
+	}
+	return &lotResponses[0], ""
+}
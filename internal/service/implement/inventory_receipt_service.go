@@ -21,7 +21,16 @@ type InventoryReceiptService struct {
 	inventoryHistoryRepository     repository.InventoryHistoryRepository
 	userRepository                 repository.UserRepository
 	productRepository              repository.ProductRepository
+	supplierRepository             repository.SupplierRepository
+	purchaseOrderRepository        repository.PurchaseOrderRepository
+	purchaseOrderItemRepository    repository.PurchaseOrderItemRepository
+	warehouseRepository            repository.WarehouseRepository
+	productCategoryRepository      repository.ProductCategoryRepository
+	inventoryLotRepository         repository.InventoryLotRepository
+	productCostHistoryRepository   repository.ProductCostHistoryRepository
+	costLayers                     *costLayerLedger
 	unitOfWork                     repository.UnitOfWork
+	unitConverter                  *unitConverter
 }
 
 func NewInventoryReceiptService(
@@ -32,6 +41,16 @@ func NewInventoryReceiptService(
 	userRepository repository.UserRepository,
 	productRepository repository.ProductRepository,
 	unitOfWork repository.UnitOfWork,
+	unitConversionRepository repository.UnitConversionRepository,
+	supplierRepository repository.SupplierRepository,
+	purchaseOrderRepository repository.PurchaseOrderRepository,
+	purchaseOrderItemRepository repository.PurchaseOrderItemRepository,
+	warehouseRepository repository.WarehouseRepository,
+	productCategoryRepository repository.ProductCategoryRepository,
+	inventoryLotRepository repository.InventoryLotRepository,
+	productCostHistoryRepository repository.ProductCostHistoryRepository,
+	inventoryCostLayerRepository repository.InventoryCostLayerRepository,
+	inventoryCostLayerMovementRepository repository.InventoryCostLayerMovementRepository,
 ) service.InventoryReceiptService {
 	return &InventoryReceiptService{
 		inventoryReceiptRepository:     inventoryReceiptRepository,
@@ -40,7 +59,16 @@ func NewInventoryReceiptService(
 		inventoryHistoryRepository:     inventoryHistoryRepository,
 		userRepository:                 userRepository,
 		productRepository:              productRepository,
+		supplierRepository:             supplierRepository,
+		purchaseOrderRepository:        purchaseOrderRepository,
+		purchaseOrderItemRepository:    purchaseOrderItemRepository,
+		warehouseRepository:            warehouseRepository,
+		productCategoryRepository:      productCategoryRepository,
+		inventoryLotRepository:         inventoryLotRepository,
+		productCostHistoryRepository:   productCostHistoryRepository,
+		costLayers:                     newCostLayerLedger(inventoryCostLayerRepository, inventoryCostLayerMovementRepository),
 		unitOfWork:                     unitOfWork,
+		unitConverter:                  newUnitConverter(unitConversionRepository),
 	}
 }
 
@@ -71,6 +99,69 @@ func (s *InventoryReceiptService) Create(ctx *gin.Context, request model.CreateI
 		}
 	}()
 
+	// Lines booked against a purchase order must match its line, and the receipt takes the order's supplier
+	purchaseOrderItems := make(map[int]*entity.PurchaseOrderItem)
+	purchaseOrders := make(map[int]*entity.PurchaseOrder)
+	supplierID := request.SupplierID
+	for _, itemRequest := range request.Items {
+		if itemRequest.PurchaseOrderItemID == nil {
+			continue
+		}
+
+		purchaseOrderItem, err := s.purchaseOrderItemRepository.GetOneByIDQuery(ctx, *itemRequest.PurchaseOrderItemID, tx)
+		if err != nil {
+			log.Error("InventoryReceiptService.Create Error when get purchase order item: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if purchaseOrderItem == nil {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+		if purchaseOrderItem.ProductID != itemRequest.ProductID {
+			return nil, error_utils.ErrorCode.PURCHASE_ORDER_ITEM_MISMATCH
+		}
+
+		// Lock the order so that concurrent receipts see each other's deliveries
+		purchaseOrder, exists := purchaseOrders[purchaseOrderItem.PurchaseOrderID]
+		if !exists {
+			purchaseOrder, err = s.purchaseOrderRepository.GetOneByIDForUpdateQuery(ctx, purchaseOrderItem.PurchaseOrderID, tx)
+			if err != nil {
+				log.Error("InventoryReceiptService.Create Error when get purchase order: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			if purchaseOrder == nil {
+				return nil, error_utils.ErrorCode.NOT_FOUND
+			}
+			purchaseOrders[purchaseOrder.ID] = purchaseOrder
+		}
+		if !isPurchaseOrderReceivable(purchaseOrder) {
+			return nil, error_utils.ErrorCode.PURCHASE_ORDER_NOT_RECEIVABLE
+		}
+		if supplierID == nil {
+			supplierID = &purchaseOrder.SupplierID
+		} else if *supplierID != purchaseOrder.SupplierID {
+			return nil, error_utils.ErrorCode.PURCHASE_ORDER_ITEM_MISMATCH
+		}
+
+		purchaseOrderItems[purchaseOrderItem.ID] = purchaseOrderItem
+	}
+
+	if supplierID != nil {
+		supplier, err := s.supplierRepository.GetOneByIDQuery(ctx, *supplierID, tx)
+		if err != nil {
+			log.Error("InventoryReceiptService.Create Error when get supplier: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if supplier == nil {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+	}
+
+	// Goods are received into the given warehouse, the default one when none is given
+	warehouse, errCode := resolveWarehouse(ctx, s.warehouseRepository, request.WarehouseID, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
 	// Set receipt date to now if not provided
 	receiptDate := request.ReceiptDate
 	if receiptDate.IsZero() {
@@ -80,6 +171,8 @@ func (s *InventoryReceiptService) Create(ctx *gin.Context, request model.CreateI
 	// Create inventory receipt entity
 	inventoryReceipt := &entity.InventoryReceipt{
 		UserID:      request.UserID,
+		SupplierID:  supplierID,
+		WarehouseID: warehouse.ID,
 		ReceiptDate: receiptDate,
 		Notes:       request.Notes,
 		TotalItems:  len(request.Items),
@@ -106,24 +199,81 @@ func (s *InventoryReceiptService) Create(ctx *gin.Context, request model.CreateI
 			return nil, error_utils.ErrorCode.NOT_FOUND
 		}
 
+		// Chemicals expire and are traced by lot, they cannot be received without a lot number and an expiry date
+		hasLot := itemRequest.LotNumber != nil && *itemRequest.LotNumber != ""
+		if !hasLot && itemRequest.ExpiryDate != nil {
+			return nil, error_utils.ErrorCode.BAD_REQUEST
+		}
+		if !hasLot || itemRequest.ExpiryDate == nil {
+			lotTracked, err := isLotTracked(ctx, s.productCategoryRepository, product, tx)
+			if err != nil {
+				log.Error("InventoryReceiptService.Create Error when get product category: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			if lotTracked {
+				return nil, error_utils.ErrorCode.LOT_REQUIRED
+			}
+		}
+
+		// Items may be received in an alternate unit (VD: thùng, lít), stock is kept in the base unit
+		factor, errCode := s.unitConverter.baseFactor(ctx, product, itemRequest.UnitID, tx)
+		if errCode != "" {
+			return nil, errCode
+		}
+		quantity, errCode := toWholeBaseQuantity(itemRequest.Quantity, factor)
+		if errCode != "" {
+			return nil, errCode
+		}
+		unitCost := itemRequest.UnitCost
+		if unitCost != nil && factor != 1 {
+			baseUnitCost := *unitCost / factor
+			unitCost = &baseUnitCost
+		}
+
+		// A delivery against a purchase order line counts towards what the line has received
+		historyNote := fmt.Sprintf("Nhập kho từ phiếu nhập %s", inventoryReceipt.Code)
+		if itemRequest.PurchaseOrderItemID != nil {
+			purchaseOrderItem := purchaseOrderItems[*itemRequest.PurchaseOrderItemID]
+			if quantity <= 0 {
+				return nil, error_utils.ErrorCode.BAD_REQUEST
+			}
+			received, err := s.purchaseOrderItemRepository.AddReceivedQuantityCommand(ctx, purchaseOrderItem.ID, quantity, tx)
+			if err != nil {
+				log.Error("InventoryReceiptService.Create Error when update purchase order item: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			if !received {
+				return nil, error_utils.ErrorCode.PURCHASE_ORDER_OVER_RECEIPT
+			}
+
+			// Without a price on the receipt the line is valued at the ordered price
+			if unitCost == nil {
+				unitCost = purchaseOrderItem.UnitCost
+			}
+			historyNote += fmt.Sprintf(" theo đơn mua hàng %s", purchaseOrders[purchaseOrderItem.PurchaseOrderID].Code)
+		}
+
 		// Create receipt item entity
 		receiptItem := &entity.InventoryReceiptItem{
-			InventoryReceiptID: inventoryReceipt.ID,
-			ProductID:          itemRequest.ProductID,
-			Quantity:           itemRequest.Quantity,
-			UnitCost:           itemRequest.UnitCost,
-			Notes:              itemRequest.Notes,
+			InventoryReceiptID:  inventoryReceipt.ID,
+			ProductID:           itemRequest.ProductID,
+			PurchaseOrderItemID: itemRequest.PurchaseOrderItemID,
+			Quantity:            quantity,
+			UnitCost:            unitCost,
+			Notes:               itemRequest.Notes,
 		}
 
-		// Create receipt item
-		err = s.inventoryReceiptItemRepository.CreateCommand(ctx, receiptItem, tx)
-		if err != nil {
-			log.Error("InventoryReceiptService.Create Error when create receipt item: " + err.Error())
-			return nil, error_utils.ErrorCode.DB_DOWN
+		// A priced receipt moves the product's weighted-average cost, before the stock it adds is counted
+		if unitCost != nil && quantity > 0 {
+			err = applyReceiptCost(ctx, s.productRepository, s.inventoryRepository, s.productCostHistoryRepository, itemRequest.ProductID, quantity, *unitCost, inventoryReceipt.ID, user.Username, tx)
+			if err != nil {
+				log.Error("InventoryReceiptService.Create Error when update product cost: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
 		}
 
 		// Get current inventory for this product
-		inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, itemRequest.ProductID, tx)
+		inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, itemRequest.ProductID, warehouse.ID, tx)
 		if err != nil {
 			log.Error("InventoryReceiptService.Create Error when get inventory: " + err.Error())
 			return nil, error_utils.ErrorCode.DB_DOWN
@@ -133,42 +283,84 @@ func (s *InventoryReceiptService) Create(ctx *gin.Context, request model.CreateI
 		if inventory == nil {
 			// Create new inventory record if doesn't exist
 			newInventory := &entity.Inventory{
-				ProductID: itemRequest.ProductID,
-				Quantity:  itemRequest.Quantity,
-				Version:   uuid.New().String(),
+				ProductID:   itemRequest.ProductID,
+				WarehouseID: warehouse.ID,
+				Quantity:    quantity,
+				Version:     uuid.New().String(),
 			}
 			err = s.inventoryRepository.CreateCommand(ctx, newInventory, tx)
 			if err != nil {
 				log.Error("InventoryReceiptService.Create Error when create inventory: " + err.Error())
 				return nil, error_utils.ErrorCode.DB_DOWN
 			}
-			finalQuantity = itemRequest.Quantity
+			finalQuantity = quantity
 		} else {
 			// Update existing inventory
 			newVersion := uuid.New().String()
-			s.inventoryRepository.GetOneByIDForUpdateQuery(ctx, itemRequest.ProductID, tx)
-			err = s.inventoryRepository.UpdateQuantityWithVersionCommand(ctx, itemRequest.ProductID, itemRequest.Quantity, inventory.Version, newVersion, tx)
+			s.inventoryRepository.GetOneByIDForUpdateQuery(ctx, inventory.ID, tx)
+			err = s.inventoryRepository.UpdateQuantityWithVersionCommand(ctx, itemRequest.ProductID, warehouse.ID, quantity, inventory.Version, newVersion, tx)
 			if err != nil {
 				log.Error("InventoryReceiptService.Create Error when update inventory: " + err.Error())
 				return nil, error_utils.ErrorCode.DB_DOWN
 			}
-			finalQuantity = inventory.Quantity + itemRequest.Quantity
+			finalQuantity = inventory.Quantity + quantity
+		}
+
+		// Stock received with a lot number is also booked to the lot, once the inventory is locked
+		if hasLot {
+			if quantity <= 0 {
+				return nil, error_utils.ErrorCode.BAD_REQUEST
+			}
+			lot, errCode := receiveLot(ctx, s.inventoryLotRepository, itemRequest.ProductID, warehouse.ID, *itemRequest.LotNumber, itemRequest.ExpiryDate, quantity, tx)
+			if errCode != "" {
+				return nil, errCode
+			}
+			receiptItem.LotNumber = &lot.LotNumber
+			receiptItem.ExpiryDate = lot.ExpiryDate
+			receiptItem.InventoryLotID = &lot.ID
+			historyNote += fmt.Sprintf(" - Lô %s", lot.LotNumber)
+		}
+
+		// Create receipt item
+		err = s.inventoryReceiptItemRepository.CreateCommand(ctx, receiptItem, tx)
+		if err != nil {
+			log.Error("InventoryReceiptService.Create Error when create receipt item: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+
+		// Each receipt line opens a FIFO cost layer, an unpriced one at the product's current cost
+		layerCost := product.Cost
+		if unitCost != nil {
+			layerCost = *unitCost
+		}
+		err = s.costLayers.add(ctx, &entity.InventoryCostLayer{
+			ProductID:              itemRequest.ProductID,
+			SourceType:             entity.InventoryCostLayerSourceType.INVENTORY_RECEIPT,
+			SourceID:               &inventoryReceipt.ID,
+			InventoryReceiptItemID: &receiptItem.ID,
+			UnitCost:               layerCost,
+			Quantity:               quantity,
+		}, tx)
+		if err != nil {
+			log.Error("InventoryReceiptService.Create Error when create cost layer: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
 		}
 
 		// Create inventory history record
-		historyNote := fmt.Sprintf("Nhập kho từ phiếu nhập %s", inventoryReceipt.Code)
 		if itemRequest.Notes != nil && *itemRequest.Notes != "" {
 			historyNote += fmt.Sprintf(" - %s", *itemRequest.Notes)
 		}
 
 		inventoryHistory := &entity.InventoryHistory{
 			ProductID:     itemRequest.ProductID,
-			Quantity:      itemRequest.Quantity,
+			WarehouseID:   &warehouse.ID,
+			Quantity:      quantity,
 			FinalQuantity: finalQuantity,
 			ImporterName:  user.Username,
 			ImportedAt:    time.Now(),
 			Note:          historyNote,
 			ReferenceID:   &inventoryReceipt.ID,
+			ReferenceType: &entity.InventoryHistoryReferenceType.INVENTORY_RECEIPT,
 		}
 
 		err = s.inventoryHistoryRepository.CreateCommand(ctx, inventoryHistory, tx)
@@ -179,17 +371,36 @@ func (s *InventoryReceiptService) Create(ctx *gin.Context, request model.CreateI
 
 		// Add to response items
 		itemResponses = append(itemResponses, model.InventoryReceiptItemResponse{
-			ID:                 receiptItem.ID,
-			InventoryReceiptID: receiptItem.InventoryReceiptID,
-			ProductID:          receiptItem.ProductID,
-			Quantity:           receiptItem.Quantity,
-			UnitCost:           receiptItem.UnitCost,
-			Notes:              receiptItem.Notes,
-			CreatedAt:          receiptItem.CreatedAt,
-			UpdatedAt:          receiptItem.UpdatedAt,
+			ID:                  receiptItem.ID,
+			InventoryReceiptID:  receiptItem.InventoryReceiptID,
+			ProductID:           receiptItem.ProductID,
+			PurchaseOrderItemID: receiptItem.PurchaseOrderItemID,
+			Quantity:            receiptItem.Quantity,
+			UnitCost:            receiptItem.UnitCost,
+			LotNumber:           receiptItem.LotNumber,
+			ExpiryDate:          receiptItem.ExpiryDate,
+			InventoryLotID:      receiptItem.InventoryLotID,
+			Notes:               receiptItem.Notes,
+			CreatedAt:           receiptItem.CreatedAt,
+			UpdatedAt:           receiptItem.UpdatedAt,
 		})
 	}
 
+	// Orders close once every line has been received in full
+	for _, purchaseOrder := range purchaseOrders {
+		purchaseOrderLines, err := s.purchaseOrderItemRepository.GetAllByPurchaseOrderIDQuery(ctx, purchaseOrder.ID, tx)
+		if err != nil {
+			log.Error("InventoryReceiptService.Create Error when get purchase order items: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		purchaseOrder.Status = purchaseOrderReceiptStatus(purchaseOrderLines)
+		err = s.purchaseOrderRepository.UpdateCommand(ctx, purchaseOrder, tx)
+		if err != nil {
+			log.Error("InventoryReceiptService.Create Error when update purchase order: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
 	// Commit transaction
 	err = s.unitOfWork.Commit(tx)
 	if err != nil {
@@ -202,6 +413,8 @@ func (s *InventoryReceiptService) Create(ctx *gin.Context, request model.CreateI
 		ID:          inventoryReceipt.ID,
 		Code:        inventoryReceipt.Code,
 		UserID:      inventoryReceipt.UserID,
+		SupplierID:  inventoryReceipt.SupplierID,
+		WarehouseID: inventoryReceipt.WarehouseID,
 		ReceiptDate: inventoryReceipt.ReceiptDate,
 		Notes:       inventoryReceipt.Notes,
 		TotalItems:  inventoryReceipt.TotalItems,
@@ -226,6 +439,8 @@ func (s *InventoryReceiptService) GetAll(ctx *gin.Context) (*model.GetAllInvento
 			ID:          receipt.ID,
 			Code:        receipt.Code,
 			UserID:      receipt.UserID,
+			SupplierID:  receipt.SupplierID,
+			WarehouseID: receipt.WarehouseID,
 			ReceiptDate: receipt.ReceiptDate,
 			Notes:       receipt.Notes,
 			TotalItems:  receipt.TotalItems,
@@ -263,14 +478,18 @@ func (s *InventoryReceiptService) GetOne(ctx *gin.Context, id int) (*model.GetOn
 	itemResponses := make([]model.InventoryReceiptItemResponse, len(receiptItems))
 	for i, item := range receiptItems {
 		itemResponses[i] = model.InventoryReceiptItemResponse{
-			ID:                 item.ID,
-			InventoryReceiptID: item.InventoryReceiptID,
-			ProductID:          item.ProductID,
-			Quantity:           item.Quantity,
-			UnitCost:           item.UnitCost,
-			Notes:              item.Notes,
-			CreatedAt:          item.CreatedAt,
-			UpdatedAt:          item.UpdatedAt,
+			ID:                  item.ID,
+			InventoryReceiptID:  item.InventoryReceiptID,
+			ProductID:           item.ProductID,
+			PurchaseOrderItemID: item.PurchaseOrderItemID,
+			Quantity:            item.Quantity,
+			UnitCost:            item.UnitCost,
+			LotNumber:           item.LotNumber,
+			ExpiryDate:          item.ExpiryDate,
+			InventoryLotID:      item.InventoryLotID,
+			Notes:               item.Notes,
+			CreatedAt:           item.CreatedAt,
+			UpdatedAt:           item.UpdatedAt,
 		}
 	}
 
@@ -280,6 +499,8 @@ func (s *InventoryReceiptService) GetOne(ctx *gin.Context, id int) (*model.GetOn
 			ID:          receipt.ID,
 			Code:        receipt.Code,
 			UserID:      receipt.UserID,
+			SupplierID:  receipt.SupplierID,
+			WarehouseID: receipt.WarehouseID,
 			ReceiptDate: receipt.ReceiptDate,
 			Notes:       receipt.Notes,
 			TotalItems:  receipt.TotalItems,
@@ -313,14 +534,15 @@ func (s *InventoryReceiptService) GetByCode(ctx *gin.Context, code string) (*mod
 	itemResponses := make([]model.InventoryReceiptItemResponse, len(receiptItems))
 	for i, item := range receiptItems {
 		itemResponses[i] = model.InventoryReceiptItemResponse{
-			ID:                 item.ID,
-			InventoryReceiptID: item.InventoryReceiptID,
-			ProductID:          item.ProductID,
-			Quantity:           item.Quantity,
-			UnitCost:           item.UnitCost,
-			Notes:              item.Notes,
-			CreatedAt:          item.CreatedAt,
-			UpdatedAt:          item.UpdatedAt,
+			ID:                  item.ID,
+			InventoryReceiptID:  item.InventoryReceiptID,
+			ProductID:           item.ProductID,
+			PurchaseOrderItemID: item.PurchaseOrderItemID,
+			Quantity:            item.Quantity,
+			UnitCost:            item.UnitCost,
+			Notes:               item.Notes,
+			CreatedAt:           item.CreatedAt,
+			UpdatedAt:           item.UpdatedAt,
 		}
 	}
 
@@ -330,6 +552,8 @@ func (s *InventoryReceiptService) GetByCode(ctx *gin.Context, code string) (*mod
 			ID:          receipt.ID,
 			Code:        receipt.Code,
 			UserID:      receipt.UserID,
+			SupplierID:  receipt.SupplierID,
+			WarehouseID: receipt.WarehouseID,
 			ReceiptDate: receipt.ReceiptDate,
 			Notes:       receipt.Notes,
 			TotalItems:  receipt.TotalItems,
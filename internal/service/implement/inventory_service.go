@@ -21,6 +21,10 @@ type InventoryService struct {
 	inventoryHistoryRepository repository.InventoryHistoryRepository
 	userRepository             repository.UserRepository
 	productRepository          repository.ProductRepository
+	reservationRepository      repository.InventoryReservationRepository
+	warehouseRepository        repository.WarehouseRepository
+	inventoryLotRepository     repository.InventoryLotRepository
+	costLayers                 *costLayerLedger
 	unitOfWork                 repository.UnitOfWork
 }
 
@@ -29,38 +33,99 @@ func NewInventoryService(
 	inventoryHistoryRepository repository.InventoryHistoryRepository,
 	userRepository repository.UserRepository,
 	productRepository repository.ProductRepository,
+	reservationRepository repository.InventoryReservationRepository,
 	unitOfWork repository.UnitOfWork,
+	warehouseRepository repository.WarehouseRepository,
+	inventoryLotRepository repository.InventoryLotRepository,
+	inventoryCostLayerRepository repository.InventoryCostLayerRepository,
+	inventoryCostLayerMovementRepository repository.InventoryCostLayerMovementRepository,
 ) service.InventoryService {
 	return &InventoryService{
 		inventoryRepository:        inventoryRepository,
 		inventoryHistoryRepository: inventoryHistoryRepository,
 		userRepository:             userRepository,
 		productRepository:          productRepository,
+		reservationRepository:      reservationRepository,
+		warehouseRepository:        warehouseRepository,
+		inventoryLotRepository:     inventoryLotRepository,
+		costLayers:                 newCostLayerLedger(inventoryCostLayerRepository, inventoryCostLayerMovementRepository),
 		unitOfWork:                 unitOfWork,
 	}
 }
 
-func (s *InventoryService) GetAll(ctx context.Context) (*model.GetAllInventoryResponse, string) {
+// GetAll returns the inventory of every product per warehouse, only of the given warehouse when
+// warehouseID is set, together with the totals of each warehouse
+func (s *InventoryService) GetAll(ctx context.Context, warehouseID *int) (*model.GetAllInventoryResponse, string) {
+	warehouses, err := s.warehouseRepository.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("InventoryService.GetAll Error when get warehouses: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if warehouseID != nil {
+		filtered := make([]entity.Warehouse, 0, 1)
+		for _, warehouse := range warehouses {
+			if warehouse.ID == *warehouseID {
+				filtered = append(filtered, warehouse)
+			}
+		}
+		if len(filtered) == 0 {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+		warehouses = filtered
+	}
+
 	// Get all inventory
-	inventories, err := s.inventoryRepository.GetAllQuery(ctx, nil)
+	inventories, err := s.inventoryRepository.GetAllQuery(ctx, warehouseID, nil)
 	if err != nil {
 		log.Error("InventoryService.GetAll Error when get inventories: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	// Reservations are held per warehouse
+	summaries := make([]model.InventoryWarehouseSummary, len(warehouses))
+	summaryIndex := make(map[int]int, len(warehouses))
+	reservedByWarehouse := make(map[int]map[int]int, len(warehouses))
+	for i, warehouse := range warehouses {
+		reservedQuantities, err := s.reservationRepository.GetAllReservedQuantitiesQuery(ctx, &warehouse.ID, nil)
+		if err != nil {
+			log.Error("InventoryService.GetAll Error when get reserved quantities: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		reservedByWarehouse[warehouse.ID] = reservedQuantities
+		summaries[i] = model.InventoryWarehouseSummary{
+			WarehouseID:   warehouse.ID,
+			WarehouseCode: warehouse.Code,
+			WarehouseName: warehouse.Name,
+		}
+		summaryIndex[warehouse.ID] = i
+	}
+
 	// Convert to response models with product info
 	inventoryResponses := make([]model.InventoryWithProductResponse, len(inventories))
 	for i, inventory := range inventories {
+		reservedQuantity := reservedByWarehouse[inventory.WarehouseID][inventory.ProductID]
+		summary := &summaries[summaryIndex[inventory.WarehouseID]]
+		summary.Quantity += inventory.Quantity
+		summary.ReservedQuantity += reservedQuantity
+		summary.AvailableQuantity += inventory.Quantity - reservedQuantity
+		if inventory.Quantity > 0 {
+			summary.ProductCount++
+		}
+
 		// Get product info for this inventory
 		product, err := s.productRepository.GetOneByIDQuery(ctx, inventory.ProductID, nil)
 		if err != nil {
 			log.Error("InventoryService.GetAll Error when get product for inventory " + string(rune(inventory.ID)) + ": " + err.Error())
 			// Continue without product info for this inventory
 			inventoryResponses[i] = model.InventoryWithProductResponse{
-				ID:        inventory.ID,
-				ProductID: inventory.ProductID,
-				Quantity:  inventory.Quantity,
-				Version:   inventory.Version,
+				ID:                inventory.ID,
+				ProductID:         inventory.ProductID,
+				WarehouseID:       inventory.WarehouseID,
+				WarehouseName:     summary.WarehouseName,
+				Quantity:          inventory.Quantity,
+				ReservedQuantity:  reservedQuantity,
+				AvailableQuantity: inventory.Quantity - reservedQuantity,
+				Version:           inventory.Version,
 				Product: model.ProductInfo{
 					ID:   inventory.ProductID,
 					Name: "N/A",
@@ -71,10 +136,14 @@ func (s *InventoryService) GetAll(ctx context.Context) (*model.GetAllInventoryRe
 		}
 
 		inventoryResponses[i] = model.InventoryWithProductResponse{
-			ID:        inventory.ID,
-			ProductID: inventory.ProductID,
-			Quantity:  inventory.Quantity,
-			Version:   inventory.Version,
+			ID:                inventory.ID,
+			ProductID:         inventory.ProductID,
+			WarehouseID:       inventory.WarehouseID,
+			WarehouseName:     summary.WarehouseName,
+			Quantity:          inventory.Quantity,
+			ReservedQuantity:  reservedQuantity,
+			AvailableQuantity: inventory.Quantity - reservedQuantity,
+			Version:           inventory.Version,
 			Product: model.ProductInfo{
 				ID:   product.ID,
 				Name: product.Name,
@@ -85,12 +154,18 @@ func (s *InventoryService) GetAll(ctx context.Context) (*model.GetAllInventoryRe
 
 	return &model.GetAllInventoryResponse{
 		Inventories: inventoryResponses,
+		Warehouses:  summaries,
 	}, ""
 }
 
-func (s *InventoryService) GetByProductID(ctx *gin.Context, productID int) (*model.InventoryResponse, string) {
+func (s *InventoryService) GetByProductID(ctx *gin.Context, productID int, warehouseID *int) (*model.InventoryResponse, string) {
+	warehouse, errCode := resolveWarehouse(ctx, s.warehouseRepository, warehouseID, nil)
+	if errCode != "" {
+		return nil, errCode
+	}
+
 	// Get inventory by product ID
-	inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, productID, nil)
+	inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, productID, warehouse.ID, nil)
 	if err != nil {
 		log.Error("InventoryService.GetByProductID Error when get inventory: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -100,13 +175,7 @@ func (s *InventoryService) GetByProductID(ctx *gin.Context, productID int) (*mod
 		return nil, error_utils.ErrorCode.NOT_FOUND
 	}
 
-	// Return response
-	return &model.InventoryResponse{
-		ID:        inventory.ID,
-		ProductID: inventory.ProductID,
-		Quantity:  inventory.Quantity,
-		Version:   inventory.Version,
-	}, ""
+	return s.toInventoryResponse(ctx, inventory)
 }
 
 func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, request model.UpdateInventoryQuantityRequest) (*model.InventoryResponse, string) {
@@ -143,7 +212,12 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 		}
 	}()
 
-	toBeLockedInventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, productID, tx)
+	warehouse, errCode := resolveWarehouse(ctx, s.warehouseRepository, request.WarehouseID, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	toBeLockedInventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, productID, warehouse.ID, tx)
 	if err != nil {
 		log.Error("InventoryService.UpdateQuantity Error when get inventory: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -172,7 +246,7 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 	newVersion := uuid.New().String()
 
 	// Update inventory quantity with version check
-	err = s.inventoryRepository.UpdateQuantityWithVersionCommand(ctx, productID, request.Quantity, request.Version, newVersion, tx)
+	err = s.inventoryRepository.UpdateQuantityWithVersionCommand(ctx, productID, warehouse.ID, request.Quantity, request.Version, newVersion, tx)
 	if err != nil {
 		// Check for specific error types
 		var constraintViolationError *error_utils.ConstraintViolationError
@@ -183,9 +257,46 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	// Stock written off is taken from the lots FEFO so that they never exceed the inventory
+	if request.Quantity < 0 {
+		_, err := drawLotsFEFO(ctx, s.inventoryLotRepository, productID, warehouse.ID, existingInventory.Quantity, -request.Quantity, tx)
+		if errors.Is(err, errInsufficientUnexpiredStock) {
+			return nil, error_utils.ErrorCode.INSUFFICIENT_UNEXPIRED_STOCK
+		}
+		if err != nil {
+			log.Error("InventoryService.UpdateQuantity Error when update lots: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// Stock written off leaves the oldest cost layers, stock found opens a layer at the current cost
+	if request.Quantity < 0 {
+		_, err = s.costLayers.consume(ctx, productID, -request.Quantity, entity.InventoryCostLayerReferenceType.INVENTORY_ADJUSTMENT, nil, tx)
+	} else {
+		product, err := s.productRepository.GetOneByIDQuery(ctx, productID, tx)
+		if err != nil {
+			log.Error("InventoryService.UpdateQuantity Error when get product: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if product == nil {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+		err = s.costLayers.add(ctx, &entity.InventoryCostLayer{
+			ProductID:  productID,
+			SourceType: entity.InventoryCostLayerSourceType.INVENTORY_ADJUSTMENT,
+			UnitCost:   product.Cost,
+			Quantity:   request.Quantity,
+		}, tx)
+	}
+	if err != nil {
+		log.Error("InventoryService.UpdateQuantity Error when update cost layers: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
 	// Create inventory history record
 	inventoryHistory := &entity.InventoryHistory{
 		ProductID:     productID,
+		WarehouseID:   &warehouse.ID,
 		Quantity:      request.Quantity,
 		FinalQuantity: existingInventory.Quantity + request.Quantity,
 		ImporterName:  user.Username,
@@ -207,17 +318,31 @@ func (s *InventoryService) UpdateQuantity(ctx *gin.Context, productID int, reque
 	}
 
 	// Get updated inventory
-	updatedInventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, productID, nil)
+	updatedInventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, productID, warehouse.ID, nil)
 	if err != nil {
 		log.Error("InventoryService.UpdateQuantity Error when get updated inventory: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
-	// Return response
+	return s.toInventoryResponse(ctx, updatedInventory)
+}
+
+// toInventoryResponse builds the response with on-hand, reserved and available quantities of the inventory's warehouse
+func (s *InventoryService) toInventoryResponse(ctx context.Context, inventory *entity.Inventory) (*model.InventoryResponse, string) {
+	reservedQuantities, err := s.reservationRepository.GetReservedQuantitiesByProductIDsQuery(ctx, []int{inventory.ProductID}, &inventory.WarehouseID, nil)
+	if err != nil {
+		log.Error("InventoryService.toInventoryResponse Error when get reserved quantities: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	reservedQuantity := reservedQuantities[inventory.ProductID]
+
 	return &model.InventoryResponse{
-		ID:        updatedInventory.ID,
-		ProductID: updatedInventory.ProductID,
-		Quantity:  updatedInventory.Quantity,
-		Version:   updatedInventory.Version,
+		ID:                inventory.ID,
+		ProductID:         inventory.ProductID,
+		WarehouseID:       inventory.WarehouseID,
+		Quantity:          inventory.Quantity,
+		ReservedQuantity:  reservedQuantity,
+		AvailableQuantity: inventory.Quantity - reservedQuantity,
+		Version:           inventory.Version,
 	}, ""
 }
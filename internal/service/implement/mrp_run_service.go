@@ -0,0 +1,579 @@
+package serviceimplement
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// mrpLine is what the MRP run works out for one product
+type mrpLine struct {
+	product        *entity.Product
+	availableStock int     // Stock the plan may use
+	fromStock      float64 // Demand covered from stock so far
+	gross          float64 // Demand before netting
+	net            float64 // Demand left after netting
+	hasBom         bool    // The shortfall is produced from components instead of bought
+}
+
+// mrpPlanner nets demands against stock one BOM level at a time: a product's demand is covered
+// from its own stock first and only the shortfall is exploded into its components, with the BOM
+// versions in effect at the demand's date. Each product's stock is used once across all demands.
+type mrpPlanner struct {
+	ctx             context.Context
+	productRepo     repository.ProductRepository
+	bomRepo         repository.ProductBomRepository
+	inventoryRepo   repository.InventoryRepository
+	reservationRepo repository.InventoryReservationRepository
+	tx              *sqlx.Tx
+	excludeReserved bool             // Stock reserved by open orders is not available to the plan
+	date            time.Time        // BOM versions in effect at this date are exploded
+	lines           map[int]*mrpLine // Per product
+	level           int              // Number of BOM levels currently being exploded
+}
+
+// line returns the product's line, loading the product and its stock the first time
+func (p *mrpPlanner) line(productID int) (*mrpLine, error) {
+	if line, exists := p.lines[productID]; exists {
+		return line, nil
+	}
+
+	product, err := p.productRepo.GetOneByIDQuery(p.ctx, productID, p.tx)
+	if err != nil || product == nil {
+		return nil, fmt.Errorf("failed to get product %d: %w", productID, err)
+	}
+
+	// Planning covers the stock of all warehouses
+	line := &mrpLine{product: product}
+	quantities, err := p.inventoryRepo.GetTotalQuantitiesByProductIDsQuery(p.ctx, []int{productID}, p.tx)
+	if err != nil {
+		return nil, err
+	}
+	line.availableStock = quantities[productID]
+	if p.excludeReserved {
+		reservedQuantities, err := p.reservationRepo.GetReservedQuantitiesByProductIDsQuery(p.ctx, []int{productID}, nil, p.tx)
+		if err != nil {
+			return nil, err
+		}
+		line.availableStock -= reservedQuantities[productID]
+	}
+
+	p.lines[productID] = line
+	return line, nil
+}
+
+// plan adds the demand for the product, covers what it can from stock and explodes the shortfall
+func (p *mrpPlanner) plan(productID int, quantity float64) error {
+	line, err := p.line(productID)
+	if err != nil {
+		return err
+	}
+	line.gross += quantity
+
+	taken := min(max(float64(line.availableStock)-line.fromStock, 0), quantity)
+	line.fromStock += taken
+
+	shortfall := roundBomQuantity(quantity - taken)
+	if shortfall <= 0 {
+		return nil
+	}
+	line.net += shortfall
+
+	// A PURCHASE type product is bought, whatever its BOM says
+	if line.product.OperationType == "PURCHASE" {
+		return nil
+	}
+
+	boms, err := p.bomRepo.GetEffectiveByParentProductIDQuery(p.ctx, productID, p.date, p.tx)
+	if err != nil {
+		return fmt.Errorf("failed to get BOMs for product %d: %w", productID, err)
+	}
+	// Without a BOM the shortfall can only be bought
+	if len(boms) == 0 {
+		return nil
+	}
+
+	// Stop before a broken structure recurses forever
+	if p.level >= maxBomDepth {
+		return errBomTooDeep
+	}
+	line.hasBom = true
+
+	p.level++
+	defer func() { p.level-- }()
+
+	for _, bom := range boms {
+		if err := p.plan(bom.ComponentProductID, grossBomQuantity(bom, line.product)*shortfall); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type MrpRunService struct {
+	mrpRunRepo       repository.MrpRunRepository
+	mrpRunItemRepo   repository.MrpRunItemRepository
+	mrpRunDemandRepo repository.MrpRunDemandRepository
+	orderRepo        repository.OrderRepository
+	orderItemRepo    repository.OrderItemRepository
+	productRepo      repository.ProductRepository
+	bomRepo          repository.ProductBomRepository
+	inventoryRepo    repository.InventoryRepository
+	reservationRepo  repository.InventoryReservationRepository
+	unitRepo         repository.UnitOfMeasureRepository
+	userRepo         repository.UserRepository
+	unitOfWork       repository.UnitOfWork
+	unitConverter    *unitConverter
+}
+
+func NewMrpRunService(
+	mrpRunRepo repository.MrpRunRepository,
+	mrpRunItemRepo repository.MrpRunItemRepository,
+	mrpRunDemandRepo repository.MrpRunDemandRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	productRepo repository.ProductRepository,
+	bomRepo repository.ProductBomRepository,
+	inventoryRepo repository.InventoryRepository,
+	reservationRepo repository.InventoryReservationRepository,
+	unitRepo repository.UnitOfMeasureRepository,
+	userRepo repository.UserRepository,
+	unitOfWork repository.UnitOfWork,
+	unitConversionRepo repository.UnitConversionRepository,
+) service.MrpRunService {
+	return &MrpRunService{
+		mrpRunRepo:       mrpRunRepo,
+		mrpRunItemRepo:   mrpRunItemRepo,
+		mrpRunDemandRepo: mrpRunDemandRepo,
+		orderRepo:        orderRepo,
+		orderItemRepo:    orderItemRepo,
+		productRepo:      productRepo,
+		bomRepo:          bomRepo,
+		inventoryRepo:    inventoryRepo,
+		reservationRepo:  reservationRepo,
+		unitRepo:         unitRepo,
+		userRepo:         userRepo,
+		unitOfWork:       unitOfWork,
+		unitConverter:    newUnitConverter(unitConversionRepo),
+	}
+}
+
+// mrpDemand is one demand line of the run and the date its BOMs are exploded at
+type mrpDemand struct {
+	demand entity.MrpRunDemand
+	date   time.Time
+}
+
+// collectDemands returns the items of the open orders still holding reservations (exploded at
+// their order date) followed by the manual demand (exploded now), in base units
+func (s *MrpRunService) collectDemands(ctx *gin.Context, request model.CreateMrpRunRequest, tx *sqlx.Tx) ([]mrpDemand, string) {
+	demands := []mrpDemand{}
+
+	if request.IncludeOpenOrders {
+		orders, err := s.orderRepo.GetAllByDeliveryStatusQuery(ctx, entity.OrderDeliveryStatus.PENDING, tx)
+		if err != nil {
+			log.Error("MrpRunService.collectDemands Error when get open orders: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		for _, order := range orders {
+			// Older pending orders had their stock issued at creation, it is no longer demand
+			reserving, err := isReservingOrder(ctx, s.reservationRepo, &order, tx)
+			if err != nil {
+				log.Error("MrpRunService.collectDemands Error when get order reservations: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			if !reserving {
+				continue
+			}
+
+			orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+			if err != nil {
+				log.Error("MrpRunService.collectDemands Error when get order items: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			for _, orderItem := range orderItems {
+				orderID := order.ID
+				demands = append(demands, mrpDemand{
+					demand: entity.MrpRunDemand{
+						ProductID: orderItem.ProductID,
+						OrderID:   &orderID,
+						Quantity:  orderItem.BaseQuantity,
+					},
+					date: order.OrderDate,
+				})
+			}
+		}
+	}
+
+	now := time.Now()
+	for _, demandRequest := range request.Demands {
+		product, err := s.productRepo.GetOneByIDQuery(ctx, demandRequest.ProductID, tx)
+		if err != nil {
+			log.Error("MrpRunService.collectDemands Error when get product: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if product == nil {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+
+		factor, errCode := s.unitConverter.baseFactor(ctx, product, demandRequest.UnitID, tx)
+		if errCode != "" {
+			return nil, errCode
+		}
+		baseQuantity, errCode := toWholeBaseQuantity(demandRequest.Quantity, factor)
+		if errCode != "" {
+			return nil, errCode
+		}
+
+		demands = append(demands, mrpDemand{
+			demand: entity.MrpRunDemand{
+				ProductID: product.ID,
+				Quantity:  baseQuantity,
+			},
+			date: now,
+		})
+	}
+
+	return demands, ""
+}
+
+// Create runs material requirements planning over the open orders and/or the given demand,
+// nets it against stock and saves the result as a new run
+func (s *MrpRunService) Create(ctx *gin.Context, request model.CreateMrpRunRequest, userID int) (*model.MrpRunResponse, string) {
+	if !request.IncludeOpenOrders && len(request.Demands) == 0 {
+		return nil, error_utils.ErrorCode.MRP_DEMAND_REQUIRED
+	}
+
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, nil)
+	if err != nil {
+		log.Error("MrpRunService.Create Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("MrpRunService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("MrpRunService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	demands, errCode := s.collectDemands(ctx, request, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Without the open orders in the demand, the stock they reserved is not available to the plan
+	planner := &mrpPlanner{
+		ctx:             ctx,
+		productRepo:     s.productRepo,
+		bomRepo:         s.bomRepo,
+		inventoryRepo:   s.inventoryRepo,
+		reservationRepo: s.reservationRepo,
+		tx:              tx,
+		excludeReserved: !request.IncludeOpenOrders,
+		lines:           make(map[int]*mrpLine),
+	}
+	for _, demand := range demands {
+		planner.date = demand.date
+		err = planner.plan(demand.demand.ProductID, float64(demand.demand.Quantity))
+		if errors.Is(err, errBomTooDeep) {
+			return nil, error_utils.ErrorCode.BOM_DEPTH_EXCEEDED
+		}
+		if err != nil {
+			log.Error("MrpRunService.Create Error when plan requirements: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	run := &entity.MrpRun{
+		IncludeOpenOrders: request.IncludeOpenOrders,
+		Note:              request.Note,
+		CreatedBy:         &user.ID,
+		CreatedByName:     user.Username,
+	}
+	err = s.mrpRunRepo.CreateCommand(ctx, run, tx)
+	if err != nil {
+		log.Error("MrpRunService.Create Error when create mrp run: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	for _, demand := range demands {
+		demand.demand.MrpRunID = run.ID
+		err = s.mrpRunDemandRepo.CreateCommand(ctx, &demand.demand, tx)
+		if err != nil {
+			log.Error("MrpRunService.Create Error when create mrp run demand: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	for productID, line := range planner.lines {
+		item := &entity.MrpRunItem{
+			MrpRunID:         run.ID,
+			ProductID:        productID,
+			GrossRequirement: roundBomQuantity(line.gross),
+			AvailableStock:   line.availableStock,
+			NetRequirement:   roundBomQuantity(line.net),
+		}
+		// Whole units are produced and bought
+		if line.hasBom {
+			item.PlannedProduction = int(math.Ceil(item.NetRequirement))
+		} else {
+			item.SuggestedPurchase = int(math.Ceil(item.NetRequirement))
+		}
+		if item.SuggestedPurchase > 0 {
+			run.ShortageMaterials++
+		}
+		run.TotalMaterials++
+
+		err = s.mrpRunItemRepo.CreateCommand(ctx, item, tx)
+		if err != nil {
+			log.Error("MrpRunService.Create Error when create mrp run item: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	err = s.mrpRunRepo.UpdateCommand(ctx, run, tx)
+	if err != nil {
+		log.Error("MrpRunService.Create Error when update mrp run totals: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("MrpRunService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response, errCode := s.GetOne(ctx, run.ID)
+	if errCode != "" {
+		return nil, errCode
+	}
+	return &response.MrpRun, ""
+}
+
+func (s *MrpRunService) GetAll(ctx *gin.Context) (*model.GetAllMrpRunsResponse, string) {
+	runs, err := s.mrpRunRepo.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("MrpRunService.GetAll Error when get mrp runs: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	resp := &model.GetAllMrpRunsResponse{MrpRuns: make([]model.MrpRunResponse, 0, len(runs))}
+	for i := range runs {
+		resp.MrpRuns = append(resp.MrpRuns, toMrpRunResponse(&runs[i]))
+	}
+
+	return resp, ""
+}
+
+func (s *MrpRunService) GetOne(ctx *gin.Context, id int) (*model.GetOneMrpRunResponse, string) {
+	run, err := s.mrpRunRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("MrpRunService.GetOne Error when get mrp run: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if run == nil {
+		return nil, error_utils.ErrorCode.MRP_RUN_NOT_FOUND
+	}
+
+	demands, err := s.mrpRunDemandRepo.GetAllByMrpRunIDQuery(ctx, run.ID, nil)
+	if err != nil {
+		log.Error("MrpRunService.GetOne Error when get mrp run demands: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	items, errCode := s.getItemResponses(ctx, run.ID)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	response := toMrpRunResponse(run)
+	response.Items = items
+	response.Demands = make([]model.MrpRunDemandResponse, 0, len(demands))
+	orderCodes := make(map[int]string)
+	for _, demand := range demands {
+		demandResponse := model.MrpRunDemandResponse{
+			ProductID: demand.ProductID,
+			OrderID:   demand.OrderID,
+			Quantity:  demand.Quantity,
+		}
+		if product, err := s.productRepo.GetOneByIDQuery(ctx, demand.ProductID, nil); err == nil && product != nil {
+			demandResponse.ProductName = product.Name
+		}
+		if demand.OrderID != nil {
+			if _, exists := orderCodes[*demand.OrderID]; !exists {
+				if order, err := s.orderRepo.GetOneByIDQuery(ctx, *demand.OrderID, nil); err == nil && order != nil {
+					orderCodes[*demand.OrderID] = order.Code
+				}
+			}
+			if orderCode, exists := orderCodes[*demand.OrderID]; exists {
+				demandResponse.OrderCode = &orderCode
+			}
+		}
+		response.Demands = append(response.Demands, demandResponse)
+	}
+
+	return &model.GetOneMrpRunResponse{MrpRun: response}, ""
+}
+
+// ExportCSV renders the run's items as a CSV file that opens in Excel
+func (s *MrpRunService) ExportCSV(ctx *gin.Context, id int) ([]byte, string, string) {
+	run, err := s.mrpRunRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("MrpRunService.ExportCSV Error when get mrp run: " + err.Error())
+		return nil, "", error_utils.ErrorCode.DB_DOWN
+	}
+	if run == nil {
+		return nil, "", error_utils.ErrorCode.MRP_RUN_NOT_FOUND
+	}
+
+	items, errCode := s.getItemResponses(ctx, run.ID)
+	if errCode != "" {
+		return nil, "", errCode
+	}
+
+	var buf bytes.Buffer
+	// UTF-8 byte order mark so that Excel shows Vietnamese names correctly
+	buf.WriteString("\xEF\xBB\xBF")
+	writer := csv.NewWriter(&buf)
+	rows := [][]string{{"Mã SP", "Tên sản phẩm", "ĐVT", "Loại", "Tổng nhu cầu", "Tồn kho dùng được", "Thiếu hụt", "Cần sản xuất", "Đề xuất mua"}}
+	for _, item := range items {
+		rows = append(rows, []string{
+			item.ProductCode,
+			item.ProductName,
+			item.UnitName,
+			item.OperationType,
+			strconv.FormatFloat(item.GrossRequirement, 'f', -1, 64),
+			strconv.Itoa(item.AvailableStock),
+			strconv.FormatFloat(item.NetRequirement, 'f', -1, 64),
+			strconv.Itoa(item.PlannedProduction),
+			strconv.Itoa(item.SuggestedPurchase),
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		log.Error("MrpRunService.ExportCSV Error when write csv: " + err.Error())
+		return nil, "", error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+
+	return buf.Bytes(), run.Code + ".csv", ""
+}
+
+// Compare lines up the items of two runs per product
+func (s *MrpRunService) Compare(ctx *gin.Context, fromRunID int, toRunID int) (*model.MrpRunComparisonResponse, string) {
+	fromRun, errCode := s.GetOne(ctx, fromRunID)
+	if errCode != "" {
+		return nil, errCode
+	}
+	toRun, errCode := s.GetOne(ctx, toRunID)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	comparisonItems := make(map[int]*model.MrpRunComparisonItem)
+	comparisonItem := func(productID int, productName string) *model.MrpRunComparisonItem {
+		if item, exists := comparisonItems[productID]; exists {
+			return item
+		}
+		item := &model.MrpRunComparisonItem{ProductID: productID, ProductName: productName}
+		comparisonItems[productID] = item
+		return item
+	}
+	for _, item := range fromRun.MrpRun.Items {
+		comparison := comparisonItem(item.ProductID, item.ProductName)
+		comparison.FromNetRequirement = item.NetRequirement
+		comparison.FromSuggestedPurchase = item.SuggestedPurchase
+	}
+	for _, item := range toRun.MrpRun.Items {
+		comparison := comparisonItem(item.ProductID, item.ProductName)
+		comparison.ToNetRequirement = item.NetRequirement
+		comparison.ToSuggestedPurchase = item.SuggestedPurchase
+	}
+
+	items := make([]model.MrpRunComparisonItem, 0, len(comparisonItems))
+	for _, item := range comparisonItems {
+		item.SuggestedPurchaseDiff = item.ToSuggestedPurchase - item.FromSuggestedPurchase
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ProductID < items[j].ProductID })
+
+	// The runs are summarized, their items are in the comparison
+	fromRun.MrpRun.Items, fromRun.MrpRun.Demands = nil, nil
+	toRun.MrpRun.Items, toRun.MrpRun.Demands = nil, nil
+
+	return &model.MrpRunComparisonResponse{
+		FromRun: fromRun.MrpRun,
+		ToRun:   toRun.MrpRun,
+		Items:   items,
+	}, ""
+}
+
+// getItemResponses returns the run's items with their product info
+func (s *MrpRunService) getItemResponses(ctx *gin.Context, runID int) ([]model.MrpRunItemResponse, string) {
+	items, err := s.mrpRunItemRepo.GetAllByMrpRunIDQuery(ctx, runID, nil)
+	if err != nil {
+		log.Error("MrpRunService.getItemResponses Error when get mrp run items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	itemResponses := make([]model.MrpRunItemResponse, 0, len(items))
+	for _, item := range items {
+		itemResponse := model.MrpRunItemResponse{
+			ProductID:         item.ProductID,
+			GrossRequirement:  item.GrossRequirement,
+			AvailableStock:    item.AvailableStock,
+			NetRequirement:    item.NetRequirement,
+			PlannedProduction: item.PlannedProduction,
+			SuggestedPurchase: item.SuggestedPurchase,
+		}
+		if product, err := s.productRepo.GetOneByIDQuery(ctx, item.ProductID, nil); err == nil && product != nil {
+			itemResponse.ProductCode = product.Code
+			itemResponse.ProductName = product.Name
+			itemResponse.OperationType = product.OperationType
+			if product.UnitID != nil {
+				if unit, err := s.unitRepo.GetOneByIDQuery(ctx, *product.UnitID, nil); err == nil && unit != nil {
+					itemResponse.UnitName = unit.Name
+				}
+			}
+		}
+		itemResponses = append(itemResponses, itemResponse)
+	}
+
+	return itemResponses, ""
+}
+
+func toMrpRunResponse(run *entity.MrpRun) model.MrpRunResponse {
+	return model.MrpRunResponse{
+		ID:                run.ID,
+		Code:              run.Code,
+		IncludeOpenOrders: run.IncludeOpenOrders,
+		Note:              run.Note,
+		TotalMaterials:    run.TotalMaterials,
+		ShortageMaterials: run.ShortageMaterials,
+		CreatedBy:         run.CreatedBy,
+		CreatedByName:     run.CreatedByName,
+		CreatedAt:         run.CreatedAt,
+	}
+}
@@ -0,0 +1,378 @@
+package serviceimplement
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-pdf/fpdf"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultPdfFontPath     = "/usr/share/fonts/dejavu/DejaVuSans.ttf"
+	defaultPdfBoldFontPath = "/usr/share/fonts/dejavu/DejaVuSans-Bold.ttf"
+	pdfFontFamily          = "DocumentFont"
+)
+
+// companyInfo is the header printed on every order document
+type companyInfo struct {
+	Name    string
+	Address string
+	Phone   string
+	TaxCode string
+}
+
+type OrderDocumentService struct {
+	orderService service.OrderService
+	company      companyInfo
+	fontPath     string
+	boldFontPath string
+}
+
+func NewOrderDocumentService(orderService service.OrderService) service.OrderDocumentService {
+	// Get configuration from environment variables
+	fontPath := os.Getenv("PDF_FONT_PATH")
+	if fontPath == "" {
+		fontPath = defaultPdfFontPath
+	}
+	boldFontPath := os.Getenv("PDF_FONT_BOLD_PATH")
+	if boldFontPath == "" {
+		boldFontPath = defaultPdfBoldFontPath
+	}
+
+	return &OrderDocumentService{
+		orderService: orderService,
+		company: companyInfo{
+			Name:    os.Getenv("COMPANY_NAME"),
+			Address: os.Getenv("COMPANY_ADDRESS"),
+			Phone:   os.Getenv("COMPANY_PHONE"),
+			TaxCode: os.Getenv("COMPANY_TAX_CODE"),
+		},
+		fontPath:     fontPath,
+		boldFontPath: boldFontPath,
+	}
+}
+
+func (s *OrderDocumentService) GenerateInvoicePDF(ctx *gin.Context, orderID int) ([]byte, string, string) {
+	resp, errCode := s.orderService.GetOneOrder(ctx, orderID)
+	if errCode != "" {
+		return nil, "", errCode
+	}
+	order := resp.Order
+
+	pdf, err := s.newDocument()
+	if err != nil {
+		log.Error("OrderDocumentService.GenerateInvoicePDF Error when load pdf fonts: " + err.Error())
+		return nil, "", error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+	s.writeHeader(pdf, "HÓA ĐƠN BÁN HÀNG", order)
+
+	// Items table
+	widths := []float64{10, 60, 16, 14, 28, 14, 38}
+	s.writeTableHeader(pdf, widths, []string{"STT", "Tên sản phẩm", "ĐVT", "SL", "Đơn giá", "CK (%)", "Thành tiền"})
+
+	itemsAmount := 0
+	pdf.SetFont(pdfFontFamily, "", 10)
+	for i, item := range order.OrderItems {
+		finalAmount := 0
+		if item.FinalAmount != nil {
+			finalAmount = *item.FinalAmount
+		}
+		itemsAmount += finalAmount
+
+		pdf.CellFormat(widths[0], 7, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[1], 7, item.ProductName, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 7, item.UnitName, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[3], 7, formatNumberWithDots(item.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 7, formatNumberWithDots(item.SellingPrice), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[5], 7, fmt.Sprintf("%d", item.DiscountPercent), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[6], 7, formatNumberWithDots(finalAmount), "1", 1, "R", false, 0, "")
+	}
+
+	totalAmount := 0
+	if order.TotalAmount != nil {
+		totalAmount = *order.TotalAmount
+	}
+	// TotalAmount = items + additional cost + tax on both
+	taxAmount := totalAmount - itemsAmount - order.AdditionalCost
+
+	labelWidth := widths[0] + widths[1] + widths[2] + widths[3] + widths[4] + widths[5]
+	s.writeSummaryRow(pdf, labelWidth, widths[6], "Cộng tiền hàng", itemsAmount, false)
+	if order.AdditionalCost != 0 {
+		label := "Chi phí phát sinh"
+		if order.AdditionalCostNote != nil && *order.AdditionalCostNote != "" {
+			label += " (" + *order.AdditionalCostNote + ")"
+		}
+		s.writeSummaryRow(pdf, labelWidth, widths[6], label, order.AdditionalCost, false)
+	}
+	s.writeSummaryRow(pdf, labelWidth, widths[6], fmt.Sprintf("Thuế (%d%%)", order.TaxPercent), taxAmount, false)
+	s.writeSummaryRow(pdf, labelWidth, widths[6], "Tổng cộng", totalAmount, true)
+	if order.ReturnedAmount != nil && *order.ReturnedAmount > 0 {
+		s.writeSummaryRow(pdf, labelWidth, widths[6], "Hàng trả lại", -*order.ReturnedAmount, false)
+	}
+	if order.PaidAmount != nil && *order.PaidAmount > 0 {
+		s.writeSummaryRow(pdf, labelWidth, widths[6], "Đã thanh toán", *order.PaidAmount, false)
+	}
+	if order.OutstandingAmount != nil && (order.PaidAmount != nil && *order.PaidAmount > 0 || order.ReturnedAmount != nil && *order.ReturnedAmount > 0) {
+		s.writeSummaryRow(pdf, labelWidth, widths[6], "Còn phải thanh toán", *order.OutstandingAmount, true)
+	}
+
+	pdf.Ln(3)
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.MultiCell(0, 6, "Số tiền viết bằng chữ: "+amountInVietnameseWords(totalAmount), "", "L", false)
+
+	s.writeSignatures(pdf, "Người mua hàng", "Người bán hàng")
+
+	return s.output(pdf, "GenerateInvoicePDF", order.Code+"-hoa-don.pdf")
+}
+
+func (s *OrderDocumentService) GenerateDeliveryNotePDF(ctx *gin.Context, orderID int) ([]byte, string, string) {
+	resp, errCode := s.orderService.GetOneOrder(ctx, orderID)
+	if errCode != "" {
+		return nil, "", errCode
+	}
+	order := resp.Order
+
+	pdf, err := s.newDocument()
+	if err != nil {
+		log.Error("OrderDocumentService.GenerateDeliveryNotePDF Error when load pdf fonts: " + err.Error())
+		return nil, "", error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+	s.writeHeader(pdf, "PHIẾU GIAO HÀNG", order)
+
+	// Items table, the driver only needs what to hand over
+	widths := []float64{10, 92, 18, 30, 30}
+	s.writeTableHeader(pdf, widths, []string{"STT", "Tên sản phẩm", "ĐVT", "Số lượng", "Thực giao"})
+
+	totalQuantity := 0
+	pdf.SetFont(pdfFontFamily, "", 10)
+	for i, item := range order.OrderItems {
+		totalQuantity += item.Quantity
+
+		pdf.CellFormat(widths[0], 7, fmt.Sprintf("%d", i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[1], 7, item.ProductName, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 7, item.UnitName, "1", 0, "C", false, 0, "")
+		pdf.CellFormat(widths[3], 7, formatNumberWithDots(item.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 7, "", "1", 1, "R", false, 0, "")
+	}
+
+	pdf.SetFont(pdfFontFamily, "B", 10)
+	pdf.CellFormat(widths[0]+widths[1]+widths[2], 7, "Tổng số lượng", "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[3], 7, formatNumberWithDots(totalQuantity), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[4], 7, "", "1", 1, "R", false, 0, "")
+
+	// Amount the driver has to collect on delivery
+	amountToCollect := 0
+	if order.OutstandingAmount != nil {
+		amountToCollect = *order.OutstandingAmount
+	} else if order.TotalAmount != nil {
+		amountToCollect = *order.TotalAmount
+	}
+
+	pdf.Ln(3)
+	pdf.SetFont(pdfFontFamily, "B", 10)
+	pdf.CellFormat(0, 6, "Số tiền cần thu: "+formatNumberWithDots(amountToCollect)+" đ", "", 1, "L", false, 0, "")
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.MultiCell(0, 6, "Bằng chữ: "+amountInVietnameseWords(amountToCollect), "", "L", false)
+
+	s.writeSignatures(pdf, "Người nhận hàng", "Người giao hàng")
+
+	return s.output(pdf, "GenerateDeliveryNotePDF", order.Code+"-phieu-giao-hang.pdf")
+}
+
+func (s *OrderDocumentService) newDocument() (*fpdf.Fpdf, error) {
+	// Core fonts cannot render Vietnamese, a UTF-8 TrueType font is required
+	regularFont, err := os.ReadFile(s.fontPath)
+	if err != nil {
+		return nil, err
+	}
+	boldFont, err := os.ReadFile(s.boldFontPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", regularFont)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", boldFont)
+	pdf.AddPage()
+	return pdf, nil
+}
+
+func (s *OrderDocumentService) writeHeader(pdf *fpdf.Fpdf, title string, order model.OrderResponse) {
+	// Company header from configuration
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	if s.company.Name != "" {
+		pdf.CellFormat(0, 6, s.company.Name, "", 1, "L", false, 0, "")
+	}
+	pdf.SetFont(pdfFontFamily, "", 9)
+	if s.company.Address != "" {
+		pdf.CellFormat(0, 5, "Địa chỉ: "+s.company.Address, "", 1, "L", false, 0, "")
+	}
+	if s.company.Phone != "" {
+		pdf.CellFormat(0, 5, "Điện thoại: "+s.company.Phone, "", 1, "L", false, 0, "")
+	}
+	if s.company.TaxCode != "" {
+		pdf.CellFormat(0, 5, "Mã số thuế: "+s.company.TaxCode, "", 1, "L", false, 0, "")
+	}
+
+	pdf.Ln(4)
+	pdf.SetFont(pdfFontFamily, "B", 16)
+	pdf.CellFormat(0, 9, title, "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfFontFamily, "", 10)
+	pdf.CellFormat(0, 6, fmt.Sprintf("Số: %s - Ngày: %s", order.Code, order.OrderDate.Format("02/01/2006")), "", 1, "C", false, 0, "")
+
+	pdf.Ln(3)
+	pdf.CellFormat(0, 6, "Khách hàng: "+order.Customer.Name, "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, "Điện thoại: "+order.Customer.Phone, "", 1, "L", false, 0, "")
+	pdf.MultiCell(0, 6, "Địa chỉ: "+order.Customer.Address, "", "L", false)
+	if order.Note != nil && *order.Note != "" {
+		pdf.MultiCell(0, 6, "Ghi chú: "+*order.Note, "", "L", false)
+	}
+	pdf.Ln(3)
+}
+
+func (s *OrderDocumentService) writeTableHeader(pdf *fpdf.Fpdf, widths []float64, titles []string) {
+	pdf.SetFont(pdfFontFamily, "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, title := range titles {
+		lineBreak := 0
+		if i == len(titles)-1 {
+			lineBreak = 1
+		}
+		pdf.CellFormat(widths[i], 8, title, "1", lineBreak, "C", true, 0, "")
+	}
+}
+
+func (s *OrderDocumentService) writeSummaryRow(pdf *fpdf.Fpdf, labelWidth float64, valueWidth float64, label string, amount int, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	pdf.SetFont(pdfFontFamily, style, 10)
+
+	value := formatNumberWithDots(amount)
+	if amount < 0 {
+		value = "-" + formatNumberWithDots(-amount)
+	}
+	pdf.CellFormat(labelWidth, 7, label, "1", 0, "R", false, 0, "")
+	pdf.CellFormat(valueWidth, 7, value, "1", 1, "R", false, 0, "")
+}
+
+func (s *OrderDocumentService) writeSignatures(pdf *fpdf.Fpdf, left string, right string) {
+	pdf.Ln(10)
+	pdf.SetFont(pdfFontFamily, "B", 10)
+	pdf.CellFormat(90, 6, left, "", 0, "C", false, 0, "")
+	pdf.CellFormat(90, 6, right, "", 1, "C", false, 0, "")
+	pdf.SetFont(pdfFontFamily, "", 9)
+	pdf.CellFormat(90, 5, "(Ký, ghi rõ họ tên)", "", 0, "C", false, 0, "")
+	pdf.CellFormat(90, 5, "(Ký, ghi rõ họ tên)", "", 1, "C", false, 0, "")
+}
+
+func (s *OrderDocumentService) output(pdf *fpdf.Fpdf, method string, fileName string) ([]byte, string, string) {
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		log.Error("OrderDocumentService." + method + " Error when render pdf: " + err.Error())
+		return nil, "", error_utils.ErrorCode.INTERNAL_SERVER_ERROR
+	}
+	return buffer.Bytes(), fileName, ""
+}
+
+var vietnameseDigits = []string{"không", "một", "hai", "ba", "bốn", "năm", "sáu", "bảy", "tám", "chín"}
+
+// readThreeDigits reads a group of three digits. full is set when a higher group has already
+// been read, so "không trăm" and "lẻ" must be spelled out (e.g. 1.005 = một nghìn không trăm lẻ năm)
+func readThreeDigits(number int, full bool) []string {
+	hundreds := number / 100
+	tens := (number / 10) % 10
+	units := number % 10
+
+	var words []string
+	if hundreds > 0 || full {
+		words = append(words, vietnameseDigits[hundreds], "trăm")
+	}
+
+	switch {
+	case tens == 0:
+		if units > 0 && (hundreds > 0 || full) {
+			words = append(words, "lẻ")
+		}
+	case tens == 1:
+		words = append(words, "mười")
+	default:
+		words = append(words, vietnameseDigits[tens], "mươi")
+	}
+
+	switch {
+	case units == 0:
+	case units == 1 && tens >= 2:
+		words = append(words, "mốt")
+	case units == 5 && tens >= 1:
+		words = append(words, "lăm")
+	default:
+		words = append(words, vietnameseDigits[units])
+	}
+
+	return words
+}
+
+// readVietnameseNumber reads a positive number, billions repeat so 10^12 is "một nghìn tỷ"
+func readVietnameseNumber(number int, full bool) []string {
+	var words []string
+	if number >= 1_000_000_000 {
+		words = append(words, readVietnameseNumber(number/1_000_000_000, full)...)
+		words = append(words, "tỷ")
+		full = true
+		number %= 1_000_000_000
+	}
+
+	groups := []struct {
+		divisor int
+		unit    string
+	}{
+		{1_000_000, "triệu"},
+		{1_000, "nghìn"},
+		{1, ""},
+	}
+	for _, group := range groups {
+		value := (number / group.divisor) % 1000
+		if value == 0 {
+			continue
+		}
+		words = append(words, readThreeDigits(value, full)...)
+		if group.unit != "" {
+			words = append(words, group.unit)
+		}
+		full = true
+	}
+
+	return words
+}
+
+// amountInVietnameseWords writes an amount in Vietnamese words, e.g. 1.250.000 = "Một triệu hai trăm năm mươi nghìn đồng"
+func amountInVietnameseWords(amount int) string {
+	prefix := ""
+	if amount < 0 {
+		prefix = "âm "
+		amount = -amount
+	}
+
+	words := "không"
+	if amount > 0 {
+		words = strings.Join(readVietnameseNumber(amount, false), " ")
+	}
+	text := prefix + words + " đồng"
+
+	// Capitalize the first letter
+	first, size := utf8.DecodeRuneInString(text)
+	return string(unicode.ToUpper(first)) + text[size:]
+}
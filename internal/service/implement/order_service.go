@@ -2,7 +2,11 @@ package serviceimplement
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,18 +24,29 @@ import (
 )
 
 type OrderService struct {
-	orderRepo            repository.OrderRepository
-	orderItemRepo        repository.OrderItemRepository
-	inventoryRepo        repository.InventoryRepository
-	inventoryHistoryRepo repository.InventoryHistoryRepository
-	productRepo          repository.ProductRepository
-	bomRepo              repository.ProductBomRepository
-	unitOfWork           repository.UnitOfWork
-	userRepo             repository.UserRepository
-	customerRepo         repository.CustomerRepository
-	orderImageRepo       repository.OrderImageRepository
-	unitRepo             repository.UnitOfMeasureRepository
-	s3Service            bean.S3Service
+	orderRepo                repository.OrderRepository
+	orderItemRepo            repository.OrderItemRepository
+	inventoryRepo            repository.InventoryRepository
+	inventoryHistoryRepo     repository.InventoryHistoryRepository
+	productRepo              repository.ProductRepository
+	bomRepo                  repository.ProductBomRepository
+	unitOfWork               repository.UnitOfWork
+	userRepo                 repository.UserRepository
+	customerRepo             repository.CustomerRepository
+	orderImageRepo           repository.OrderImageRepository
+	orderStatusHistoryRepo   repository.OrderStatusHistoryRepository
+	paymentRepo              repository.PaymentRepository
+	salesReturnRepo          repository.SalesReturnRepository
+	inventoryReservationRepo repository.InventoryReservationRepository
+	unitRepo                 repository.UnitOfMeasureRepository
+	warehouseRepo            repository.WarehouseRepository
+	inventoryLotRepo         repository.InventoryLotRepository
+	orderItemLotRepo         repository.OrderItemLotRepository
+	costLayers               *costLayerLedger
+	valuationMethod          string
+	s3Service                bean.S3Service
+	stockRounding            stockRounding
+	unitConverter            *unitConverter
 }
 
 func NewOrderService(
@@ -47,20 +62,41 @@ func NewOrderService(
 	s3Service bean.S3Service,
 	customerRepo repository.CustomerRepository,
 	unitRepo repository.UnitOfMeasureRepository,
+	orderStatusHistoryRepo repository.OrderStatusHistoryRepository,
+	paymentRepo repository.PaymentRepository,
+	salesReturnRepo repository.SalesReturnRepository,
+	inventoryReservationRepo repository.InventoryReservationRepository,
+	unitConversionRepo repository.UnitConversionRepository,
+	warehouseRepo repository.WarehouseRepository,
+	inventoryLotRepo repository.InventoryLotRepository,
+	orderItemLotRepo repository.OrderItemLotRepository,
+	inventoryCostLayerRepo repository.InventoryCostLayerRepository,
+	inventoryCostLayerMovementRepo repository.InventoryCostLayerMovementRepository,
 ) service.OrderService {
 	return &OrderService{
-		orderRepo:            orderRepo,
-		inventoryRepo:        inventoryRepo,
-		inventoryHistoryRepo: inventoryHistoryRepo,
-		orderItemRepo:        orderItemRepo,
-		productRepo:          productRepo,
-		bomRepo:              bomRepo,
-		unitOfWork:           unitOfWork,
-		userRepo:             userRepo,
-		customerRepo:         customerRepo,
-		orderImageRepo:       orderImageRepo,
-		unitRepo:             unitRepo,
-		s3Service:            s3Service,
+		orderRepo:                orderRepo,
+		inventoryRepo:            inventoryRepo,
+		inventoryHistoryRepo:     inventoryHistoryRepo,
+		orderItemRepo:            orderItemRepo,
+		productRepo:              productRepo,
+		bomRepo:                  bomRepo,
+		unitOfWork:               unitOfWork,
+		userRepo:                 userRepo,
+		customerRepo:             customerRepo,
+		orderImageRepo:           orderImageRepo,
+		unitRepo:                 unitRepo,
+		orderStatusHistoryRepo:   orderStatusHistoryRepo,
+		paymentRepo:              paymentRepo,
+		salesReturnRepo:          salesReturnRepo,
+		inventoryReservationRepo: inventoryReservationRepo,
+		warehouseRepo:            warehouseRepo,
+		inventoryLotRepo:         inventoryLotRepo,
+		orderItemLotRepo:         orderItemLotRepo,
+		costLayers:               newCostLayerLedger(inventoryCostLayerRepo, inventoryCostLayerMovementRepo),
+		valuationMethod:          valuationMethodFromEnv(),
+		s3Service:                s3Service,
+		stockRounding:            stockRoundingFromEnv(),
+		unitConverter:            newUnitConverter(unitConversionRepo),
 	}
 }
 
@@ -88,125 +124,344 @@ type RequiredMaterial struct {
 	Quantity  int
 }
 
-// calculateRequiredMaterialsForProduct calculates the required raw materials for a direct product order
-func (s *OrderService) calculateRequiredMaterialsForProduct(ctx *gin.Context, productID int, quantity int, tx *sqlx.Tx) ([]RequiredMaterial, error) {
-	product, err := s.productRepo.GetOneByIDQuery(ctx, productID, tx)
+// stockRounding is how fractional BOM quantities become whole stock quantities. By default the
+// totals of an order are rounded up, so that stock is never issued short.
+type stockRounding struct {
+	perItem bool // Round each order item on its own instead of the order totals
+	nearest bool // Round to the nearest unit instead of up
+}
+
+// stockRoundingFromEnv reads BOM_STOCK_ROUNDING, one of UP_PER_ORDER (default), UP_PER_ITEM,
+// NEAREST_PER_ORDER and NEAREST_PER_ITEM
+func stockRoundingFromEnv() stockRounding {
+	switch value := os.Getenv("BOM_STOCK_ROUNDING"); value {
+	case "", "UP_PER_ORDER":
+		return stockRounding{}
+	case "UP_PER_ITEM":
+		return stockRounding{perItem: true}
+	case "NEAREST_PER_ORDER":
+		return stockRounding{nearest: true}
+	case "NEAREST_PER_ITEM":
+		return stockRounding{perItem: true, nearest: true}
+	default:
+		log.Warn("Invalid BOM_STOCK_ROUNDING " + value + ", using UP_PER_ORDER")
+		return stockRounding{}
+	}
+}
+
+// round turns a fractional quantity into a whole stock quantity
+func (r stockRounding) round(quantity float64) int {
+	quantity = roundBomQuantity(quantity)
+	if r.nearest {
+		return int(math.Round(quantity))
+	}
+	return int(math.Ceil(quantity))
+}
+
+// addRounded rounds the quantities and adds them to the totals
+func (r stockRounding) addRounded(totals map[int]int, quantities map[int]float64) {
+	for productID, quantity := range quantities {
+		if rounded := r.round(quantity); rounded != 0 {
+			totals[productID] += rounded
+		}
+	}
+}
+
+// roundBomQuantity cuts a calculated quantity back to the 3 decimals BOM quantities are stored
+// with, so that floating point noise such as 2.0000000001 does not round up to 3
+func roundBomQuantity(quantity float64) float64 {
+	return math.Round(quantity*1000) / 1000
+}
+
+// grossBomQuantity returns how much of the component one unit of the parent consumes, the
+// component's scrap and the parent's yield loss included
+func grossBomQuantity(bom entity.ProductBom, parent *entity.Product) float64 {
+	quantity := bom.Quantity * (1 + bom.ScrapPercent/100)
+	if parent.YieldPercent > 0 {
+		quantity = quantity * 100 / parent.YieldPercent
+	}
+	return quantity
+}
+
+// explodeProductMaterials expands a product into the raw materials it is made of, following its
+// BOMs recursively. The quantities are exact, the caller decides how to round them. They are the
+// net BOM quantities, what was lost to scrap and yield is not in the finished product.
+func explodeProductMaterials(ctx context.Context, productRepo repository.ProductRepository, bomRepo repository.ProductBomRepository, productID int, quantity float64, date time.Time, tx *sqlx.Tx) (map[int]float64, error) {
+	materials := make(map[int]float64)
+	if err := explodeProductMaterialsAtLevel(ctx, productRepo, bomRepo, productID, quantity, date, 0, materials, tx); err != nil {
+		return nil, err
+	}
+	return materials, nil
+}
+
+func explodeProductMaterialsAtLevel(ctx context.Context, productRepo repository.ProductRepository, bomRepo repository.ProductBomRepository, productID int, quantity float64, date time.Time, level int, materials map[int]float64, tx *sqlx.Tx) error {
+	product, err := productRepo.GetOneByIDQuery(ctx, productID, tx)
 	if err != nil || product == nil {
-		return nil, fmt.Errorf("failed to get product %d: %w", productID, err)
+		return fmt.Errorf("failed to get product %d: %w", productID, err)
 	}
 
 	// If it's a PURCHASE type product, it's a raw material itself
 	if product.OperationType == "PURCHASE" {
-		return []RequiredMaterial{{ProductID: productID, Quantity: quantity}}, nil
+		materials[productID] += quantity
+		return nil
+	}
+
+	// Stop before a broken structure recurses forever
+	if level >= maxBomDepth {
+		return errBomTooDeep
 	}
 
 	// For PACKAGING and MANUFACTURING, expand their BOMs
-	boms, err := s.bomRepo.GetByParentProductIDQuery(ctx, productID, tx)
+	boms, err := bomRepo.GetEffectiveByParentProductIDQuery(ctx, productID, date, tx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get BOMs for product %d: %w", productID, err)
+		return fmt.Errorf("failed to get BOMs for product %d: %w", productID, err)
 	}
 
-	var allMaterials []RequiredMaterial
 	for _, bom := range boms {
 		// Recursively calculate materials for each component
-		componentMaterials, err := s.calculateRequiredMaterialsForProduct(ctx, bom.ComponentProductID, bom.Quantity*quantity, tx)
+		err := explodeProductMaterialsAtLevel(ctx, productRepo, bomRepo, bom.ComponentProductID, bom.Quantity*quantity, date, level+1, materials, tx)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		allMaterials = append(allMaterials, componentMaterials...)
 	}
 
-	return allMaterials, nil
+	return nil
 }
 
-// calculateFinalAmount calculates the final amount after applying discount
-func (s *OrderService) calculateFinalAmount(sellingPrice, quantity, discountPercent int) int {
-	subtotal := sellingPrice * quantity
-	discount := (subtotal * discountPercent) / 100
-	return subtotal - discount
+// stockAllocator works out what a set of demands draws from stock. Products with available
+// stock are taken as they are, finished goods included; only the shortfall of manufactured and
+// packaged products is exploded into their BOM components. Only the stock of one warehouse is
+// drawn from. The result is only a plan, the availability is checked again once the inventories
+// are locked.
+type stockAllocator struct {
+	ctx             context.Context
+	productRepo     repository.ProductRepository
+	bomRepo         repository.ProductBomRepository
+	inventoryRepo   repository.InventoryRepository
+	reservationRepo repository.InventoryReservationRepository
+	tx              *sqlx.Tx
+	warehouseID     int             // Warehouse the stock is drawn from
+	date            time.Time       // BOMs are exploded with the versions in effect at this date
+	ownHeld         map[int]int     // What the caller already holds and may draw from again
+	available       map[int]float64 // Remaining available quantity per product
+	allocated       map[int]float64 // Planned quantity to draw per product, not rounded yet
+	level           int             // Number of BOM levels currently being exploded
 }
 
-func (s *OrderService) CreateOrder(ctx *gin.Context, orderRequest model.CreateOrderRequest, userId int) (*model.OrderResponse, string) {
-	// Begin transaction
-	tx, err := s.unitOfWork.Begin(ctx)
+func newStockAllocator(ctx context.Context, productRepo repository.ProductRepository, bomRepo repository.ProductBomRepository, inventoryRepo repository.InventoryRepository, reservationRepo repository.InventoryReservationRepository, warehouseID int, ownHeld map[int]int, date time.Time, tx *sqlx.Tx) *stockAllocator {
+	return &stockAllocator{
+		ctx:             ctx,
+		productRepo:     productRepo,
+		bomRepo:         bomRepo,
+		inventoryRepo:   inventoryRepo,
+		reservationRepo: reservationRepo,
+		tx:              tx,
+		warehouseID:     warehouseID,
+		date:            date,
+		ownHeld:         ownHeld,
+		available:       make(map[int]float64),
+		allocated:       make(map[int]float64),
+	}
+}
+
+// takeAllocated returns what has been allocated since the last call
+func (a *stockAllocator) takeAllocated() map[int]float64 {
+	allocated := a.allocated
+	a.allocated = make(map[int]float64)
+	return allocated
+}
+
+// availableQuantity returns on hand minus reserved (plus what the caller holds) minus what has been allocated so far
+func (a *stockAllocator) availableQuantity(productID int) (float64, error) {
+	if quantity, exists := a.available[productID]; exists {
+		return quantity, nil
+	}
+
+	quantity := a.ownHeld[productID]
+	inventory, err := a.inventoryRepo.GetOneByProductIDQuery(a.ctx, productID, a.warehouseID, a.tx)
 	if err != nil {
-		log.Error("OrderService.CreateOrder Error when begin transaction: " + err.Error())
-		return nil, error_utils.ErrorCode.DB_DOWN
+		return 0, err
+	}
+	if inventory != nil {
+		quantity += inventory.Quantity
+	}
+	reservedQuantities, err := a.reservationRepo.GetReservedQuantitiesByProductIDsQuery(a.ctx, []int{productID}, &a.warehouseID, a.tx)
+	if err != nil {
+		return 0, err
 	}
+	quantity -= reservedQuantities[productID]
 
-	// Defer rollback in case of error
-	defer func() {
-		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
-			log.Error("OrderService.CreateOrder Error when rollback transaction: " + rollbackErr.Error())
-		}
-	}()
+	a.available[productID] = float64(quantity)
+	return float64(quantity), nil
+}
 
-	// Calculate total required materials from all order items
-	requiredMaterials := make(map[int]int) // productID -> total quantity needed
+// allocate draws the quantity of the product from stock and explodes the shortfall through its BOM
+func (a *stockAllocator) allocate(productID int, quantity float64) error {
+	product, err := a.productRepo.GetOneByIDQuery(a.ctx, productID, a.tx)
+	if err != nil || product == nil {
+		return fmt.Errorf("failed to get product %d: %w", productID, err)
+	}
 
-	for _, item := range orderRequest.Items {
-		var materials []RequiredMaterial
+	// A PURCHASE type product is a raw material, it can only come from stock
+	if product.OperationType == "PURCHASE" {
+		a.allocated[productID] += quantity
+		return nil
+	}
 
-		// Product order (can be direct product or parent product from BOM)
-		materials, err = s.calculateRequiredMaterialsForProduct(ctx, item.ProductID, item.Quantity, tx)
+	// Draw finished goods first
+	availableQty, err := a.availableQuantity(productID)
+	if err != nil {
+		return fmt.Errorf("failed to get available quantity of product %d: %w", productID, err)
+	}
+	taken := min(max(availableQty, 0), quantity)
+	if taken > 0 {
+		a.allocated[productID] += taken
+		a.available[productID] -= taken
+	}
+
+	shortfall := roundBomQuantity(quantity - taken)
+	if shortfall <= 0 {
+		return nil
+	}
+
+	return a.allocateComponents(productID, shortfall)
+}
 
+// allocateComponents explodes the quantity of the product one BOM level down and allocates each component
+func (a *stockAllocator) allocateComponents(productID int, quantity float64) error {
+	// Stop before a broken structure recurses forever
+	if a.level >= maxBomDepth {
+		return errBomTooDeep
+	}
+
+	product, err := a.productRepo.GetOneByIDQuery(a.ctx, productID, a.tx)
+	if err != nil || product == nil {
+		return fmt.Errorf("failed to get product %d: %w", productID, err)
+	}
+	boms, err := a.bomRepo.GetEffectiveByParentProductIDQuery(a.ctx, productID, a.date, a.tx)
+	if err != nil {
+		return fmt.Errorf("failed to get BOMs for product %d: %w", productID, err)
+	}
+
+	// Without a BOM the product can only come from its own stock, the shortage check reports it
+	if len(boms) == 0 {
+		a.allocated[productID] += quantity
+		return nil
+	}
+
+	a.level++
+	defer func() { a.level-- }()
+
+	for _, bom := range boms {
+		if err := a.allocate(bom.ComponentProductID, grossBomQuantity(bom, product)*quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lockInventories locks the inventories of the given products in the warehouse, in product order,
+// so that concurrent orders touching the same materials are applied one after another. Products
+// the warehouse has never held get an empty inventory first.
+func lockInventories(ctx context.Context, inventoryRepo repository.InventoryRepository, productIDs []int, warehouseID int, tx *sqlx.Tx) (map[int]*entity.Inventory, string) {
+	for _, productID := range productIDs {
+		inventory, err := inventoryRepo.GetOneByProductIDQuery(ctx, productID, warehouseID, tx)
 		if err != nil {
-			log.Error("OrderService.CreateOrder Error calculating required materials: " + err.Error())
+			log.Error("lockInventories Error when get inventory: " + err.Error())
 			return nil, error_utils.ErrorCode.DB_DOWN
 		}
-
-		// Aggregate the required materials
-		for _, material := range materials {
-			requiredMaterials[material.ProductID] += material.Quantity
+		if inventory != nil {
+			continue
+		}
+		newInventory := &entity.Inventory{
+			ProductID:   productID,
+			WarehouseID: warehouseID,
+			Quantity:    0,
+			Version:     uuid.New().String(),
+		}
+		if err := inventoryRepo.CreateCommand(ctx, newInventory, tx); err != nil {
+			log.Error("lockInventories Error when create inventory: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
 		}
 	}
 
-	// Get all required product IDs for inventory checking
-	var productIDs []int
-	for productID := range requiredMaterials {
-		productIDs = append(productIDs, productID)
+	inventoryIDs, err := inventoryRepo.GetInventoryIDsByProductIDsQuery(ctx, productIDs, warehouseID, tx)
+	if err != nil {
+		log.Error("lockInventories Error when get inventory ids: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
 	}
-
-	// Lock the inventories to prevent concurrent access
-	inventories, err := s.inventoryRepo.SelectManyForUpdate(ctx, productIDs, tx)
+	inventories, err := inventoryRepo.SelectManyForUpdate(ctx, inventoryIDs, tx)
 	if err != nil {
-		log.Error("OrderService.CreateOrder Error when lock inventories: " + err.Error())
+		log.Error("lockInventories Error when lock inventories: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
-	// Check if we have enough inventory for all required materials
 	inventoryMap := make(map[int]*entity.Inventory)
 	for i := range inventories {
 		inventoryMap[inventories[i].ProductID] = &inventories[i]
 	}
-
-	var insufficientItems []string
-	for productID, requiredQty := range requiredMaterials {
-		inventory, exists := inventoryMap[productID]
-		if !exists {
-			log.Error(fmt.Sprintf("OrderService.CreateOrder Error: inventory not found for product ID %d", productID))
+	for _, productID := range productIDs {
+		if _, exists := inventoryMap[productID]; !exists {
+			log.Error(fmt.Sprintf("lockInventories Error: inventory not found for product ID %d", productID))
 			return nil, error_utils.ErrorCode.NOT_FOUND
 		}
-		if inventory.Quantity < requiredQty {
+	}
+
+	return inventoryMap, ""
+}
+
+// checkStockAvailability makes sure the required quantities fit into the available stock of the
+// warehouse, that is on hand minus what orders have reserved there. ownReserved is what the
+// caller itself holds and may draw from. Shortages are reported with the detailed "Thiếu ..." message.
+func checkStockAvailability(ctx *gin.Context, productRepo repository.ProductRepository, unitRepo repository.UnitOfMeasureRepository, reservationRepo repository.InventoryReservationRepository, warehouseID int, inventoryMap map[int]*entity.Inventory, required map[int]int, ownReserved map[int]int, tx *sqlx.Tx) string {
+	var productIDs []int
+	for productID, quantity := range required {
+		if quantity > 0 {
+			productIDs = append(productIDs, productID)
+		}
+	}
+	if len(productIDs) == 0 {
+		return ""
+	}
+	sort.Ints(productIDs)
+
+	reservedQuantities, err := reservationRepo.GetReservedQuantitiesByProductIDsQuery(ctx, productIDs, &warehouseID, tx)
+	if err != nil {
+		log.Error("checkStockAvailability Error when get reserved quantities: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	var insufficientItems []string
+	for _, productID := range productIDs {
+		inventory := inventoryMap[productID]
+		reservedByOthers := reservedQuantities[productID] - ownReserved[productID]
+		availableQty := inventory.Quantity - reservedByOthers
+		requiredQty := required[productID]
+		if availableQty < requiredQty {
 			// Get product details for detailed error message
-			product, err := s.productRepo.GetOneByIDQuery(ctx, productID, tx)
+			product, err := productRepo.GetOneByIDQuery(ctx, productID, tx)
 			if err != nil || product == nil {
-				log.Error(fmt.Sprintf("OrderService.CreateOrder Error: failed to get product details for ID %d", productID))
-				return nil, error_utils.ErrorCode.DB_DOWN
+				log.Error(fmt.Sprintf("checkStockAvailability Error: failed to get product details for ID %d", productID))
+				return error_utils.ErrorCode.DB_DOWN
 			}
 
-			shortage := requiredQty - inventory.Quantity
+			shortage := requiredQty - availableQty
 			unitName := "đơn vị"
 
 			// Get unit name if available
 			if product.UnitID != nil {
-				unit, err := s.unitRepo.GetOneByIDQuery(ctx, *product.UnitID, tx)
+				unit, err := unitRepo.GetOneByIDQuery(ctx, *product.UnitID, tx)
 				if err == nil && unit != nil {
 					unitName = unit.Name
 				}
 			}
 
-			insufficientItems = append(insufficientItems, fmt.Sprintf("%s %s %s (tồn kho %s %s)", formatNumberWithDots(shortage), unitName, product.Name, formatNumberWithDots(inventory.Quantity), unitName))
+			if reservedByOthers > 0 {
+				insufficientItems = append(insufficientItems, fmt.Sprintf("%s %s %s (tồn kho %s %s, đã giữ chỗ %s %s)", formatNumberWithDots(shortage), unitName, product.Name, formatNumberWithDots(inventory.Quantity), unitName, formatNumberWithDots(reservedByOthers), unitName))
+			} else {
+				insufficientItems = append(insufficientItems, fmt.Sprintf("%s %s %s (tồn kho %s %s)", formatNumberWithDots(shortage), unitName, product.Name, formatNumberWithDots(inventory.Quantity), unitName))
+			}
 		}
 	}
 
@@ -215,12 +470,512 @@ func (s *OrderService) CreateOrder(ctx *gin.Context, orderRequest model.CreateOr
 
 		// Store detailed error message in context for handler to access
 		ctx.Set("detailed_error_message", detailedMessage)
-		return nil, error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED
+		return error_utils.ErrorCode.INVENTORY_QUANTITY_EXCEEDED
+	}
+
+	return ""
+}
+
+// restoreOrderCostLayers puts stock coming back from the order into the cost layers the order
+// took it from, valuing what the order did not take from a layer at the product's current cost
+func (s *OrderService) restoreOrderCostLayers(ctx context.Context, orderID int, productID int, quantity int, tx *sqlx.Tx) error {
+	product, err := s.productRepo.GetOneByIDQuery(ctx, productID, tx)
+	if err != nil {
+		return err
+	}
+	var fallbackCost float64
+	if product != nil {
+		fallbackCost = product.Cost
+	}
+	return s.costLayers.restore(ctx, productID, quantity, entity.InventoryCostLayerReferenceType.ORDER, orderID, fallbackCost, tx)
+}
+
+// applyInventoryChanges locks the inventories of the given products in the order's warehouse and
+// applies the signed on-hand quantity changes (negative = issue, positive = restock), writing one
+// history row per product that references the order. ownReserved is the part of the issue that the order had
+// reserved; it is consumed from the reservation ledger.
+func (s *OrderService) applyInventoryChanges(ctx *gin.Context, order *entity.Order, changes map[int]int, ownReserved map[int]int, importerName string, note string, tx *sqlx.Tx) string {
+	var productIDs []int
+	for productID, quantity := range changes {
+		if quantity != 0 {
+			productIDs = append(productIDs, productID)
+		}
+	}
+	sort.Ints(productIDs)
+
+	// Lock the inventories to prevent concurrent access
+	inventoryMap, errCode := lockInventories(ctx, s.inventoryRepo, productIDs, order.WarehouseID, tx)
+	if errCode != "" {
+		return errCode
+	}
+
+	// Check if we have enough available inventory for all issued materials
+	required := make(map[int]int)
+	for _, productID := range productIDs {
+		if changes[productID] < 0 {
+			required[productID] = -changes[productID]
+		}
+	}
+	if errCode := checkStockAvailability(ctx, s.productRepo, s.unitRepo, s.inventoryReservationRepo, order.WarehouseID, inventoryMap, required, ownReserved, tx); errCode != "" {
+		return errCode
+	}
+
+	for _, productID := range productIDs {
+		inventory := inventoryMap[productID]
+		change := changes[productID]
+		newQuantity := inventory.Quantity + change
+
+		err := s.inventoryRepo.UpdateQuantityCommand(ctx, productID, order.WarehouseID, change, uuid.New().String(), tx)
+		if err != nil {
+			log.Error(fmt.Sprintf("OrderService.applyInventoryChanges Error when update inventory for product ID %d: %s", productID, err.Error()))
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		// Issues draw lots FEFO, stock coming back returns to the lots the order drew
+		if change < 0 {
+			err = drawOrderLots(ctx, s.inventoryLotRepo, s.orderItemLotRepo, s.orderItemRepo, order, productID, inventory.Quantity, -change, tx)
+		} else {
+			err = returnOrderLots(ctx, s.inventoryLotRepo, s.orderItemLotRepo, order.ID, productID, change, tx)
+		}
+		if errors.Is(err, errInsufficientUnexpiredStock) {
+			return error_utils.ErrorCode.INSUFFICIENT_UNEXPIRED_STOCK
+		}
+		if err != nil {
+			log.Error(fmt.Sprintf("OrderService.applyInventoryChanges Error when update lots for product ID %d: %s", productID, err.Error()))
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		// Issues take the oldest cost layers, stock coming back returns to the layers the order took
+		if change < 0 {
+			_, err = s.costLayers.consume(ctx, productID, -change, entity.InventoryCostLayerReferenceType.ORDER, &order.ID, tx)
+		} else {
+			err = s.restoreOrderCostLayers(ctx, order.ID, productID, change, tx)
+		}
+		if err != nil {
+			log.Error(fmt.Sprintf("OrderService.applyInventoryChanges Error when update cost layers for product ID %d: %s", productID, err.Error()))
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		inventoryHistory := &entity.InventoryHistory{
+			ProductID:     productID,
+			WarehouseID:   &order.WarehouseID,
+			Quantity:      change,
+			FinalQuantity: newQuantity,
+			ImporterName:  importerName,
+			ImportedAt:    time.Now(),
+			Note:          note,
+			ReferenceID:   &order.ID,
+			ReferenceType: &entity.InventoryHistoryReferenceType.ORDER,
+		}
+
+		err = s.inventoryHistoryRepo.CreateCommand(ctx, inventoryHistory, tx)
+		if err != nil {
+			log.Error("OrderService.applyInventoryChanges Error when create inventory history: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		// The issued stock no longer needs to be held for the order
+		issuedFromReservation := min(ownReserved[productID], -change)
+		if issuedFromReservation > 0 {
+			reservation := &entity.InventoryReservation{
+				ProductID:     productID,
+				WarehouseID:   order.WarehouseID,
+				OrderID:       order.ID,
+				Quantity:      -issuedFromReservation,
+				EntryType:     entity.InventoryReservationEntryType.ISSUE,
+				Note:          &note,
+				CreatedByName: importerName,
+			}
+			err = s.inventoryReservationRepo.CreateCommand(ctx, reservation, tx)
+			if err != nil {
+				log.Error("OrderService.applyInventoryChanges Error when create reservation entry: " + err.Error())
+				return error_utils.ErrorCode.DB_DOWN
+			}
+		}
+	}
+
+	return s.snapshotFifoCost(ctx, order, tx)
+}
+
+// snapshotFifoCost rewrites the cost of the order lines with the cost of the stock issued for the
+// order when stock is valued FIFO. A line sold straight from stock takes the FIFO cost of its
+// product; the materials issued for lines built from their BOM are shared between those lines in
+// proportion to their moving-average cost. Under AVERAGE the moving-average cost snapshot taken when
+// the line was saved stays, as it does once the order has given back all its stock.
+func (s *OrderService) snapshotFifoCost(ctx context.Context, order *entity.Order, tx *sqlx.Tx) string {
+	if s.valuationMethod != entity.InventoryValuationMethod.FIFO {
+		return ""
+	}
+
+	consumedStock, err := s.getConsumedStock(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.snapshotFifoCost Error when get inventory histories: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	layerQuantities, layerValues, err := s.costLayers.consumedByProduct(ctx, entity.InventoryCostLayerReferenceType.ORDER, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.snapshotFifoCost Error when get consumed cost layers: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	productCosts := make(map[int]float64)
+	getProductCost := func(productID int) (float64, error) {
+		if cost, exists := productCosts[productID]; exists {
+			return cost, nil
+		}
+		product, err := s.productRepo.GetOneByIDQuery(ctx, productID, tx)
+		if err != nil {
+			return 0, err
+		}
+		if product != nil {
+			productCosts[productID] = product.Cost
+		}
+		return productCosts[productID], nil
+	}
+
+	// FIFO cost of what the order holds per product
+	issuedValues := make(map[int]float64)
+	var totalValue float64
+	for productID, quantity := range consumedStock {
+		if quantity <= 0 {
+			continue
+		}
+		averageCost, err := getProductCost(productID)
+		if err != nil {
+			log.Error("OrderService.snapshotFifoCost Error when get product: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		issuedValues[productID] = fifoIssueValue(quantity, layerQuantities[productID], layerValues[productID], averageCost)
+		totalValue += issuedValues[productID]
+	}
+	if len(issuedValues) == 0 {
+		return ""
+	}
+
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.snapshotFifoCost Error when get order items: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	orderedQuantities := make(map[int]int)
+	for _, item := range orderItems {
+		orderedQuantities[item.ProductID] += item.BaseQuantity
+	}
+
+	// Lines sold straight from stock take the cost of their own product, what is left is the
+	// cost of the materials of the lines built to order
+	lineValues := make(map[int]float64)
+	builtQuantities := make(map[int]int)
+	builtWeights := make(map[int]float64)
+	remainingValue := totalValue
+	var totalBuilt int
+	var totalWeight float64
+	for productID, ordered := range orderedQuantities {
+		direct := min(max(consumedStock[productID], 0), ordered)
+		if direct > 0 {
+			lineValues[productID] = issuedValues[productID] * float64(direct) / float64(consumedStock[productID])
+			remainingValue -= lineValues[productID]
+		}
+		if built := ordered - direct; built > 0 {
+			averageCost, err := getProductCost(productID)
+			if err != nil {
+				log.Error("OrderService.snapshotFifoCost Error when get product: " + err.Error())
+				return error_utils.ErrorCode.DB_DOWN
+			}
+			builtQuantities[productID] = built
+			builtWeights[productID] = float64(built) * averageCost
+			totalBuilt += built
+			totalWeight += builtWeights[productID]
+		}
+	}
+	for productID, weight := range builtWeights {
+		share := weight / totalWeight
+		if totalWeight <= 0 {
+			share = float64(builtQuantities[productID]) / float64(totalBuilt)
+		}
+		lineValues[productID] += remainingValue * share
+	}
+
+	var totalOriginalCost int
+	for i := range orderItems {
+		item := &orderItems[i]
+		if ordered := orderedQuantities[item.ProductID]; ordered > 0 && item.Quantity > 0 {
+			baseUnitCost := lineValues[item.ProductID] / float64(ordered)
+			item.OriginalPrice = int(math.Round(baseUnitCost * float64(item.BaseQuantity) / float64(item.Quantity)))
+			if err := s.orderItemRepo.UpdateCommand(ctx, item, tx); err != nil {
+				log.Error("OrderService.snapshotFifoCost Error when update order item: " + err.Error())
+				return error_utils.ErrorCode.DB_DOWN
+			}
+		}
+		totalOriginalCost += item.OriginalPrice * item.Quantity
+	}
+
+	order.TotalOriginalCost = totalOriginalCost
+	if err := s.orderRepo.UpdateCommand(ctx, order, tx); err != nil {
+		log.Error("OrderService.snapshotFifoCost Error when update order totals: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	return ""
+}
+
+// applyReservationChanges locks the inventories of the given products in the order's warehouse
+// and writes the signed reservation changes (positive = reserve, negative = release) to the
+// ledger. On-hand stock is not touched. New reservations must fit into the available stock.
+func (s *OrderService) applyReservationChanges(ctx *gin.Context, order *entity.Order, changes map[int]int, importerName string, note string, tx *sqlx.Tx) string {
+	var productIDs []int
+	for productID, quantity := range changes {
+		if quantity != 0 {
+			productIDs = append(productIDs, productID)
+		}
+	}
+	sort.Ints(productIDs)
+
+	// Lock the inventories so that two orders cannot reserve the same stock
+	inventoryMap, errCode := lockInventories(ctx, s.inventoryRepo, productIDs, order.WarehouseID, tx)
+	if errCode != "" {
+		return errCode
+	}
+
+	if errCode := checkStockAvailability(ctx, s.productRepo, s.unitRepo, s.inventoryReservationRepo, order.WarehouseID, inventoryMap, changes, nil, tx); errCode != "" {
+		return errCode
+	}
+
+	for _, productID := range productIDs {
+		change := changes[productID]
+		entryType := entity.InventoryReservationEntryType.RESERVE
+		if change < 0 {
+			entryType = entity.InventoryReservationEntryType.RELEASE
+		}
+
+		reservation := &entity.InventoryReservation{
+			ProductID:     productID,
+			WarehouseID:   order.WarehouseID,
+			OrderID:       order.ID,
+			Quantity:      change,
+			EntryType:     entryType,
+			Note:          &note,
+			CreatedByName: importerName,
+		}
+		err := s.inventoryReservationRepo.CreateCommand(ctx, reservation, tx)
+		if err != nil {
+			log.Error("OrderService.applyReservationChanges Error when create reservation entry: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	return ""
+}
+
+// isReservingOrder reports whether the order holds its materials in the reservation ledger
+// instead of having them issued. Pending orders created before reservations existed had their
+// stock deducted at creation and keep being adjusted on hand.
+func isReservingOrder(ctx context.Context, reservationRepo repository.InventoryReservationRepository, order *entity.Order, tx *sqlx.Tx) (bool, error) {
+	if currentOrderStatus(order) != entity.OrderDeliveryStatus.PENDING {
+		return false, nil
+	}
+	reservations, err := reservationRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		return false, err
+	}
+	return len(reservations) > 0, nil
+}
+
+// orderStatusTransitions lists the statuses an order may move to from each status.
+// COMPLETED and CANCELLED are terminal. Only an order not yet delivered can be cancelled,
+// delivered goods come back through a sales return.
+var orderStatusTransitions = map[string][]string{
+	entity.OrderDeliveryStatus.PENDING:   {entity.OrderDeliveryStatus.DELIVERED, entity.OrderDeliveryStatus.CANCELLED},
+	entity.OrderDeliveryStatus.DELIVERED: {entity.OrderDeliveryStatus.UNPAID, entity.OrderDeliveryStatus.COMPLETED},
+	entity.OrderDeliveryStatus.UNPAID:    {entity.OrderDeliveryStatus.COMPLETED},
+	entity.OrderDeliveryStatus.COMPLETED: {},
+	entity.OrderDeliveryStatus.CANCELLED: {},
+}
+
+// canTransitionOrderStatus reports whether an order may move from one status to another
+func canTransitionOrderStatus(from string, to string) bool {
+	for _, allowed := range orderStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// currentOrderStatus returns the order status, treating orders created before the
+// status column existed as PENDING
+func currentOrderStatus(order *entity.Order) string {
+	if order.DeliveryStatus == "" {
+		return entity.OrderDeliveryStatus.PENDING
+	}
+	return order.DeliveryStatus
+}
+
+// recordStatusChange writes an order status history row for the given user
+func (s *OrderService) recordStatusChange(ctx context.Context, orderID int, fromStatus *string, toStatus string, userID int, reason *string, tx *sqlx.Tx) string {
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, tx)
+	if err != nil {
+		log.Error("OrderService.recordStatusChange Error when get user: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	history := &entity.OrderStatusHistory{
+		OrderID:       orderID,
+		FromStatus:    fromStatus,
+		ToStatus:      toStatus,
+		ChangedBy:     &user.ID,
+		ChangedByName: user.Username,
+		Reason:        reason,
+		ChangedAt:     time.Now(),
+	}
+
+	err = s.orderStatusHistoryRepo.CreateCommand(ctx, history, tx)
+	if err != nil {
+		log.Error("OrderService.recordStatusChange Error when create status history: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+// calculateFinalAmount calculates the final amount after applying discount
+func (s *OrderService) calculateFinalAmount(sellingPrice, quantity, discountPercent int) int {
+	subtotal := sellingPrice * quantity
+	discount := (subtotal * discountPercent) / 100
+	return subtotal - discount
+}
+
+// allocateOrderStock returns what the ordered products draw from the warehouse's stock per
+// product, rounded to whole stock quantities. ownHeld is what the order already holds (reserved
+// or consumed) and may keep. BOMs are exploded with the versions in effect at the order date.
+func (s *OrderService) allocateOrderStock(ctx context.Context, lines []RequiredMaterial, ownHeld map[int]int, warehouseID int, orderDate time.Time, tx *sqlx.Tx) (map[int]int, error) {
+	allocator := newStockAllocator(ctx, s.productRepo, s.bomRepo, s.inventoryRepo, s.inventoryReservationRepo, warehouseID, ownHeld, orderDate, tx)
+	requiredStock := make(map[int]int)
+	for _, line := range lines {
+		if err := allocator.allocate(line.ProductID, float64(line.Quantity)); err != nil {
+			return nil, err
+		}
+		if s.stockRounding.perItem {
+			s.stockRounding.addRounded(requiredStock, allocator.takeAllocated())
+		}
+	}
+	s.stockRounding.addRounded(requiredStock, allocator.takeAllocated())
+	return requiredStock, nil
+}
+
+// toBaseQuantity converts an ordered quantity into the product's base unit, which stock is kept in.
+// It also returns the product's current moving-average cost per ordered unit, the cost the line is
+// sold at; under FIFO it is replaced by the cost of the stock issued (see snapshotFifoCost).
+func (s *OrderService) toBaseQuantity(ctx context.Context, productID int, unitID *int, quantity int, tx *sqlx.Tx) (int, int, string) {
+	product, err := s.productRepo.GetOneByIDQuery(ctx, productID, tx)
+	if err != nil {
+		log.Error("OrderService.toBaseQuantity Error when get product: " + err.Error())
+		return 0, 0, error_utils.ErrorCode.DB_DOWN
+	}
+	if product == nil {
+		return 0, 0, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	factor, errCode := s.unitConverter.baseFactor(ctx, product, unitID, tx)
+	if errCode != "" {
+		return 0, 0, errCode
+	}
+	baseQuantity, errCode := toWholeBaseQuantity(quantity, factor)
+	if errCode != "" {
+		return 0, 0, errCode
+	}
+	return baseQuantity, int(math.Round(product.Cost * factor)), ""
+}
+
+// sameUnit tells whether two order lines are sold in the same unit, nil being the base unit
+func sameUnit(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// getConsumedStock works out what the order actually consumed from its inventory histories,
+// so that reversals do not depend on the current BOM
+func (s *OrderService) getConsumedStock(ctx context.Context, orderID int, tx *sqlx.Tx) (map[int]int, error) {
+	histories, err := s.inventoryHistoryRepo.GetAllByReferenceQuery(ctx, entity.InventoryHistoryReferenceType.ORDER, orderID, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	consumedStock := make(map[int]int) // productID -> quantity taken out
+	for _, history := range histories {
+		consumedStock[history.ProductID] -= history.Quantity
+	}
+	return consumedStock, nil
+}
+
+func (s *OrderService) CreateOrder(ctx *gin.Context, orderRequest model.CreateOrderRequest, userId int) (*model.OrderResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderService.CreateOrder Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderService.CreateOrder Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	response, errCode := s.CreateOrderTx(ctx, orderRequest, userId, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderService.CreateOrder Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return response, ""
+}
+
+// CreateOrderTx creates the order inside the caller's transaction, the caller commits it
+func (s *OrderService) CreateOrderTx(ctx *gin.Context, orderRequest model.CreateOrderRequest, userId int, tx *sqlx.Tx) (*model.OrderResponse, string) {
+	// Stock is drawn from the given warehouse, the default one when none is given
+	warehouse, errCode := resolveWarehouse(ctx, s.warehouseRepo, orderRequest.WarehouseID, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Calculate total required stock from all order items in their base units, finished goods first
+	orderedProducts := make([]RequiredMaterial, 0, len(orderRequest.Items))
+	baseQuantities := make([]int, len(orderRequest.Items))
+	originalPrices := make([]int, len(orderRequest.Items))
+	for i, item := range orderRequest.Items {
+		baseQuantity, originalPrice, errCode := s.toBaseQuantity(ctx, item.ProductID, item.UnitID, item.Quantity, tx)
+		if errCode != "" {
+			return nil, errCode
+		}
+		baseQuantities[i] = baseQuantity
+		originalPrices[i] = originalPrice
+		orderedProducts = append(orderedProducts, RequiredMaterial{ProductID: item.ProductID, Quantity: baseQuantity})
+	}
+	requiredMaterials, err := s.allocateOrderStock(ctx, orderedProducts, nil, warehouse.ID, orderRequest.OrderDate, tx)
+	if errors.Is(err, errBomTooDeep) {
+		return nil, error_utils.ErrorCode.BOM_DEPTH_EXCEEDED
+	}
+	if err != nil {
+		log.Error("OrderService.CreateOrder Error calculating required materials: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
 	// Create the order
 	order := &entity.Order{
 		CustomerID:         orderRequest.CustomerID,
+		WarehouseID:        warehouse.ID,
 		OrderDate:          orderRequest.OrderDate,
 		Note:               orderRequest.Note,
 		TotalOriginalCost:  0, // Will be calculated
@@ -230,6 +985,9 @@ func (s *OrderService) CreateOrder(ctx *gin.Context, orderRequest model.CreateOr
 		TaxPercent:         orderRequest.TaxPercent,
 		DeliveryStatus:     orderRequest.DeliveryStatus,
 	}
+	if order.DeliveryStatus == "" {
+		order.DeliveryStatus = entity.OrderDeliveryStatus.PENDING
+	}
 
 	err = s.orderRepo.CreateCommand(ctx, order, tx)
 	if err != nil {
@@ -239,15 +997,17 @@ func (s *OrderService) CreateOrder(ctx *gin.Context, orderRequest model.CreateOr
 
 	// Calculate totals and create order items
 	var totalOriginalCost, totalSalesRevenue int
-	for _, itemRequest := range orderRequest.Items {
+	for i, itemRequest := range orderRequest.Items {
 		finalAmount := s.calculateFinalAmount(itemRequest.SellingPrice, itemRequest.Quantity, itemRequest.DiscountPercent)
 
 		orderItem := &entity.OrderItem{
 			OrderID:         order.ID,
 			ProductID:       itemRequest.ProductID,
 			Quantity:        itemRequest.Quantity,
+			UnitID:          itemRequest.UnitID,
+			BaseQuantity:    baseQuantities[i],
 			SellingPrice:    itemRequest.SellingPrice,
-			OriginalPrice:   itemRequest.OriginalPrice,
+			OriginalPrice:   originalPrices[i], // Snapshot of the product cost at sale time
 			DiscountPercent: itemRequest.DiscountPercent,
 			FinalAmount:     finalAmount,
 		}
@@ -258,7 +1018,7 @@ func (s *OrderService) CreateOrder(ctx *gin.Context, orderRequest model.CreateOr
 			return nil, error_utils.ErrorCode.DB_DOWN
 		}
 
-		totalOriginalCost += itemRequest.OriginalPrice * itemRequest.Quantity
+		totalOriginalCost += orderItem.OriginalPrice * itemRequest.Quantity
 		totalSalesRevenue += finalAmount
 	}
 
@@ -277,34 +1037,24 @@ func (s *OrderService) CreateOrder(ctx *gin.Context, orderRequest model.CreateOr
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
-	// Deduct inventory for all required materials
-	for productID, requiredQty := range requiredMaterials {
-		inventory := inventoryMap[productID]
-		newQuantity := inventory.Quantity - requiredQty
-
-		uuid := uuid.New()
-		err = s.inventoryRepo.UpdateQuantityCommand(ctx, productID, newQuantity, uuid.String(), tx)
-		if err != nil {
-			log.Error(fmt.Sprintf("OrderService.CreateOrder Error when update inventory for product ID %d: %s", productID, err.Error()))
-			return nil, error_utils.ErrorCode.DB_DOWN
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.PENDING {
+		// Pending orders only reserve their materials, stock is issued on delivery
+		if errCode := s.applyReservationChanges(ctx, order, requiredMaterials, user.Username, "Giữ chỗ cho đơn hàng: "+order.Code, tx); errCode != "" {
+			return nil, errCode
 		}
-
-		inventoryHistory := &entity.InventoryHistory{
-			ProductID:     productID,
-			Quantity:      -requiredQty,
-			FinalQuantity: newQuantity,
-			ImporterName:  user.Username,
-			ImportedAt:    time.Now(),
-			Note:          "Xuất cho đơn hàng: " + order.Code,
-			ReferenceID:   &order.ID,
+	} else {
+		// Deduct inventory for all required materials
+		inventoryChanges := make(map[int]int)
+		for productID, requiredQty := range requiredMaterials {
+			inventoryChanges[productID] = -requiredQty
 		}
-
-		err = s.inventoryHistoryRepo.CreateCommand(ctx, inventoryHistory, tx)
-		if err != nil {
-			log.Error("OrderService.CreateOrder Error when create inventory history: " + err.Error())
-			return nil, error_utils.ErrorCode.DB_DOWN
+		if errCode := s.applyInventoryChanges(ctx, order, inventoryChanges, nil, user.Username, "Xuất cho đơn hàng: "+order.Code, tx); errCode != "" {
+			return nil, errCode
 		}
+	}
 
+	if errCode := s.recordStatusChange(ctx, order.ID, nil, order.DeliveryStatus, userId, nil, tx); errCode != "" {
+		return nil, errCode
 	}
 
 	customer, err := s.customerRepo.GetOneByIDQuery(ctx, order.CustomerID, tx)
@@ -313,18 +1063,12 @@ func (s *OrderService) CreateOrder(ctx *gin.Context, orderRequest model.CreateOr
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
-	// Commit transaction
-	err = s.unitOfWork.Commit(tx)
-	if err != nil {
-		log.Error("OrderService.CreateOrder Error when commit transaction: " + err.Error())
-		return nil, error_utils.ErrorCode.DB_DOWN
-	}
-
 	// Return response
 	return &model.OrderResponse{
-		ID:        order.ID,
-		Code:      order.Code,
-		OrderDate: order.OrderDate,
+		ID:          order.ID,
+		Code:        order.Code,
+		OrderDate:   order.OrderDate,
+		WarehouseID: order.WarehouseID,
 		Customer: model.CustomerResponse{
 			ID:      order.CustomerID,
 			Name:    customer.Name,
@@ -358,6 +1102,64 @@ func calculateOrderAmountsAndProductCount(orderItems []entity.OrderItem) (totalA
 	return
 }
 
+// calculateOrderAmountDue returns the amount the customer owes for an order
+// (items + additional cost + tax) and the number of distinct products in it
+func calculateOrderAmountDue(order *entity.Order, orderItems []entity.OrderItem) (amountDue int, productCount int) {
+	amountDue, productCount = calculateOrderAmountsAndProductCount(orderItems)
+	amountDue += order.AdditionalCost
+	amountDue += int(float64(amountDue) * float64(order.TaxPercent) / 100)
+	return
+}
+
+// checkAmountDueCoversPaid rejects an edit that lowers the order's amount due, less its returns,
+// below what the customer already paid: the overpayment would vanish from the outstanding amount.
+// The order must be locked so that no payment is recorded in the meantime.
+func (s *OrderService) checkAmountDueCoversPaid(ctx context.Context, order *entity.Order, previousAmountDue int, tx *sqlx.Tx) string {
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.checkAmountDueCoversPaid Error when get order items: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	amountDue, _ := calculateOrderAmountDue(order, orderItems)
+	if amountDue >= previousAmountDue {
+		return ""
+	}
+
+	paidAmount, err := s.paymentRepo.GetTotalAmountByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.checkAmountDueCoversPaid Error when get paid amount: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	returnedAmount, err := s.salesReturnRepo.GetTotalCreditAmountByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.checkAmountDueCoversPaid Error when get returned amount: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if amountDue-returnedAmount < paidAmount {
+		return error_utils.ErrorCode.ORDER_AMOUNT_BELOW_PAID
+	}
+	return ""
+}
+
+// calculatePaymentStatus derives the outstanding balance and payment status from the amount due and the amount paid
+func calculatePaymentStatus(amountDue int, paidAmount int) (outstandingAmount int, paymentStatus string) {
+	outstandingAmount = amountDue - paidAmount
+	if outstandingAmount < 0 {
+		outstandingAmount = 0
+	}
+
+	switch {
+	case outstandingAmount == 0:
+		paymentStatus = entity.OrderPaymentStatus.PAID
+	case paidAmount > 0:
+		paymentStatus = entity.OrderPaymentStatus.PARTIALLY_PAID
+	default:
+		paymentStatus = entity.OrderPaymentStatus.UNPAID
+	}
+	return
+}
+
 func (s *OrderService) GetOneOrder(ctx *gin.Context, id int) (model.GetOneOrderResponse, string) {
 	order, err := s.orderRepo.GetOneByIDQuery(ctx, id, nil)
 	if err != nil {
@@ -382,6 +1184,29 @@ func (s *OrderService) GetOneOrder(ctx *gin.Context, id int) (model.GetOneOrderR
 		return model.GetOneOrderResponse{}, error_utils.ErrorCode.DB_DOWN
 	}
 
+	// Fetch the lots the items were issued from
+	orderItemLots, err := s.orderItemLotRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+	if err != nil {
+		log.Error("OrderService.GetOne Error fetching order item lots: " + err.Error())
+		return model.GetOneOrderResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+	netLots := getNetOrderItemLots(orderItemLots)
+	lotIDSet := make(map[int]struct{})
+	var lotIDs []int
+	for _, itemLots := range netLots {
+		for lotID := range itemLots {
+			if _, exists := lotIDSet[lotID]; !exists {
+				lotIDSet[lotID] = struct{}{}
+				lotIDs = append(lotIDs, lotID)
+			}
+		}
+	}
+	lots, err := s.inventoryLotRepo.GetManyByIDsQuery(ctx, lotIDs, nil)
+	if err != nil {
+		log.Error("OrderService.GetOne Error fetching lots: " + err.Error())
+		return model.GetOneOrderResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+
 	orderItemResponses := make([]model.OrderItemResponse, 0, len(orderItems))
 	totalOriginalCost := 0
 	totalProfitLoss := 0
@@ -416,12 +1241,40 @@ func (s *OrderService) GetOneOrder(ctx *gin.Context, id int) (model.GetOneOrderR
 		totalOriginalCost += originalCost
 		totalProfitLoss += profitLoss
 
+		// The unit the item was sold in, the product's base unit when none was given
+		unitName := ""
+		unitID := item.UnitID
+		if unitID == nil {
+			unitID = product.UnitID
+		}
+		if unitID != nil {
+			if unit, err := s.unitRepo.GetOneByIDQuery(ctx, *unitID, nil); err == nil && unit != nil {
+				unitName = unit.Name
+			}
+		}
+
+		// Lots are listed in FEFO order
+		var lotResponses []model.OrderItemLotResponse
+		for _, lot := range lots {
+			if quantity := netLots[item.ID][lot.ID]; quantity > 0 {
+				lotResponses = append(lotResponses, model.OrderItemLotResponse{
+					InventoryLotID: lot.ID,
+					LotNumber:      lot.LotNumber,
+					ExpiryDate:     lot.ExpiryDate,
+					Quantity:       quantity,
+				})
+			}
+		}
+
 		orderItemResponses = append(orderItemResponses, model.OrderItemResponse{
 			ID:              item.ID,
 			OrderID:         item.OrderID,
 			ProductID:       item.ProductID,
 			ProductName:     product.Name,
 			Quantity:        item.Quantity,
+			UnitID:          item.UnitID,
+			UnitName:        unitName,
+			BaseQuantity:    item.BaseQuantity,
 			SellingPrice:    item.SellingPrice,
 			DiscountPercent: item.DiscountPercent,
 			FinalAmount:     &finalAmount,
@@ -429,12 +1282,23 @@ func (s *OrderService) GetOneOrder(ctx *gin.Context, id int) (model.GetOneOrderR
 			OriginalPrice:        &item.OriginalPrice,
 			ProfitLoss:           &profitLoss,
 			ProfitLossPercentage: &profitLossPercentage,
+			Lots:                 lotResponses,
 		})
 	}
-
-	totalAmount, productCount := calculateOrderAmountsAndProductCount(orderItems)
-	totalAmount += order.AdditionalCost
-	totalAmount += int(float64(totalAmount) * float64(order.TaxPercent) / 100)
+
+	totalAmount, productCount := calculateOrderAmountDue(order, orderItems)
+
+	paidAmount, err := s.paymentRepo.GetTotalAmountByOrderIDQuery(ctx, order.ID, nil)
+	if err != nil {
+		log.Error("OrderService.GetOne Error fetching paid amount: " + err.Error())
+		return model.GetOneOrderResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+	returnedAmount, err := s.salesReturnRepo.GetTotalCreditAmountByOrderIDQuery(ctx, order.ID, nil)
+	if err != nil {
+		log.Error("OrderService.GetOne Error fetching returned amount: " + err.Error())
+		return model.GetOneOrderResponse{}, error_utils.ErrorCode.DB_DOWN
+	}
+	outstandingAmount, paymentStatus := calculatePaymentStatus(totalAmount-returnedAmount, paidAmount)
 
 	// Use stored values for total order profit/loss
 	totalProfitLoss = order.TotalSalesRevenue - order.TotalOriginalCost + order.AdditionalCost
@@ -474,7 +1338,9 @@ func (s *OrderService) GetOneOrder(ctx *gin.Context, id int) (model.GetOneOrderR
 
 	resp := model.GetOneOrderResponse{Order: model.OrderResponse{
 		ID:                 order.ID,
+		Code:               order.Code,
 		OrderDate:          order.OrderDate,
+		WarehouseID:        order.WarehouseID,
 		Note:               order.Note,
 		AdditionalCost:     order.AdditionalCost,
 		AdditionalCostNote: order.AdditionalCostNote,
@@ -482,14 +1348,19 @@ func (s *OrderService) GetOneOrder(ctx *gin.Context, id int) (model.GetOneOrderR
 		DeliveryStatus:     order.DeliveryStatus,
 		Customer: model.CustomerResponse{
 			ID:      customer.ID,
+			Code:    customer.Code,
 			Name:    customer.Name,
 			Phone:   customer.Phone,
 			Address: customer.Address,
 		},
-		OrderItems:   orderItemResponses,
-		Images:       imageResponses,
-		TotalAmount:  &totalAmount,
-		ProductCount: &productCount,
+		OrderItems:        orderItemResponses,
+		Images:            imageResponses,
+		TotalAmount:       &totalAmount,
+		ReturnedAmount:    &returnedAmount,
+		PaidAmount:        &paidAmount,
+		OutstandingAmount: &outstandingAmount,
+		PaymentStatus:     paymentStatus,
+		ProductCount:      &productCount,
 		// Profit/Loss fields for total order
 		TotalProfitLoss:           &totalProfitLoss,
 		TotalProfitLossPercentage: &totalProfitLossPercentage,
@@ -498,8 +1369,22 @@ func (s *OrderService) GetOneOrder(ctx *gin.Context, id int) (model.GetOneOrderR
 	return resp, ""
 }
 
-func (s *OrderService) Update(ctx context.Context, req model.UpdateOrderRequest) string {
-	existing, err := s.orderRepo.GetOneByIDQuery(ctx, req.ID, nil)
+func (s *OrderService) Update(ctx *gin.Context, req model.UpdateOrderRequest, userID int) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderService.Update Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderService.Update Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	existing, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, req.ID, tx)
 	if err != nil {
 		log.Error("OrderService.Update Error: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
@@ -507,6 +1392,17 @@ func (s *OrderService) Update(ctx context.Context, req model.UpdateOrderRequest)
 	if existing == nil {
 		return error_utils.ErrorCode.NOT_FOUND
 	}
+	if existing.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return error_utils.ErrorCode.ORDER_ALREADY_CANCELLED
+	}
+
+	// Lowering the additional cost or the tax must not leave the order below what was paid
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, existing.ID, tx)
+	if err != nil {
+		log.Error("OrderService.Update Error when get order items: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	previousAmountDue, _ := calculateOrderAmountDue(existing, orderItems)
 
 	if req.CustomerID != 0 {
 		existing.CustomerID = req.CustomerID
@@ -526,16 +1422,424 @@ func (s *OrderService) Update(ctx context.Context, req model.UpdateOrderRequest)
 	if req.TaxPercent != nil {
 		existing.TaxPercent = *req.TaxPercent
 	}
-	if req.DeliveryStatus != nil {
+
+	fromStatus := currentOrderStatus(existing)
+	statusChanged := req.DeliveryStatus != nil && *req.DeliveryStatus != fromStatus
+	if statusChanged {
+		// Cancellation must go through CancelOrder so that inventory is restocked
+		if *req.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+			return error_utils.ErrorCode.BAD_REQUEST
+		}
+		if !canTransitionOrderStatus(fromStatus, *req.DeliveryStatus) {
+			return error_utils.ErrorCode.INVALID_ORDER_STATUS_TRANSITION
+		}
 		existing.DeliveryStatus = *req.DeliveryStatus
 	}
 
-	err = s.orderRepo.UpdateCommand(ctx, existing, nil)
+	err = s.orderRepo.UpdateCommand(ctx, existing, tx)
 	if err != nil {
 		log.Error("OrderService.Update Error when update order: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
 	}
 
+	if errCode := s.checkAmountDueCoversPaid(ctx, existing, previousAmountDue, tx); errCode != "" {
+		return errCode
+	}
+
+	if statusChanged && fromStatus == entity.OrderDeliveryStatus.PENDING {
+		// Leaving PENDING means the goods ship, the reserved materials are now issued
+		if errCode := s.issueReservedMaterials(ctx, existing, userID, tx); errCode != "" {
+			return errCode
+		}
+	}
+
+	if statusChanged {
+		if errCode := s.recordStatusChange(ctx, existing.ID, &fromStatus, existing.DeliveryStatus, userID, req.StatusReason, tx); errCode != "" {
+			return errCode
+		}
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderService.Update Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+// issueReservedMaterials deducts the materials the order has reserved from on-hand stock and
+// closes the reservation
+func (s *OrderService) issueReservedMaterials(ctx *gin.Context, order *entity.Order, userID int, tx *sqlx.Tx) string {
+	reservedMaterials, err := s.inventoryReservationRepo.GetReservedQuantitiesByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.issueReservedMaterials Error when get reserved quantities: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if len(reservedMaterials) == 0 {
+		return ""
+	}
+
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, tx)
+	if err != nil {
+		log.Error("OrderService.issueReservedMaterials Error when get user: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	inventoryChanges := make(map[int]int)
+	for productID, reservedQty := range reservedMaterials {
+		inventoryChanges[productID] = -reservedQty
+	}
+
+	return s.applyInventoryChanges(ctx, order, inventoryChanges, reservedMaterials, user.Username, "Xuất cho đơn hàng: "+order.Code, tx)
+}
+
+// reallocateOrderStock works out what the new order lines draw from stock, keeping what the
+// order already holds where possible, and applies the difference to the order's reservation
+// or, once the stock has been issued, to the inventory
+func (s *OrderService) reallocateOrderStock(ctx *gin.Context, order *entity.Order, orderedProducts []RequiredMaterial, importerName string, tx *sqlx.Tx) string {
+	reserving, err := isReservingOrder(ctx, s.inventoryReservationRepo, order, tx)
+	if err != nil {
+		log.Error("OrderService.reallocateOrderStock Error when get reservations: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	var heldStock map[int]int
+	if reserving {
+		heldStock, err = s.inventoryReservationRepo.GetReservedQuantitiesByOrderIDQuery(ctx, order.ID, tx)
+	} else {
+		heldStock, err = s.getConsumedStock(ctx, order.ID, tx)
+	}
+	if err != nil {
+		log.Error("OrderService.reallocateOrderStock Error when get held stock: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	requiredStock, err := s.allocateOrderStock(ctx, orderedProducts, heldStock, order.WarehouseID, order.OrderDate, tx)
+	if errors.Is(err, errBomTooDeep) {
+		return error_utils.ErrorCode.BOM_DEPTH_EXCEEDED
+	}
+	if err != nil {
+		log.Error("OrderService.reallocateOrderStock Error calculating required materials: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Difference per product between what is needed now and what is held (positive = needs more)
+	stockChanges := make(map[int]int)
+	for productID, quantity := range requiredStock {
+		stockChanges[productID] += quantity
+	}
+	for productID, quantity := range heldStock {
+		stockChanges[productID] -= quantity
+	}
+
+	if reserving {
+		return s.applyReservationChanges(ctx, order, stockChanges, importerName, "Điều chỉnh giữ chỗ đơn hàng: "+order.Code, tx)
+	}
+
+	inventoryChanges := make(map[int]int) // productID -> signed change (negative = issue)
+	for productID, change := range stockChanges {
+		inventoryChanges[productID] = -change
+	}
+	return s.applyInventoryChanges(ctx, order, inventoryChanges, nil, importerName, "Điều chỉnh đơn hàng: "+order.Code, tx)
+}
+
+func (s *OrderService) UpdateItems(ctx *gin.Context, orderID int, request model.UpdateOrderItemsRequest, userID int) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderService.UpdateItems Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the order so that concurrent edits are applied one after another
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when get order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return error_utils.ErrorCode.ORDER_ALREADY_CANCELLED
+	}
+
+	// Returned quantities are tied to the current order lines
+	salesReturns, err := s.salesReturnRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when get sales returns: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if len(salesReturns) > 0 {
+		return error_utils.ErrorCode.ORDER_HAS_SALES_RETURNS
+	}
+
+	existingItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when get order items: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	previousAmountDue, _ := calculateOrderAmountDue(order, existingItems)
+
+	// Net quantity change per ordered product: new lines minus old lines
+	quantityDeltas := make(map[int]int)
+	existingItemMap := make(map[int]entity.OrderItem)
+	for _, item := range existingItems {
+		existingItemMap[item.ID] = item
+		quantityDeltas[item.ProductID] -= item.BaseQuantity
+	}
+
+	keptItemIDs := make(map[int]struct{})
+	orderedProducts := make([]RequiredMaterial, 0, len(request.Items))
+	baseQuantities := make([]int, len(request.Items))
+	originalPrices := make([]int, len(request.Items))
+	for i, itemRequest := range request.Items {
+		if itemRequest.ID != nil {
+			if _, exists := existingItemMap[*itemRequest.ID]; !exists {
+				log.Error(fmt.Sprintf("OrderService.UpdateItems Error: order item %d does not belong to order %d", *itemRequest.ID, order.ID))
+				return error_utils.ErrorCode.BAD_REQUEST
+			}
+			if _, duplicated := keptItemIDs[*itemRequest.ID]; duplicated {
+				return error_utils.ErrorCode.BAD_REQUEST
+			}
+			keptItemIDs[*itemRequest.ID] = struct{}{}
+		}
+
+		baseQuantity, originalPrice, errCode := s.toBaseQuantity(ctx, itemRequest.ProductID, itemRequest.UnitID, itemRequest.Quantity, tx)
+		if errCode != "" {
+			return errCode
+		}
+		baseQuantities[i] = baseQuantity
+		originalPrices[i] = originalPrice
+
+		// A kept line of the same product and unit stays at the cost it was sold at
+		if itemRequest.ID != nil {
+			existingItem := existingItemMap[*itemRequest.ID]
+			if existingItem.ProductID == itemRequest.ProductID && sameUnit(existingItem.UnitID, itemRequest.UnitID) {
+				originalPrices[i] = existingItem.OriginalPrice
+			}
+		}
+
+		quantityDeltas[itemRequest.ProductID] += baseQuantity
+		orderedProducts = append(orderedProducts, RequiredMaterial{ProductID: itemRequest.ProductID, Quantity: baseQuantity})
+	}
+
+	// Stock only moves when the ordered quantities change, editing prices leaves it alone
+	quantitiesChanged := false
+	for _, delta := range quantityDeltas {
+		if delta != 0 {
+			quantitiesChanged = true
+			break
+		}
+	}
+
+	// Remove the lines that are no longer in the order
+	for _, item := range existingItems {
+		if _, kept := keptItemIDs[item.ID]; kept {
+			continue
+		}
+		err = s.orderItemRepo.DeleteCommand(ctx, item.ID, tx)
+		if err != nil {
+			log.Error("OrderService.UpdateItems Error when delete order item: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// Update kept lines, create new ones and recompute the order totals
+	var totalOriginalCost, totalSalesRevenue int
+	for i, itemRequest := range request.Items {
+		finalAmount := s.calculateFinalAmount(itemRequest.SellingPrice, itemRequest.Quantity, itemRequest.DiscountPercent)
+
+		orderItem := &entity.OrderItem{
+			OrderID:         order.ID,
+			ProductID:       itemRequest.ProductID,
+			Quantity:        itemRequest.Quantity,
+			UnitID:          itemRequest.UnitID,
+			BaseQuantity:    baseQuantities[i],
+			SellingPrice:    itemRequest.SellingPrice,
+			OriginalPrice:   originalPrices[i],
+			DiscountPercent: itemRequest.DiscountPercent,
+			FinalAmount:     finalAmount,
+		}
+
+		if itemRequest.ID != nil {
+			orderItem.ID = *itemRequest.ID
+			err = s.orderItemRepo.UpdateCommand(ctx, orderItem, tx)
+		} else {
+			err = s.orderItemRepo.CreateCommand(ctx, orderItem, tx)
+		}
+		if err != nil {
+			log.Error("OrderService.UpdateItems Error when save order item: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		totalOriginalCost += orderItem.OriginalPrice * itemRequest.Quantity
+		totalSalesRevenue += finalAmount
+	}
+
+	order.TotalOriginalCost = totalOriginalCost
+	order.TotalSalesRevenue = totalSalesRevenue
+	err = s.orderRepo.UpdateCommand(ctx, order, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when update order totals: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := s.checkAmountDueCoversPaid(ctx, order, previousAmountDue, tx); errCode != "" {
+		return errCode
+	}
+
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when get user: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	if quantitiesChanged {
+		if errCode := s.reallocateOrderStock(ctx, order, orderedProducts, user.Username, tx); errCode != "" {
+			return errCode
+		}
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderService.UpdateItems Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *OrderService) CancelOrder(ctx *gin.Context, orderID int, request model.CancelOrderRequest, userID int) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("OrderService.CancelOrder Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("OrderService.CancelOrder Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the order so that two cancellations cannot restock twice
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("OrderService.CancelOrder Error when get order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return error_utils.ErrorCode.ORDER_ALREADY_CANCELLED
+	}
+	fromStatus := currentOrderStatus(order)
+	if fromStatus == entity.OrderDeliveryStatus.DELIVERED || fromStatus == entity.OrderDeliveryStatus.UNPAID {
+		return error_utils.ErrorCode.ORDER_ALREADY_DELIVERED
+	}
+	if !canTransitionOrderStatus(fromStatus, entity.OrderDeliveryStatus.CANCELLED) {
+		return error_utils.ErrorCode.INVALID_ORDER_STATUS_TRANSITION
+	}
+
+	// Received money would drop out of the receivables once the order is cancelled. Payments lock
+	// the order too, so none can be recorded between this check and the cancellation.
+	paidAmount, err := s.paymentRepo.GetTotalAmountByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.CancelOrder Error when get paid amount: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if paidAmount != 0 {
+		return error_utils.ErrorCode.ORDER_HAS_PAYMENTS
+	}
+
+	// Returned goods were already restocked or written off, restocking everything again would double count
+	salesReturns, err := s.salesReturnRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.CancelOrder Error when get sales returns: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if len(salesReturns) > 0 {
+		return error_utils.ErrorCode.ORDER_HAS_SALES_RETURNS
+	}
+
+	// Put back what the order actually consumed
+	consumedMaterials, err := s.getConsumedStock(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.CancelOrder Error when get inventory histories: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, tx)
+	if err != nil {
+		log.Error("OrderService.CancelOrder Error when get user: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	note := "Hoàn kho do hủy đơn hàng: " + order.Code
+	if request.Reason != nil && *request.Reason != "" {
+		note += " - " + *request.Reason
+	}
+
+	// Put back every consumed material and write the compensating histories
+	if errCode := s.applyInventoryChanges(ctx, order, consumedMaterials, nil, user.Username, note, tx); errCode != "" {
+		return errCode
+	}
+
+	// Release whatever the order still holds
+	reservedMaterials, err := s.inventoryReservationRepo.GetReservedQuantitiesByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("OrderService.CancelOrder Error when get reserved quantities: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	releasedMaterials := make(map[int]int)
+	for productID, reservedQty := range reservedMaterials {
+		releasedMaterials[productID] = -reservedQty
+	}
+	if errCode := s.applyReservationChanges(ctx, order, releasedMaterials, user.Username, "Nhả giữ chỗ do hủy đơn hàng: "+order.Code, tx); errCode != "" {
+		return errCode
+	}
+
+	order.DeliveryStatus = entity.OrderDeliveryStatus.CANCELLED
+	err = s.orderRepo.UpdateCommand(ctx, order, tx)
+	if err != nil {
+		log.Error("OrderService.CancelOrder Error when update order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := s.recordStatusChange(ctx, order.ID, &fromStatus, order.DeliveryStatus, userID, request.Reason, tx); errCode != "" {
+		return errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("OrderService.CancelOrder Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
 	return ""
 }
 
@@ -564,14 +1868,26 @@ func (s *OrderService) GetAll(ctx context.Context, userID int, customerID int, s
 			log.Error("OrderService.GetAll Error fetching order items: " + err.Error())
 			continue
 		}
-		totalAmount, productCount := calculateOrderAmountsAndProductCount(orderItems)
-		totalAmount += o.AdditionalCost
-		totalAmount += int(float64(totalAmount) * float64(o.TaxPercent) / 100)
+		totalAmount, productCount := calculateOrderAmountDue(&o, orderItems)
+		paidAmount, err := s.paymentRepo.GetTotalAmountByOrderIDQuery(ctx, o.ID, nil)
+		if err != nil {
+			log.Error("OrderService.GetAll Error fetching paid amount: " + err.Error())
+			continue
+		}
+		returnedAmount, err := s.salesReturnRepo.GetTotalCreditAmountByOrderIDQuery(ctx, o.ID, nil)
+		if err != nil {
+			log.Error("OrderService.GetAll Error fetching returned amount: " + err.Error())
+			continue
+		}
+		outstandingAmount, paymentStatus := calculatePaymentStatus(totalAmount-returnedAmount, paidAmount)
 		// Calculate profit/loss from stored cost and revenue values
 		totalProfitLoss := o.TotalSalesRevenue - o.TotalOriginalCost + o.AdditionalCost
 
-		allOrderTotalAmount += totalAmount
-		allOrderTotalProfitLoss += totalProfitLoss
+		// Cancelled orders are still listed but do not count towards the totals
+		if o.DeliveryStatus != entity.OrderDeliveryStatus.CANCELLED {
+			allOrderTotalAmount += totalAmount
+			allOrderTotalProfitLoss += totalProfitLoss
+		}
 
 		totalProfitLossPercentage := 0.0
 		if o.TotalOriginalCost > 0 {
@@ -580,7 +1896,9 @@ func (s *OrderService) GetAll(ctx context.Context, userID int, customerID int, s
 
 		resp.Orders = append(resp.Orders, model.OrderResponse{
 			ID:                 o.ID,
+			Code:               o.Code,
 			OrderDate:          o.OrderDate,
+			WarehouseID:        o.WarehouseID,
 			Note:               o.Note,
 			AdditionalCost:     o.AdditionalCost,
 			AdditionalCostNote: o.AdditionalCostNote,
@@ -588,12 +1906,17 @@ func (s *OrderService) GetAll(ctx context.Context, userID int, customerID int, s
 			DeliveryStatus:     o.DeliveryStatus,
 			Customer: model.CustomerResponse{
 				ID:      customer.ID,
+				Code:    customer.Code,
 				Name:    customer.Name,
 				Phone:   customer.Phone,
 				Address: customer.Address,
 			},
 			OrderItems:                nil, // Omit order items in GetAll
 			TotalAmount:               &totalAmount,
+			ReturnedAmount:            &returnedAmount,
+			PaidAmount:                &paidAmount,
+			OutstandingAmount:         &outstandingAmount,
+			PaymentStatus:             paymentStatus,
 			ProductCount:              &productCount,
 			TotalProfitLoss:           &totalProfitLoss,
 			TotalProfitLossPercentage: &totalProfitLossPercentage,
@@ -605,3 +1928,38 @@ func (s *OrderService) GetAll(ctx context.Context, userID int, customerID int, s
 
 	return resp, ""
 }
+
+func (s *OrderService) GetStatusHistories(ctx *gin.Context, orderID int) (*model.GetOrderStatusHistoriesResponse, string) {
+	order, err := s.orderRepo.GetOneByIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("OrderService.GetStatusHistories Error when get order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	histories, err := s.orderStatusHistoryRepo.GetAllByOrderIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("OrderService.GetStatusHistories Error when get status histories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	historyResponses := make([]model.OrderStatusHistoryResponse, len(histories))
+	for i, history := range histories {
+		historyResponses[i] = model.OrderStatusHistoryResponse{
+			ID:            history.ID,
+			OrderID:       history.OrderID,
+			FromStatus:    history.FromStatus,
+			ToStatus:      history.ToStatus,
+			ChangedBy:     history.ChangedBy,
+			ChangedByName: history.ChangedByName,
+			Reason:        history.Reason,
+			ChangedAt:     history.ChangedAt,
+		}
+	}
+
+	return &model.GetOrderStatusHistoriesResponse{
+		StatusHistories: historyResponses,
+	}, ""
+}
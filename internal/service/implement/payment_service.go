@@ -0,0 +1,197 @@
+package serviceimplement
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type PaymentService struct {
+	paymentRepo     repository.PaymentRepository
+	orderRepo       repository.OrderRepository
+	orderItemRepo   repository.OrderItemRepository
+	salesReturnRepo repository.SalesReturnRepository
+	userRepo        repository.UserRepository
+	unitOfWork      repository.UnitOfWork
+}
+
+func NewPaymentService(
+	paymentRepo repository.PaymentRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	salesReturnRepo repository.SalesReturnRepository,
+	userRepo repository.UserRepository,
+	unitOfWork repository.UnitOfWork,
+) service.PaymentService {
+	return &PaymentService{
+		paymentRepo:     paymentRepo,
+		orderRepo:       orderRepo,
+		orderItemRepo:   orderItemRepo,
+		salesReturnRepo: salesReturnRepo,
+		userRepo:        userRepo,
+		unitOfWork:      unitOfWork,
+	}
+}
+
+func (s *PaymentService) Create(ctx *gin.Context, orderID int, request model.CreatePaymentRequest, userID int) (*model.PaymentResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PaymentService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the order so concurrent payments cannot both pass the outstanding check
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when get order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return nil, error_utils.ErrorCode.ORDER_ALREADY_CANCELLED
+	}
+
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when get order items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	amountDue, _ := calculateOrderAmountDue(order, orderItems)
+
+	// Returned goods are credited against the amount due
+	returnedAmount, err := s.salesReturnRepo.GetTotalCreditAmountByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when get returned amount: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	paidAmount, err := s.paymentRepo.GetTotalAmountByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when get paid amount: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if paidAmount+request.Amount > amountDue-returnedAmount {
+		return nil, error_utils.ErrorCode.PAYMENT_EXCEEDS_OUTSTANDING
+	}
+
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	paymentDate := time.Now()
+	if request.PaymentDate != nil {
+		paymentDate = *request.PaymentDate
+	}
+
+	payment := &entity.Payment{
+		OrderID:         order.ID,
+		Amount:          request.Amount,
+		PaymentMethod:   request.PaymentMethod,
+		PaymentDate:     paymentDate,
+		ReferenceNumber: request.ReferenceNumber,
+		Note:            request.Note,
+		CreatedBy:       &user.ID,
+		CreatedByName:   user.Username,
+		CreatedAt:       time.Now(),
+	}
+
+	err = s.paymentRepo.CreateCommand(ctx, payment, tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when create payment: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PaymentService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toPaymentResponse(*payment)
+	return &response, ""
+}
+
+func (s *PaymentService) GetAllByOrderID(ctx *gin.Context, orderID int) (*model.GetOrderPaymentsResponse, string) {
+	order, err := s.orderRepo.GetOneByIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("PaymentService.GetAllByOrderID Error when get order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+	if err != nil {
+		log.Error("PaymentService.GetAllByOrderID Error when get order items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	payments, err := s.paymentRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+	if err != nil {
+		log.Error("PaymentService.GetAllByOrderID Error when get payments: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	paidAmount := 0
+	paymentResponses := make([]model.PaymentResponse, len(payments))
+	for i, payment := range payments {
+		paidAmount += payment.Amount
+		paymentResponses[i] = toPaymentResponse(payment)
+	}
+
+	returnedAmount, err := s.salesReturnRepo.GetTotalCreditAmountByOrderIDQuery(ctx, order.ID, nil)
+	if err != nil {
+		log.Error("PaymentService.GetAllByOrderID Error when get returned amount: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	amountDue, _ := calculateOrderAmountDue(order, orderItems)
+	outstandingAmount, paymentStatus := calculatePaymentStatus(amountDue-returnedAmount, paidAmount)
+
+	return &model.GetOrderPaymentsResponse{
+		Payments:          paymentResponses,
+		AmountDue:         amountDue,
+		ReturnedAmount:    returnedAmount,
+		PaidAmount:        paidAmount,
+		OutstandingAmount: outstandingAmount,
+		PaymentStatus:     paymentStatus,
+	}, ""
+}
+
+func toPaymentResponse(payment entity.Payment) model.PaymentResponse {
+	return model.PaymentResponse{
+		ID:              payment.ID,
+		OrderID:         payment.OrderID,
+		Amount:          payment.Amount,
+		PaymentMethod:   payment.PaymentMethod,
+		PaymentDate:     payment.PaymentDate,
+		ReferenceNumber: payment.ReferenceNumber,
+		Note:            payment.Note,
+		CreatedBy:       payment.CreatedBy,
+		CreatedByName:   payment.CreatedByName,
+		CreatedAt:       payment.CreatedAt,
+	}
+}
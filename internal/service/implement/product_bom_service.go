@@ -1,7 +1,14 @@
 package serviceimplement
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
 	"github.com/pna/management-app-backend/internal/domain/entity"
 	"github.com/pna/management-app-backend/internal/domain/model"
 	"github.com/pna/management-app-backend/internal/repository"
@@ -10,12 +17,106 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// maxBomDepth is the maximum number of BOM levels a product structure may have. The recursive
+// calculators stop there as well, so that a broken structure cannot run them forever.
+const maxBomDepth = 20
+
+var errBomTooDeep = errors.New("BOM explosion exceeds the maximum depth")
+
+// bomGraph is the product structure built from all BOM entries
+type bomGraph struct {
+	components map[int][]int // parent product ID -> component product IDs
+	parents    map[int][]int // component product ID -> parent product IDs
+}
+
+func newBomGraph(boms []entity.ProductBom) *bomGraph {
+	graph := &bomGraph{
+		components: make(map[int][]int),
+		parents:    make(map[int][]int),
+	}
+	for _, bom := range boms {
+		graph.components[bom.ParentProductID] = append(graph.components[bom.ParentProductID], bom.ComponentProductID)
+		graph.parents[bom.ComponentProductID] = append(graph.parents[bom.ComponentProductID], bom.ParentProductID)
+	}
+	return graph
+}
+
+// findCycle returns the first cycle below the product as a path that starts and ends with the
+// same product, nil when there is none
+func (g *bomGraph) findCycle(productID int) []int {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[int]int)
+	var path []int
+
+	var visit func(id int) []int
+	visit = func(id int) []int {
+		state[id] = visiting
+		path = append(path, id)
+		for _, componentID := range g.components[id] {
+			switch state[componentID] {
+			case visiting:
+				for i, pathID := range path {
+					if pathID == componentID {
+						cycle := append([]int{}, path[i:]...)
+						return append(cycle, componentID)
+					}
+				}
+			case 0:
+				if cycle := visit(componentID); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+
+	return visit(productID)
+}
+
+// levels returns the number of BOM levels the product's structure spans, counting both the
+// products it is used in and the components below it
+func (g *bomGraph) levels(productID int) int {
+	return longestBomChain(g.parents, productID, make(map[int]int), make(map[int]bool)) +
+		longestBomChain(g.components, productID, make(map[int]int), make(map[int]bool))
+}
+
+// longestBomChain returns the length of the longest chain of edges starting at the product
+func longestBomChain(edges map[int][]int, productID int, memo map[int]int, onPath map[int]bool) int {
+	if length, exists := memo[productID]; exists {
+		return length
+	}
+
+	onPath[productID] = true
+	length := 0
+	for _, nextID := range edges[productID] {
+		// Skip edges back into the chain, a cycle elsewhere is reported by findCycle
+		if onPath[nextID] {
+			continue
+		}
+		length = max(length, longestBomChain(edges, nextID, memo, onPath)+1)
+	}
+	delete(onPath, productID)
+
+	memo[productID] = length
+	return length
+}
+
 type ProductBomService struct {
-	bomRepository      repository.ProductBomRepository
-	productRepository  repository.ProductRepository
-	categoryRepository repository.ProductCategoryRepository
-	unitRepository     repository.UnitOfMeasureRepository
-	unitOfWork         repository.UnitOfWork
+	bomRepository        repository.ProductBomRepository
+	bomVersionRepository repository.ProductBomVersionRepository
+	productRepository    repository.ProductRepository
+	categoryRepository   repository.ProductCategoryRepository
+	unitRepository       repository.UnitOfMeasureRepository
+	orderRepository      repository.OrderRepository
+	orderItemRepository  repository.OrderItemRepository
+	unitOfWork           repository.UnitOfWork
+	stockRounding        stockRounding
+	unitConverter        *unitConverter
 }
 
 func NewProductBomService(
@@ -24,13 +125,254 @@ func NewProductBomService(
 	categoryRepository repository.ProductCategoryRepository,
 	unitRepository repository.UnitOfMeasureRepository,
 	unitOfWork repository.UnitOfWork,
+	unitConversionRepository repository.UnitConversionRepository,
+	bomVersionRepository repository.ProductBomVersionRepository,
+	orderRepository repository.OrderRepository,
+	orderItemRepository repository.OrderItemRepository,
 ) service.ProductBomService {
 	return &ProductBomService{
-		bomRepository:      bomRepository,
-		productRepository:  productRepository,
-		categoryRepository: categoryRepository,
-		unitRepository:     unitRepository,
-		unitOfWork:         unitOfWork,
+		bomRepository:        bomRepository,
+		bomVersionRepository: bomVersionRepository,
+		productRepository:    productRepository,
+		categoryRepository:   categoryRepository,
+		unitRepository:       unitRepository,
+		orderRepository:      orderRepository,
+		orderItemRepository:  orderItemRepository,
+		unitOfWork:           unitOfWork,
+		stockRounding:        stockRoundingFromEnv(),
+		unitConverter:        newUnitConverter(unitConversionRepository),
+	}
+}
+
+// validateBomStructure checks the product structure once the parent's new components are in
+// place, for the whole time they are in effect from the date until the optional end: the
+// structure changes whenever another version takes effect, so it is checked at each of those
+// times too. The parent's earliest version also stands for the time before it starts. A cycle is
+// reported with its path in "detailed_error_message".
+func (s *ProductBomService) validateBomStructure(ctx *gin.Context, parentProductID int, date time.Time, endDate *time.Time, tx *sqlx.Tx) string {
+	parentVersions, err := s.bomVersionRepository.GetAllByParentProductIDQuery(ctx, parentProductID, tx)
+	if err != nil {
+		log.Error("ProductBomService.validateBomStructure Error when get bom versions: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	var startDate *time.Time
+	for _, version := range parentVersions {
+		if version.IsActive && version.EffectiveFrom.Before(date) {
+			startDate = &date
+			break
+		}
+	}
+
+	starts, err := s.bomVersionRepository.GetActiveStartsBetweenQuery(ctx, startDate, endDate, tx)
+	if err != nil {
+		log.Error("ProductBomService.validateBomStructure Error when get bom version starts: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	for _, checkDate := range append([]time.Time{date}, starts...) {
+		if errCode := s.validateBomStructureAt(ctx, parentProductID, checkDate, tx); errCode != "" {
+			return errCode
+		}
+	}
+
+	return ""
+}
+
+// validateBomStructureAt checks the product structure in effect at the date
+func (s *ProductBomService) validateBomStructureAt(ctx *gin.Context, parentProductID int, date time.Time, tx *sqlx.Tx) string {
+	allBoms, err := s.bomRepository.GetEffectiveQuery(ctx, date, tx)
+	if err != nil {
+		log.Error("ProductBomService.validateBomStructure Error when get boms: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	graph := newBomGraph(allBoms)
+	if cycle := graph.findCycle(parentProductID); cycle != nil {
+		productNames := make([]string, len(cycle))
+		for i, productID := range cycle {
+			productNames[i] = fmt.Sprintf("#%d", productID)
+			if product, err := s.productRepository.GetOneByIDQuery(ctx, productID, tx); err == nil && product != nil {
+				productNames[i] = fmt.Sprintf("%s (#%d)", product.Name, productID)
+			}
+		}
+		ctx.Set("detailed_error_message", "BOM tạo vòng lặp: "+strings.Join(productNames, " -> "))
+		return error_utils.ErrorCode.BOM_CYCLE_DETECTED
+	}
+
+	if graph.levels(parentProductID) > maxBomDepth {
+		return error_utils.ErrorCode.BOM_DEPTH_EXCEEDED
+	}
+
+	return ""
+}
+
+// toBaseComponentQuantity converts the component quantity into the component's base unit, which
+// BOMs are stored and exploded in
+func (s *ProductBomService) toBaseComponentQuantity(ctx *gin.Context, component model.BomComponent, tx *sqlx.Tx) (float64, string) {
+	quantity := component.Quantity
+	if component.UnitID != nil {
+		componentProduct, err := s.productRepository.GetOneByIDQuery(ctx, component.ComponentProductID, tx)
+		if err != nil {
+			log.Error("ProductBomService.toBaseComponentQuantity Error when get component product: " + err.Error())
+			return 0, error_utils.ErrorCode.DB_DOWN
+		}
+		if componentProduct == nil {
+			return 0, error_utils.ErrorCode.NOT_FOUND
+		}
+
+		factor, errCode := s.unitConverter.baseFactor(ctx, componentProduct, component.UnitID, tx)
+		if errCode != "" {
+			return 0, errCode
+		}
+		quantity *= factor
+	}
+
+	quantity = roundBomQuantity(quantity)
+	if quantity <= 0 {
+		return 0, error_utils.ErrorCode.BAD_REQUEST
+	}
+	return quantity, ""
+}
+
+// createBomVersion saves the components as a new version of the parent's BOM, effective from the
+// given time (now when empty). The version in effect at that time ends there, and the new version
+// ends where a later scheduled version starts, so that past orders keep their recipe.
+func (s *ProductBomService) createBomVersion(ctx *gin.Context, parentProductID int, components []model.BomComponent, effectiveFrom *time.Time, note *string, tx *sqlx.Tx) (*entity.ProductBomVersion, []model.BomComponentResponse, string) {
+	from := time.Now()
+	if effectiveFrom != nil {
+		from = *effectiveFrom
+	}
+	from = from.Truncate(time.Second)
+
+	existingVersions, err := s.bomVersionRepository.GetAllByParentProductIDQuery(ctx, parentProductID, tx)
+	if err != nil {
+		log.Error("ProductBomService.createBomVersion Error when get bom versions: " + err.Error())
+		return nil, nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	version := &entity.ProductBomVersion{
+		ParentProductID: parentProductID,
+		VersionNumber:   1,
+		EffectiveFrom:   from,
+		IsActive:        true,
+		Note:            note,
+	}
+	for _, existing := range existingVersions {
+		version.VersionNumber = max(version.VersionNumber, existing.VersionNumber+1)
+		if !existing.IsActive {
+			continue
+		}
+
+		// Two versions starting together would leave the recipe in effect undefined
+		if existing.EffectiveFrom.Equal(from) {
+			return nil, nil, error_utils.ErrorCode.BOM_VERSION_DATE_TAKEN
+		}
+
+		// A version scheduled after this one takes over when it starts
+		if existing.EffectiveFrom.After(from) {
+			if version.EffectiveTo == nil || existing.EffectiveFrom.Before(*version.EffectiveTo) {
+				effectiveTo := existing.EffectiveFrom
+				version.EffectiveTo = &effectiveTo
+			}
+			continue
+		}
+
+		// The version in effect at that time ends when this one starts
+		if existing.EffectiveFrom.Before(from) && (existing.EffectiveTo == nil || existing.EffectiveTo.After(from)) {
+			existing.EffectiveTo = &from
+			err = s.bomVersionRepository.UpdateCommand(ctx, &existing, tx)
+			if err != nil {
+				log.Error("ProductBomService.createBomVersion Error when close previous bom version: " + err.Error())
+				return nil, nil, error_utils.ErrorCode.DB_DOWN
+			}
+		}
+	}
+
+	err = s.bomVersionRepository.CreateCommand(ctx, version, tx)
+	if err != nil {
+		var constraintErr *error_utils.ConstraintViolationError
+		if errors.As(err, &constraintErr) {
+			return nil, nil, error_utils.ErrorCode.BOM_VERSION_CONFLICT
+		}
+		log.Error("ProductBomService.createBomVersion Error when create bom version: " + err.Error())
+		return nil, nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if createdVersion, err := s.bomVersionRepository.GetOneByIDQuery(ctx, version.ID, tx); err == nil && createdVersion != nil {
+		version = createdVersion
+	}
+
+	// Create BOM entries for each component
+	bomComponents := make([]model.BomComponentResponse, len(components))
+	for i, component := range components {
+		quantity, errCode := s.toBaseComponentQuantity(ctx, component, tx)
+		if errCode != "" {
+			return nil, nil, errCode
+		}
+		bom := &entity.ProductBom{
+			BomVersionID:       version.ID,
+			ParentProductID:    parentProductID,
+			ComponentProductID: component.ComponentProductID,
+			Quantity:           quantity,
+			ScrapPercent:       component.ScrapPercent,
+		}
+
+		err = s.bomRepository.CreateCommand(ctx, bom, tx)
+		if err != nil {
+			log.Error("ProductBomService.createBomVersion Error when create bom: " + err.Error())
+			return nil, nil, error_utils.ErrorCode.DB_DOWN
+		}
+
+		// Get component product info
+		componentProduct, _ := s.productRepository.GetOneByIDQuery(ctx, component.ComponentProductID, tx)
+
+		bomComponents[i] = model.BomComponentResponse{
+			ID:                 bom.ID,
+			ComponentProductID: component.ComponentProductID,
+			Quantity:           bom.Quantity,
+			ScrapPercent:       bom.ScrapPercent,
+		}
+
+		if componentProduct != nil {
+			bomComponents[i].ComponentProduct = s.buildProductBomInfo(ctx, componentProduct)
+		}
+	}
+
+	// Reject self-references, cycles and structures that are too deep
+	if errCode := s.validateBomStructure(ctx, parentProductID, from, version.EffectiveTo, tx); errCode != "" {
+		return nil, nil, errCode
+	}
+
+	return version, bomComponents, ""
+}
+
+// lockBomGraph waits for the BOM change in progress, the caller must release the lock after
+// its transaction ends
+func (s *ProductBomService) lockBomGraph(ctx *gin.Context, method string) (func(), string) {
+	release, acquired, err := s.bomRepository.LockGraphCommand(ctx)
+	if err != nil {
+		log.Error("ProductBomService." + method + " Error when lock bom graph: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if !acquired {
+		return nil, error_utils.ErrorCode.BOM_GRAPH_BUSY
+	}
+	return release, ""
+}
+
+func toBomVersionResponse(version *entity.ProductBomVersion) *model.BomVersionResponse {
+	if version == nil {
+		return nil
+	}
+
+	return &model.BomVersionResponse{
+		ID:              version.ID,
+		ParentProductID: version.ParentProductID,
+		VersionNumber:   version.VersionNumber,
+		EffectiveFrom:   version.EffectiveFrom,
+		EffectiveTo:     version.EffectiveTo,
+		IsActive:        version.IsActive,
+		Note:            version.Note,
+		CreatedAt:       version.CreatedAt,
 	}
 }
 
@@ -69,6 +411,13 @@ func (s *ProductBomService) buildProductBomInfo(ctx *gin.Context, product *entit
 }
 
 func (s *ProductBomService) Create(ctx *gin.Context, request model.CreateProductBomRequest) (*model.ProductBomResponse, string) {
+	// Serialize BOM changes so the cycle check sees every committed structure
+	release, errCode := s.lockBomGraph(ctx, "Create")
+	if errCode != "" {
+		return nil, errCode
+	}
+	defer release()
+
 	// Begin transaction
 	tx, err := s.unitOfWork.Begin(ctx)
 	if err != nil {
@@ -93,35 +442,10 @@ func (s *ProductBomService) Create(ctx *gin.Context, request model.CreateProduct
 		return nil, error_utils.ErrorCode.NOT_FOUND
 	}
 
-	// Create BOM entries for each component
-	bomComponents := make([]model.BomComponentResponse, len(request.Components))
-	for i, component := range request.Components {
-		// Create BOM entity
-		bom := &entity.ProductBom{
-			ParentProductID:    request.ParentProductID,
-			ComponentProductID: component.ComponentProductID,
-			Quantity:           component.Quantity,
-		}
-
-		// Save to database
-		err = s.bomRepository.CreateCommand(ctx, bom, tx)
-		if err != nil {
-			log.Error("ProductBomService.Create Error when create bom: " + err.Error())
-			return nil, error_utils.ErrorCode.DB_DOWN
-		}
-
-		// Get component product info
-		componentProduct, _ := s.productRepository.GetOneByIDQuery(ctx, component.ComponentProductID, tx)
-
-		bomComponents[i] = model.BomComponentResponse{
-			ID:                 bom.ID,
-			ComponentProductID: component.ComponentProductID,
-			Quantity:           component.Quantity,
-		}
-
-		if componentProduct != nil {
-			bomComponents[i].ComponentProduct = s.buildProductBomInfo(ctx, componentProduct)
-		}
+	// Create the BOM as a new version
+	version, bomComponents, errCode := s.createBomVersion(ctx, request.ParentProductID, request.Components, request.EffectiveFrom, request.Note, tx)
+	if errCode != "" {
+		return nil, errCode
 	}
 
 	// Commit transaction
@@ -134,6 +458,7 @@ func (s *ProductBomService) Create(ctx *gin.Context, request model.CreateProduct
 	// Prepare response
 	response := &model.ProductBomResponse{
 		ParentProductID: request.ParentProductID,
+		Version:         toBomVersionResponse(version),
 		Components:      bomComponents,
 		TotalComponents: len(bomComponents),
 	}
@@ -146,6 +471,13 @@ func (s *ProductBomService) Create(ctx *gin.Context, request model.CreateProduct
 }
 
 func (s *ProductBomService) Update(ctx *gin.Context, request model.UpdateProductBomRequest) (*model.ProductBomResponse, string) {
+	// Serialize BOM changes so the cycle check sees every committed structure
+	release, errCode := s.lockBomGraph(ctx, "Update")
+	if errCode != "" {
+		return nil, errCode
+	}
+	defer release()
+
 	// Begin transaction
 	tx, err := s.unitOfWork.Begin(ctx)
 	if err != nil {
@@ -170,50 +502,10 @@ func (s *ProductBomService) Update(ctx *gin.Context, request model.UpdateProduct
 		return nil, error_utils.ErrorCode.NOT_FOUND
 	}
 
-	// Delete all existing BOM entries for this parent product
-	existingBoms, err := s.bomRepository.GetByParentProductIDQuery(ctx, request.ParentProductID, tx)
-	if err != nil {
-		log.Error("ProductBomService.Update Error when get existing boms: " + err.Error())
-		return nil, error_utils.ErrorCode.DB_DOWN
-	}
-
-	for _, existingBom := range existingBoms {
-		err = s.bomRepository.DeleteCommand(ctx, existingBom.ID, tx)
-		if err != nil {
-			log.Error("ProductBomService.Update Error when delete existing bom: " + err.Error())
-			return nil, error_utils.ErrorCode.DB_DOWN
-		}
-	}
-
-	// Create new BOM entries for each component
-	bomComponents := make([]model.BomComponentResponse, len(request.Components))
-	for i, component := range request.Components {
-		// Create BOM entity
-		bom := &entity.ProductBom{
-			ParentProductID:    request.ParentProductID,
-			ComponentProductID: component.ComponentProductID,
-			Quantity:           component.Quantity,
-		}
-
-		// Save to database
-		err = s.bomRepository.CreateCommand(ctx, bom, tx)
-		if err != nil {
-			log.Error("ProductBomService.Update Error when create bom: " + err.Error())
-			return nil, error_utils.ErrorCode.DB_DOWN
-		}
-
-		// Get component product info
-		componentProduct, _ := s.productRepository.GetOneByIDQuery(ctx, component.ComponentProductID, tx)
-
-		bomComponents[i] = model.BomComponentResponse{
-			ID:                 bom.ID,
-			ComponentProductID: component.ComponentProductID,
-			Quantity:           component.Quantity,
-		}
-
-		if componentProduct != nil {
-			bomComponents[i].ComponentProduct = s.buildProductBomInfo(ctx, componentProduct)
-		}
+	// Keep the existing versions and add the new components as a new version
+	version, bomComponents, errCode := s.createBomVersion(ctx, request.ParentProductID, request.Components, request.EffectiveFrom, request.Note, tx)
+	if errCode != "" {
+		return nil, errCode
 	}
 
 	// Commit transaction
@@ -226,6 +518,7 @@ func (s *ProductBomService) Update(ctx *gin.Context, request model.UpdateProduct
 	// Prepare response
 	response := &model.ProductBomResponse{
 		ParentProductID: request.ParentProductID,
+		Version:         toBomVersionResponse(version),
 		Components:      bomComponents,
 		TotalComponents: len(bomComponents),
 	}
@@ -238,8 +531,8 @@ func (s *ProductBomService) Update(ctx *gin.Context, request model.UpdateProduct
 }
 
 func (s *ProductBomService) GetAll(ctx *gin.Context) (*model.GetAllProductBomsResponse, string) {
-	// Get all BOM entries
-	allBoms, err := s.bomRepository.GetAllQuery(ctx, nil)
+	// Get the BOM entries of the versions in effect now
+	allBoms, err := s.bomRepository.GetEffectiveQuery(ctx, time.Now(), nil)
 	if err != nil {
 		log.Error("ProductBomService.GetAll Error when get boms: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -267,6 +560,7 @@ func (s *ProductBomService) GetAll(ctx *gin.Context) (*model.GetAllProductBomsRe
 				ID:                 component.ID,
 				ComponentProductID: component.ComponentProductID,
 				Quantity:           component.Quantity,
+				ScrapPercent:       component.ScrapPercent,
 			}
 
 			if componentProduct != nil {
@@ -280,6 +574,10 @@ func (s *ProductBomService) GetAll(ctx *gin.Context) (*model.GetAllProductBomsRe
 			TotalComponents: len(bomComponents),
 		}
 
+		if version, _ := s.bomVersionRepository.GetOneByIDQuery(ctx, components[0].BomVersionID, nil); version != nil {
+			bomResponse.Version = toBomVersionResponse(version)
+		}
+
 		if parentProduct != nil {
 			bomResponse.ParentProduct = s.buildProductBomInfo(ctx, parentProduct)
 		}
@@ -293,13 +591,22 @@ func (s *ProductBomService) GetAll(ctx *gin.Context) (*model.GetAllProductBomsRe
 }
 
 func (s *ProductBomService) GetByParentProductID(ctx *gin.Context, parentProductID int) (*model.GetOneProductBomResponse, string) {
-	// Get BOM entries for parent product
-	boms, err := s.bomRepository.GetByParentProductIDQuery(ctx, parentProductID, nil)
+	// Get the version in effect now and its BOM entries
+	version, err := s.bomVersionRepository.GetEffectiveByParentProductIDQuery(ctx, parentProductID, time.Now(), nil)
 	if err != nil {
-		log.Error("ProductBomService.GetByParentProductID Error when get boms: " + err.Error())
+		log.Error("ProductBomService.GetByParentProductID Error when get bom version: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	boms := []entity.ProductBom{}
+	if version != nil {
+		boms, err = s.bomRepository.GetByBomVersionIDQuery(ctx, version.ID, nil)
+		if err != nil {
+			log.Error("ProductBomService.GetByParentProductID Error when get boms: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
 	// Get parent product info
 	parentProduct, err := s.productRepository.GetOneByIDQuery(ctx, parentProductID, nil)
 	if err != nil {
@@ -321,6 +628,7 @@ func (s *ProductBomService) GetByParentProductID(ctx *gin.Context, parentProduct
 			ID:                 bom.ID,
 			ComponentProductID: bom.ComponentProductID,
 			Quantity:           bom.Quantity,
+			ScrapPercent:       bom.ScrapPercent,
 		}
 
 		if componentProduct != nil {
@@ -330,6 +638,7 @@ func (s *ProductBomService) GetByParentProductID(ctx *gin.Context, parentProduct
 
 	response := model.ProductBomResponse{
 		ParentProductID: parentProductID,
+		Version:         toBomVersionResponse(version),
 		Components:      bomComponents,
 		TotalComponents: len(bomComponents),
 	}
@@ -344,8 +653,8 @@ func (s *ProductBomService) GetByParentProductID(ctx *gin.Context, parentProduct
 }
 
 func (s *ProductBomService) GetByComponentProductID(ctx *gin.Context, componentProductID int) (*model.GetAllProductBomsResponse, string) {
-	// Get BOM entries where this product is used as component
-	boms, err := s.bomRepository.GetByComponentProductIDQuery(ctx, componentProductID, nil)
+	// Get BOM entries in effect now where this product is used as component
+	boms, err := s.bomRepository.GetEffectiveByComponentProductIDQuery(ctx, componentProductID, time.Now(), nil)
 	if err != nil {
 		log.Error("ProductBomService.GetByComponentProductID Error when get boms: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -374,6 +683,13 @@ func (s *ProductBomService) GetByComponentProductID(ctx *gin.Context, componentP
 }
 
 func (s *ProductBomService) DeleteByParentProductID(ctx *gin.Context, parentProductID int) string {
+	// Serialize BOM changes so the cycle check sees every committed structure
+	release, errCode := s.lockBomGraph(ctx, "DeleteByParentProductID")
+	if errCode != "" {
+		return errCode
+	}
+	defer release()
+
 	// Begin transaction
 	tx, err := s.unitOfWork.Begin(ctx)
 	if err != nil {
@@ -388,24 +704,37 @@ func (s *ProductBomService) DeleteByParentProductID(ctx *gin.Context, parentProd
 		}
 	}()
 
-	// Get all BOM entries for this parent product
-	boms, err := s.bomRepository.GetByParentProductIDQuery(ctx, parentProductID, tx)
+	// End the BOM instead of deleting it, past orders keep their recipe
+	versions, err := s.bomVersionRepository.GetAllByParentProductIDQuery(ctx, parentProductID, tx)
 	if err != nil {
-		log.Error("ProductBomService.DeleteByParentProductID Error when get boms: " + err.Error())
+		log.Error("ProductBomService.DeleteByParentProductID Error when get bom versions: " + err.Error())
 		return error_utils.ErrorCode.DB_DOWN
 	}
 
-	if len(boms) == 0 {
-		return error_utils.ErrorCode.NOT_FOUND
-	}
+	now := time.Now().Truncate(time.Second)
+	endedVersions := 0
+	for _, version := range versions {
+		if !version.IsActive || (version.EffectiveTo != nil && !version.EffectiveTo.After(now)) {
+			continue
+		}
 
-	// Delete all BOM entries
-	for _, bom := range boms {
-		err = s.bomRepository.DeleteCommand(ctx, bom.ID, tx)
+		// The version in effect ends now, scheduled ones never take effect
+		if version.EffectiveFrom.Before(now) {
+			version.EffectiveTo = &now
+		} else {
+			version.IsActive = false
+		}
+
+		err = s.bomVersionRepository.UpdateCommand(ctx, &version, tx)
 		if err != nil {
-			log.Error("ProductBomService.DeleteByParentProductID Error when delete bom: " + err.Error())
+			log.Error("ProductBomService.DeleteByParentProductID Error when end bom version: " + err.Error())
 			return error_utils.ErrorCode.DB_DOWN
 		}
+		endedVersions++
+	}
+
+	if endedVersions == 0 {
+		return error_utils.ErrorCode.NOT_FOUND
 	}
 
 	// Commit transaction
@@ -429,11 +758,21 @@ func (s *ProductBomService) CalculateMaterialRequirements(ctx *gin.Context, requ
 		return nil, error_utils.ErrorCode.NOT_FOUND
 	}
 
-	// Map to accumulate material requirements by product ID
-	materialMap := make(map[int]int)
+	// Explode the BOM versions in effect at the requested date
+	effectiveDate := time.Now()
+	if request.Date != nil {
+		effectiveDate = *request.Date
+	}
+
+	// Maps to accumulate material requirements by product ID, with and without scrap and yield loss
+	materialMap := make(map[int]float64)
+	netMaterialMap := make(map[int]float64)
 
 	// Recursively calculate material requirements
-	err = s.calculateRequirementsRecursive(ctx, request.ParentProductID, request.Quantity, materialMap)
+	err = s.calculateRequirementsRecursive(ctx, request.ParentProductID, float64(request.Quantity), float64(request.Quantity), effectiveDate, 0, materialMap, netMaterialMap)
+	if errors.Is(err, errBomTooDeep) {
+		return nil, error_utils.ErrorCode.BOM_DEPTH_EXCEEDED
+	}
 	if err != nil {
 		log.Error("ProductBomService.CalculateMaterialRequirements Error in recursive calculation: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -451,7 +790,10 @@ func (s *ProductBomService) CalculateMaterialRequirements(ctx *gin.Context, requ
 
 		requirement := model.MaterialRequirement{
 			ProductID:        productID,
-			RequiredQuantity: quantity,
+			RequiredQuantity: roundBomQuantity(quantity),
+			GrossQuantity:    roundBomQuantity(quantity),
+			NetQuantity:      roundBomQuantity(netMaterialMap[productID]),
+			StockQuantity:    s.stockRounding.round(quantity),
 		}
 
 		if product != nil {
@@ -465,6 +807,7 @@ func (s *ProductBomService) CalculateMaterialRequirements(ctx *gin.Context, requ
 	response := &model.MaterialRequirementsResponse{
 		ParentProductID:      request.ParentProductID,
 		RequestedQuantity:    request.Quantity,
+		EffectiveDate:        effectiveDate,
 		MaterialRequirements: materialRequirements,
 		TotalMaterials:       len(materialRequirements),
 	}
@@ -476,10 +819,11 @@ func (s *ProductBomService) CalculateMaterialRequirements(ctx *gin.Context, requ
 	return response, ""
 }
 
-// Helper function to recursively calculate material requirements
-func (s *ProductBomService) calculateRequirementsRecursive(ctx *gin.Context, productID int, quantity int, materialMap map[int]int) error {
+// Helper function to recursively calculate material requirements. The gross quantity carries the
+// scrap and yield loss of every level, the net quantity is what the BOMs list without them.
+func (s *ProductBomService) calculateRequirementsRecursive(ctx *gin.Context, productID int, quantity float64, netQuantity float64, date time.Time, level int, materialMap map[int]float64, netMaterialMap map[int]float64) error {
 	// Get BOM for this product
-	boms, err := s.bomRepository.GetByParentProductIDQuery(ctx, productID, nil)
+	boms, err := s.bomRepository.GetEffectiveByParentProductIDQuery(ctx, productID, date, nil)
 	if err != nil {
 		return err
 	}
@@ -488,13 +832,27 @@ func (s *ProductBomService) calculateRequirementsRecursive(ctx *gin.Context, pro
 	if len(boms) == 0 {
 		// Accumulate quantity for this raw material
 		materialMap[productID] += quantity
+		netMaterialMap[productID] += netQuantity
 		return nil
 	}
 
+	// Stop before a broken structure recurses forever
+	if level >= maxBomDepth {
+		return errBomTooDeep
+	}
+
+	product, err := s.productRepository.GetOneByIDQuery(ctx, productID, nil)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return fmt.Errorf("product %d not found", productID)
+	}
+
 	// If BOM exists, recursively calculate for each component
 	for _, bom := range boms {
-		componentQuantity := bom.Quantity * quantity
-		err = s.calculateRequirementsRecursive(ctx, bom.ComponentProductID, componentQuantity, materialMap)
+		componentQuantity := grossBomQuantity(bom, product) * quantity
+		err = s.calculateRequirementsRecursive(ctx, bom.ComponentProductID, componentQuantity, bom.Quantity*netQuantity, date, level+1, materialMap, netMaterialMap)
 		if err != nil {
 			return err
 		}
@@ -502,3 +860,547 @@ func (s *ProductBomService) calculateRequirementsRecursive(ctx *gin.Context, pro
 
 	return nil
 }
+
+func (s *ProductBomService) GetVersions(ctx *gin.Context, parentProductID int) (*model.GetBomVersionsResponse, string) {
+	parentProduct, err := s.productRepository.GetOneByIDQuery(ctx, parentProductID, nil)
+	if err != nil {
+		log.Error("ProductBomService.GetVersions Error when get parent product: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if parentProduct == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	versions, err := s.bomVersionRepository.GetAllByParentProductIDQuery(ctx, parentProductID, nil)
+	if err != nil {
+		log.Error("ProductBomService.GetVersions Error when get bom versions: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	versionResponses := make([]model.BomVersionResponse, len(versions))
+	for i := range versions {
+		versionResponses[i] = *toBomVersionResponse(&versions[i])
+	}
+
+	return &model.GetBomVersionsResponse{
+		Versions: versionResponses,
+	}, ""
+}
+
+func (s *ProductBomService) GetVersion(ctx *gin.Context, versionID int) (*model.GetOneProductBomResponse, string) {
+	version, err := s.bomVersionRepository.GetOneByIDQuery(ctx, versionID, nil)
+	if err != nil {
+		log.Error("ProductBomService.GetVersion Error when get bom version: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if version == nil {
+		return nil, error_utils.ErrorCode.BOM_VERSION_NOT_FOUND
+	}
+
+	boms, err := s.bomRepository.GetByBomVersionIDQuery(ctx, version.ID, nil)
+	if err != nil {
+		log.Error("ProductBomService.GetVersion Error when get boms: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Convert components
+	bomComponents := make([]model.BomComponentResponse, len(boms))
+	for i, bom := range boms {
+		componentProduct, _ := s.productRepository.GetOneByIDQuery(ctx, bom.ComponentProductID, nil)
+
+		bomComponents[i] = model.BomComponentResponse{
+			ID:                 bom.ID,
+			ComponentProductID: bom.ComponentProductID,
+			Quantity:           bom.Quantity,
+			ScrapPercent:       bom.ScrapPercent,
+		}
+
+		if componentProduct != nil {
+			bomComponents[i].ComponentProduct = s.buildProductBomInfo(ctx, componentProduct)
+		}
+	}
+
+	response := model.ProductBomResponse{
+		ParentProductID: version.ParentProductID,
+		Version:         toBomVersionResponse(version),
+		Components:      bomComponents,
+		TotalComponents: len(bomComponents),
+	}
+
+	if parentProduct, _ := s.productRepository.GetOneByIDQuery(ctx, version.ParentProductID, nil); parentProduct != nil {
+		response.ParentProduct = s.buildProductBomInfo(ctx, parentProduct)
+	}
+
+	return &model.GetOneProductBomResponse{
+		Bom: response,
+	}, ""
+}
+
+// UpdateVersionStatus turns a BOM version on or off. An inactive version is skipped when BOMs are
+// exploded, the next version in effect is used instead.
+func (s *ProductBomService) UpdateVersionStatus(ctx *gin.Context, versionID int, request model.UpdateBomVersionStatusRequest) (*model.BomVersionResponse, string) {
+	// Serialize BOM changes so the cycle check sees every committed structure
+	release, errCode := s.lockBomGraph(ctx, "UpdateVersionStatus")
+	if errCode != "" {
+		return nil, errCode
+	}
+	defer release()
+
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("ProductBomService.UpdateVersionStatus Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("ProductBomService.UpdateVersionStatus Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	version, err := s.bomVersionRepository.GetOneByIDQuery(ctx, versionID, tx)
+	if err != nil {
+		log.Error("ProductBomService.UpdateVersionStatus Error when get bom version: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if version == nil {
+		return nil, error_utils.ErrorCode.BOM_VERSION_NOT_FOUND
+	}
+
+	// A version turned back on must not start together with another active one
+	if *request.IsActive && !version.IsActive {
+		siblings, err := s.bomVersionRepository.GetAllByParentProductIDQuery(ctx, version.ParentProductID, tx)
+		if err != nil {
+			log.Error("ProductBomService.UpdateVersionStatus Error when get bom versions: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		for _, sibling := range siblings {
+			if sibling.ID != version.ID && sibling.IsActive && sibling.EffectiveFrom.Equal(version.EffectiveFrom) {
+				return nil, error_utils.ErrorCode.BOM_VERSION_DATE_TAKEN
+			}
+		}
+	}
+
+	version.IsActive = *request.IsActive
+	err = s.bomVersionRepository.UpdateCommand(ctx, version, tx)
+	if err != nil {
+		log.Error("ProductBomService.UpdateVersionStatus Error when update bom version: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// A version turned back on must not close a cycle with the structures in effect by then
+	if version.IsActive {
+		if errCode := s.validateBomStructure(ctx, version.ParentProductID, version.EffectiveFrom, version.EffectiveTo, tx); errCode != "" {
+			return nil, errCode
+		}
+	}
+
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("ProductBomService.UpdateVersionStatus Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return toBomVersionResponse(version), ""
+}
+
+// GetTree returns the full BOM structure below the product, using the BOM versions in effect at
+// the date, with the cost rolled up from the leaf materials' products.cost
+func (s *ProductBomService) GetTree(ctx *gin.Context, parentProductID int, quantity int, date time.Time) (*model.BomTreeResponse, string) {
+	parentProduct, err := s.productRepository.GetOneByIDQuery(ctx, parentProductID, nil)
+	if err != nil {
+		log.Error("ProductBomService.GetTree Error when get parent product: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if parentProduct == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	products := map[int]*entity.Product{parentProduct.ID: parentProduct}
+	tree, totalLevels, err := s.buildBomTreeNode(ctx, parentProduct, 1, float64(quantity), 0, date, products)
+	if errors.Is(err, errBomTooDeep) {
+		return nil, error_utils.ErrorCode.BOM_DEPTH_EXCEEDED
+	}
+	if err != nil {
+		log.Error("ProductBomService.GetTree Error when build bom tree: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return &model.BomTreeResponse{
+		ParentProductID:  parentProduct.ID,
+		Quantity:         quantity,
+		EffectiveDate:    date,
+		Tree:             *tree,
+		TotalLevels:      totalLevels,
+		ManualUnitCost:   parentProduct.Cost,
+		RolledUpUnitCost: tree.RolledUpUnitCost,
+		CostDifference:   parentProduct.Cost - tree.RolledUpUnitCost,
+	}, ""
+}
+
+// buildBomTreeNode builds the tree node of the product and everything below it. A product without
+// a BOM is a leaf and costs its products.cost; a parent costs the sum of its components, their
+// scrap and its own yield loss included. The number of levels below the node is returned as well.
+func (s *ProductBomService) buildBomTreeNode(ctx *gin.Context, product *entity.Product, quantityPerParent float64, extendedQuantity float64, level int, date time.Time, products map[int]*entity.Product) (*model.BomTreeNode, int, error) {
+	node := &model.BomTreeNode{
+		ProductID:         product.ID,
+		ProductName:       product.Name,
+		OperationType:     product.OperationType,
+		Level:             level,
+		QuantityPerParent: quantityPerParent,
+		YieldPercent:      product.YieldPercent,
+		GrossPerParent:    quantityPerParent,
+		ExtendedQuantity:  roundBomQuantity(extendedQuantity),
+		Cost:              product.Cost,
+		Components:        []model.BomTreeNode{},
+	}
+	if productInfo := s.buildProductBomInfo(ctx, product); productInfo != nil {
+		node.UnitName = productInfo.UnitName
+	}
+
+	boms, err := s.bomRepository.GetEffectiveByParentProductIDQuery(ctx, product.ID, date, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get BOMs for product %d: %w", product.ID, err)
+	}
+
+	// A leaf material costs what is entered on the product
+	if len(boms) == 0 {
+		node.RolledUpUnitCost = product.Cost
+		node.ExtendedCost = product.Cost * extendedQuantity
+		return node, 0, nil
+	}
+
+	// Stop before a broken structure recurses forever
+	if level >= maxBomDepth {
+		return nil, 0, errBomTooDeep
+	}
+
+	node.BomVersionID = &boms[0].BomVersionID
+	levelsBelow := 0
+	for _, bom := range boms {
+		componentProduct, exists := products[bom.ComponentProductID]
+		if !exists {
+			componentProduct, err = s.productRepository.GetOneByIDQuery(ctx, bom.ComponentProductID, nil)
+			if err != nil || componentProduct == nil {
+				return nil, 0, fmt.Errorf("failed to get product %d: %w", bom.ComponentProductID, err)
+			}
+			products[componentProduct.ID] = componentProduct
+		}
+
+		grossQuantity := grossBomQuantity(bom, product)
+		componentNode, componentLevels, err := s.buildBomTreeNode(ctx, componentProduct, bom.Quantity, grossQuantity*extendedQuantity, level+1, date, products)
+		if err != nil {
+			return nil, 0, err
+		}
+		componentNode.ScrapPercent = bom.ScrapPercent
+		componentNode.GrossPerParent = roundBomQuantity(grossQuantity)
+
+		node.RolledUpUnitCost += grossQuantity * componentNode.RolledUpUnitCost
+		node.Components = append(node.Components, *componentNode)
+		levelsBelow = max(levelsBelow, componentLevels+1)
+	}
+	node.ExtendedCost = node.RolledUpUnitCost * extendedQuantity
+
+	return node, levelsBelow, nil
+}
+
+// DiffVersions lists the components added, removed or changed in quantity or scrap from one version
+// of a BOM to another
+func (s *ProductBomService) DiffVersions(ctx *gin.Context, fromVersionID int, toVersionID int) (*model.BomVersionDiffResponse, string) {
+	fromVersion, err := s.bomVersionRepository.GetOneByIDQuery(ctx, fromVersionID, nil)
+	if err != nil {
+		log.Error("ProductBomService.DiffVersions Error when get from version: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	toVersion, err := s.bomVersionRepository.GetOneByIDQuery(ctx, toVersionID, nil)
+	if err != nil {
+		log.Error("ProductBomService.DiffVersions Error when get to version: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if fromVersion == nil || toVersion == nil {
+		return nil, error_utils.ErrorCode.BOM_VERSION_NOT_FOUND
+	}
+	if fromVersion.ParentProductID != toVersion.ParentProductID {
+		return nil, error_utils.ErrorCode.BOM_VERSION_MISMATCH
+	}
+
+	fromBoms, err := s.bomRepository.GetByBomVersionIDQuery(ctx, fromVersion.ID, nil)
+	if err != nil {
+		log.Error("ProductBomService.DiffVersions Error when get from boms: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	toBoms, err := s.bomRepository.GetByBomVersionIDQuery(ctx, toVersion.ID, nil)
+	if err != nil {
+		log.Error("ProductBomService.DiffVersions Error when get to boms: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	toComponents := make(map[int]entity.ProductBom)
+	for _, bom := range toBoms {
+		toComponents[bom.ComponentProductID] = bom
+	}
+
+	// Removed and changed components in the order of the old version, then the added ones
+	changes := []model.BomComponentChange{}
+	unchangedComponents := 0
+	fromComponents := make(map[int]bool)
+	for _, bom := range fromBoms {
+		fromComponents[bom.ComponentProductID] = true
+		toBom, exists := toComponents[bom.ComponentProductID]
+		switch {
+		case !exists:
+			changes = append(changes, model.BomComponentChange{
+				ComponentProductID: bom.ComponentProductID,
+				ChangeType:         entity.BomChangeType.REMOVED,
+				FromQuantity:       bom.Quantity,
+				FromScrapPercent:   bom.ScrapPercent,
+			})
+		case toBom.Quantity != bom.Quantity || toBom.ScrapPercent != bom.ScrapPercent:
+			changes = append(changes, model.BomComponentChange{
+				ComponentProductID: bom.ComponentProductID,
+				ChangeType:         entity.BomChangeType.CHANGED,
+				FromQuantity:       bom.Quantity,
+				ToQuantity:         toBom.Quantity,
+				FromScrapPercent:   bom.ScrapPercent,
+				ToScrapPercent:     toBom.ScrapPercent,
+			})
+		default:
+			unchangedComponents++
+		}
+	}
+	for _, bom := range toBoms {
+		if !fromComponents[bom.ComponentProductID] {
+			changes = append(changes, model.BomComponentChange{
+				ComponentProductID: bom.ComponentProductID,
+				ChangeType:         entity.BomChangeType.ADDED,
+				ToQuantity:         bom.Quantity,
+				ToScrapPercent:     bom.ScrapPercent,
+			})
+		}
+	}
+
+	for i := range changes {
+		if componentProduct, _ := s.productRepository.GetOneByIDQuery(ctx, changes[i].ComponentProductID, nil); componentProduct != nil {
+			changes[i].ComponentProduct = s.buildProductBomInfo(ctx, componentProduct)
+		}
+	}
+
+	return &model.BomVersionDiffResponse{
+		ParentProductID:     fromVersion.ParentProductID,
+		FromVersion:         *toBomVersionResponse(fromVersion),
+		ToVersion:           *toBomVersionResponse(toVersion),
+		Changes:             changes,
+		UnchangedComponents: unchangedComponents,
+	}, ""
+}
+
+// whereUsedUsage is what the where-used walk adds up for one product above the component
+type whereUsedUsage struct {
+	level         int     // Shortest number of levels down to the component
+	quantity      float64 // Component per unit of the product, over all paths
+	grossQuantity float64 // Same with scrap and yield loss
+	paths         [][]int // Product IDs from the component up to the product
+}
+
+// whereUsedWalker follows the BOMs in effect at a date upwards from a component
+type whereUsedWalker struct {
+	ctx      *gin.Context
+	service  *ProductBomService
+	date     time.Time
+	parents  map[int][]entity.ProductBom // Component product ID -> BOM entries using it
+	products map[int]*entity.Product
+	usages   map[int]*whereUsedUsage
+}
+
+func (w *whereUsedWalker) product(productID int) (*entity.Product, error) {
+	if product, exists := w.products[productID]; exists {
+		return product, nil
+	}
+	product, err := w.service.productRepository.GetOneByIDQuery(w.ctx, productID, nil)
+	if err != nil || product == nil {
+		return nil, fmt.Errorf("failed to get product %d: %w", productID, err)
+	}
+	w.products[productID] = product
+	return product, nil
+}
+
+func (w *whereUsedWalker) parentBoms(productID int) ([]entity.ProductBom, error) {
+	if boms, exists := w.parents[productID]; exists {
+		return boms, nil
+	}
+	boms, err := w.service.bomRepository.GetEffectiveByComponentProductIDQuery(w.ctx, productID, w.date, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BOMs using product %d: %w", productID, err)
+	}
+	w.parents[productID] = boms
+	return boms, nil
+}
+
+// walk adds the product's quantities to every product it is used in. Each path is followed on its
+// own, so a product reached through several sub-assemblies sums all of them.
+func (w *whereUsedWalker) walk(productID int, quantity float64, grossQuantity float64, path []int) error {
+	boms, err := w.parentBoms(productID)
+	if err != nil {
+		return err
+	}
+	if len(boms) > 0 && len(path) > maxBomDepth {
+		return errBomTooDeep
+	}
+
+	for _, bom := range boms {
+		parentProduct, err := w.product(bom.ParentProductID)
+		if err != nil {
+			return err
+		}
+
+		parentQuantity := bom.Quantity * quantity
+		parentGrossQuantity := grossBomQuantity(bom, parentProduct) * grossQuantity
+		parentPath := append(append([]int{}, path...), bom.ParentProductID)
+
+		usage, exists := w.usages[bom.ParentProductID]
+		if !exists {
+			usage = &whereUsedUsage{level: len(path)}
+			w.usages[bom.ParentProductID] = usage
+		}
+		usage.level = min(usage.level, len(path))
+		usage.quantity += parentQuantity
+		usage.grossQuantity += parentGrossQuantity
+		usage.paths = append(usage.paths, parentPath)
+
+		if err := w.walk(bom.ParentProductID, parentQuantity, parentGrossQuantity, parentPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetWhereUsed lists every product that uses the component at any level of the BOMs in effect at
+// the date, with the quantity needed per unit of each, and the open orders that need it
+func (s *ProductBomService) GetWhereUsed(ctx *gin.Context, componentProductID int, date time.Time) (*model.WhereUsedResponse, string) {
+	componentProduct, err := s.productRepository.GetOneByIDQuery(ctx, componentProductID, nil)
+	if err != nil {
+		log.Error("ProductBomService.GetWhereUsed Error when get component product: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if componentProduct == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	walker := &whereUsedWalker{
+		ctx:      ctx,
+		service:  s,
+		date:     date,
+		parents:  make(map[int][]entity.ProductBom),
+		products: map[int]*entity.Product{componentProduct.ID: componentProduct},
+		usages:   make(map[int]*whereUsedUsage),
+	}
+	err = walker.walk(componentProduct.ID, 1, 1, []int{componentProduct.ID})
+	if errors.Is(err, errBomTooDeep) {
+		return nil, error_utils.ErrorCode.BOM_DEPTH_EXCEEDED
+	}
+	if err != nil {
+		log.Error("ProductBomService.GetWhereUsed Error when walk boms: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	usedIn := make([]model.WhereUsedProduct, 0, len(walker.usages))
+	topLevelProducts := 0
+	for productID, usage := range walker.usages {
+		// Paths read from the product down to the component
+		paths := make([]string, len(usage.paths))
+		for i, path := range usage.paths {
+			productNames := make([]string, len(path))
+			for j, pathProductID := range path {
+				productNames[len(path)-1-j] = walker.products[pathProductID].Name
+			}
+			paths[i] = strings.Join(productNames, " -> ")
+		}
+
+		product := walker.products[productID]
+		isTopLevel := len(walker.parents[productID]) == 0
+		if isTopLevel {
+			topLevelProducts++
+		}
+		usedIn = append(usedIn, model.WhereUsedProduct{
+			ProductID:            productID,
+			Product:              s.buildProductBomInfo(ctx, product),
+			Level:                usage.level,
+			IsTopLevel:           isTopLevel,
+			QuantityPerUnit:      roundBomQuantity(usage.quantity),
+			GrossQuantityPerUnit: roundBomQuantity(usage.grossQuantity),
+			Paths:                paths,
+		})
+	}
+	sort.Slice(usedIn, func(i, j int) bool {
+		if usedIn[i].Level != usedIn[j].Level {
+			return usedIn[i].Level < usedIn[j].Level
+		}
+		return usedIn[i].ProductID < usedIn[j].ProductID
+	})
+
+	// Orders not delivered yet that sell the component itself or any product using it
+	orders, err := s.orderRepository.GetAllByDeliveryStatusQuery(ctx, entity.OrderDeliveryStatus.PENDING, nil)
+	if err != nil {
+		log.Error("ProductBomService.GetWhereUsed Error when get open orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	affectedOrders := []model.WhereUsedOrder{}
+	totalComponentQuantity := 0.0
+	for _, order := range orders {
+		orderItems, err := s.orderItemRepository.GetAllByOrderIDQuery(ctx, order.ID, nil)
+		if err != nil {
+			log.Error("ProductBomService.GetWhereUsed Error when get order items: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+
+		affectedOrder := model.WhereUsedOrder{
+			OrderID:    order.ID,
+			OrderCode:  order.Code,
+			OrderDate:  order.OrderDate,
+			CustomerID: order.CustomerID,
+			Items:      []model.WhereUsedOrderItem{},
+		}
+		for _, orderItem := range orderItems {
+			grossQuantityPerUnit := 1.0
+			if orderItem.ProductID != componentProduct.ID {
+				usage, exists := walker.usages[orderItem.ProductID]
+				if !exists {
+					continue
+				}
+				grossQuantityPerUnit = usage.grossQuantity
+			}
+
+			productName := ""
+			if product, err := walker.product(orderItem.ProductID); err == nil {
+				productName = product.Name
+			}
+			componentQuantity := grossQuantityPerUnit * float64(orderItem.BaseQuantity)
+			affectedOrder.Items = append(affectedOrder.Items, model.WhereUsedOrderItem{
+				ProductID:         orderItem.ProductID,
+				ProductName:       productName,
+				Quantity:          orderItem.BaseQuantity,
+				ComponentQuantity: roundBomQuantity(componentQuantity),
+			})
+			affectedOrder.ComponentQuantity += componentQuantity
+		}
+		if len(affectedOrder.Items) == 0 {
+			continue
+		}
+
+		totalComponentQuantity += affectedOrder.ComponentQuantity
+		affectedOrder.ComponentQuantity = roundBomQuantity(affectedOrder.ComponentQuantity)
+		affectedOrders = append(affectedOrders, affectedOrder)
+	}
+
+	return &model.WhereUsedResponse{
+		ComponentProductID:     componentProduct.ID,
+		ComponentProduct:       s.buildProductBomInfo(ctx, componentProduct),
+		EffectiveDate:          date,
+		UsedIn:                 usedIn,
+		TopLevelProducts:       topLevelProducts,
+		AffectedOrders:         affectedOrders,
+		TotalComponentQuantity: roundBomQuantity(totalComponentQuantity),
+	}, ""
+}
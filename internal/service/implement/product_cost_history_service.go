@@ -0,0 +1,116 @@
+package serviceimplement
+
+import (
+	"context"
+	"math"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type ProductCostHistoryService struct {
+	productCostHistoryRepository repository.ProductCostHistoryRepository
+	productRepository            repository.ProductRepository
+}
+
+func NewProductCostHistoryService(
+	productCostHistoryRepository repository.ProductCostHistoryRepository,
+	productRepository repository.ProductRepository,
+) service.ProductCostHistoryService {
+	return &ProductCostHistoryService{
+		productCostHistoryRepository: productCostHistoryRepository,
+		productRepository:            productRepository,
+	}
+}
+
+// roundCost cuts a calculated cost back to the 3 decimals products.cost is stored with
+func roundCost(cost float64) float64 {
+	return math.Round(cost*1000) / 1000
+}
+
+// applyReceiptCost folds a received quantity into the product's moving weighted-average cost and
+// records the change. It must run before the received quantity is added to the inventory, the
+// average is weighted by the stock on hand in all warehouses before the receipt.
+func applyReceiptCost(ctx context.Context, productRepo repository.ProductRepository, inventoryRepo repository.InventoryRepository, costHistoryRepo repository.ProductCostHistoryRepository, productID int, quantity int, unitCost float64, receiptID int, userName string, tx *sqlx.Tx) error {
+	// The product row serialises concurrent receipts of the same product
+	product, err := productRepo.GetOneByIDForUpdateQuery(ctx, productID, tx)
+	if err != nil || product == nil {
+		return err
+	}
+
+	onHandQuantities, err := inventoryRepo.GetTotalQuantitiesByProductIDsQuery(ctx, []int{productID}, tx)
+	if err != nil {
+		return err
+	}
+	onHand := onHandQuantities[productID]
+
+	// Stock that is gone (or oversold) carries no cost, the receipt sets the cost on its own
+	newCost := unitCost
+	if onHand > 0 {
+		newCost = (float64(onHand)*product.Cost + float64(quantity)*unitCost) / float64(onHand+quantity)
+	}
+	newCost = roundCost(newCost)
+
+	if newCost != product.Cost {
+		if err := productRepo.UpdateCostCommand(ctx, productID, newCost, tx); err != nil {
+			return err
+		}
+	}
+
+	history := &entity.ProductCostHistory{
+		ProductID:        productID,
+		PreviousCost:     product.Cost,
+		NewCost:          newCost,
+		OnHandQuantity:   onHand,
+		ReceivedQuantity: quantity,
+		UnitCost:         &unitCost,
+		ReferenceType:    entity.ProductCostHistoryReferenceType.INVENTORY_RECEIPT,
+		ReferenceID:      &receiptID,
+		CreatedByName:    &userName,
+	}
+	return costHistoryRepo.CreateCommand(ctx, history, tx)
+}
+
+func (s *ProductCostHistoryService) GetAll(ctx *gin.Context, productID int) (*model.GetAllProductCostHistoriesResponse, string) {
+	product, err := s.productRepository.GetOneByIDQuery(ctx, productID, nil)
+	if err != nil {
+		log.Error("ProductCostHistoryService.GetAll Error when get product: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if product == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	histories, err := s.productCostHistoryRepository.GetAllByProductIDQuery(ctx, productID, nil)
+	if err != nil {
+		log.Error("ProductCostHistoryService.GetAll Error when get cost histories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	historyResponses := make([]model.ProductCostHistoryResponse, len(histories))
+	for i, history := range histories {
+		historyResponses[i] = model.ProductCostHistoryResponse{
+			ID:               history.ID,
+			ProductID:        history.ProductID,
+			PreviousCost:     history.PreviousCost,
+			NewCost:          history.NewCost,
+			OnHandQuantity:   history.OnHandQuantity,
+			ReceivedQuantity: history.ReceivedQuantity,
+			UnitCost:         history.UnitCost,
+			ReferenceType:    history.ReferenceType,
+			ReferenceID:      history.ReferenceID,
+			CreatedByName:    history.CreatedByName,
+			CreatedAt:        history.CreatedAt,
+		}
+	}
+
+	return &model.GetAllProductCostHistoriesResponse{
+		CostHistories: historyResponses,
+	}, ""
+}
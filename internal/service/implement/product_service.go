@@ -22,6 +22,8 @@ type ProductService struct {
 	bomRepository          repository.ProductBomRepository
 	unitOfWork             repository.UnitOfWork
 	productImageRepository repository.ProductImageRepository
+	warehouseRepository    repository.WarehouseRepository
+	costHistoryRepository  repository.ProductCostHistoryRepository
 	s3Service              bean.S3Service
 }
 
@@ -34,6 +36,8 @@ func NewProductService(
 	unitOfWork repository.UnitOfWork,
 	productImageRepository repository.ProductImageRepository,
 	s3Service bean.S3Service,
+	warehouseRepository repository.WarehouseRepository,
+	costHistoryRepository repository.ProductCostHistoryRepository,
 ) service.ProductService {
 	return &ProductService{
 		productRepository:      productRepository,
@@ -43,6 +47,8 @@ func NewProductService(
 		bomRepository:          bomRepository,
 		unitOfWork:             unitOfWork,
 		productImageRepository: productImageRepository,
+		warehouseRepository:    warehouseRepository,
+		costHistoryRepository:  costHistoryRepository,
 		s3Service:              s3Service,
 	}
 }
@@ -62,14 +68,22 @@ func (s *ProductService) buildProductResponseWithOptions(ctx *gin.Context, produ
 		UnitID:        product.UnitID,
 		Description:   product.Description,
 		OperationType: product.OperationType,
+		YieldPercent:  product.YieldPercent,
 	}
 
-	// Get inventory info
-	inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, product.ID, nil)
-	if err == nil && inventory != nil {
-		response.Inventory = &model.InventoryInfo{
-			Quantity: inventory.Quantity,
-			Version:  inventory.Version,
+	// Get inventory info, the quantity of all warehouses and the version of the default one
+	quantities, err := s.inventoryRepository.GetTotalQuantitiesByProductIDsQuery(ctx, []int{product.ID}, nil)
+	if err == nil {
+		if quantity, exists := quantities[product.ID]; exists {
+			response.Inventory = &model.InventoryInfo{
+				Quantity: quantity,
+			}
+			if warehouse, errCode := resolveWarehouse(ctx, s.warehouseRepository, nil, nil); errCode == "" {
+				inventory, err := s.inventoryRepository.GetOneByProductIDQuery(ctx, product.ID, warehouse.ID, nil)
+				if err == nil && inventory != nil {
+					response.Inventory.Version = inventory.Version
+				}
+			}
 		}
 	}
 
@@ -102,7 +116,7 @@ func (s *ProductService) buildProductResponseWithOptions(ctx *gin.Context, produ
 	// Skip BOM info if noBom is true (for performance optimization)
 	if !noBom {
 		// Get BOM info (if this product can be built from other products)
-		bomEntries, err := s.bomRepository.GetByParentProductIDQuery(ctx, product.ID, nil)
+		bomEntries, err := s.bomRepository.GetEffectiveByParentProductIDQuery(ctx, product.ID, time.Now(), nil)
 		if err == nil && len(bomEntries) > 0 {
 			bomComponents := make([]model.BomComponentResponse, len(bomEntries))
 			for i, bomEntry := range bomEntries {
@@ -113,6 +127,7 @@ func (s *ProductService) buildProductResponseWithOptions(ctx *gin.Context, produ
 					ID:                 bomEntry.ID,
 					ComponentProductID: bomEntry.ComponentProductID,
 					Quantity:           bomEntry.Quantity,
+					ScrapPercent:       bomEntry.ScrapPercent,
 				}
 
 				if componentProduct != nil {
@@ -151,7 +166,7 @@ func (s *ProductService) buildProductResponseWithOptions(ctx *gin.Context, produ
 		}
 
 		// Get usage info (where this product is used as component)
-		usageEntries, err := s.bomRepository.GetByComponentProductIDQuery(ctx, product.ID, nil)
+		usageEntries, err := s.bomRepository.GetEffectiveByComponentProductIDQuery(ctx, product.ID, time.Now(), nil)
 		if err == nil && len(usageEntries) > 0 {
 			usageInfo := make([]model.ProductBOMUsage, len(usageEntries))
 			for i, usageEntry := range usageEntries {
@@ -218,6 +233,10 @@ func (s *ProductService) Create(ctx *gin.Context, request model.CreateProductReq
 		UnitID:        request.UnitID,
 		Description:   request.Description,
 		OperationType: request.OperationType,
+		YieldPercent:  100,
+	}
+	if request.YieldPercent != nil {
+		product.YieldPercent = *request.YieldPercent
 	}
 
 	// Save product to database
@@ -227,11 +246,16 @@ func (s *ProductService) Create(ctx *gin.Context, request model.CreateProductReq
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
-	// Create inventory for the product
+	// Create inventory for the product in the default warehouse, other warehouses get one when stock arrives
+	warehouse, errCode := resolveWarehouse(ctx, s.warehouseRepository, nil, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
 	inventory := &entity.Inventory{
-		ProductID: product.ID,
-		Quantity:  0, // Start with 0 quantity
-		Version:   uuid.New().String(),
+		ProductID:   product.ID,
+		WarehouseID: warehouse.ID,
+		Quantity:    0, // Start with 0 quantity
+		Version:     uuid.New().String(),
 	}
 
 	err = s.inventoryRepository.CreateCommand(ctx, inventory, tx)
@@ -256,8 +280,22 @@ func (s *ProductService) Create(ctx *gin.Context, request model.CreateProductReq
 }
 
 func (s *ProductService) Update(ctx *gin.Context, request model.UpdateProductRequest) (*model.ProductResponse, string) {
-	// Check if product exists
-	existingProduct, err := s.productRepository.GetOneByIDQuery(ctx, request.ID, nil)
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("ProductService.Update Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("ProductService.Update Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Check if product exists, locking it so that a receipt cannot move the cost meanwhile
+	existingProduct, err := s.productRepository.GetOneByIDForUpdateQuery(ctx, request.ID, tx)
 	if err != nil {
 		log.Error("ProductService.Update Error when get product: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
@@ -271,20 +309,47 @@ func (s *ProductService) Update(ctx *gin.Context, request model.UpdateProductReq
 	product := &entity.Product{
 		ID:            request.ID,
 		Name:          request.Name,
-		Cost:          request.Cost,
+		Cost:          existingProduct.Cost,
 		CategoryID:    request.CategoryID,
 		UnitID:        request.UnitID,
 		Description:   request.Description,
 		OperationType: request.OperationType,
+		YieldPercent:  existingProduct.YieldPercent,
+	}
+	if request.YieldPercent != nil {
+		product.YieldPercent = *request.YieldPercent
+	}
+
+	// The cost is kept by receipts, a manual correction is recorded in the cost history
+	if request.Cost != nil && roundCost(*request.Cost) != existingProduct.Cost {
+		product.Cost = roundCost(*request.Cost)
+		costHistory := &entity.ProductCostHistory{
+			ProductID:     product.ID,
+			PreviousCost:  existingProduct.Cost,
+			NewCost:       product.Cost,
+			ReferenceType: entity.ProductCostHistoryReferenceType.MANUAL,
+		}
+		err = s.costHistoryRepository.CreateCommand(ctx, costHistory, tx)
+		if err != nil {
+			log.Error("ProductService.Update Error when create cost history: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
 	}
 
 	// Save to database
-	err = s.productRepository.UpdateCommand(ctx, product, nil)
+	err = s.productRepository.UpdateCommand(ctx, product, tx)
 	if err != nil {
 		log.Error("ProductService.Update Error when update product: " + err.Error())
 		return nil, error_utils.ErrorCode.DB_DOWN
 	}
 
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("ProductService.Update Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
 	// Return complete response with all related info
 	response, errCode := s.buildProductResponse(ctx, product)
 	if errCode != "" {
@@ -317,6 +382,7 @@ func (s *ProductService) GetAll(ctx *gin.Context, categoryFilter string, operati
 				UnitID:        product.UnitID,
 				Description:   product.Description,
 				OperationType: product.OperationType,
+				YieldPercent:  product.YieldPercent,
 			}
 			continue
 		}
@@ -0,0 +1,407 @@
+package serviceimplement
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type PurchaseOrderService struct {
+	purchaseOrderRepo     repository.PurchaseOrderRepository
+	purchaseOrderItemRepo repository.PurchaseOrderItemRepository
+	supplierRepo          repository.SupplierRepository
+	productRepo           repository.ProductRepository
+	unitOfWork            repository.UnitOfWork
+	unitConverter         *unitConverter
+}
+
+func NewPurchaseOrderService(
+	purchaseOrderRepo repository.PurchaseOrderRepository,
+	purchaseOrderItemRepo repository.PurchaseOrderItemRepository,
+	supplierRepo repository.SupplierRepository,
+	productRepo repository.ProductRepository,
+	unitOfWork repository.UnitOfWork,
+	unitConversionRepository repository.UnitConversionRepository,
+) service.PurchaseOrderService {
+	return &PurchaseOrderService{
+		purchaseOrderRepo:     purchaseOrderRepo,
+		purchaseOrderItemRepo: purchaseOrderItemRepo,
+		supplierRepo:          supplierRepo,
+		productRepo:           productRepo,
+		unitOfWork:            unitOfWork,
+		unitConverter:         newUnitConverter(unitConversionRepository),
+	}
+}
+
+// purchaseOrderStatusTransitions lists the statuses a purchase order may be moved to by hand.
+// PARTIALLY_RECEIVED and RECEIVED follow from the inventory receipts; cancelling a partially
+// received order means the rest will not be delivered.
+var purchaseOrderStatusTransitions = map[string][]string{
+	entity.PurchaseOrderStatus.DRAFT:              {entity.PurchaseOrderStatus.ORDERED, entity.PurchaseOrderStatus.CANCELLED},
+	entity.PurchaseOrderStatus.ORDERED:            {entity.PurchaseOrderStatus.CANCELLED},
+	entity.PurchaseOrderStatus.PARTIALLY_RECEIVED: {entity.PurchaseOrderStatus.CANCELLED},
+}
+
+// isPurchaseOrderReceivable reports whether inventory receipts may still be booked against the order
+func isPurchaseOrderReceivable(purchaseOrder *entity.PurchaseOrder) bool {
+	return purchaseOrder.Status == entity.PurchaseOrderStatus.ORDERED || purchaseOrder.Status == entity.PurchaseOrderStatus.PARTIALLY_RECEIVED
+}
+
+// purchaseOrderReceiptStatus returns the status of an order that has received deliveries:
+// RECEIVED once every line is complete, PARTIALLY_RECEIVED before
+func purchaseOrderReceiptStatus(items []entity.PurchaseOrderItem) string {
+	for _, item := range items {
+		if item.ReceivedQuantity < item.Quantity {
+			return entity.PurchaseOrderStatus.PARTIALLY_RECEIVED
+		}
+	}
+	return entity.PurchaseOrderStatus.RECEIVED
+}
+
+// replaceItems validates the products and writes the order lines in the products' base unit
+func (s *PurchaseOrderService) replaceItems(ctx *gin.Context, purchaseOrderID int, itemRequests []model.PurchaseOrderItemRequest, tx *sqlx.Tx) string {
+	err := s.purchaseOrderItemRepo.DeleteByPurchaseOrderIDCommand(ctx, purchaseOrderID, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.replaceItems Error when delete items: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	for _, itemRequest := range itemRequests {
+		product, err := s.productRepo.GetOneByIDQuery(ctx, itemRequest.ProductID, tx)
+		if err != nil {
+			log.Error("PurchaseOrderService.replaceItems Error when get product: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		if product == nil {
+			return error_utils.ErrorCode.NOT_FOUND
+		}
+
+		// Items may be ordered in an alternate unit (VD: thùng), receipts are counted in the base unit
+		factor, errCode := s.unitConverter.baseFactor(ctx, product, itemRequest.UnitID, tx)
+		if errCode != "" {
+			return errCode
+		}
+		quantity, errCode := toWholeBaseQuantity(itemRequest.Quantity, factor)
+		if errCode != "" {
+			return errCode
+		}
+		unitCost := itemRequest.UnitCost
+		if unitCost != nil && factor != 1 {
+			baseUnitCost := *unitCost / factor
+			unitCost = &baseUnitCost
+		}
+
+		item := &entity.PurchaseOrderItem{
+			PurchaseOrderID: purchaseOrderID,
+			ProductID:       itemRequest.ProductID,
+			Quantity:        quantity,
+			UnitCost:        unitCost,
+			ExpectedDate:    itemRequest.ExpectedDate,
+			Note:            itemRequest.Note,
+		}
+		err = s.purchaseOrderItemRepo.CreateCommand(ctx, item, tx)
+		if err != nil {
+			log.Error("PurchaseOrderService.replaceItems Error when create item: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	return ""
+}
+
+func (s *PurchaseOrderService) Create(ctx *gin.Context, request model.CreatePurchaseOrderRequest, userID int) (*model.PurchaseOrderResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PurchaseOrderService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	supplier, err := s.supplierRepo.GetOneByIDQuery(ctx, request.SupplierID, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Create Error when get supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if supplier == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	orderDate := time.Now()
+	if request.OrderDate != nil {
+		orderDate = *request.OrderDate
+	}
+
+	purchaseOrder := &entity.PurchaseOrder{
+		SupplierID:   request.SupplierID,
+		OrderDate:    orderDate,
+		ExpectedDate: request.ExpectedDate,
+		Status:       entity.PurchaseOrderStatus.DRAFT,
+		Note:         request.Note,
+		CreatedBy:    &userID,
+	}
+
+	err = s.purchaseOrderRepo.CreateCommand(ctx, purchaseOrder, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Create Error when create purchase order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := s.replaceItems(ctx, purchaseOrder.ID, request.Items, tx); errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response, errCode := s.GetOne(ctx, purchaseOrder.ID)
+	if errCode != "" {
+		return nil, errCode
+	}
+	return &response.PurchaseOrder, ""
+}
+
+func (s *PurchaseOrderService) Update(ctx *gin.Context, id int, request model.UpdatePurchaseOrderRequest) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Update Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PurchaseOrderService.Update Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	purchaseOrder, err := s.purchaseOrderRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Update Error when get purchase order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if purchaseOrder == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+	// Once sent to the supplier the lines are what receipts are booked against
+	if purchaseOrder.Status != entity.PurchaseOrderStatus.DRAFT {
+		return error_utils.ErrorCode.PURCHASE_ORDER_NOT_EDITABLE
+	}
+
+	supplier, err := s.supplierRepo.GetOneByIDQuery(ctx, request.SupplierID, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Update Error when get supplier: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if supplier == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	purchaseOrder.SupplierID = request.SupplierID
+	if request.OrderDate != nil {
+		purchaseOrder.OrderDate = *request.OrderDate
+	}
+	purchaseOrder.ExpectedDate = request.ExpectedDate
+	purchaseOrder.Note = request.Note
+
+	err = s.purchaseOrderRepo.UpdateCommand(ctx, purchaseOrder, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Update Error when update purchase order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := s.replaceItems(ctx, purchaseOrder.ID, request.Items, tx); errCode != "" {
+		return errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.Update Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *PurchaseOrderService) UpdateStatus(ctx *gin.Context, id int, request model.UpdatePurchaseOrderStatusRequest) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("PurchaseOrderService.UpdateStatus Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("PurchaseOrderService.UpdateStatus Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	purchaseOrder, err := s.purchaseOrderRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.UpdateStatus Error when get purchase order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if purchaseOrder == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	if purchaseOrder.Status == request.Status {
+		return ""
+	}
+	allowed := false
+	for _, status := range purchaseOrderStatusTransitions[purchaseOrder.Status] {
+		if status == request.Status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return error_utils.ErrorCode.INVALID_PURCHASE_ORDER_STATUS_TRANSITION
+	}
+
+	purchaseOrder.Status = request.Status
+	err = s.purchaseOrderRepo.UpdateCommand(ctx, purchaseOrder, tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.UpdateStatus Error when update purchase order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("PurchaseOrderService.UpdateStatus Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *PurchaseOrderService) GetAll(ctx *gin.Context, supplierID int, status string) (*model.GetAllPurchaseOrdersResponse, string) {
+	purchaseOrders, err := s.purchaseOrderRepo.GetAllWithFiltersQuery(ctx, supplierID, status, nil)
+	if err != nil {
+		log.Error("PurchaseOrderService.GetAll Error when get purchase orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	resp := &model.GetAllPurchaseOrdersResponse{PurchaseOrders: make([]model.PurchaseOrderResponse, 0, len(purchaseOrders))}
+	for i := range purchaseOrders {
+		purchaseOrderResponse, errCode := s.toPurchaseOrderResponse(ctx, &purchaseOrders[i], false)
+		if errCode != "" {
+			return nil, errCode
+		}
+		resp.PurchaseOrders = append(resp.PurchaseOrders, *purchaseOrderResponse)
+	}
+
+	return resp, ""
+}
+
+func (s *PurchaseOrderService) GetOne(ctx *gin.Context, id int) (*model.GetOnePurchaseOrderResponse, string) {
+	purchaseOrder, err := s.purchaseOrderRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("PurchaseOrderService.GetOne Error when get purchase order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if purchaseOrder == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	purchaseOrderResponse, errCode := s.toPurchaseOrderResponse(ctx, purchaseOrder, true)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	return &model.GetOnePurchaseOrderResponse{PurchaseOrder: *purchaseOrderResponse}, ""
+}
+
+func (s *PurchaseOrderService) toPurchaseOrderResponse(ctx *gin.Context, purchaseOrder *entity.PurchaseOrder, withItems bool) (*model.PurchaseOrderResponse, string) {
+	supplier, err := s.supplierRepo.GetOneByIDQuery(ctx, purchaseOrder.SupplierID, nil)
+	if err != nil {
+		log.Error("PurchaseOrderService.toPurchaseOrderResponse Error when get supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	items, err := s.purchaseOrderItemRepo.GetAllByPurchaseOrderIDQuery(ctx, purchaseOrder.ID, nil)
+	if err != nil {
+		log.Error("PurchaseOrderService.toPurchaseOrderResponse Error when get items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	totalAmount := 0.0
+	for _, item := range items {
+		if item.UnitCost != nil {
+			totalAmount += *item.UnitCost * float64(item.Quantity)
+		}
+	}
+
+	response := &model.PurchaseOrderResponse{
+		ID:           purchaseOrder.ID,
+		Code:         purchaseOrder.Code,
+		OrderDate:    purchaseOrder.OrderDate,
+		ExpectedDate: purchaseOrder.ExpectedDate,
+		Status:       purchaseOrder.Status,
+		Note:         purchaseOrder.Note,
+		TotalAmount:  totalAmount,
+		CreatedBy:    purchaseOrder.CreatedBy,
+		CreatedAt:    purchaseOrder.CreatedAt,
+		UpdatedAt:    purchaseOrder.UpdatedAt,
+	}
+	if supplier != nil {
+		response.Supplier = toSupplierResponse(supplier)
+	}
+
+	if withItems {
+		response.Items = make([]model.PurchaseOrderItemResponse, 0, len(items))
+		for _, item := range items {
+			productName := ""
+			product, err := s.productRepo.GetOneByIDQuery(ctx, item.ProductID, nil)
+			if err != nil {
+				log.Error("PurchaseOrderService.toPurchaseOrderResponse Error when get product: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			if product != nil {
+				productName = product.Name
+			}
+
+			// Lines without their own date are expected with the order
+			expectedDate := item.ExpectedDate
+			if expectedDate == nil {
+				expectedDate = purchaseOrder.ExpectedDate
+			}
+
+			response.Items = append(response.Items, model.PurchaseOrderItemResponse{
+				ID:                item.ID,
+				ProductID:         item.ProductID,
+				ProductName:       productName,
+				Quantity:          item.Quantity,
+				ReceivedQuantity:  item.ReceivedQuantity,
+				RemainingQuantity: item.Quantity - item.ReceivedQuantity,
+				UnitCost:          item.UnitCost,
+				ExpectedDate:      expectedDate,
+				Note:              item.Note,
+			})
+		}
+	}
+
+	return response, ""
+}
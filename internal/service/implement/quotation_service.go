@@ -0,0 +1,545 @@
+package serviceimplement
+
+import (
+	"math"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type QuotationService struct {
+	quotationRepo     repository.QuotationRepository
+	quotationItemRepo repository.QuotationItemRepository
+	customerRepo      repository.CustomerRepository
+	productRepo       repository.ProductRepository
+	unitRepo          repository.UnitOfMeasureRepository
+	unitOfWork        repository.UnitOfWork
+	orderService      service.OrderService
+	unitConverter     *unitConverter
+}
+
+func NewQuotationService(
+	quotationRepo repository.QuotationRepository,
+	quotationItemRepo repository.QuotationItemRepository,
+	customerRepo repository.CustomerRepository,
+	productRepo repository.ProductRepository,
+	unitRepo repository.UnitOfMeasureRepository,
+	unitOfWork repository.UnitOfWork,
+	orderService service.OrderService,
+	unitConversionRepo repository.UnitConversionRepository,
+) service.QuotationService {
+	return &QuotationService{
+		quotationRepo:     quotationRepo,
+		quotationItemRepo: quotationItemRepo,
+		customerRepo:      customerRepo,
+		productRepo:       productRepo,
+		unitRepo:          unitRepo,
+		unitOfWork:        unitOfWork,
+		orderService:      orderService,
+		unitConverter:     newUnitConverter(unitConversionRepo),
+	}
+}
+
+// quotationStatusTransitions lists the statuses a quotation may be moved to by hand.
+// ACCEPTED and EXPIRED are terminal; converting also accepts the quotation.
+var quotationStatusTransitions = map[string][]string{
+	entity.QuotationStatus.DRAFT: {entity.QuotationStatus.SENT, entity.QuotationStatus.EXPIRED},
+	entity.QuotationStatus.SENT:  {entity.QuotationStatus.ACCEPTED, entity.QuotationStatus.EXPIRED},
+}
+
+// effectiveQuotationStatus returns the quotation status, treating open quotations whose
+// validity has run out as EXPIRED even before anyone marks them so
+func effectiveQuotationStatus(quotation *entity.Quotation) string {
+	isOpen := quotation.Status == entity.QuotationStatus.DRAFT || quotation.Status == entity.QuotationStatus.SENT
+	// Valid until the end of the valid_until day
+	if isOpen && time.Now().After(quotation.ValidUntil.AddDate(0, 0, 1)) {
+		return entity.QuotationStatus.EXPIRED
+	}
+	return quotation.Status
+}
+
+// calculateQuotationTotalAmount returns items + additional cost + tax, the same way an order's amount due is calculated
+func calculateQuotationTotalAmount(quotation *entity.Quotation, items []entity.QuotationItem) int {
+	totalAmount := 0
+	for _, item := range items {
+		totalAmount += item.FinalAmount
+	}
+	totalAmount += quotation.AdditionalCost
+	totalAmount += int(float64(totalAmount) * float64(quotation.TaxPercent) / 100)
+	return totalAmount
+}
+
+// replaceItems validates the products and units and writes the quotation lines. Inventory is not touched.
+func (s *QuotationService) replaceItems(ctx *gin.Context, quotationID int, itemRequests []model.QuotationItemRequest, tx *sqlx.Tx) string {
+	err := s.quotationItemRepo.DeleteByQuotationIDCommand(ctx, quotationID, tx)
+	if err != nil {
+		log.Error("QuotationService.replaceItems Error when delete items: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	for _, itemRequest := range itemRequests {
+		product, err := s.productRepo.GetOneByIDQuery(ctx, itemRequest.ProductID, tx)
+		if err != nil {
+			log.Error("QuotationService.replaceItems Error when get product: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		if product == nil {
+			return error_utils.ErrorCode.NOT_FOUND
+		}
+
+		// The unit must convert to whole base units, as it will when the quotation becomes an order
+		factor, errCode := s.unitConverter.baseFactor(ctx, product, itemRequest.UnitID, tx)
+		if errCode != "" {
+			return errCode
+		}
+		if _, errCode := toWholeBaseQuantity(itemRequest.Quantity, factor); errCode != "" {
+			return errCode
+		}
+
+		itemTotal := itemRequest.SellingPrice * itemRequest.Quantity
+		item := &entity.QuotationItem{
+			QuotationID:     quotationID,
+			ProductID:       itemRequest.ProductID,
+			Quantity:        itemRequest.Quantity,
+			UnitID:          itemRequest.UnitID,
+			SellingPrice:    itemRequest.SellingPrice,
+			OriginalPrice:   int(math.Round(product.Cost * factor)), // Estimated cost per unit sold, the order snapshots the cost again when converted
+			DiscountPercent: itemRequest.DiscountPercent,
+			FinalAmount:     itemTotal - (itemTotal*itemRequest.DiscountPercent)/100,
+		}
+		err = s.quotationItemRepo.CreateCommand(ctx, item, tx)
+		if err != nil {
+			log.Error("QuotationService.replaceItems Error when create item: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	return ""
+}
+
+func (s *QuotationService) Create(ctx *gin.Context, request model.CreateQuotationRequest, userID int) (*model.QuotationResponse, string) {
+	if request.ValidUntil.Before(request.ValidFrom) {
+		return nil, error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("QuotationService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("QuotationService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	customer, err := s.customerRepo.GetOneByIDQuery(ctx, request.CustomerID, tx)
+	if err != nil {
+		log.Error("QuotationService.Create Error when get customer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if customer == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	quotationDate := time.Now()
+	if request.QuotationDate != nil {
+		quotationDate = *request.QuotationDate
+	}
+
+	quotation := &entity.Quotation{
+		CustomerID:         request.CustomerID,
+		QuotationDate:      quotationDate,
+		ValidFrom:          request.ValidFrom,
+		ValidUntil:         request.ValidUntil,
+		Note:               request.Note,
+		AdditionalCost:     request.AdditionalCost,
+		AdditionalCostNote: request.AdditionalCostNote,
+		TaxPercent:         request.TaxPercent,
+		Status:             entity.QuotationStatus.DRAFT,
+		CreatedBy:          &userID,
+	}
+
+	err = s.quotationRepo.CreateCommand(ctx, quotation, tx)
+	if err != nil {
+		log.Error("QuotationService.Create Error when create quotation: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := s.replaceItems(ctx, quotation.ID, request.Items, tx); errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("QuotationService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response, errCode := s.GetOne(ctx, quotation.ID)
+	if errCode != "" {
+		return nil, errCode
+	}
+	return &response.Quotation, ""
+}
+
+func (s *QuotationService) Update(ctx *gin.Context, id int, request model.UpdateQuotationRequest) string {
+	if request.ValidUntil.Before(request.ValidFrom) {
+		return error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("QuotationService.Update Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("QuotationService.Update Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	quotation, err := s.quotationRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("QuotationService.Update Error when get quotation: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if quotation == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+	// Only open quotations can be edited, an expired quote can be revived by extending its validity
+	if quotation.Status != entity.QuotationStatus.DRAFT && quotation.Status != entity.QuotationStatus.SENT {
+		return error_utils.ErrorCode.QUOTATION_NOT_EDITABLE
+	}
+
+	customer, err := s.customerRepo.GetOneByIDQuery(ctx, request.CustomerID, tx)
+	if err != nil {
+		log.Error("QuotationService.Update Error when get customer: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if customer == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	quotation.CustomerID = request.CustomerID
+	if request.QuotationDate != nil {
+		quotation.QuotationDate = *request.QuotationDate
+	}
+	quotation.ValidFrom = request.ValidFrom
+	quotation.ValidUntil = request.ValidUntil
+	quotation.Note = request.Note
+	quotation.AdditionalCost = request.AdditionalCost
+	quotation.AdditionalCostNote = request.AdditionalCostNote
+	quotation.TaxPercent = request.TaxPercent
+
+	err = s.quotationRepo.UpdateCommand(ctx, quotation, tx)
+	if err != nil {
+		log.Error("QuotationService.Update Error when update quotation: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := s.replaceItems(ctx, quotation.ID, request.Items, tx); errCode != "" {
+		return errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("QuotationService.Update Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *QuotationService) UpdateStatus(ctx *gin.Context, id int, request model.UpdateQuotationStatusRequest) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("QuotationService.UpdateStatus Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("QuotationService.UpdateStatus Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	quotation, err := s.quotationRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("QuotationService.UpdateStatus Error when get quotation: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if quotation == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	currentStatus := effectiveQuotationStatus(quotation)
+	if currentStatus == request.Status {
+		return ""
+	}
+	allowed := false
+	for _, status := range quotationStatusTransitions[currentStatus] {
+		if status == request.Status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return error_utils.ErrorCode.INVALID_QUOTATION_STATUS_TRANSITION
+	}
+
+	quotation.Status = request.Status
+	err = s.quotationRepo.UpdateCommand(ctx, quotation, tx)
+	if err != nil {
+		log.Error("QuotationService.UpdateStatus Error when update quotation: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("QuotationService.UpdateStatus Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *QuotationService) GetAll(ctx *gin.Context, customerID int, status string) (*model.GetAllQuotationsResponse, string) {
+	quotations, err := s.quotationRepo.GetAllWithFiltersQuery(ctx, customerID, nil)
+	if err != nil {
+		log.Error("QuotationService.GetAll Error when get quotations: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	resp := &model.GetAllQuotationsResponse{Quotations: make([]model.QuotationResponse, 0, len(quotations))}
+	for i := range quotations {
+		quotation := &quotations[i]
+		// Filter on the effective status so that lapsed quotations show up as EXPIRED
+		if status != "" && effectiveQuotationStatus(quotation) != status {
+			continue
+		}
+
+		quotationResponse, errCode := s.toQuotationResponse(ctx, quotation, false)
+		if errCode != "" {
+			return nil, errCode
+		}
+		resp.Quotations = append(resp.Quotations, *quotationResponse)
+	}
+
+	return resp, ""
+}
+
+func (s *QuotationService) GetOne(ctx *gin.Context, id int) (*model.GetOneQuotationResponse, string) {
+	quotation, err := s.quotationRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("QuotationService.GetOne Error when get quotation: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if quotation == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	quotationResponse, errCode := s.toQuotationResponse(ctx, quotation, true)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	return &model.GetOneQuotationResponse{Quotation: *quotationResponse}, ""
+}
+
+// Convert creates an order from the quotation through the regular CreateOrder flow, which is
+// where inventory is checked and issued. The quotation itself never touches inventory.
+func (s *QuotationService) Convert(ctx *gin.Context, id int, request model.ConvertQuotationRequest, userID int) (*model.OrderResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("QuotationService.Convert Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("QuotationService.Convert Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the quotation so that it is converted into one order only
+	quotation, err := s.quotationRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("QuotationService.Convert Error when get quotation: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if quotation == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if quotation.OrderID != nil {
+		return nil, error_utils.ErrorCode.QUOTATION_ALREADY_CONVERTED
+	}
+	switch effectiveQuotationStatus(quotation) {
+	case entity.QuotationStatus.EXPIRED:
+		return nil, error_utils.ErrorCode.QUOTATION_EXPIRED
+	case entity.QuotationStatus.DRAFT:
+		return nil, error_utils.ErrorCode.QUOTATION_NOT_SENT
+	}
+	if time.Now().Before(quotation.ValidFrom) {
+		return nil, error_utils.ErrorCode.QUOTATION_NOT_YET_VALID
+	}
+
+	items, err := s.quotationItemRepo.GetAllByQuotationIDQuery(ctx, quotation.ID, tx)
+	if err != nil {
+		log.Error("QuotationService.Convert Error when get quotation items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	orderDate := time.Now()
+	if request.OrderDate != nil {
+		orderDate = *request.OrderDate
+	}
+
+	note := "Từ báo giá " + quotation.Code
+	if request.Note != nil && *request.Note != "" {
+		note = *request.Note + " (" + note + ")"
+	} else if quotation.Note != nil && *quotation.Note != "" {
+		note = *quotation.Note + " (" + note + ")"
+	}
+
+	orderRequest := model.CreateOrderRequest{
+		CustomerID:         quotation.CustomerID,
+		WarehouseID:        request.WarehouseID,
+		OrderDate:          orderDate,
+		Note:               &note,
+		AdditionalCost:     quotation.AdditionalCost,
+		AdditionalCostNote: quotation.AdditionalCostNote,
+		TaxPercent:         quotation.TaxPercent,
+		Items:              make([]model.CreateOrderItemRequest, 0, len(items)),
+	}
+	for _, item := range items {
+		orderRequest.Items = append(orderRequest.Items, model.CreateOrderItemRequest{
+			ProductID:       item.ProductID,
+			Quantity:        item.Quantity,
+			UnitID:          item.UnitID,
+			SellingPrice:    item.SellingPrice,
+			DiscountPercent: item.DiscountPercent,
+		})
+	}
+
+	// The order and the link to it are committed together. Inventory shortages come back as
+	// INVENTORY_QUANTITY_EXCEEDED with the detailed message in ctx.
+	order, errCode := s.orderService.CreateOrderTx(ctx, orderRequest, userID, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	converted, err := s.quotationRepo.MarkConvertedCommand(ctx, quotation.ID, order.ID, tx)
+	if err != nil {
+		log.Error("QuotationService.Convert Error when mark quotation converted: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if !converted {
+		return nil, error_utils.ErrorCode.QUOTATION_ALREADY_CONVERTED
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("QuotationService.Convert Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	return order, ""
+}
+
+func (s *QuotationService) toQuotationResponse(ctx *gin.Context, quotation *entity.Quotation, withItems bool) (*model.QuotationResponse, string) {
+	customer, err := s.customerRepo.GetOneByIDQuery(ctx, quotation.CustomerID, nil)
+	if err != nil {
+		log.Error("QuotationService.toQuotationResponse Error when get customer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	items, err := s.quotationItemRepo.GetAllByQuotationIDQuery(ctx, quotation.ID, nil)
+	if err != nil {
+		log.Error("QuotationService.toQuotationResponse Error when get items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := &model.QuotationResponse{
+		ID:                 quotation.ID,
+		Code:               quotation.Code,
+		QuotationDate:      quotation.QuotationDate,
+		ValidFrom:          quotation.ValidFrom,
+		ValidUntil:         quotation.ValidUntil,
+		Note:               quotation.Note,
+		AdditionalCost:     quotation.AdditionalCost,
+		AdditionalCostNote: quotation.AdditionalCostNote,
+		TaxPercent:         quotation.TaxPercent,
+		Status:             effectiveQuotationStatus(quotation),
+		OrderID:            quotation.OrderID,
+		TotalAmount:        calculateQuotationTotalAmount(quotation, items),
+		CreatedAt:          quotation.CreatedAt,
+		UpdatedAt:          quotation.UpdatedAt,
+	}
+	if customer != nil {
+		response.Customer = toCustomerResponse(customer)
+	}
+
+	if withItems {
+		response.Items = make([]model.QuotationItemResponse, 0, len(items))
+		for _, item := range items {
+			productName := ""
+			product, err := s.productRepo.GetOneByIDQuery(ctx, item.ProductID, nil)
+			if err != nil {
+				log.Error("QuotationService.toQuotationResponse Error when get product: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			if product != nil {
+				productName = product.Name
+			}
+
+			// The unit the item was quoted in, the product's base unit when none was given
+			unitName := ""
+			unitID := item.UnitID
+			if unitID == nil && product != nil {
+				unitID = product.UnitID
+			}
+			if unitID != nil {
+				if unit, err := s.unitRepo.GetOneByIDQuery(ctx, *unitID, nil); err == nil && unit != nil {
+					unitName = unit.Name
+				}
+			}
+
+			response.Items = append(response.Items, model.QuotationItemResponse{
+				ID:              item.ID,
+				ProductID:       item.ProductID,
+				ProductName:     productName,
+				Quantity:        item.Quantity,
+				UnitID:          item.UnitID,
+				UnitName:        unitName,
+				SellingPrice:    item.SellingPrice,
+				OriginalPrice:   item.OriginalPrice,
+				DiscountPercent: item.DiscountPercent,
+				FinalAmount:     item.FinalAmount,
+			})
+		}
+	}
+
+	return response, ""
+}
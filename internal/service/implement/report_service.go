@@ -0,0 +1,595 @@
+package serviceimplement
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type ReportService struct {
+	orderRepo        repository.OrderRepository
+	orderItemRepo    repository.OrderItemRepository
+	paymentRepo      repository.PaymentRepository
+	salesReturnRepo  repository.SalesReturnRepository
+	customerRepo     repository.CustomerRepository
+	productRepo      repository.ProductRepository
+	warehouseRepo    repository.WarehouseRepository
+	lotRepo          repository.InventoryLotRepository
+	orderItemLotRepo repository.OrderItemLotRepository
+	workOrderLotRepo repository.WorkOrderLotRepository
+	categoryRepo     repository.ProductCategoryRepository
+	historyRepo      repository.InventoryHistoryRepository
+	costHistoryRepo  repository.ProductCostHistoryRepository
+	costLayerRepo    repository.InventoryCostLayerRepository
+	valuationMethod  string
+}
+
+func NewReportService(
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	paymentRepo repository.PaymentRepository,
+	salesReturnRepo repository.SalesReturnRepository,
+	customerRepo repository.CustomerRepository,
+	productRepo repository.ProductRepository,
+	warehouseRepo repository.WarehouseRepository,
+	lotRepo repository.InventoryLotRepository,
+	orderItemLotRepo repository.OrderItemLotRepository,
+	categoryRepo repository.ProductCategoryRepository,
+	historyRepo repository.InventoryHistoryRepository,
+	costHistoryRepo repository.ProductCostHistoryRepository,
+	costLayerRepo repository.InventoryCostLayerRepository,
+	workOrderLotRepo repository.WorkOrderLotRepository,
+) service.ReportService {
+	return &ReportService{
+		orderRepo:        orderRepo,
+		orderItemRepo:    orderItemRepo,
+		paymentRepo:      paymentRepo,
+		salesReturnRepo:  salesReturnRepo,
+		customerRepo:     customerRepo,
+		productRepo:      productRepo,
+		warehouseRepo:    warehouseRepo,
+		lotRepo:          lotRepo,
+		orderItemLotRepo: orderItemLotRepo,
+		workOrderLotRepo: workOrderLotRepo,
+		categoryRepo:     categoryRepo,
+		historyRepo:      historyRepo,
+		costHistoryRepo:  costHistoryRepo,
+		costLayerRepo:    costLayerRepo,
+		valuationMethod:  valuationMethodFromEnv(),
+	}
+}
+
+// addToAgingBucket adds an outstanding amount to the bucket matching its age in days
+func addToAgingBucket(buckets *model.ReceivableAgingBuckets, ageInDays int, amount int) {
+	switch {
+	case ageInDays <= 30:
+		buckets.Days0To30 += amount
+	case ageInDays <= 60:
+		buckets.Days31To60 += amount
+	case ageInDays <= 90:
+		buckets.Days61To90 += amount
+	default:
+		buckets.Over90Days += amount
+	}
+	buckets.Total += amount
+}
+
+func toCustomerResponse(customer *entity.Customer) model.CustomerResponse {
+	return model.CustomerResponse{
+		ID:      customer.ID,
+		Code:    customer.Code,
+		Name:    customer.Name,
+		Phone:   customer.Phone,
+		Address: customer.Address,
+	}
+}
+
+func (s *ReportService) GetReceivablesAging(ctx context.Context, asOfDate time.Time) (*model.GetReceivablesAgingResponse, string) {
+	// Everything dated on the as-of day is included
+	cutoff := asOfDate.AddDate(0, 0, 1)
+
+	orders, err := s.orderRepo.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("ReportService.GetReceivablesAging Error when get orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	receivablesByCustomer := make(map[int]*model.CustomerReceivableResponse)
+	customerIDs := make([]int, 0)
+	totals := model.ReceivableAgingBuckets{}
+
+	for i := range orders {
+		order := &orders[i]
+		// Cancelled orders are not owed and orders placed after the as-of date do not exist yet
+		if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED || !order.OrderDate.Before(cutoff) {
+			continue
+		}
+
+		orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+		if err != nil {
+			log.Error("ReportService.GetReceivablesAging Error when get order items: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		amountDue, _ := calculateOrderAmountDue(order, orderItems)
+
+		payments, err := s.paymentRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+		if err != nil {
+			log.Error("ReportService.GetReceivablesAging Error when get payments: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		paidAmount := 0
+		for _, payment := range payments {
+			if payment.PaymentDate.Before(cutoff) {
+				paidAmount += payment.Amount
+			}
+		}
+
+		salesReturns, err := s.salesReturnRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+		if err != nil {
+			log.Error("ReportService.GetReceivablesAging Error when get sales returns: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		for _, salesReturn := range salesReturns {
+			if salesReturn.ReturnDate.Before(cutoff) {
+				amountDue -= salesReturn.CreditAmount
+			}
+		}
+
+		outstandingAmount, _ := calculatePaymentStatus(amountDue, paidAmount)
+		if outstandingAmount == 0 {
+			continue
+		}
+
+		receivable, exists := receivablesByCustomer[order.CustomerID]
+		if !exists {
+			customer, err := s.customerRepo.GetOneByIDQuery(ctx, order.CustomerID, nil)
+			if err != nil {
+				log.Error("ReportService.GetReceivablesAging Error when get customer: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			if customer == nil {
+				continue
+			}
+			receivable = &model.CustomerReceivableResponse{Customer: toCustomerResponse(customer)}
+			receivablesByCustomer[order.CustomerID] = receivable
+			customerIDs = append(customerIDs, order.CustomerID)
+		}
+
+		ageInDays := int(asOfDate.Sub(order.OrderDate).Hours() / 24)
+		receivable.UnpaidOrderCount++
+		addToAgingBucket(&receivable.Buckets, ageInDays, outstandingAmount)
+		addToAgingBucket(&totals, ageInDays, outstandingAmount)
+	}
+
+	// Largest balances first, the accountant chases those
+	sort.Slice(customerIDs, func(i, j int) bool {
+		return receivablesByCustomer[customerIDs[i]].Buckets.Total > receivablesByCustomer[customerIDs[j]].Buckets.Total
+	})
+
+	customerReceivables := make([]model.CustomerReceivableResponse, 0, len(customerIDs))
+	for _, customerID := range customerIDs {
+		customerReceivables = append(customerReceivables, *receivablesByCustomer[customerID])
+	}
+
+	return &model.GetReceivablesAgingResponse{
+		AsOfDate:  asOfDate,
+		Customers: customerReceivables,
+		Totals:    totals,
+	}, ""
+}
+
+func (s *ReportService) GetCustomerStatement(ctx context.Context, customerID int, fromDate *time.Time, toDate *time.Time) (*model.GetCustomerStatementResponse, string) {
+	customer, err := s.customerRepo.GetOneByIDQuery(ctx, customerID, nil)
+	if err != nil {
+		log.Error("ReportService.GetCustomerStatement Error when get customer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if customer == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	orders, err := s.orderRepo.GetByCustomerIDQuery(ctx, customerID, nil)
+	if err != nil {
+		log.Error("ReportService.GetCustomerStatement Error when get orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	entries := make([]model.CustomerStatementEntry, 0)
+	for i := range orders {
+		order := &orders[i]
+		if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+			continue
+		}
+
+		orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+		if err != nil {
+			log.Error("ReportService.GetCustomerStatement Error when get order items: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		amountDue, _ := calculateOrderAmountDue(order, orderItems)
+
+		entries = append(entries, model.CustomerStatementEntry{
+			Date:        order.OrderDate,
+			EntryType:   model.CustomerStatementEntryType.ORDER,
+			OrderID:     order.ID,
+			OrderCode:   order.Code,
+			Description: "Đơn hàng " + order.Code,
+			Debit:       amountDue,
+		})
+
+		payments, err := s.paymentRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+		if err != nil {
+			log.Error("ReportService.GetCustomerStatement Error when get payments: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		for _, payment := range payments {
+			paymentID := payment.ID
+			paymentMethod := payment.PaymentMethod
+			entries = append(entries, model.CustomerStatementEntry{
+				Date:          payment.PaymentDate,
+				EntryType:     model.CustomerStatementEntryType.PAYMENT,
+				OrderID:       order.ID,
+				OrderCode:     order.Code,
+				PaymentID:     &paymentID,
+				PaymentMethod: &paymentMethod,
+				Description:   "Thanh toán đơn hàng " + order.Code,
+				Credit:        payment.Amount,
+			})
+		}
+
+		salesReturns, err := s.salesReturnRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+		if err != nil {
+			log.Error("ReportService.GetCustomerStatement Error when get sales returns: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		for _, salesReturn := range salesReturns {
+			salesReturnID := salesReturn.ID
+			entries = append(entries, model.CustomerStatementEntry{
+				Date:          salesReturn.ReturnDate,
+				EntryType:     model.CustomerStatementEntryType.SALES_RETURN,
+				OrderID:       order.ID,
+				OrderCode:     order.Code,
+				SalesReturnID: &salesReturnID,
+				Description:   "Trả hàng " + salesReturn.Code + " của đơn hàng " + order.Code,
+				Credit:        salesReturn.CreditAmount,
+			})
+		}
+	}
+
+	// Chronological order, an order comes before the payments and returns made on the same day
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].EntryType == model.CustomerStatementEntryType.ORDER && entries[j].EntryType != model.CustomerStatementEntryType.ORDER
+	})
+
+	openingBalance := 0
+	balance := 0
+	periodEntries := make([]model.CustomerStatementEntry, 0, len(entries))
+	for _, entry := range entries {
+		if fromDate != nil && entry.Date.Before(*fromDate) {
+			openingBalance += entry.Debit - entry.Credit
+			balance = openingBalance
+			continue
+		}
+		// The to date is inclusive
+		if toDate != nil && !entry.Date.Before(toDate.AddDate(0, 0, 1)) {
+			break
+		}
+		balance += entry.Debit - entry.Credit
+		entry.Balance = balance
+		periodEntries = append(periodEntries, entry)
+	}
+
+	return &model.GetCustomerStatementResponse{
+		Customer:       toCustomerResponse(customer),
+		FromDate:       fromDate,
+		ToDate:         toDate,
+		OpeningBalance: openingBalance,
+		Entries:        periodEntries,
+		ClosingBalance: balance,
+	}, ""
+}
+
+// GetLotRecall lists every order and customer that received the lot, in every warehouse the lot
+// is kept in. productID narrows the report when several products share the lot number. The lot
+// is followed through the work orders that consumed it to the lots of their finished goods, and
+// the customers of those are recalled as well.
+func (s *ReportService) GetLotRecall(ctx context.Context, lotNumber string, productID int) (*model.GetLotRecallResponse, string) {
+	lots, err := s.lotRepo.GetAllWithFiltersQuery(ctx, productID, 0, lotNumber, false, nil)
+	if err != nil {
+		log.Error("ReportService.GetLotRecall Error when get lots: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if len(lots) == 0 {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	lotResponses, errCode := toInventoryLotResponses(ctx, s.productRepo, s.warehouseRepo, lots)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	lotMap := make(map[int]model.InventoryLotResponse, len(lotResponses))
+	lotIDs := make([]int, len(lotResponses))
+	onHandQuantity := 0
+	for i, lot := range lotResponses {
+		lotMap[lot.ID] = lot
+		lotIDs[i] = lot.ID
+		onHandQuantity += lot.Quantity
+	}
+
+	// Follow the lots level by level to the finished goods made from them
+	workOrderResponses := make([]model.LotRecallWorkOrder, 0)
+	producedLotResponses := make([]model.InventoryLotResponse, 0)
+	frontier := lotIDs
+	for len(frontier) > 0 {
+		consumptions, err := s.workOrderLotRepo.GetConsumptionsByLotIDsQuery(ctx, frontier, nil)
+		if err != nil {
+			log.Error("ReportService.GetLotRecall Error when get work order consumptions: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+
+		var producedLotIDs []int
+		for _, consumption := range consumptions {
+			componentLot := lotMap[consumption.InventoryLotID]
+			workOrderResponses = append(workOrderResponses, model.LotRecallWorkOrder{
+				WorkOrderID:          consumption.WorkOrderID,
+				WorkOrderCode:        consumption.WorkOrderCode,
+				Status:               consumption.WorkOrderStatus,
+				ComponentProductID:   consumption.ProductID,
+				ComponentProductName: componentLot.ProductName,
+				ComponentLotNumber:   componentLot.LotNumber,
+				Quantity:             consumption.Quantity,
+				FinishedLotID:        consumption.FinishedLotID,
+			})
+			if consumption.FinishedLotID == nil {
+				continue
+			}
+			if _, exists := lotMap[*consumption.FinishedLotID]; !exists {
+				// Reserve the entry so a lot consumed by several work orders is followed once
+				lotMap[*consumption.FinishedLotID] = model.InventoryLotResponse{}
+				producedLotIDs = append(producedLotIDs, *consumption.FinishedLotID)
+			}
+		}
+
+		producedLots, err := s.lotRepo.GetManyByIDsQuery(ctx, producedLotIDs, nil)
+		if err != nil {
+			log.Error("ReportService.GetLotRecall Error when get produced lots: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		producedResponses, errCode := toInventoryLotResponses(ctx, s.productRepo, s.warehouseRepo, producedLots)
+		if errCode != "" {
+			return nil, errCode
+		}
+		for _, lot := range producedResponses {
+			lotMap[lot.ID] = lot
+			onHandQuantity += lot.Quantity
+		}
+		producedLotResponses = append(producedLotResponses, producedResponses...)
+		frontier = producedLotIDs
+	}
+
+	allLotIDs := make([]int, 0, len(lotMap))
+	for lotID := range lotMap {
+		allLotIDs = append(allLotIDs, lotID)
+	}
+	sort.Ints(allLotIDs)
+
+	shipments, err := s.orderItemLotRepo.GetShipmentsByLotIDsQuery(ctx, allLotIDs, nil)
+	if err != nil {
+		log.Error("ReportService.GetLotRecall Error when get shipments: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	customerMap := make(map[int]model.CustomerResponse)
+	var customerIDs []int
+	shipmentResponses := make([]model.LotRecallShipment, len(shipments))
+	shippedQuantity := 0
+	for i, shipment := range shipments {
+		customerResponse, exists := customerMap[shipment.CustomerID]
+		if !exists {
+			customer, err := s.customerRepo.GetOneByIDQuery(ctx, shipment.CustomerID, nil)
+			if err != nil {
+				log.Error("ReportService.GetLotRecall Error when get customer: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			if customer != nil {
+				customerResponse = toCustomerResponse(customer)
+			}
+			customerMap[shipment.CustomerID] = customerResponse
+			customerIDs = append(customerIDs, shipment.CustomerID)
+		}
+
+		lot := lotMap[shipment.InventoryLotID]
+		shipmentResponses[i] = model.LotRecallShipment{
+			OrderID:     shipment.OrderID,
+			OrderCode:   shipment.OrderCode,
+			OrderDate:   shipment.OrderDate,
+			Customer:    customerResponse,
+			OrderItemID: shipment.OrderItemID,
+			ProductID:   shipment.ProductID,
+			ProductName: lot.ProductName,
+			WarehouseID: lot.WarehouseID,
+			LotNumber:   lot.LotNumber,
+			Quantity:    shipment.Quantity,
+		}
+		shippedQuantity += shipment.Quantity
+	}
+
+	sort.Ints(customerIDs)
+	customers := make([]model.CustomerResponse, len(customerIDs))
+	for i, customerID := range customerIDs {
+		customers[i] = customerMap[customerID]
+	}
+
+	return &model.GetLotRecallResponse{
+		LotNumber:       lotNumber,
+		Lots:            lotResponses,
+		WorkOrders:      workOrderResponses,
+		ProducedLots:    producedLotResponses,
+		Shipments:       shipmentResponses,
+		Customers:       customers,
+		ShippedQuantity: shippedQuantity,
+		OnHandQuantity:  onHandQuantity,
+	}, ""
+}
+
+// GetStockValuation values the stock on hand at the end of the as-of day. Quantities are the
+// current inventory with the history changes recorded after the day taken back off; under AVERAGE they are valued at the moving-average cost the
+// product had then, under FIFO at the cost of what its layers held then. A product whose layers
+// were empty at the time falls back to its average cost.
+func (s *ReportService) GetStockValuation(ctx context.Context, asOfDate time.Time) (*model.GetStockValuationResponse, string) {
+	// Everything dated on the as-of day is included
+	cutoff := asOfDate.AddDate(0, 0, 1)
+
+	quantities, err := s.historyRepo.GetQuantitiesBeforeQuery(ctx, cutoff, nil)
+	if err != nil {
+		log.Error("ReportService.GetStockValuation Error when get quantities: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	averageCosts, err := s.costHistoryRepo.GetCostsBeforeQuery(ctx, cutoff, nil)
+	if err != nil {
+		log.Error("ReportService.GetStockValuation Error when get cost histories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	layers := make(map[int][]entity.InventoryCostLayer) // productID -> layers held at the cutoff, newest first
+	if s.valuationMethod == entity.InventoryValuationMethod.FIFO {
+		remainingLayers, err := s.costLayerRepo.GetRemainingBeforeQuery(ctx, cutoff, nil)
+		if err != nil {
+			log.Error("ReportService.GetStockValuation Error when get cost layers: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		for _, layer := range remainingLayers {
+			layers[layer.ProductID] = append(layers[layer.ProductID], layer)
+		}
+	}
+
+	products, err := s.productRepo.GetAllQuery(ctx, "", "", nil)
+	if err != nil {
+		log.Error("ReportService.GetStockValuation Error when get products: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	categories, err := s.categoryRepo.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("ReportService.GetStockValuation Error when get categories: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	categoryNames := make(map[int]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	response := &model.GetStockValuationResponse{
+		AsOfDate:       asOfDate,
+		Method:         s.valuationMethod,
+		Products:       []model.StockValuationProduct{},
+		Categories:     []model.StockValuationCategoryTotal{},
+		OperationTypes: []model.StockValuationOperationTypeTotal{},
+	}
+	categoryTotals := make(map[int]*model.StockValuationCategoryTotal) // categoryID (0 = chưa phân loại) -> total
+	operationTypeTotals := make(map[string]*model.StockValuationOperationTypeTotal)
+	for _, product := range products {
+		quantity := quantities[product.ID]
+		if quantity == 0 {
+			continue
+		}
+
+		averageCost, exists := averageCosts[product.ID]
+		if !exists {
+			averageCost = product.Cost
+		}
+
+		// Under FIFO the stock on hand is the newest stock received, valued at its layers. What the
+		// layers do not cover is valued at the average cost and reported apart.
+		var layerValue, uncoveredValue float64
+		uncoveredQuantity := 0
+		if s.valuationMethod == entity.InventoryValuationMethod.FIFO {
+			remaining := quantity
+			for _, layer := range layers[product.ID] {
+				if remaining <= 0 {
+					break
+				}
+				taken := min(layer.RemainingQuantity, remaining)
+				layerValue += float64(taken) * layer.UnitCost
+				remaining -= taken
+			}
+			uncoveredQuantity = max(remaining, 0)
+			uncoveredValue = roundCost(float64(uncoveredQuantity) * averageCost)
+			if quantity < 0 {
+				// Oversold stock holds no layers
+				layerValue = float64(quantity) * averageCost
+			}
+		} else {
+			layerValue = float64(quantity) * averageCost
+		}
+		value := roundCost(layerValue + uncoveredValue)
+		unitCost := roundCost(value / float64(quantity))
+
+		categoryID := 0
+		categoryName := "Chưa phân loại"
+		if product.CategoryID != nil {
+			categoryID = *product.CategoryID
+			categoryName = categoryNames[categoryID]
+		}
+
+		response.Products = append(response.Products, model.StockValuationProduct{
+			ProductID:         product.ID,
+			ProductCode:       product.Code,
+			ProductName:       product.Name,
+			CategoryID:        product.CategoryID,
+			CategoryName:      categoryName,
+			OperationType:     product.OperationType,
+			Quantity:          quantity,
+			UnitCost:          unitCost,
+			Value:             value,
+			UncoveredQuantity: uncoveredQuantity,
+			UncoveredValue:    uncoveredValue,
+		})
+
+		categoryTotal, exists := categoryTotals[categoryID]
+		if !exists {
+			categoryTotal = &model.StockValuationCategoryTotal{CategoryID: product.CategoryID, CategoryName: categoryName}
+			categoryTotals[categoryID] = categoryTotal
+		}
+		categoryTotal.Quantity += quantity
+		categoryTotal.Value = roundCost(categoryTotal.Value + value)
+
+		operationTypeTotal, exists := operationTypeTotals[product.OperationType]
+		if !exists {
+			operationTypeTotal = &model.StockValuationOperationTypeTotal{OperationType: product.OperationType}
+			operationTypeTotals[product.OperationType] = operationTypeTotal
+		}
+		operationTypeTotal.Quantity += quantity
+		operationTypeTotal.Value = roundCost(operationTypeTotal.Value + value)
+
+		response.TotalValue = roundCost(response.TotalValue + value)
+		response.UncoveredValue = roundCost(response.UncoveredValue + uncoveredValue)
+	}
+
+	for _, categoryTotal := range categoryTotals {
+		response.Categories = append(response.Categories, *categoryTotal)
+	}
+	sort.Slice(response.Categories, func(i, j int) bool {
+		return response.Categories[i].CategoryName < response.Categories[j].CategoryName
+	})
+	for _, operationTypeTotal := range operationTypeTotals {
+		response.OperationTypes = append(response.OperationTypes, *operationTypeTotal)
+	}
+	sort.Slice(response.OperationTypes, func(i, j int) bool {
+		return response.OperationTypes[i].OperationType < response.OperationTypes[j].OperationType
+	})
+
+	return response, ""
+}
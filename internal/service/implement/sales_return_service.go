@@ -0,0 +1,425 @@
+package serviceimplement
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type SalesReturnService struct {
+	salesReturnRepo      repository.SalesReturnRepository
+	salesReturnItemRepo  repository.SalesReturnItemRepository
+	orderRepo            repository.OrderRepository
+	orderItemRepo        repository.OrderItemRepository
+	inventoryRepo        repository.InventoryRepository
+	inventoryHistoryRepo repository.InventoryHistoryRepository
+	productRepo          repository.ProductRepository
+	bomRepo              repository.ProductBomRepository
+	userRepo             repository.UserRepository
+	unitOfWork           repository.UnitOfWork
+	inventoryLotRepo     repository.InventoryLotRepository
+	orderItemLotRepo     repository.OrderItemLotRepository
+	costLayers           *costLayerLedger
+}
+
+func NewSalesReturnService(
+	salesReturnRepo repository.SalesReturnRepository,
+	salesReturnItemRepo repository.SalesReturnItemRepository,
+	orderRepo repository.OrderRepository,
+	orderItemRepo repository.OrderItemRepository,
+	inventoryRepo repository.InventoryRepository,
+	inventoryHistoryRepo repository.InventoryHistoryRepository,
+	productRepo repository.ProductRepository,
+	bomRepo repository.ProductBomRepository,
+	userRepo repository.UserRepository,
+	unitOfWork repository.UnitOfWork,
+	inventoryLotRepo repository.InventoryLotRepository,
+	orderItemLotRepo repository.OrderItemLotRepository,
+	inventoryCostLayerRepo repository.InventoryCostLayerRepository,
+	inventoryCostLayerMovementRepo repository.InventoryCostLayerMovementRepository,
+) service.SalesReturnService {
+	return &SalesReturnService{
+		salesReturnRepo:      salesReturnRepo,
+		salesReturnItemRepo:  salesReturnItemRepo,
+		orderRepo:            orderRepo,
+		orderItemRepo:        orderItemRepo,
+		inventoryRepo:        inventoryRepo,
+		inventoryHistoryRepo: inventoryHistoryRepo,
+		productRepo:          productRepo,
+		bomRepo:              bomRepo,
+		userRepo:             userRepo,
+		unitOfWork:           unitOfWork,
+		inventoryLotRepo:     inventoryLotRepo,
+		orderItemLotRepo:     orderItemLotRepo,
+		costLayers:           newCostLayerLedger(inventoryCostLayerRepo, inventoryCostLayerMovementRepo),
+	}
+}
+
+func (s *SalesReturnService) Create(ctx *gin.Context, orderID int, request model.CreateSalesReturnRequest, userID int) (*model.SalesReturnResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("SalesReturnService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("SalesReturnService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the order so that concurrent returns cannot exceed the ordered quantities
+	order, err := s.orderRepo.GetOneByIDForUpdateQuery(ctx, orderID, tx)
+	if err != nil {
+		log.Error("SalesReturnService.Create Error when get order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if order.DeliveryStatus == entity.OrderDeliveryStatus.CANCELLED {
+		return nil, error_utils.ErrorCode.ORDER_ALREADY_CANCELLED
+	}
+	// Goods can only come back once they have left
+	if currentOrderStatus(order) == entity.OrderDeliveryStatus.PENDING {
+		return nil, error_utils.ErrorCode.SALES_RETURN_NOT_ALLOWED
+	}
+
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, tx)
+	if err != nil {
+		log.Error("SalesReturnService.Create Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	orderItems, err := s.orderItemRepo.GetAllByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("SalesReturnService.Create Error when get order items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	orderItemMap := make(map[int]entity.OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		orderItemMap[item.ID] = item
+	}
+
+	returnedQuantities, err := s.salesReturnItemRepo.GetReturnedQuantitiesByOrderIDQuery(ctx, order.ID, tx)
+	if err != nil {
+		log.Error("SalesReturnService.Create Error when get returned quantities: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Validate quantities and work out the credit and the inventory to put back
+	returnItems := make([]*entity.SalesReturnItem, 0, len(request.Items))
+	restockChanges := make(map[int]int) // productID -> quantity to put back
+	itemsCreditAmount := 0
+	for _, itemRequest := range request.Items {
+		orderItem, exists := orderItemMap[itemRequest.OrderItemID]
+		if !exists {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+
+		returnedQuantities[orderItem.ID] += itemRequest.Quantity
+		if returnedQuantities[orderItem.ID] > orderItem.Quantity {
+			return nil, error_utils.ErrorCode.RETURN_QUANTITY_EXCEEDED
+		}
+
+		// Credit the returned share of the line amount, discount included
+		lineAmount := orderItem.FinalAmount
+		if lineAmount == 0 {
+			itemTotal := orderItem.Quantity * orderItem.SellingPrice
+			lineAmount = itemTotal - (itemTotal*orderItem.DiscountPercent)/100
+		}
+		creditAmount := lineAmount * itemRequest.Quantity / orderItem.Quantity
+		itemsCreditAmount += creditAmount
+
+		// Returns are counted in the unit the item was sold in, stock is kept in the base unit
+		returnedBaseQuantity := float64(itemRequest.Quantity) * float64(orderItem.BaseQuantity) / float64(orderItem.Quantity)
+
+		switch itemRequest.Disposition {
+		case entity.SalesReturnDisposition.RESTOCK_PRODUCT:
+			restockChanges[orderItem.ProductID] += int(math.Floor(roundBomQuantity(returnedBaseQuantity)))
+		case entity.SalesReturnDisposition.RESTOCK_MATERIALS:
+			// Break the product down by the recipe it was ordered with
+			materials, err := explodeProductMaterials(ctx, s.productRepo, s.bomRepo, orderItem.ProductID, returnedBaseQuantity, order.OrderDate, tx)
+			if errors.Is(err, errBomTooDeep) {
+				return nil, error_utils.ErrorCode.BOM_DEPTH_EXCEEDED
+			}
+			if err != nil {
+				log.Error("SalesReturnService.Create Error when calculate materials: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+			// Only whole units of the materials can be put back on the shelf
+			for productID, quantity := range materials {
+				restockChanges[productID] += int(math.Floor(roundBomQuantity(quantity)))
+			}
+		}
+
+		returnItems = append(returnItems, &entity.SalesReturnItem{
+			OrderItemID:  orderItem.ID,
+			ProductID:    orderItem.ProductID,
+			Quantity:     itemRequest.Quantity,
+			Disposition:  itemRequest.Disposition,
+			Reason:       itemRequest.Reason,
+			CreditAmount: creditAmount,
+		})
+	}
+
+	returnDate := time.Now()
+	if request.ReturnDate != nil {
+		returnDate = *request.ReturnDate
+	}
+
+	// Tax was charged on the returned goods, so it is credited back too
+	salesReturn := &entity.SalesReturn{
+		OrderID:       order.ID,
+		ReturnDate:    returnDate,
+		Reason:        request.Reason,
+		CreditAmount:  itemsCreditAmount + int(float64(itemsCreditAmount)*float64(order.TaxPercent)/100),
+		CreatedBy:     &user.ID,
+		CreatedByName: user.Username,
+		CreatedAt:     time.Now(),
+	}
+
+	err = s.salesReturnRepo.CreateCommand(ctx, salesReturn, tx)
+	if err != nil {
+		log.Error("SalesReturnService.Create Error when create sales return: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	for _, item := range returnItems {
+		item.SalesReturnID = salesReturn.ID
+		err = s.salesReturnItemRepo.CreateCommand(ctx, item, tx)
+		if err != nil {
+			log.Error("SalesReturnService.Create Error when create sales return item: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	note := fmt.Sprintf("Nhập lại kho từ phiếu trả hàng %s (đơn hàng %s)", salesReturn.Code, order.Code)
+	if errCode := s.restockInventory(ctx, salesReturn, order.WarehouseID, restockChanges, user.Username, note, tx); errCode != "" {
+		return nil, errCode
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("SalesReturnService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toSalesReturnResponse(*salesReturn)
+	response.Items = make([]model.SalesReturnItemResponse, 0, len(returnItems))
+	for _, item := range returnItems {
+		response.Items = append(response.Items, s.toSalesReturnItemResponse(ctx, *item))
+	}
+
+	return &response, ""
+}
+
+// restockInventory puts the returned quantities back into the inventory of the warehouse the
+// order was shipped from with optimistic locking, and into the lots they were shipped from,
+// writing one history row per product that references the sales return
+func (s *SalesReturnService) restockInventory(ctx *gin.Context, salesReturn *entity.SalesReturn, warehouseID int, changes map[int]int, importerName string, note string, tx *sqlx.Tx) string {
+	productIDs := make([]int, 0, len(changes))
+	for productID, quantity := range changes {
+		if quantity > 0 {
+			productIDs = append(productIDs, productID)
+		}
+	}
+	sort.Ints(productIDs)
+
+	for _, productID := range productIDs {
+		quantity := changes[productID]
+
+		inventory, err := s.inventoryRepo.GetOneByProductIDQuery(ctx, productID, warehouseID, tx)
+		if err != nil {
+			log.Error("SalesReturnService.restockInventory Error when get inventory: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		if inventory != nil {
+			// Get inventory with FOR UPDATE lock
+			inventory, err = s.inventoryRepo.GetOneByIDForUpdateQuery(ctx, inventory.ID, tx)
+			if err != nil {
+				log.Error("SalesReturnService.restockInventory Error when lock inventory: " + err.Error())
+				return error_utils.ErrorCode.DB_DOWN
+			}
+		}
+
+		var finalQuantity int
+		if inventory == nil {
+			// Create new inventory record if doesn't exist
+			newInventory := &entity.Inventory{
+				ProductID:   productID,
+				WarehouseID: warehouseID,
+				Quantity:    quantity,
+				Version:     uuid.New().String(),
+			}
+			err = s.inventoryRepo.CreateCommand(ctx, newInventory, tx)
+			if err != nil {
+				log.Error("SalesReturnService.restockInventory Error when create inventory: " + err.Error())
+				return error_utils.ErrorCode.DB_DOWN
+			}
+			finalQuantity = quantity
+		} else {
+			err = s.inventoryRepo.UpdateQuantityWithVersionCommand(ctx, productID, warehouseID, quantity, inventory.Version, uuid.New().String(), tx)
+			if err != nil {
+				var versionMismatchError *error_utils.VersionMismatchError
+				if errors.As(err, &versionMismatchError) {
+					return error_utils.ErrorCode.INVENTORY_VERSION_MISMATCH
+				}
+				log.Error("SalesReturnService.restockInventory Error when update inventory: " + err.Error())
+				return error_utils.ErrorCode.DB_DOWN
+			}
+			finalQuantity = inventory.Quantity + quantity
+		}
+
+		// Returned goods go back to the lots the order shipped them from
+		err = returnOrderLots(ctx, s.inventoryLotRepo, s.orderItemLotRepo, salesReturn.OrderID, productID, quantity, tx)
+		if err != nil {
+			log.Error("SalesReturnService.restockInventory Error when update lots: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		// Their cost goes back to the layers the order took it from
+		product, err := s.productRepo.GetOneByIDQuery(ctx, productID, tx)
+		if err != nil {
+			log.Error("SalesReturnService.restockInventory Error when get product: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		var fallbackCost float64
+		if product != nil {
+			fallbackCost = product.Cost
+		}
+		err = s.costLayers.restore(ctx, productID, quantity, entity.InventoryCostLayerReferenceType.ORDER, salesReturn.OrderID, fallbackCost, tx)
+		if err != nil {
+			log.Error("SalesReturnService.restockInventory Error when update cost layers: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		inventoryHistory := &entity.InventoryHistory{
+			ProductID:     productID,
+			WarehouseID:   &warehouseID,
+			Quantity:      quantity,
+			FinalQuantity: finalQuantity,
+			ImporterName:  importerName,
+			ImportedAt:    time.Now(),
+			Note:          note,
+			ReferenceID:   &salesReturn.ID,
+			ReferenceType: &entity.InventoryHistoryReferenceType.SALES_RETURN,
+		}
+
+		err = s.inventoryHistoryRepo.CreateCommand(ctx, inventoryHistory, tx)
+		if err != nil {
+			log.Error("SalesReturnService.restockInventory Error when create inventory history: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	return ""
+}
+
+func (s *SalesReturnService) GetAllByOrderID(ctx *gin.Context, orderID int) (*model.GetAllSalesReturnsResponse, string) {
+	order, err := s.orderRepo.GetOneByIDQuery(ctx, orderID, nil)
+	if err != nil {
+		log.Error("SalesReturnService.GetAllByOrderID Error when get order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if order == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	salesReturns, err := s.salesReturnRepo.GetAllByOrderIDQuery(ctx, order.ID, nil)
+	if err != nil {
+		log.Error("SalesReturnService.GetAllByOrderID Error when get sales returns: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Items omitted for the list
+	salesReturnResponses := make([]model.SalesReturnResponse, len(salesReturns))
+	for i, salesReturn := range salesReturns {
+		salesReturnResponses[i] = toSalesReturnResponse(salesReturn)
+	}
+
+	return &model.GetAllSalesReturnsResponse{
+		SalesReturns: salesReturnResponses,
+	}, ""
+}
+
+func (s *SalesReturnService) GetOne(ctx *gin.Context, id int) (*model.GetOneSalesReturnResponse, string) {
+	salesReturn, err := s.salesReturnRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("SalesReturnService.GetOne Error when get sales return: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if salesReturn == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	items, err := s.salesReturnItemRepo.GetAllBySalesReturnIDQuery(ctx, salesReturn.ID, nil)
+	if err != nil {
+		log.Error("SalesReturnService.GetOne Error when get sales return items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toSalesReturnResponse(*salesReturn)
+	response.Items = make([]model.SalesReturnItemResponse, 0, len(items))
+	for _, item := range items {
+		response.Items = append(response.Items, s.toSalesReturnItemResponse(ctx, item))
+	}
+
+	return &model.GetOneSalesReturnResponse{
+		SalesReturn: response,
+	}, ""
+}
+
+func toSalesReturnResponse(salesReturn entity.SalesReturn) model.SalesReturnResponse {
+	return model.SalesReturnResponse{
+		ID:            salesReturn.ID,
+		Code:          salesReturn.Code,
+		OrderID:       salesReturn.OrderID,
+		ReturnDate:    salesReturn.ReturnDate,
+		Reason:        salesReturn.Reason,
+		CreditAmount:  salesReturn.CreditAmount,
+		CreatedBy:     salesReturn.CreatedBy,
+		CreatedByName: salesReturn.CreatedByName,
+		CreatedAt:     salesReturn.CreatedAt,
+	}
+}
+
+func (s *SalesReturnService) toSalesReturnItemResponse(ctx *gin.Context, item entity.SalesReturnItem) model.SalesReturnItemResponse {
+	productName := ""
+	product, err := s.productRepo.GetOneByIDQuery(ctx, item.ProductID, nil)
+	if err != nil {
+		log.Error("SalesReturnService.toSalesReturnItemResponse Error when get product: " + err.Error())
+	} else if product != nil {
+		productName = product.Name
+	}
+
+	return model.SalesReturnItemResponse{
+		ID:            item.ID,
+		SalesReturnID: item.SalesReturnID,
+		OrderItemID:   item.OrderItemID,
+		ProductID:     item.ProductID,
+		ProductName:   productName,
+		Quantity:      item.Quantity,
+		Disposition:   item.Disposition,
+		Reason:        item.Reason,
+		CreditAmount:  item.CreditAmount,
+	}
+}
@@ -48,14 +48,15 @@ func (s *StatisticsService) GetDashboardStats(ctx context.Context) (model.Dashbo
 	lowStockProducts := 0
 
 	for _, product := range products {
-		inventory, err := s.inventoryRepo.GetOneByProductIDQuery(ctx, product.ID, nil)
+		// Stock is counted over all warehouses
+		quantities, err := s.inventoryRepo.GetTotalQuantitiesByProductIDsQuery(ctx, []int{product.ID}, nil)
 		if err != nil {
 			log.Error("StatisticsService.GetDashboardStats Error fetching inventory for product " + string(rune(product.ID)) + ": " + err.Error())
 			continue
 		}
-		if inventory != nil {
-			totalInventoryItems += inventory.Quantity
-			if inventory.Quantity < 10 { // Consider low stock if less than 10
+		if quantity, exists := quantities[product.ID]; exists {
+			totalInventoryItems += quantity
+			if quantity < 10 { // Consider low stock if less than 10
 				lowStockProducts++
 			}
 		}
@@ -0,0 +1,352 @@
+package serviceimplement
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type StockTransferService struct {
+	stockTransferRepo     repository.StockTransferRepository
+	stockTransferItemRepo repository.StockTransferItemRepository
+	warehouseRepo         repository.WarehouseRepository
+	inventoryRepo         repository.InventoryRepository
+	inventoryHistoryRepo  repository.InventoryHistoryRepository
+	inventoryLotRepo      repository.InventoryLotRepository
+	reservationRepo       repository.InventoryReservationRepository
+	productRepo           repository.ProductRepository
+	unitRepo              repository.UnitOfMeasureRepository
+	userRepo              repository.UserRepository
+	unitOfWork            repository.UnitOfWork
+	unitConverter         *unitConverter
+}
+
+func NewStockTransferService(
+	stockTransferRepo repository.StockTransferRepository,
+	stockTransferItemRepo repository.StockTransferItemRepository,
+	warehouseRepo repository.WarehouseRepository,
+	inventoryRepo repository.InventoryRepository,
+	inventoryHistoryRepo repository.InventoryHistoryRepository,
+	reservationRepo repository.InventoryReservationRepository,
+	productRepo repository.ProductRepository,
+	unitRepo repository.UnitOfMeasureRepository,
+	userRepo repository.UserRepository,
+	unitOfWork repository.UnitOfWork,
+	unitConversionRepo repository.UnitConversionRepository,
+	inventoryLotRepo repository.InventoryLotRepository,
+) service.StockTransferService {
+	return &StockTransferService{
+		stockTransferRepo:     stockTransferRepo,
+		stockTransferItemRepo: stockTransferItemRepo,
+		warehouseRepo:         warehouseRepo,
+		inventoryRepo:         inventoryRepo,
+		inventoryHistoryRepo:  inventoryHistoryRepo,
+		inventoryLotRepo:      inventoryLotRepo,
+		reservationRepo:       reservationRepo,
+		productRepo:           productRepo,
+		unitRepo:              unitRepo,
+		userRepo:              userRepo,
+		unitOfWork:            unitOfWork,
+		unitConverter:         newUnitConverter(unitConversionRepo),
+	}
+}
+
+func (s *StockTransferService) Create(ctx *gin.Context, request model.CreateStockTransferRequest, userID int) (*model.StockTransferResponse, string) {
+	if request.FromWarehouseID == request.ToWarehouseID {
+		return nil, error_utils.ErrorCode.STOCK_TRANSFER_SAME_WAREHOUSE
+	}
+
+	// Get user details to get username for history
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, nil)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		log.Error("StockTransferService.Create Error: user not found")
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("StockTransferService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	fromWarehouse, errCode := resolveWarehouse(ctx, s.warehouseRepo, &request.FromWarehouseID, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+	toWarehouse, errCode := resolveWarehouse(ctx, s.warehouseRepo, &request.ToWarehouseID, tx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Quantities are moved in the base unit, lines of the same product are moved together
+	items := make([]*entity.StockTransferItem, len(request.Items))
+	quantities := make(map[int]int)
+	var productIDs []int
+	for i, itemRequest := range request.Items {
+		product, err := s.productRepo.GetOneByIDQuery(ctx, itemRequest.ProductID, tx)
+		if err != nil {
+			log.Error("StockTransferService.Create Error when get product: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if product == nil {
+			log.Error(fmt.Sprintf("StockTransferService.Create Error: product with ID %d not found", itemRequest.ProductID))
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+
+		factor, errCode := s.unitConverter.baseFactor(ctx, product, itemRequest.UnitID, tx)
+		if errCode != "" {
+			return nil, errCode
+		}
+		quantity, errCode := toWholeBaseQuantity(itemRequest.Quantity, factor)
+		if errCode != "" {
+			return nil, errCode
+		}
+
+		items[i] = &entity.StockTransferItem{
+			ProductID: itemRequest.ProductID,
+			Quantity:  quantity,
+			Note:      itemRequest.Note,
+		}
+		if _, exists := quantities[itemRequest.ProductID]; !exists {
+			productIDs = append(productIDs, itemRequest.ProductID)
+		}
+		quantities[itemRequest.ProductID] += quantity
+	}
+	sort.Ints(productIDs)
+
+	// Lock both sides in warehouse order so that opposite transfers cannot deadlock
+	warehouseIDs := []int{fromWarehouse.ID, toWarehouse.ID}
+	sort.Ints(warehouseIDs)
+	inventoryMaps := make(map[int]map[int]*entity.Inventory)
+	for _, warehouseID := range warehouseIDs {
+		inventoryMap, errCode := lockInventories(ctx, s.inventoryRepo, productIDs, warehouseID, tx)
+		if errCode != "" {
+			return nil, errCode
+		}
+		inventoryMaps[warehouseID] = inventoryMap
+	}
+
+	// Stock reserved for orders in the source warehouse cannot be moved away
+	if errCode := checkStockAvailability(ctx, s.productRepo, s.unitRepo, s.reservationRepo, fromWarehouse.ID, inventoryMaps[fromWarehouse.ID], quantities, nil, tx); errCode != "" {
+		return nil, errCode
+	}
+
+	transferDate := time.Now()
+	if request.TransferDate != nil {
+		transferDate = *request.TransferDate
+	}
+
+	stockTransfer := &entity.StockTransfer{
+		FromWarehouseID: fromWarehouse.ID,
+		ToWarehouseID:   toWarehouse.ID,
+		TransferDate:    transferDate,
+		Note:            request.Note,
+		CreatedBy:       &user.ID,
+		CreatedByName:   user.Username,
+	}
+
+	// Create stock transfer (code will be generated in repository)
+	err = s.stockTransferRepo.CreateCommand(ctx, stockTransfer, tx)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when create stock transfer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	for _, item := range items {
+		item.StockTransferID = stockTransfer.ID
+		err = s.stockTransferItemRepo.CreateCommand(ctx, item, tx)
+		if err != nil {
+			log.Error("StockTransferService.Create Error when create stock transfer item: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// Move the stock and record the movement on both sides
+	for _, productID := range productIDs {
+		quantity := quantities[productID]
+		movements := []struct {
+			warehouse *entity.Warehouse
+			change    int
+			note      string
+		}{
+			{fromWarehouse, -quantity, fmt.Sprintf("Chuyển kho sang %s theo phiếu %s", toWarehouse.Name, stockTransfer.Code)},
+			{toWarehouse, quantity, fmt.Sprintf("Nhận chuyển kho từ %s theo phiếu %s", fromWarehouse.Name, stockTransfer.Code)},
+		}
+		for _, movement := range movements {
+			inventory := inventoryMaps[movement.warehouse.ID][productID]
+			err = s.inventoryRepo.UpdateQuantityCommand(ctx, productID, movement.warehouse.ID, movement.change, uuid.New().String(), tx)
+			if err != nil {
+				log.Error(fmt.Sprintf("StockTransferService.Create Error when update inventory for product ID %d: %s", productID, err.Error()))
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+
+			inventoryHistory := &entity.InventoryHistory{
+				ProductID:     productID,
+				WarehouseID:   &movement.warehouse.ID,
+				Quantity:      movement.change,
+				FinalQuantity: inventory.Quantity + movement.change,
+				ImporterName:  user.Username,
+				ImportedAt:    time.Now(),
+				Note:          movement.note,
+				ReferenceID:   &stockTransfer.ID,
+				ReferenceType: &entity.InventoryHistoryReferenceType.STOCK_TRANSFER,
+			}
+			err = s.inventoryHistoryRepo.CreateCommand(ctx, inventoryHistory, tx)
+			if err != nil {
+				log.Error("StockTransferService.Create Error when create inventory history: " + err.Error())
+				return nil, error_utils.ErrorCode.DB_DOWN
+			}
+		}
+
+		// Lots move with the stock, keeping their number and expiry date
+		draws, err := drawLotsFEFO(ctx, s.inventoryLotRepo, productID, fromWarehouse.ID, inventoryMaps[fromWarehouse.ID][productID].Quantity, quantity, tx)
+		if errors.Is(err, errInsufficientUnexpiredStock) {
+			return nil, error_utils.ErrorCode.INSUFFICIENT_UNEXPIRED_STOCK
+		}
+		if err != nil {
+			log.Error(fmt.Sprintf("StockTransferService.Create Error when update lots for product ID %d: %s", productID, err.Error()))
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		for _, draw := range draws {
+			if _, errCode := receiveLot(ctx, s.inventoryLotRepo, productID, toWarehouse.ID, draw.lot.LotNumber, draw.lot.ExpiryDate, draw.quantity, tx); errCode != "" {
+				return nil, errCode
+			}
+		}
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("StockTransferService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	result, errCode := s.GetOne(ctx, stockTransfer.ID)
+	if errCode != "" {
+		return nil, errCode
+	}
+	return &result.StockTransfer, ""
+}
+
+func (s *StockTransferService) GetAll(ctx *gin.Context, warehouseID int) (*model.GetAllStockTransfersResponse, string) {
+	stockTransfers, err := s.stockTransferRepo.GetAllWithFiltersQuery(ctx, warehouseID, nil)
+	if err != nil {
+		log.Error("StockTransferService.GetAll Error when get stock transfers: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	warehouseNames, errCode := s.getWarehouseNames(ctx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	// Items omitted for GetAll
+	stockTransferResponses := make([]model.StockTransferResponse, len(stockTransfers))
+	for i := range stockTransfers {
+		stockTransferResponses[i] = toStockTransferResponse(&stockTransfers[i], warehouseNames)
+	}
+
+	return &model.GetAllStockTransfersResponse{
+		StockTransfers: stockTransferResponses,
+	}, ""
+}
+
+func (s *StockTransferService) GetOne(ctx *gin.Context, id int) (*model.GetOneStockTransferResponse, string) {
+	stockTransfer, err := s.stockTransferRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("StockTransferService.GetOne Error when get stock transfer: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if stockTransfer == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	items, err := s.stockTransferItemRepo.GetAllByStockTransferIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("StockTransferService.GetOne Error when get stock transfer items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	warehouseNames, errCode := s.getWarehouseNames(ctx)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	response := toStockTransferResponse(stockTransfer, warehouseNames)
+	response.Items = make([]model.StockTransferItemResponse, len(items))
+	for i, item := range items {
+		productName := ""
+		product, err := s.productRepo.GetOneByIDQuery(ctx, item.ProductID, nil)
+		if err != nil {
+			log.Error("StockTransferService.GetOne Error when get product: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if product != nil {
+			productName = product.Name
+		}
+
+		response.Items[i] = model.StockTransferItemResponse{
+			ID:          item.ID,
+			ProductID:   item.ProductID,
+			ProductName: productName,
+			Quantity:    item.Quantity,
+			Note:        item.Note,
+		}
+	}
+
+	return &model.GetOneStockTransferResponse{
+		StockTransfer: response,
+	}, ""
+}
+
+func (s *StockTransferService) getWarehouseNames(ctx *gin.Context) (map[int]string, string) {
+	warehouses, err := s.warehouseRepo.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("StockTransferService.getWarehouseNames Error when get warehouses: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	warehouseNames := make(map[int]string, len(warehouses))
+	for _, warehouse := range warehouses {
+		warehouseNames[warehouse.ID] = warehouse.Name
+	}
+	return warehouseNames, ""
+}
+
+func toStockTransferResponse(stockTransfer *entity.StockTransfer, warehouseNames map[int]string) model.StockTransferResponse {
+	return model.StockTransferResponse{
+		ID:                stockTransfer.ID,
+		Code:              stockTransfer.Code,
+		FromWarehouseID:   stockTransfer.FromWarehouseID,
+		FromWarehouseName: warehouseNames[stockTransfer.FromWarehouseID],
+		ToWarehouseID:     stockTransfer.ToWarehouseID,
+		ToWarehouseName:   warehouseNames[stockTransfer.ToWarehouseID],
+		TransferDate:      stockTransfer.TransferDate,
+		Note:              stockTransfer.Note,
+		CreatedBy:         stockTransfer.CreatedBy,
+		CreatedByName:     stockTransfer.CreatedByName,
+		CreatedAt:         stockTransfer.CreatedAt,
+	}
+}
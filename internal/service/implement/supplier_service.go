@@ -0,0 +1,149 @@
+package serviceimplement
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type SupplierService struct {
+	supplierRepository repository.SupplierRepository
+	unitOfWork         repository.UnitOfWork
+}
+
+func NewSupplierService(supplierRepository repository.SupplierRepository, unitOfWork repository.UnitOfWork) service.SupplierService {
+	return &SupplierService{
+		supplierRepository: supplierRepository,
+		unitOfWork:         unitOfWork,
+	}
+}
+
+func toSupplierResponse(supplier *entity.Supplier) model.SupplierResponse {
+	return model.SupplierResponse{
+		ID:      supplier.ID,
+		Code:    supplier.Code,
+		Name:    supplier.Name,
+		Phone:   supplier.Phone,
+		Email:   supplier.Email,
+		Address: supplier.Address,
+		TaxCode: supplier.TaxCode,
+	}
+}
+
+func (s *SupplierService) Create(ctx *gin.Context, request model.CreateSupplierRequest) (*model.SupplierResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("SupplierService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("SupplierService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	supplier := &entity.Supplier{
+		Name:    request.Name,
+		Phone:   request.Phone,
+		Email:   request.Email,
+		Address: request.Address,
+		TaxCode: request.TaxCode,
+	}
+
+	// Save supplier to database, the code is generated from the ID
+	err = s.supplierRepository.CreateCommand(ctx, supplier, tx)
+	if err != nil {
+		log.Error("SupplierService.Create Error when create supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("SupplierService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toSupplierResponse(supplier)
+	return &response, ""
+}
+
+func (s *SupplierService) Update(ctx *gin.Context, supplierID int, request model.UpdateSupplierRequest) (*model.SupplierResponse, string) {
+	// Check if supplier exists
+	supplier, err := s.supplierRepository.GetOneByIDQuery(ctx, supplierID, nil)
+	if err != nil {
+		log.Error("SupplierService.Update Error when get supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if supplier == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// Only update fields that are given
+	if request.Name != "" {
+		supplier.Name = request.Name
+	}
+	if request.Phone != "" {
+		supplier.Phone = request.Phone
+	}
+	if request.Email != nil {
+		supplier.Email = request.Email
+	}
+	if request.Address != "" {
+		supplier.Address = request.Address
+	}
+	if request.TaxCode != nil {
+		supplier.TaxCode = request.TaxCode
+	}
+
+	// Save to database
+	err = s.supplierRepository.UpdateCommand(ctx, supplier, nil)
+	if err != nil {
+		log.Error("SupplierService.Update Error when update supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toSupplierResponse(supplier)
+	return &response, ""
+}
+
+func (s *SupplierService) GetAll(ctx *gin.Context) (*model.GetAllSuppliersResponse, string) {
+	suppliers, err := s.supplierRepository.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("SupplierService.GetAll Error when get suppliers: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	supplierResponses := make([]model.SupplierResponse, len(suppliers))
+	for i := range suppliers {
+		supplierResponses[i] = toSupplierResponse(&suppliers[i])
+	}
+
+	return &model.GetAllSuppliersResponse{
+		Suppliers: supplierResponses,
+	}, ""
+}
+
+func (s *SupplierService) GetOne(ctx *gin.Context, id int) (*model.GetOneSupplierResponse, string) {
+	supplier, err := s.supplierRepository.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("SupplierService.GetOne Error when get supplier: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if supplier == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	return &model.GetOneSupplierResponse{
+		Supplier: toSupplierResponse(supplier),
+	}, ""
+}
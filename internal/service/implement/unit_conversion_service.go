@@ -0,0 +1,292 @@
+package serviceimplement
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// unitConverter converts quantities given in an alternate unit into the product's base unit.
+// Conversions may be chained (THUNG -> L -> ML) and used in both directions, the product's own
+// conversions take precedence over the global ones.
+type unitConverter struct {
+	conversionRepo repository.UnitConversionRepository
+}
+
+func newUnitConverter(conversionRepo repository.UnitConversionRepository) *unitConverter {
+	return &unitConverter{conversionRepo: conversionRepo}
+}
+
+// baseFactor returns how many base units of the product one unit of unitID is. No unit means
+// the base unit itself.
+func (c *unitConverter) baseFactor(ctx context.Context, product *entity.Product, unitID *int, tx *sqlx.Tx) (float64, string) {
+	if unitID == nil || (product.UnitID != nil && *unitID == *product.UnitID) {
+		return 1, ""
+	}
+	if product.UnitID == nil {
+		return 0, error_utils.ErrorCode.UNIT_CONVERSION_NOT_FOUND
+	}
+
+	conversions, err := c.conversionRepo.GetAllApplicableByProductIDQuery(ctx, product.ID, tx)
+	if err != nil {
+		log.Error("unitConverter.baseFactor Error when get unit conversions: " + err.Error())
+		return 0, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Product conversions come last and override the global ones between the same units
+	factors := make(map[int]map[int]float64) // from unit -> to unit -> factor
+	addFactor := func(fromUnitID int, toUnitID int, factor float64) {
+		if factors[fromUnitID] == nil {
+			factors[fromUnitID] = make(map[int]float64)
+		}
+		factors[fromUnitID][toUnitID] = factor
+	}
+	for _, conversion := range conversions {
+		addFactor(conversion.FromUnitID, conversion.ToUnitID, conversion.Factor)
+		addFactor(conversion.ToUnitID, conversion.FromUnitID, 1/conversion.Factor)
+	}
+
+	// Breadth first, so that the shortest chain of conversions is used
+	reached := map[int]float64{*unitID: 1}
+	queue := []int{*unitID}
+	for len(queue) > 0 {
+		currentUnitID := queue[0]
+		queue = queue[1:]
+		if currentUnitID == *product.UnitID {
+			return reached[currentUnitID], ""
+		}
+
+		nextUnitIDs := make([]int, 0, len(factors[currentUnitID]))
+		for nextUnitID := range factors[currentUnitID] {
+			nextUnitIDs = append(nextUnitIDs, nextUnitID)
+		}
+		sort.Ints(nextUnitIDs)
+		for _, nextUnitID := range nextUnitIDs {
+			if _, exists := reached[nextUnitID]; !exists {
+				reached[nextUnitID] = reached[currentUnitID] * factors[currentUnitID][nextUnitID]
+				queue = append(queue, nextUnitID)
+			}
+		}
+	}
+
+	return 0, error_utils.ErrorCode.UNIT_CONVERSION_NOT_FOUND
+}
+
+// toWholeBaseQuantity converts a quantity with the factor from baseFactor. Stock is kept in whole
+// base units, so the result has to be whole.
+func toWholeBaseQuantity(quantity int, factor float64) (int, string) {
+	baseQuantity := roundBomQuantity(float64(quantity) * factor)
+	if baseQuantity != math.Trunc(baseQuantity) {
+		return 0, error_utils.ErrorCode.INVALID_UNIT_QUANTITY
+	}
+	return int(baseQuantity), ""
+}
+
+type UnitConversionService struct {
+	conversionRepository repository.UnitConversionRepository
+	unitRepository       repository.UnitOfMeasureRepository
+	productRepository    repository.ProductRepository
+}
+
+func NewUnitConversionService(
+	conversionRepository repository.UnitConversionRepository,
+	unitRepository repository.UnitOfMeasureRepository,
+	productRepository repository.ProductRepository,
+) service.UnitConversionService {
+	return &UnitConversionService{
+		conversionRepository: conversionRepository,
+		unitRepository:       unitRepository,
+		productRepository:    productRepository,
+	}
+}
+
+func (s *UnitConversionService) Create(ctx *gin.Context, request model.CreateUnitConversionRequest) (*model.UnitConversionResponse, string) {
+	if request.ProductID != nil {
+		product, err := s.productRepository.GetOneByIDQuery(ctx, *request.ProductID, nil)
+		if err != nil {
+			log.Error("UnitConversionService.Create Error when get product: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+		if product == nil {
+			return nil, error_utils.ErrorCode.NOT_FOUND
+		}
+	}
+
+	if errCode := s.validateUnits(ctx, request.ProductID, request.FromUnitID, request.ToUnitID, 0); errCode != "" {
+		return nil, errCode
+	}
+
+	conversion := &entity.UnitConversion{
+		ProductID:  request.ProductID,
+		FromUnitID: request.FromUnitID,
+		ToUnitID:   request.ToUnitID,
+		Factor:     request.Factor,
+	}
+
+	err := s.conversionRepository.CreateCommand(ctx, conversion, nil)
+	if err != nil {
+		var constraintErr *error_utils.ConstraintViolationError
+		if errors.As(err, &constraintErr) {
+			return nil, error_utils.ErrorCode.UNIT_CONVERSION_ALREADY_EXISTS
+		}
+		log.Error("UnitConversionService.Create Error when create unit conversion: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response, errCode := s.GetOne(ctx, conversion.ID)
+	if errCode != "" {
+		return nil, errCode
+	}
+	return &response.Conversion, ""
+}
+
+func (s *UnitConversionService) Update(ctx *gin.Context, id int, request model.UpdateUnitConversionRequest) (*model.UnitConversionResponse, string) {
+	conversion, err := s.conversionRepository.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("UnitConversionService.Update Error when get unit conversion: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if conversion == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	if errCode := s.validateUnits(ctx, conversion.ProductID, request.FromUnitID, request.ToUnitID, conversion.ID); errCode != "" {
+		return nil, errCode
+	}
+
+	conversion.FromUnitID = request.FromUnitID
+	conversion.ToUnitID = request.ToUnitID
+	conversion.Factor = request.Factor
+
+	err = s.conversionRepository.UpdateCommand(ctx, conversion, nil)
+	if err != nil {
+		var constraintErr *error_utils.ConstraintViolationError
+		if errors.As(err, &constraintErr) {
+			return nil, error_utils.ErrorCode.UNIT_CONVERSION_ALREADY_EXISTS
+		}
+		log.Error("UnitConversionService.Update Error when update unit conversion: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response, errCode := s.GetOne(ctx, conversion.ID)
+	if errCode != "" {
+		return nil, errCode
+	}
+	return &response.Conversion, ""
+}
+
+func (s *UnitConversionService) Delete(ctx *gin.Context, id int) string {
+	conversion, err := s.conversionRepository.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("UnitConversionService.Delete Error when get unit conversion: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if conversion == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+
+	err = s.conversionRepository.DeleteCommand(ctx, id, nil)
+	if err != nil {
+		log.Error("UnitConversionService.Delete Error when delete unit conversion: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+func (s *UnitConversionService) GetAll(ctx *gin.Context, productID int) (*model.GetAllUnitConversionsResponse, string) {
+	conversions, err := s.conversionRepository.GetAllWithFiltersQuery(ctx, productID, nil)
+	if err != nil {
+		log.Error("UnitConversionService.GetAll Error when get unit conversions: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	resp := &model.GetAllUnitConversionsResponse{Conversions: make([]model.UnitConversionResponse, 0, len(conversions))}
+	for _, conversion := range conversions {
+		resp.Conversions = append(resp.Conversions, s.toUnitConversionResponse(ctx, conversion))
+	}
+
+	return resp, ""
+}
+
+func (s *UnitConversionService) GetOne(ctx *gin.Context, id int) (*model.GetOneUnitConversionResponse, string) {
+	conversion, err := s.conversionRepository.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("UnitConversionService.GetOne Error when get unit conversion: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if conversion == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	return &model.GetOneUnitConversionResponse{Conversion: s.toUnitConversionResponse(ctx, *conversion)}, ""
+}
+
+// validateUnits checks that both units exist and differ, and that the conversion is not defined
+// yet. The unique key does not cover global conversions since their product is NULL.
+func (s *UnitConversionService) validateUnits(ctx *gin.Context, productID *int, fromUnitID int, toUnitID int, conversionID int) string {
+	if fromUnitID == toUnitID {
+		return error_utils.ErrorCode.BAD_REQUEST
+	}
+
+	for _, unitID := range []int{fromUnitID, toUnitID} {
+		unit, err := s.unitRepository.GetOneByIDQuery(ctx, unitID, nil)
+		if err != nil {
+			log.Error("UnitConversionService.validateUnits Error when get unit: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		if unit == nil {
+			return error_utils.ErrorCode.NOT_FOUND
+		}
+	}
+
+	// The same pair the other way round would define the conversion twice
+	for _, pair := range [][2]int{{fromUnitID, toUnitID}, {toUnitID, fromUnitID}} {
+		existing, err := s.conversionRepository.GetOneByUnitsQuery(ctx, productID, pair[0], pair[1], nil)
+		if err != nil {
+			log.Error("UnitConversionService.validateUnits Error when get unit conversion: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		if existing != nil && existing.ID != conversionID {
+			return error_utils.ErrorCode.UNIT_CONVERSION_ALREADY_EXISTS
+		}
+	}
+
+	return ""
+}
+
+func (s *UnitConversionService) toUnitConversionResponse(ctx *gin.Context, conversion entity.UnitConversion) model.UnitConversionResponse {
+	response := model.UnitConversionResponse{
+		ID:         conversion.ID,
+		ProductID:  conversion.ProductID,
+		FromUnitID: conversion.FromUnitID,
+		ToUnitID:   conversion.ToUnitID,
+		Factor:     conversion.Factor,
+		CreatedAt:  conversion.CreatedAt,
+		UpdatedAt:  conversion.UpdatedAt,
+	}
+
+	if conversion.ProductID != nil {
+		if product, err := s.productRepository.GetOneByIDQuery(ctx, *conversion.ProductID, nil); err == nil && product != nil {
+			response.ProductName = &product.Name
+		}
+	}
+	if unit, err := s.unitRepository.GetOneByIDQuery(ctx, conversion.FromUnitID, nil); err == nil && unit != nil {
+		response.FromUnitName = unit.Name
+	}
+	if unit, err := s.unitRepository.GetOneByIDQuery(ctx, conversion.ToUnitID, nil); err == nil && unit != nil {
+		response.ToUnitName = unit.Name
+	}
+
+	return response
+}
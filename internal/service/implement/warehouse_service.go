@@ -0,0 +1,203 @@
+package serviceimplement
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type WarehouseService struct {
+	warehouseRepository repository.WarehouseRepository
+	unitOfWork          repository.UnitOfWork
+}
+
+func NewWarehouseService(warehouseRepository repository.WarehouseRepository, unitOfWork repository.UnitOfWork) service.WarehouseService {
+	return &WarehouseService{
+		warehouseRepository: warehouseRepository,
+		unitOfWork:          unitOfWork,
+	}
+}
+
+// resolveWarehouse returns the given warehouse, or the default one when the document does not name one
+func resolveWarehouse(ctx context.Context, warehouseRepo repository.WarehouseRepository, warehouseID *int, tx *sqlx.Tx) (*entity.Warehouse, string) {
+	var warehouse *entity.Warehouse
+	var err error
+	if warehouseID != nil {
+		warehouse, err = warehouseRepo.GetOneByIDQuery(ctx, *warehouseID, tx)
+	} else {
+		warehouse, err = warehouseRepo.GetDefaultQuery(ctx, tx)
+	}
+	if err != nil {
+		log.Error("resolveWarehouse Error when get warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if warehouse == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	return warehouse, ""
+}
+
+func toWarehouseResponse(warehouse *entity.Warehouse) model.WarehouseResponse {
+	return model.WarehouseResponse{
+		ID:        warehouse.ID,
+		Code:      warehouse.Code,
+		Name:      warehouse.Name,
+		Address:   warehouse.Address,
+		IsDefault: warehouse.IsDefault,
+	}
+}
+
+func (s *WarehouseService) Create(ctx *gin.Context, request model.CreateWarehouseRequest) (*model.WarehouseResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("WarehouseService.Create Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("WarehouseService.Create Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	warehouse := &entity.Warehouse{
+		Name:      request.Name,
+		Address:   request.Address,
+		IsDefault: request.IsDefault,
+	}
+
+	// Save warehouse to database, the code is generated from the ID
+	err = s.warehouseRepository.CreateCommand(ctx, warehouse, tx)
+	if err != nil {
+		log.Error("WarehouseService.Create Error when create warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Only one warehouse is the default
+	if warehouse.IsDefault {
+		err = s.warehouseRepository.ClearDefaultCommand(ctx, warehouse.ID, tx)
+		if err != nil {
+			log.Error("WarehouseService.Create Error when clear default warehouse: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("WarehouseService.Create Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toWarehouseResponse(warehouse)
+	return &response, ""
+}
+
+func (s *WarehouseService) Update(ctx *gin.Context, warehouseID int, request model.UpdateWarehouseRequest) (*model.WarehouseResponse, string) {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("WarehouseService.Update Error when begin transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("WarehouseService.Update Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Check if warehouse exists
+	warehouse, err := s.warehouseRepository.GetOneByIDQuery(ctx, warehouseID, tx)
+	if err != nil {
+		log.Error("WarehouseService.Update Error when get warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if warehouse == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	// Only update fields that are given
+	if request.Name != "" {
+		warehouse.Name = request.Name
+	}
+	if request.Address != nil {
+		warehouse.Address = request.Address
+	}
+	if request.IsDefault != nil {
+		// The default moves by making another warehouse the default, it cannot be unset
+		if !*request.IsDefault && warehouse.IsDefault {
+			return nil, error_utils.ErrorCode.BAD_REQUEST
+		}
+		warehouse.IsDefault = *request.IsDefault
+	}
+
+	// Save to database
+	err = s.warehouseRepository.UpdateCommand(ctx, warehouse, tx)
+	if err != nil {
+		log.Error("WarehouseService.Update Error when update warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if warehouse.IsDefault {
+		err = s.warehouseRepository.ClearDefaultCommand(ctx, warehouse.ID, tx)
+		if err != nil {
+			log.Error("WarehouseService.Update Error when clear default warehouse: " + err.Error())
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("WarehouseService.Update Error when commit transaction: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := toWarehouseResponse(warehouse)
+	return &response, ""
+}
+
+func (s *WarehouseService) GetAll(ctx *gin.Context) (*model.GetAllWarehousesResponse, string) {
+	warehouses, err := s.warehouseRepository.GetAllQuery(ctx, nil)
+	if err != nil {
+		log.Error("WarehouseService.GetAll Error when get warehouses: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	warehouseResponses := make([]model.WarehouseResponse, len(warehouses))
+	for i := range warehouses {
+		warehouseResponses[i] = toWarehouseResponse(&warehouses[i])
+	}
+
+	return &model.GetAllWarehousesResponse{
+		Warehouses: warehouseResponses,
+	}, ""
+}
+
+func (s *WarehouseService) GetOne(ctx *gin.Context, id int) (*model.GetOneWarehouseResponse, string) {
+	warehouse, err := s.warehouseRepository.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("WarehouseService.GetOne Error when get warehouse: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	if warehouse == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	return &model.GetOneWarehouseResponse{
+		Warehouse: toWarehouseResponse(warehouse),
+	}, ""
+}
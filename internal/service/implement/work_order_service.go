@@ -0,0 +1,621 @@
+package serviceimplement
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pna/management-app-backend/internal/domain/entity"
+	"github.com/pna/management-app-backend/internal/domain/model"
+	"github.com/pna/management-app-backend/internal/repository"
+	"github.com/pna/management-app-backend/internal/service"
+	"github.com/pna/management-app-backend/internal/utils/error_utils"
+	log "github.com/sirupsen/logrus"
+)
+
+type WorkOrderService struct {
+	workOrderRepo        repository.WorkOrderRepository
+	workOrderItemRepo    repository.WorkOrderItemRepository
+	productRepo          repository.ProductRepository
+	bomRepo              repository.ProductBomRepository
+	inventoryRepo        repository.InventoryRepository
+	inventoryHistoryRepo repository.InventoryHistoryRepository
+	reservationRepo      repository.InventoryReservationRepository
+	unitRepo             repository.UnitOfMeasureRepository
+	userRepo             repository.UserRepository
+	warehouseRepo        repository.WarehouseRepository
+	inventoryLotRepo     repository.InventoryLotRepository
+	workOrderLotRepo     repository.WorkOrderLotRepository
+	categoryRepo         repository.ProductCategoryRepository
+	costLayers           *costLayerLedger
+	valuationMethod      string
+	unitOfWork           repository.UnitOfWork
+	stockRounding        stockRounding
+}
+
+func NewWorkOrderService(
+	workOrderRepo repository.WorkOrderRepository,
+	workOrderItemRepo repository.WorkOrderItemRepository,
+	productRepo repository.ProductRepository,
+	bomRepo repository.ProductBomRepository,
+	inventoryRepo repository.InventoryRepository,
+	inventoryHistoryRepo repository.InventoryHistoryRepository,
+	reservationRepo repository.InventoryReservationRepository,
+	unitRepo repository.UnitOfMeasureRepository,
+	userRepo repository.UserRepository,
+	unitOfWork repository.UnitOfWork,
+	warehouseRepo repository.WarehouseRepository,
+	inventoryLotRepo repository.InventoryLotRepository,
+	inventoryCostLayerRepo repository.InventoryCostLayerRepository,
+	inventoryCostLayerMovementRepo repository.InventoryCostLayerMovementRepository,
+	workOrderLotRepo repository.WorkOrderLotRepository,
+	categoryRepo repository.ProductCategoryRepository,
+) service.WorkOrderService {
+	return &WorkOrderService{
+		workOrderRepo:        workOrderRepo,
+		workOrderItemRepo:    workOrderItemRepo,
+		productRepo:          productRepo,
+		bomRepo:              bomRepo,
+		inventoryRepo:        inventoryRepo,
+		inventoryHistoryRepo: inventoryHistoryRepo,
+		reservationRepo:      reservationRepo,
+		unitRepo:             unitRepo,
+		userRepo:             userRepo,
+		warehouseRepo:        warehouseRepo,
+		inventoryLotRepo:     inventoryLotRepo,
+		workOrderLotRepo:     workOrderLotRepo,
+		categoryRepo:         categoryRepo,
+		costLayers:           newCostLayerLedger(inventoryCostLayerRepo, inventoryCostLayerMovementRepo),
+		valuationMethod:      valuationMethodFromEnv(),
+		unitOfWork:           unitOfWork,
+		stockRounding:        stockRoundingFromEnv(),
+	}
+}
+
+// workOrderStatusTransitions lists the statuses a work order may move to. DONE is terminal.
+var workOrderStatusTransitions = map[string]string{
+	entity.WorkOrderStatus.PLANNED:     entity.WorkOrderStatus.IN_PROGRESS,
+	entity.WorkOrderStatus.IN_PROGRESS: entity.WorkOrderStatus.DONE,
+}
+
+func (s *WorkOrderService) Create(ctx *gin.Context, request model.CreateWorkOrderRequest, userID int) (*model.WorkOrderResponse, string) {
+	product, err := s.productRepo.GetOneByIDQuery(ctx, request.ProductID, nil)
+	if err != nil {
+		log.Error("WorkOrderService.Create Error when get product: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if product == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+	if product.OperationType == "PURCHASE" {
+		return nil, error_utils.ErrorCode.INVALID_WORK_ORDER_PRODUCT
+	}
+
+	boms, err := s.bomRepo.GetEffectiveByParentProductIDQuery(ctx, product.ID, time.Now(), nil)
+	if err != nil {
+		log.Error("WorkOrderService.Create Error when get BOMs: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if len(boms) == 0 {
+		return nil, error_utils.ErrorCode.INVALID_WORK_ORDER_PRODUCT
+	}
+
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, nil)
+	if err != nil {
+		log.Error("WorkOrderService.Create Error when get user: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return nil, error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	// Production draws from and delivers to one warehouse, the default one when none is given
+	warehouse, errCode := resolveWarehouse(ctx, s.warehouseRepo, request.WarehouseID, nil)
+	if errCode != "" {
+		return nil, errCode
+	}
+
+	workOrder := &entity.WorkOrder{
+		ProductID:     product.ID,
+		WarehouseID:   warehouse.ID,
+		Quantity:      request.Quantity,
+		MultiLevel:    request.MultiLevel,
+		Status:        entity.WorkOrderStatus.PLANNED,
+		PlannedDate:   request.PlannedDate,
+		Note:          request.Note,
+		CreatedBy:     &user.ID,
+		CreatedByName: user.Username,
+	}
+
+	err = s.workOrderRepo.CreateCommand(ctx, workOrder, nil)
+	if err != nil {
+		log.Error("WorkOrderService.Create Error when create work order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response, errCode := s.GetOne(ctx, workOrder.ID)
+	if errCode != "" {
+		return nil, errCode
+	}
+	return &response.WorkOrder, ""
+}
+
+func (s *WorkOrderService) GetAll(ctx *gin.Context, productID int, status string) (*model.GetAllWorkOrdersResponse, string) {
+	workOrders, err := s.workOrderRepo.GetAllWithFiltersQuery(ctx, productID, status, nil)
+	if err != nil {
+		log.Error("WorkOrderService.GetAll Error when get work orders: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	resp := &model.GetAllWorkOrdersResponse{WorkOrders: make([]model.WorkOrderResponse, 0, len(workOrders))}
+	for i := range workOrders {
+		resp.WorkOrders = append(resp.WorkOrders, s.toWorkOrderResponse(ctx, &workOrders[i]))
+	}
+
+	return resp, ""
+}
+
+func (s *WorkOrderService) GetOne(ctx *gin.Context, id int) (*model.GetOneWorkOrderResponse, string) {
+	workOrder, err := s.workOrderRepo.GetOneByIDQuery(ctx, id, nil)
+	if err != nil {
+		log.Error("WorkOrderService.GetOne Error when get work order: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if workOrder == nil {
+		return nil, error_utils.ErrorCode.NOT_FOUND
+	}
+
+	items, err := s.workOrderItemRepo.GetAllByWorkOrderIDQuery(ctx, workOrder.ID, nil)
+	if err != nil {
+		log.Error("WorkOrderService.GetOne Error when get work order items: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+
+	response := s.toWorkOrderResponse(ctx, workOrder)
+	response.Items = make([]model.WorkOrderItemResponse, 0, len(items))
+	for _, item := range items {
+		productName := ""
+		if product, err := s.productRepo.GetOneByIDQuery(ctx, item.ProductID, nil); err == nil && product != nil {
+			productName = product.Name
+		}
+		response.Items = append(response.Items, model.WorkOrderItemResponse{
+			ID:          item.ID,
+			ProductID:   item.ProductID,
+			ProductName: productName,
+			Quantity:    item.Quantity,
+			UnitCost:    item.UnitCost,
+		})
+	}
+
+	return &model.GetOneWorkOrderResponse{WorkOrder: response}, ""
+}
+
+// UpdateStatus moves the work order forward. Starting it issues the components from stock,
+// completing it adds the produced quantity to the product's inventory.
+func (s *WorkOrderService) UpdateStatus(ctx *gin.Context, id int, request model.UpdateWorkOrderStatusRequest, userID int) string {
+	// Begin transaction
+	tx, err := s.unitOfWork.Begin(ctx)
+	if err != nil {
+		log.Error("WorkOrderService.UpdateStatus Error when begin transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Defer rollback in case of error
+	defer func() {
+		if rollbackErr := s.unitOfWork.Rollback(tx); rollbackErr != nil {
+			log.Error("WorkOrderService.UpdateStatus Error when rollback transaction: " + rollbackErr.Error())
+		}
+	}()
+
+	// Lock the work order so that its components are not issued twice
+	workOrder, err := s.workOrderRepo.GetOneByIDForUpdateQuery(ctx, id, tx)
+	if err != nil {
+		log.Error("WorkOrderService.UpdateStatus Error when get work order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if workOrder == nil {
+		return error_utils.ErrorCode.NOT_FOUND
+	}
+	if workOrderStatusTransitions[workOrder.Status] != request.Status {
+		return error_utils.ErrorCode.INVALID_WORK_ORDER_STATUS_TRANSITION
+	}
+
+	user, err := s.userRepo.FindByIDQuery(ctx, userID, tx)
+	if err != nil {
+		log.Error("WorkOrderService.UpdateStatus Error when get user: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	if user == nil {
+		return error_utils.ErrorCode.UNAUTHORIZED
+	}
+
+	now := time.Now()
+	switch request.Status {
+	case entity.WorkOrderStatus.IN_PROGRESS:
+		if errCode := s.issueComponents(ctx, workOrder, user.Username, tx); errCode != "" {
+			return errCode
+		}
+		workOrder.StartedAt = &now
+	case entity.WorkOrderStatus.DONE:
+		if errCode := s.receiveFinishedGoods(ctx, workOrder, user.Username, tx); errCode != "" {
+			return errCode
+		}
+		workOrder.CompletedAt = &now
+	}
+
+	workOrder.Status = request.Status
+	err = s.workOrderRepo.UpdateCommand(ctx, workOrder, tx)
+	if err != nil {
+		log.Error("WorkOrderService.UpdateStatus Error when update work order: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	// Commit transaction
+	err = s.unitOfWork.Commit(tx)
+	if err != nil {
+		log.Error("WorkOrderService.UpdateStatus Error when commit transaction: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+// planComponentConsumption returns the components the work order consumes per product, using the
+// BOM versions in effect when production starts. A single-level work order takes its direct BOM
+// components from stock as they are; a multi-level one draws sub-assemblies from stock first and
+// produces the shortfall from their own components.
+func (s *WorkOrderService) planComponentConsumption(ctx *gin.Context, workOrder *entity.WorkOrder, tx *sqlx.Tx) (map[int]int, string) {
+	startDate := time.Now()
+	boms, err := s.bomRepo.GetEffectiveByParentProductIDQuery(ctx, workOrder.ProductID, startDate, tx)
+	if err != nil {
+		log.Error("WorkOrderService.planComponentConsumption Error when get BOMs: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	if len(boms) == 0 {
+		return nil, error_utils.ErrorCode.INVALID_WORK_ORDER_PRODUCT
+	}
+
+	if !workOrder.MultiLevel {
+		product, err := s.productRepo.GetOneByIDQuery(ctx, workOrder.ProductID, tx)
+		if err != nil || product == nil {
+			log.Error(fmt.Sprintf("WorkOrderService.planComponentConsumption Error when get product %d: %v", workOrder.ProductID, err))
+			return nil, error_utils.ErrorCode.DB_DOWN
+		}
+
+		// Scrap and yield loss are consumed as well
+		consumption := make(map[int]float64)
+		for _, bom := range boms {
+			consumption[bom.ComponentProductID] += grossBomQuantity(bom, product) * float64(workOrder.Quantity)
+		}
+		requiredStock := make(map[int]int)
+		s.stockRounding.addRounded(requiredStock, consumption)
+		return requiredStock, ""
+	}
+
+	allocator := newStockAllocator(ctx, s.productRepo, s.bomRepo, s.inventoryRepo, s.reservationRepo, workOrder.WarehouseID, nil, startDate, tx)
+	err = allocator.allocateComponents(workOrder.ProductID, float64(workOrder.Quantity))
+	if errors.Is(err, errBomTooDeep) {
+		return nil, error_utils.ErrorCode.BOM_DEPTH_EXCEEDED
+	}
+	if err != nil {
+		log.Error("WorkOrderService.planComponentConsumption Error calculating required components: " + err.Error())
+		return nil, error_utils.ErrorCode.DB_DOWN
+	}
+	requiredStock := make(map[int]int)
+	s.stockRounding.addRounded(requiredStock, allocator.takeAllocated())
+	return requiredStock, ""
+}
+
+// issueComponents takes the components out of stock, leaving what is reserved for orders alone
+func (s *WorkOrderService) issueComponents(ctx *gin.Context, workOrder *entity.WorkOrder, importerName string, tx *sqlx.Tx) string {
+	consumption, errCode := s.planComponentConsumption(ctx, workOrder, tx)
+	if errCode != "" {
+		return errCode
+	}
+
+	productIDs := make([]int, 0, len(consumption))
+	for productID, quantity := range consumption {
+		if quantity > 0 {
+			productIDs = append(productIDs, productID)
+		}
+	}
+	sort.Ints(productIDs)
+
+	// Lock the inventories to prevent concurrent access
+	inventoryMap, errCode := lockInventories(ctx, s.inventoryRepo, productIDs, workOrder.WarehouseID, tx)
+	if errCode != "" {
+		return errCode
+	}
+	if errCode := checkStockAvailability(ctx, s.productRepo, s.unitRepo, s.reservationRepo, workOrder.WarehouseID, inventoryMap, consumption, nil, tx); errCode != "" {
+		return errCode
+	}
+
+	note := "Xuất nguyên liệu cho lệnh sản xuất: " + workOrder.Code
+	for _, productID := range productIDs {
+		quantity := consumption[productID]
+
+		err := s.inventoryRepo.UpdateQuantityCommand(ctx, productID, workOrder.WarehouseID, -quantity, uuid.New().String(), tx)
+		if err != nil {
+			log.Error(fmt.Sprintf("WorkOrderService.issueComponents Error when update inventory for product ID %d: %s", productID, err.Error()))
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		// Components are consumed from their lots FEFO, the lots are recorded so that a recall can
+		// follow them to the finished goods
+		draws, err := drawLotsFEFO(ctx, s.inventoryLotRepo, productID, workOrder.WarehouseID, inventoryMap[productID].Quantity, quantity, tx)
+		if errors.Is(err, errInsufficientUnexpiredStock) {
+			return error_utils.ErrorCode.INSUFFICIENT_UNEXPIRED_STOCK
+		}
+		if err != nil {
+			log.Error(fmt.Sprintf("WorkOrderService.issueComponents Error when update lots for product ID %d: %s", productID, err.Error()))
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		for _, draw := range draws {
+			workOrderLot := &entity.WorkOrderLot{
+				WorkOrderID:    workOrder.ID,
+				ProductID:      productID,
+				InventoryLotID: draw.lot.ID,
+				Quantity:       draw.quantity,
+			}
+			if err := s.workOrderLotRepo.CreateCommand(ctx, workOrderLot, tx); err != nil {
+				log.Error("WorkOrderService.issueComponents Error when create work order lot: " + err.Error())
+				return error_utils.ErrorCode.DB_DOWN
+			}
+		}
+
+		// They leave the oldest cost layers, which make up the cost of the finished goods
+		if _, err = s.costLayers.consume(ctx, productID, quantity, entity.InventoryCostLayerReferenceType.WORK_ORDER, &workOrder.ID, tx); err != nil {
+			log.Error(fmt.Sprintf("WorkOrderService.issueComponents Error when update cost layers for product ID %d: %s", productID, err.Error()))
+			return error_utils.ErrorCode.DB_DOWN
+		}
+
+		inventoryHistory := &entity.InventoryHistory{
+			ProductID:     productID,
+			WarehouseID:   &workOrder.WarehouseID,
+			Quantity:      -quantity,
+			FinalQuantity: inventoryMap[productID].Quantity - quantity,
+			ImporterName:  importerName,
+			ImportedAt:    time.Now(),
+			Note:          note,
+			ReferenceID:   &workOrder.ID,
+			ReferenceType: &entity.InventoryHistoryReferenceType.WORK_ORDER,
+		}
+		err = s.inventoryHistoryRepo.CreateCommand(ctx, inventoryHistory, tx)
+		if err != nil {
+			log.Error("WorkOrderService.issueComponents Error when create inventory history: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	// The components are costed by the valuation method: the cost layers they took under FIFO,
+	// the moving-average cost under AVERAGE
+	layerQuantities, layerValues, err := s.costLayers.consumedByProduct(ctx, entity.InventoryCostLayerReferenceType.WORK_ORDER, workOrder.ID, tx)
+	if err != nil {
+		log.Error("WorkOrderService.issueComponents Error when get consumed cost layers: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	for _, productID := range productIDs {
+		quantity := consumption[productID]
+		product, err := s.productRepo.GetOneByIDQuery(ctx, productID, tx)
+		if err != nil || product == nil {
+			log.Error(fmt.Sprintf("WorkOrderService.issueComponents Error when get product %d: %v", productID, err))
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		unitCost := product.Cost
+		if s.valuationMethod == entity.InventoryValuationMethod.FIFO {
+			unitCost = fifoIssueValue(quantity, layerQuantities[productID], layerValues[productID], product.Cost) / float64(quantity)
+		}
+
+		item := &entity.WorkOrderItem{
+			WorkOrderID: workOrder.ID,
+			ProductID:   productID,
+			Quantity:    quantity,
+			UnitCost:    roundCost(unitCost),
+		}
+		err = s.workOrderItemRepo.CreateCommand(ctx, item, tx)
+		if err != nil {
+			log.Error("WorkOrderService.issueComponents Error when create work order item: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+	}
+
+	return ""
+}
+
+// receiveFinishedGoods adds the produced quantity to the product's inventory in the work order's warehouse
+func (s *WorkOrderService) receiveFinishedGoods(ctx *gin.Context, workOrder *entity.WorkOrder, importerName string, tx *sqlx.Tx) string {
+	inventory, err := s.inventoryRepo.GetOneByProductIDQuery(ctx, workOrder.ProductID, workOrder.WarehouseID, tx)
+	if err != nil {
+		log.Error("WorkOrderService.receiveFinishedGoods Error when get inventory: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	var finalQuantity int
+	if inventory == nil {
+		// Create new inventory record if doesn't exist
+		newInventory := &entity.Inventory{
+			ProductID:   workOrder.ProductID,
+			WarehouseID: workOrder.WarehouseID,
+			Quantity:    workOrder.Quantity,
+			Version:     uuid.New().String(),
+		}
+		err = s.inventoryRepo.CreateCommand(ctx, newInventory, tx)
+		if err != nil {
+			log.Error("WorkOrderService.receiveFinishedGoods Error when create inventory: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		finalQuantity = workOrder.Quantity
+	} else {
+		// Get inventory with FOR UPDATE lock
+		inventory, err = s.inventoryRepo.GetOneByIDForUpdateQuery(ctx, inventory.ID, tx)
+		if err != nil {
+			log.Error("WorkOrderService.receiveFinishedGoods Error when lock inventory: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		err = s.inventoryRepo.UpdateQuantityCommand(ctx, workOrder.ProductID, workOrder.WarehouseID, workOrder.Quantity, uuid.New().String(), tx)
+		if err != nil {
+			log.Error("WorkOrderService.receiveFinishedGoods Error when update inventory: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		finalQuantity = inventory.Quantity + workOrder.Quantity
+	}
+
+	inventoryHistory := &entity.InventoryHistory{
+		ProductID:     workOrder.ProductID,
+		WarehouseID:   &workOrder.WarehouseID,
+		Quantity:      workOrder.Quantity,
+		FinalQuantity: finalQuantity,
+		ImporterName:  importerName,
+		ImportedAt:    time.Now(),
+		Note:          "Nhập thành phẩm từ lệnh sản xuất: " + workOrder.Code,
+		ReferenceID:   &workOrder.ID,
+		ReferenceType: &entity.InventoryHistoryReferenceType.WORK_ORDER,
+	}
+	err = s.inventoryHistoryRepo.CreateCommand(ctx, inventoryHistory, tx)
+	if err != nil {
+		log.Error("WorkOrderService.receiveFinishedGoods Error when create inventory history: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if errCode := s.receiveFinishedLot(ctx, workOrder, tx); errCode != "" {
+		return errCode
+	}
+
+	// The finished goods open a cost layer worth the FIFO cost of the components they consumed,
+	// at the product's cost when the work order consumed nothing of value
+	componentValue, errCode := s.componentLayerValue(ctx, workOrder, tx)
+	if errCode != "" {
+		return errCode
+	}
+	unitCost := componentValue / float64(workOrder.Quantity)
+	if componentValue <= 0 {
+		product, err := s.productRepo.GetOneByIDQuery(ctx, workOrder.ProductID, tx)
+		if err != nil {
+			log.Error("WorkOrderService.receiveFinishedGoods Error when get product: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		if product != nil {
+			unitCost = product.Cost
+		}
+	}
+	err = s.costLayers.add(ctx, &entity.InventoryCostLayer{
+		ProductID:  workOrder.ProductID,
+		SourceType: entity.InventoryCostLayerSourceType.WORK_ORDER,
+		SourceID:   &workOrder.ID,
+		UnitCost:   unitCost,
+		Quantity:   workOrder.Quantity,
+	}, tx)
+	if err != nil {
+		log.Error("WorkOrderService.receiveFinishedGoods Error when create cost layer: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	return ""
+}
+
+// componentLayerValue returns the FIFO cost of the components the work order consumed, whatever
+// the valuation method, so that the finished goods layer stays in step with the layers it came from
+func (s *WorkOrderService) componentLayerValue(ctx *gin.Context, workOrder *entity.WorkOrder, tx *sqlx.Tx) (float64, string) {
+	items, err := s.workOrderItemRepo.GetAllByWorkOrderIDQuery(ctx, workOrder.ID, tx)
+	if err != nil {
+		log.Error("WorkOrderService.componentLayerValue Error when get work order items: " + err.Error())
+		return 0, error_utils.ErrorCode.DB_DOWN
+	}
+	layerQuantities, layerValues, err := s.costLayers.consumedByProduct(ctx, entity.InventoryCostLayerReferenceType.WORK_ORDER, workOrder.ID, tx)
+	if err != nil {
+		log.Error("WorkOrderService.componentLayerValue Error when get consumed cost layers: " + err.Error())
+		return 0, error_utils.ErrorCode.DB_DOWN
+	}
+
+	var value float64
+	for _, item := range items {
+		product, err := s.productRepo.GetOneByIDQuery(ctx, item.ProductID, tx)
+		if err != nil || product == nil {
+			log.Error(fmt.Sprintf("WorkOrderService.componentLayerValue Error when get product %d: %v", item.ProductID, err))
+			return 0, error_utils.ErrorCode.DB_DOWN
+		}
+		value += fifoIssueValue(item.Quantity, layerQuantities[item.ProductID], layerValues[item.ProductID], product.Cost)
+	}
+	return value, ""
+}
+
+// receiveFinishedLot receives the finished goods into a lot numbered after the work order when
+// its components came from lots or the product is tracked by lot. The lot expires with the
+// earliest expiring component lot.
+func (s *WorkOrderService) receiveFinishedLot(ctx *gin.Context, workOrder *entity.WorkOrder, tx *sqlx.Tx) string {
+	workOrderLots, err := s.workOrderLotRepo.GetAllByWorkOrderIDQuery(ctx, workOrder.ID, tx)
+	if err != nil {
+		log.Error("WorkOrderService.receiveFinishedLot Error when get work order lots: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+
+	if len(workOrderLots) == 0 {
+		product, err := s.productRepo.GetOneByIDQuery(ctx, workOrder.ProductID, tx)
+		if err != nil || product == nil {
+			log.Error(fmt.Sprintf("WorkOrderService.receiveFinishedLot Error when get product %d: %v", workOrder.ProductID, err))
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		tracked, err := isLotTracked(ctx, s.categoryRepo, product, tx)
+		if err != nil {
+			log.Error("WorkOrderService.receiveFinishedLot Error when get category: " + err.Error())
+			return error_utils.ErrorCode.DB_DOWN
+		}
+		if !tracked {
+			return ""
+		}
+	}
+
+	componentLotIDs := make([]int, len(workOrderLots))
+	for i, workOrderLot := range workOrderLots {
+		componentLotIDs[i] = workOrderLot.InventoryLotID
+	}
+	componentLots, err := s.inventoryLotRepo.GetManyByIDsQuery(ctx, componentLotIDs, tx)
+	if err != nil {
+		log.Error("WorkOrderService.receiveFinishedLot Error when get component lots: " + err.Error())
+		return error_utils.ErrorCode.DB_DOWN
+	}
+	var expiryDate *time.Time
+	for _, lot := range componentLots {
+		if lot.ExpiryDate != nil && (expiryDate == nil || lot.ExpiryDate.Before(*expiryDate)) {
+			expiryDate = lot.ExpiryDate
+		}
+	}
+
+	lot, errCode := receiveLot(ctx, s.inventoryLotRepo, workOrder.ProductID, workOrder.WarehouseID, workOrder.Code, expiryDate, workOrder.Quantity, tx)
+	if errCode != "" {
+		return errCode
+	}
+	workOrder.FinishedLotID = &lot.ID
+	return ""
+}
+
+func (s *WorkOrderService) toWorkOrderResponse(ctx *gin.Context, workOrder *entity.WorkOrder) model.WorkOrderResponse {
+	productName := ""
+	if product, err := s.productRepo.GetOneByIDQuery(ctx, workOrder.ProductID, nil); err == nil && product != nil {
+		productName = product.Name
+	}
+
+	return model.WorkOrderResponse{
+		ID:            workOrder.ID,
+		Code:          workOrder.Code,
+		ProductID:     workOrder.ProductID,
+		ProductName:   productName,
+		WarehouseID:   workOrder.WarehouseID,
+		Quantity:      workOrder.Quantity,
+		MultiLevel:    workOrder.MultiLevel,
+		Status:        workOrder.Status,
+		PlannedDate:   workOrder.PlannedDate,
+		Note:          workOrder.Note,
+		StartedAt:     workOrder.StartedAt,
+		CompletedAt:   workOrder.CompletedAt,
+		FinishedLotID: workOrder.FinishedLotID,
+		CreatedBy:     workOrder.CreatedBy,
+		CreatedByName: workOrder.CreatedByName,
+		CreatedAt:     workOrder.CreatedAt,
+		UpdatedAt:     workOrder.UpdatedAt,
+	}
+}
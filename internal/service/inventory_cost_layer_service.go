@@ -0,0 +1,10 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type InventoryCostLayerService interface {
+	GetValuation(ctx *gin.Context, productID int) (*model.GetInventoryValuationResponse, string)
+}
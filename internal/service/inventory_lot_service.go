@@ -0,0 +1,11 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type InventoryLotService interface {
+	GetAll(ctx *gin.Context, productID int, warehouseID int) (*model.GetAllInventoryLotsResponse, string)
+	WriteOff(ctx *gin.Context, lotID int, request model.WriteOffInventoryLotRequest, userID int) (*model.InventoryLotResponse, string)
+}
@@ -8,7 +8,7 @@ import (
 )
 
 type InventoryService interface {
-	GetAll(ctx context.Context) (*model.GetAllInventoryResponse, string)
-	GetByProductID(ctx *gin.Context, productID int) (*model.InventoryResponse, string)
+	GetAll(ctx context.Context, warehouseID *int) (*model.GetAllInventoryResponse, string)
+	GetByProductID(ctx *gin.Context, productID int, warehouseID *int) (*model.InventoryResponse, string)
 	UpdateQuantity(ctx *gin.Context, productID int, request model.UpdateInventoryQuantityRequest) (*model.InventoryResponse, string)
 }
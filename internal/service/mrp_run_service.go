@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type MrpRunService interface {
+	Create(ctx *gin.Context, request model.CreateMrpRunRequest, userID int) (*model.MrpRunResponse, string)
+	GetAll(ctx *gin.Context) (*model.GetAllMrpRunsResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.GetOneMrpRunResponse, string)
+	ExportCSV(ctx *gin.Context, id int) ([]byte, string, string)
+	Compare(ctx *gin.Context, fromRunID int, toRunID int) (*model.MrpRunComparisonResponse, string)
+}
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type OrderDocumentService interface {
+	GenerateInvoicePDF(ctx *gin.Context, orderID int) ([]byte, string, string)
+	GenerateDeliveryNotePDF(ctx *gin.Context, orderID int) ([]byte, string, string)
+}
@@ -5,12 +5,17 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
 	"github.com/pna/management-app-backend/internal/domain/model"
 )
 
 type OrderService interface {
 	CreateOrder(ctx *gin.Context, orderRequest model.CreateOrderRequest, userId int) (*model.OrderResponse, string)
+	CreateOrderTx(ctx *gin.Context, orderRequest model.CreateOrderRequest, userId int, tx *sqlx.Tx) (*model.OrderResponse, string)
 	GetOneOrder(ctx *gin.Context, orderID int) (model.GetOneOrderResponse, string)
-	Update(ctx context.Context, req model.UpdateOrderRequest) string
+	Update(ctx *gin.Context, req model.UpdateOrderRequest, userID int) string
+	UpdateItems(ctx *gin.Context, orderID int, request model.UpdateOrderItemsRequest, userID int) string
+	CancelOrder(ctx *gin.Context, orderID int, request model.CancelOrderRequest, userID int) string
+	GetStatusHistories(ctx *gin.Context, orderID int) (*model.GetOrderStatusHistoriesResponse, string)
 	GetAll(ctx context.Context, userID int, customerID int, sortBy string, fromDate *time.Time, toDate *time.Time) (model.GetAllOrdersResponse, string)
 }
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type PaymentService interface {
+	Create(ctx *gin.Context, orderID int, request model.CreatePaymentRequest, userID int) (*model.PaymentResponse, string)
+	GetAllByOrderID(ctx *gin.Context, orderID int) (*model.GetOrderPaymentsResponse, string)
+}
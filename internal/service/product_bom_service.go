@@ -1,6 +1,8 @@
 package service
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/pna/management-app-backend/internal/domain/model"
 )
@@ -13,4 +15,10 @@ type ProductBomService interface {
 	GetByComponentProductID(ctx *gin.Context, componentProductID int) (*model.GetAllProductBomsResponse, string)
 	DeleteByParentProductID(ctx *gin.Context, parentProductID int) string
 	CalculateMaterialRequirements(ctx *gin.Context, request model.CalculateMaterialRequirementsRequest) (*model.MaterialRequirementsResponse, string)
+	GetVersions(ctx *gin.Context, parentProductID int) (*model.GetBomVersionsResponse, string)
+	GetVersion(ctx *gin.Context, versionID int) (*model.GetOneProductBomResponse, string)
+	UpdateVersionStatus(ctx *gin.Context, versionID int, request model.UpdateBomVersionStatusRequest) (*model.BomVersionResponse, string)
+	GetTree(ctx *gin.Context, parentProductID int, quantity int, date time.Time) (*model.BomTreeResponse, string)
+	DiffVersions(ctx *gin.Context, fromVersionID int, toVersionID int) (*model.BomVersionDiffResponse, string)
+	GetWhereUsed(ctx *gin.Context, componentProductID int, date time.Time) (*model.WhereUsedResponse, string)
 }
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type PurchaseOrderService interface {
+	Create(ctx *gin.Context, request model.CreatePurchaseOrderRequest, userID int) (*model.PurchaseOrderResponse, string)
+	Update(ctx *gin.Context, id int, request model.UpdatePurchaseOrderRequest) string
+	UpdateStatus(ctx *gin.Context, id int, request model.UpdatePurchaseOrderStatusRequest) string
+	GetAll(ctx *gin.Context, supplierID int, status string) (*model.GetAllPurchaseOrdersResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.GetOnePurchaseOrderResponse, string)
+}
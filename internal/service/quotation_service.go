@@ -0,0 +1,15 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type QuotationService interface {
+	Create(ctx *gin.Context, request model.CreateQuotationRequest, userID int) (*model.QuotationResponse, string)
+	Update(ctx *gin.Context, id int, request model.UpdateQuotationRequest) string
+	UpdateStatus(ctx *gin.Context, id int, request model.UpdateQuotationStatusRequest) string
+	GetAll(ctx *gin.Context, customerID int, status string) (*model.GetAllQuotationsResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.GetOneQuotationResponse, string)
+	Convert(ctx *gin.Context, id int, request model.ConvertQuotationRequest, userID int) (*model.OrderResponse, string)
+}
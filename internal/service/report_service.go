@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type ReportService interface {
+	GetReceivablesAging(ctx context.Context, asOfDate time.Time) (*model.GetReceivablesAgingResponse, string)
+	GetCustomerStatement(ctx context.Context, customerID int, fromDate *time.Time, toDate *time.Time) (*model.GetCustomerStatementResponse, string)
+	GetLotRecall(ctx context.Context, lotNumber string, productID int) (*model.GetLotRecallResponse, string)
+	GetStockValuation(ctx context.Context, asOfDate time.Time) (*model.GetStockValuationResponse, string)
+}
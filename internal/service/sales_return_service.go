@@ -0,0 +1,12 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type SalesReturnService interface {
+	Create(ctx *gin.Context, orderID int, request model.CreateSalesReturnRequest, userID int) (*model.SalesReturnResponse, string)
+	GetAllByOrderID(ctx *gin.Context, orderID int) (*model.GetAllSalesReturnsResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.GetOneSalesReturnResponse, string)
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type StockTransferService interface {
+	Create(ctx *gin.Context, request model.CreateStockTransferRequest, userID int) (*model.StockTransferResponse, string)
+	GetAll(ctx *gin.Context, warehouseID int) (*model.GetAllStockTransfersResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.GetOneStockTransferResponse, string)
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type UnitConversionService interface {
+	Create(ctx *gin.Context, request model.CreateUnitConversionRequest) (*model.UnitConversionResponse, string)
+	Update(ctx *gin.Context, id int, request model.UpdateUnitConversionRequest) (*model.UnitConversionResponse, string)
+	Delete(ctx *gin.Context, id int) string
+	GetAll(ctx *gin.Context, productID int) (*model.GetAllUnitConversionsResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.GetOneUnitConversionResponse, string)
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pna/management-app-backend/internal/domain/model"
+)
+
+type WorkOrderService interface {
+	Create(ctx *gin.Context, request model.CreateWorkOrderRequest, userID int) (*model.WorkOrderResponse, string)
+	GetAll(ctx *gin.Context, productID int, status string) (*model.GetAllWorkOrdersResponse, string)
+	GetOne(ctx *gin.Context, id int) (*model.GetOneWorkOrderResponse, string)
+	UpdateStatus(ctx *gin.Context, id int, request model.UpdateWorkOrderStatusRequest, userID int) string
+}
@@ -53,32 +53,114 @@ type errorCode struct {
 	DB_DOWN string
 
 	// auth related
-	FORBIDDEN                   string
-	INTERNAL_SERVER_ERROR       string
-	BAD_REQUEST                 string
-	ACCESS_TOKEN_INVALID        string
-	USERNAME_NOT_FOUND          string
-	UNAUTHORIZED                string
-	INVENTORY_VERSION_MISMATCH  string
-	INVENTORY_QUANTITY_NEGATIVE string
-	INVENTORY_QUANTITY_EXCEEDED string
-	DUPLICATE_ORDER_ITEMS       string
+	FORBIDDEN                                string
+	INTERNAL_SERVER_ERROR                    string
+	BAD_REQUEST                              string
+	ACCESS_TOKEN_INVALID                     string
+	USERNAME_NOT_FOUND                       string
+	UNAUTHORIZED                             string
+	INVENTORY_VERSION_MISMATCH               string
+	INVENTORY_QUANTITY_NEGATIVE              string
+	INVENTORY_QUANTITY_EXCEEDED              string
+	DUPLICATE_ORDER_ITEMS                    string
+	ORDER_ALREADY_CANCELLED                  string
+	INVALID_ORDER_STATUS_TRANSITION          string
+	PAYMENT_EXCEEDS_OUTSTANDING              string
+	SALES_RETURN_NOT_ALLOWED                 string
+	RETURN_QUANTITY_EXCEEDED                 string
+	ORDER_HAS_SALES_RETURNS                  string
+	QUOTATION_NOT_EDITABLE                   string
+	INVALID_QUOTATION_STATUS_TRANSITION      string
+	QUOTATION_EXPIRED                        string
+	QUOTATION_ALREADY_CONVERTED              string
+	IDEMPOTENCY_KEY_REUSED                   string
+	IDEMPOTENCY_REQUEST_IN_PROGRESS          string
+	INVALID_WORK_ORDER_PRODUCT               string
+	INVALID_WORK_ORDER_STATUS_TRANSITION     string
+	BOM_CYCLE_DETECTED                       string
+	BOM_DEPTH_EXCEEDED                       string
+	UNIT_CONVERSION_NOT_FOUND                string
+	INVALID_UNIT_QUANTITY                    string
+	UNIT_CONVERSION_ALREADY_EXISTS           string
+	BOM_VERSION_NOT_FOUND                    string
+	BOM_VERSION_MISMATCH                     string
+	BOM_VERSION_CONFLICT                     string
+	MRP_RUN_NOT_FOUND                        string
+	MRP_DEMAND_REQUIRED                      string
+	PURCHASE_ORDER_NOT_EDITABLE              string
+	INVALID_PURCHASE_ORDER_STATUS_TRANSITION string
+	PURCHASE_ORDER_NOT_RECEIVABLE            string
+	PURCHASE_ORDER_ITEM_MISMATCH             string
+	PURCHASE_ORDER_OVER_RECEIPT              string
+	STOCK_TRANSFER_SAME_WAREHOUSE            string
+	LOT_REQUIRED                             string
+	LOT_EXPIRY_MISMATCH                      string
+	INSUFFICIENT_UNEXPIRED_STOCK             string
+	LOT_QUANTITY_EXCEEDED                    string
+	QUOTATION_NOT_SENT                       string
+	QUOTATION_NOT_YET_VALID                  string
+	BOM_GRAPH_BUSY                           string
+	BOM_VERSION_DATE_TAKEN                   string
+	ORDER_HAS_PAYMENTS                       string
+	ORDER_ALREADY_DELIVERED                  string
+	ORDER_AMOUNT_BELOW_PAID                  string
 
 	// generic
 	NOT_FOUND string
 }
 
 var ErrorCode = errorCode{
-	DB_DOWN:                     "DB_DOWN",
-	FORBIDDEN:                   "FORBIDDEN",
-	BAD_REQUEST:                 "BAD_REQUEST",
-	INTERNAL_SERVER_ERROR:       "INTERNAL_SERVER_ERROR",
-	ACCESS_TOKEN_INVALID:        "ACCESS_TOKEN_INVALID",
-	USERNAME_NOT_FOUND:          "USER_NOT_FOUND",
-	UNAUTHORIZED:                "UNAUTHORIZED",
-	NOT_FOUND:                   "NOT_FOUND",
-	INVENTORY_VERSION_MISMATCH:  "INVENTORY_VERSION_MISMATCH",
-	INVENTORY_QUANTITY_NEGATIVE: "INVENTORY_QUANTITY_NEGATIVE",
-	INVENTORY_QUANTITY_EXCEEDED: "INVENTORY_QUANTITY_EXCEEDED",
-	DUPLICATE_ORDER_ITEMS:       "DUPLICATE_ORDER_ITEMS",
+	DB_DOWN:                                  "DB_DOWN",
+	FORBIDDEN:                                "FORBIDDEN",
+	BAD_REQUEST:                              "BAD_REQUEST",
+	INTERNAL_SERVER_ERROR:                    "INTERNAL_SERVER_ERROR",
+	ACCESS_TOKEN_INVALID:                     "ACCESS_TOKEN_INVALID",
+	USERNAME_NOT_FOUND:                       "USER_NOT_FOUND",
+	UNAUTHORIZED:                             "UNAUTHORIZED",
+	NOT_FOUND:                                "NOT_FOUND",
+	INVENTORY_VERSION_MISMATCH:               "INVENTORY_VERSION_MISMATCH",
+	INVENTORY_QUANTITY_NEGATIVE:              "INVENTORY_QUANTITY_NEGATIVE",
+	INVENTORY_QUANTITY_EXCEEDED:              "INVENTORY_QUANTITY_EXCEEDED",
+	DUPLICATE_ORDER_ITEMS:                    "DUPLICATE_ORDER_ITEMS",
+	ORDER_ALREADY_CANCELLED:                  "ORDER_ALREADY_CANCELLED",
+	INVALID_ORDER_STATUS_TRANSITION:          "INVALID_ORDER_STATUS_TRANSITION",
+	PAYMENT_EXCEEDS_OUTSTANDING:              "PAYMENT_EXCEEDS_OUTSTANDING",
+	SALES_RETURN_NOT_ALLOWED:                 "SALES_RETURN_NOT_ALLOWED",
+	RETURN_QUANTITY_EXCEEDED:                 "RETURN_QUANTITY_EXCEEDED",
+	ORDER_HAS_SALES_RETURNS:                  "ORDER_HAS_SALES_RETURNS",
+	QUOTATION_NOT_EDITABLE:                   "QUOTATION_NOT_EDITABLE",
+	INVALID_QUOTATION_STATUS_TRANSITION:      "INVALID_QUOTATION_STATUS_TRANSITION",
+	QUOTATION_EXPIRED:                        "QUOTATION_EXPIRED",
+	QUOTATION_ALREADY_CONVERTED:              "QUOTATION_ALREADY_CONVERTED",
+	IDEMPOTENCY_KEY_REUSED:                   "IDEMPOTENCY_KEY_REUSED",
+	IDEMPOTENCY_REQUEST_IN_PROGRESS:          "IDEMPOTENCY_REQUEST_IN_PROGRESS",
+	INVALID_WORK_ORDER_PRODUCT:               "INVALID_WORK_ORDER_PRODUCT",
+	INVALID_WORK_ORDER_STATUS_TRANSITION:     "INVALID_WORK_ORDER_STATUS_TRANSITION",
+	BOM_CYCLE_DETECTED:                       "BOM_CYCLE_DETECTED",
+	BOM_DEPTH_EXCEEDED:                       "BOM_DEPTH_EXCEEDED",
+	UNIT_CONVERSION_NOT_FOUND:                "UNIT_CONVERSION_NOT_FOUND",
+	INVALID_UNIT_QUANTITY:                    "INVALID_UNIT_QUANTITY",
+	UNIT_CONVERSION_ALREADY_EXISTS:           "UNIT_CONVERSION_ALREADY_EXISTS",
+	BOM_VERSION_NOT_FOUND:                    "BOM_VERSION_NOT_FOUND",
+	BOM_VERSION_MISMATCH:                     "BOM_VERSION_MISMATCH",
+	BOM_VERSION_CONFLICT:                     "BOM_VERSION_CONFLICT",
+	MRP_RUN_NOT_FOUND:                        "MRP_RUN_NOT_FOUND",
+	MRP_DEMAND_REQUIRED:                      "MRP_DEMAND_REQUIRED",
+	PURCHASE_ORDER_NOT_EDITABLE:              "PURCHASE_ORDER_NOT_EDITABLE",
+	INVALID_PURCHASE_ORDER_STATUS_TRANSITION: "INVALID_PURCHASE_ORDER_STATUS_TRANSITION",
+	PURCHASE_ORDER_NOT_RECEIVABLE:            "PURCHASE_ORDER_NOT_RECEIVABLE",
+	PURCHASE_ORDER_ITEM_MISMATCH:             "PURCHASE_ORDER_ITEM_MISMATCH",
+	PURCHASE_ORDER_OVER_RECEIPT:              "PURCHASE_ORDER_OVER_RECEIPT",
+	STOCK_TRANSFER_SAME_WAREHOUSE:            "STOCK_TRANSFER_SAME_WAREHOUSE",
+	LOT_REQUIRED:                             "LOT_REQUIRED",
+	LOT_EXPIRY_MISMATCH:                      "LOT_EXPIRY_MISMATCH",
+	INSUFFICIENT_UNEXPIRED_STOCK:             "INSUFFICIENT_UNEXPIRED_STOCK",
+	LOT_QUANTITY_EXCEEDED:                    "LOT_QUANTITY_EXCEEDED",
+	QUOTATION_NOT_SENT:                       "QUOTATION_NOT_SENT",
+	QUOTATION_NOT_YET_VALID:                  "QUOTATION_NOT_YET_VALID",
+	BOM_GRAPH_BUSY:                           "BOM_GRAPH_BUSY",
+	BOM_VERSION_DATE_TAKEN:                   "BOM_VERSION_DATE_TAKEN",
+	ORDER_HAS_PAYMENTS:                       "ORDER_HAS_PAYMENTS",
+	ORDER_ALREADY_DELIVERED:                  "ORDER_ALREADY_DELIVERED",
+	ORDER_AMOUNT_BELOW_PAID:                  "ORDER_AMOUNT_BELOW_PAID",
 }
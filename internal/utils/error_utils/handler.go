@@ -50,6 +50,293 @@ func ErrorCodeToHttpResponse(errCode string, field string) (statusCode int, http
 			Field:   field,
 			Code:    ErrorCode.DUPLICATE_ORDER_ITEMS,
 		})
+	case ErrorCode.ORDER_ALREADY_CANCELLED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Order has already been cancelled",
+			Field:   field,
+			Code:    ErrorCode.ORDER_ALREADY_CANCELLED,
+		})
+	case ErrorCode.INVALID_ORDER_STATUS_TRANSITION:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Order cannot move from its current status to the requested status",
+			Field:   field,
+			Code:    ErrorCode.INVALID_ORDER_STATUS_TRANSITION,
+		})
+	case ErrorCode.PAYMENT_EXCEEDS_OUTSTANDING:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Payment amount exceeds the outstanding balance of the order",
+			Field:   field,
+			Code:    ErrorCode.PAYMENT_EXCEEDS_OUTSTANDING,
+		})
+	case ErrorCode.SALES_RETURN_NOT_ALLOWED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Returns can only be recorded for orders that have been delivered",
+			Field:   field,
+			Code:    ErrorCode.SALES_RETURN_NOT_ALLOWED,
+		})
+	case ErrorCode.RETURN_QUANTITY_EXCEEDED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Returned quantity exceeds the quantity left to return on the order item",
+			Field:   field,
+			Code:    ErrorCode.RETURN_QUANTITY_EXCEEDED,
+		})
+	case ErrorCode.ORDER_HAS_SALES_RETURNS:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Order has sales returns and can no longer be changed",
+			Field:   field,
+			Code:    ErrorCode.ORDER_HAS_SALES_RETURNS,
+		})
+	case ErrorCode.QUOTATION_NOT_EDITABLE:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Only draft or sent quotations can be edited",
+			Field:   field,
+			Code:    ErrorCode.QUOTATION_NOT_EDITABLE,
+		})
+	case ErrorCode.INVALID_QUOTATION_STATUS_TRANSITION:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Quotation cannot move from its current status to the requested status",
+			Field:   field,
+			Code:    ErrorCode.INVALID_QUOTATION_STATUS_TRANSITION,
+		})
+	case ErrorCode.QUOTATION_EXPIRED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Quotation has expired",
+			Field:   field,
+			Code:    ErrorCode.QUOTATION_EXPIRED,
+		})
+	case ErrorCode.QUOTATION_ALREADY_CONVERTED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Quotation has already been converted into an order",
+			Field:   field,
+			Code:    ErrorCode.QUOTATION_ALREADY_CONVERTED,
+		})
+	case ErrorCode.IDEMPOTENCY_KEY_REUSED:
+		statusCode = http.StatusUnprocessableEntity
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Idempotency-Key was already used with a different request",
+			Field:   field,
+			Code:    ErrorCode.IDEMPOTENCY_KEY_REUSED,
+		})
+	case ErrorCode.IDEMPOTENCY_REQUEST_IN_PROGRESS:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "A request with this Idempotency-Key is still being processed",
+			Field:   field,
+			Code:    ErrorCode.IDEMPOTENCY_REQUEST_IN_PROGRESS,
+		})
+	case ErrorCode.INVALID_WORK_ORDER_PRODUCT:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Only manufactured or packaged products with a BOM can be produced",
+			Field:   field,
+			Code:    ErrorCode.INVALID_WORK_ORDER_PRODUCT,
+		})
+	case ErrorCode.INVALID_WORK_ORDER_STATUS_TRANSITION:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Work order cannot move from its current status to the requested status",
+			Field:   field,
+			Code:    ErrorCode.INVALID_WORK_ORDER_STATUS_TRANSITION,
+		})
+	case ErrorCode.BOM_CYCLE_DETECTED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "BOM would make a product contain itself",
+			Field:   field,
+			Code:    ErrorCode.BOM_CYCLE_DETECTED,
+		})
+	case ErrorCode.BOM_DEPTH_EXCEEDED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "BOM structure exceeds the maximum number of levels",
+			Field:   field,
+			Code:    ErrorCode.BOM_DEPTH_EXCEEDED,
+		})
+	case ErrorCode.UNIT_CONVERSION_NOT_FOUND:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "No conversion from the given unit to the product's base unit",
+			Field:   field,
+			Code:    ErrorCode.UNIT_CONVERSION_NOT_FOUND,
+		})
+	case ErrorCode.INVALID_UNIT_QUANTITY:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Quantity does not convert to a whole number of the product's base unit",
+			Field:   field,
+			Code:    ErrorCode.INVALID_UNIT_QUANTITY,
+		})
+	case ErrorCode.UNIT_CONVERSION_ALREADY_EXISTS:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "A conversion between these units already exists",
+			Field:   field,
+			Code:    ErrorCode.UNIT_CONVERSION_ALREADY_EXISTS,
+		})
+	case ErrorCode.BOM_VERSION_NOT_FOUND:
+		statusCode = http.StatusNotFound
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "BOM version not found",
+			Field:   field,
+			Code:    ErrorCode.BOM_VERSION_NOT_FOUND,
+		})
+	case ErrorCode.BOM_VERSION_MISMATCH:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "BOM versions belong to different products",
+			Field:   field,
+			Code:    ErrorCode.BOM_VERSION_MISMATCH,
+		})
+	case ErrorCode.BOM_VERSION_CONFLICT:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "BOM was changed at the same time, please try again",
+			Field:   field,
+			Code:    ErrorCode.BOM_VERSION_CONFLICT,
+		})
+	case ErrorCode.MRP_RUN_NOT_FOUND:
+		statusCode = http.StatusNotFound
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "MRP run not found",
+			Field:   field,
+			Code:    ErrorCode.MRP_RUN_NOT_FOUND,
+		})
+	case ErrorCode.MRP_DEMAND_REQUIRED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Include open orders or list at least one demand",
+			Field:   field,
+			Code:    ErrorCode.MRP_DEMAND_REQUIRED,
+		})
+	case ErrorCode.PURCHASE_ORDER_NOT_EDITABLE:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Only draft purchase orders can be edited",
+			Field:   field,
+			Code:    ErrorCode.PURCHASE_ORDER_NOT_EDITABLE,
+		})
+	case ErrorCode.INVALID_PURCHASE_ORDER_STATUS_TRANSITION:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Purchase order cannot move from its current status to the requested status",
+			Field:   field,
+			Code:    ErrorCode.INVALID_PURCHASE_ORDER_STATUS_TRANSITION,
+		})
+	case ErrorCode.PURCHASE_ORDER_NOT_RECEIVABLE:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Purchase order is not open for receiving",
+			Field:   field,
+			Code:    ErrorCode.PURCHASE_ORDER_NOT_RECEIVABLE,
+		})
+	case ErrorCode.PURCHASE_ORDER_ITEM_MISMATCH:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Receipt item does not match the purchase order line",
+			Field:   field,
+			Code:    ErrorCode.PURCHASE_ORDER_ITEM_MISMATCH,
+		})
+	case ErrorCode.PURCHASE_ORDER_OVER_RECEIPT:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Received quantity exceeds the quantity still open on the purchase order line",
+			Field:   field,
+			Code:    ErrorCode.PURCHASE_ORDER_OVER_RECEIPT,
+		})
+	case ErrorCode.STOCK_TRANSFER_SAME_WAREHOUSE:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Source and destination warehouses must be different",
+			Field:   field,
+			Code:    ErrorCode.STOCK_TRANSFER_SAME_WAREHOUSE,
+		})
+	case ErrorCode.LOT_REQUIRED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Lot number and expiry date are required for chemical products",
+			Field:   field,
+			Code:    ErrorCode.LOT_REQUIRED,
+		})
+	case ErrorCode.LOT_EXPIRY_MISMATCH:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Lot already exists with a different expiry date",
+			Field:   field,
+			Code:    ErrorCode.LOT_EXPIRY_MISMATCH,
+		})
+	case ErrorCode.INSUFFICIENT_UNEXPIRED_STOCK:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Stock that has not expired cannot cover the requested quantity",
+			Field:   field,
+			Code:    ErrorCode.INSUFFICIENT_UNEXPIRED_STOCK,
+		})
+	case ErrorCode.LOT_QUANTITY_EXCEEDED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Quantity exceeds what is left in the lot",
+			Field:   field,
+			Code:    ErrorCode.LOT_QUANTITY_EXCEEDED,
+		})
+	case ErrorCode.QUOTATION_NOT_SENT:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "The quotation has not been sent to the customer yet",
+			Field:   field,
+			Code:    ErrorCode.QUOTATION_NOT_SENT,
+		})
+	case ErrorCode.QUOTATION_NOT_YET_VALID:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "The quotation is not valid yet",
+			Field:   field,
+			Code:    ErrorCode.QUOTATION_NOT_YET_VALID,
+		})
+	case ErrorCode.BOM_GRAPH_BUSY:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Another BOM change is in progress, please retry",
+			Field:   field,
+			Code:    ErrorCode.BOM_GRAPH_BUSY,
+		})
+	case ErrorCode.BOM_VERSION_DATE_TAKEN:
+		statusCode = http.StatusConflict
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Another active BOM version of this product takes effect at the same time",
+			Field:   field,
+			Code:    ErrorCode.BOM_VERSION_DATE_TAKEN,
+		})
+	case ErrorCode.ORDER_HAS_PAYMENTS:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Order has recorded payments and cannot be cancelled",
+			Field:   field,
+			Code:    ErrorCode.ORDER_HAS_PAYMENTS,
+		})
+	case ErrorCode.ORDER_ALREADY_DELIVERED:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Order has been delivered, return the goods through a sales return instead of cancelling",
+			Field:   field,
+			Code:    ErrorCode.ORDER_ALREADY_DELIVERED,
+		})
+	case ErrorCode.ORDER_AMOUNT_BELOW_PAID:
+		statusCode = http.StatusBadRequest
+		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
+			Message: "Order total cannot be lower than the amount already paid",
+			Field:   field,
+			Code:    ErrorCode.ORDER_AMOUNT_BELOW_PAID,
+		})
 	case ErrorCode.FORBIDDEN:
 		statusCode = http.StatusForbidden
 		httpErrResponse = httpcommon.NewErrorResponse(httpcommon.Error{
@@ -40,6 +40,21 @@ var handlerSet = wire.NewSet(
 	v1.NewInventoryReceiptHandler,
 	v1.NewProductImageHandler,
 	v1.NewOrderHandler,
+	v1.NewPaymentHandler,
+	v1.NewReportHandler,
+	v1.NewSalesReturnHandler,
+	v1.NewOrderDocumentHandler,
+	v1.NewQuotationHandler,
+	v1.NewWorkOrderHandler,
+	v1.NewUnitConversionHandler,
+	v1.NewMrpRunHandler,
+	v1.NewSupplierHandler,
+	v1.NewPurchaseOrderHandler,
+	v1.NewWarehouseHandler,
+	v1.NewStockTransferHandler,
+	v1.NewInventoryLotHandler,
+	v1.NewProductCostHistoryHandler,
+	v1.NewInventoryCostLayerHandler,
 )
 
 var serviceSet = wire.NewSet(
@@ -57,6 +72,22 @@ var serviceSet = wire.NewSet(
 	serviceimplement.NewInventoryReceiptService,
 	serviceimplement.NewOrderService,
 	serviceimplement.NewOrderImageService,
+	serviceimplement.NewPaymentService,
+	serviceimplement.NewReportService,
+	serviceimplement.NewSalesReturnService,
+	serviceimplement.NewOrderDocumentService,
+	serviceimplement.NewQuotationService,
+	serviceimplement.NewIdempotencyService,
+	serviceimplement.NewWorkOrderService,
+	serviceimplement.NewUnitConversionService,
+	serviceimplement.NewMrpRunService,
+	serviceimplement.NewSupplierService,
+	serviceimplement.NewPurchaseOrderService,
+	serviceimplement.NewWarehouseService,
+	serviceimplement.NewStockTransferService,
+	serviceimplement.NewInventoryLotService,
+	serviceimplement.NewProductCostHistoryService,
+	serviceimplement.NewInventoryCostLayerService,
 )
 
 var repositorySet = wire.NewSet(
@@ -76,10 +107,38 @@ var repositorySet = wire.NewSet(
 	repositoryimplement.NewOrderRepository,
 	repositoryimplement.NewOrderItemRepository,
 	repositoryimplement.NewOrderImageRepository,
+	repositoryimplement.NewOrderStatusHistoryRepository,
+	repositoryimplement.NewPaymentRepository,
+	repositoryimplement.NewSalesReturnRepository,
+	repositoryimplement.NewSalesReturnItemRepository,
+	repositoryimplement.NewQuotationRepository,
+	repositoryimplement.NewQuotationItemRepository,
+	repositoryimplement.NewInventoryReservationRepository,
+	repositoryimplement.NewIdempotencyKeyRepository,
+	repositoryimplement.NewWorkOrderRepository,
+	repositoryimplement.NewWorkOrderItemRepository,
+	repositoryimplement.NewUnitConversionRepository,
+	repositoryimplement.NewProductBomVersionRepository,
+	repositoryimplement.NewMrpRunRepository,
+	repositoryimplement.NewMrpRunItemRepository,
+	repositoryimplement.NewMrpRunDemandRepository,
+	repositoryimplement.NewSupplierRepository,
+	repositoryimplement.NewPurchaseOrderRepository,
+	repositoryimplement.NewPurchaseOrderItemRepository,
+	repositoryimplement.NewWarehouseRepository,
+	repositoryimplement.NewStockTransferRepository,
+	repositoryimplement.NewStockTransferItemRepository,
+	repositoryimplement.NewInventoryLotRepository,
+	repositoryimplement.NewOrderItemLotRepository,
+	repositoryimplement.NewProductCostHistoryRepository,
+	repositoryimplement.NewInventoryCostLayerRepository,
+	repositoryimplement.NewInventoryCostLayerMovementRepository,
+	repositoryimplement.NewWorkOrderLotRepository,
 )
 
 var middlewareSet = wire.NewSet(
 	middleware.NewAuthMiddleware,
+	middleware.NewIdempotencyMiddleware,
 )
 
 var beanSet = wire.NewSet(
@@ -27,6 +27,9 @@ func InitializeContainer(db database.Db) *controller.ApiContainer {
 	helloWorldService := serviceimplement.NewHelloWorldService(helloWorldRepository, passwordEncoder)
 	helloWorldHandler := v1.NewHelloWorldHandler(helloWorldService)
 	authMiddleware := middleware.NewAuthMiddleware()
+	idempotencyKeyRepository := repositoryimplement.NewIdempotencyKeyRepository(db)
+	idempotencyService := serviceimplement.NewIdempotencyService(idempotencyKeyRepository)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyService)
 	userRepository := repositoryimplement.NewUserRepository(db)
 	userService := serviceimplement.NewUserService(userRepository, passwordEncoder)
 	userHandler := v1.NewUserHandler(userService)
@@ -38,22 +41,35 @@ func InitializeContainer(db database.Db) *controller.ApiContainer {
 	unitOfWork := repositoryimplement.NewUnitOfWork(db)
 	productImageRepository := repositoryimplement.NewProductImageRepository(db)
 	s3Service := beanimplement.NewS3Service()
-	productService := serviceimplement.NewProductService(productRepository, inventoryRepository, productCategoryRepository, unitOfMeasureRepository, productBomRepository, unitOfWork, productImageRepository, s3Service)
+	warehouseRepository := repositoryimplement.NewWarehouseRepository(db)
+	productCostHistoryRepository := repositoryimplement.NewProductCostHistoryRepository(db)
+	productService := serviceimplement.NewProductService(productRepository, inventoryRepository, productCategoryRepository, unitOfMeasureRepository, productBomRepository, unitOfWork, productImageRepository, s3Service, warehouseRepository, productCostHistoryRepository)
 	productHandler := v1.NewProductHandler(productService)
-	productBomService := serviceimplement.NewProductBomService(productBomRepository, productRepository, productCategoryRepository, unitOfMeasureRepository, unitOfWork)
+	unitConversionRepository := repositoryimplement.NewUnitConversionRepository(db)
+	productBomVersionRepository := repositoryimplement.NewProductBomVersionRepository(db)
+	orderRepository := repositoryimplement.NewOrderRepository(db)
+	orderItemRepository := repositoryimplement.NewOrderItemRepository(db)
+	productBomService := serviceimplement.NewProductBomService(productBomRepository, productRepository, productCategoryRepository, unitOfMeasureRepository, unitOfWork, unitConversionRepository, productBomVersionRepository, orderRepository, orderItemRepository)
 	productBomHandler := v1.NewProductBomHandler(productBomService)
 	productCategoryService := serviceimplement.NewProductCategoryService(productCategoryRepository)
 	productCategoryHandler := v1.NewProductCategoryHandler(productCategoryService)
 	unitOfMeasureService := serviceimplement.NewUnitOfMeasureService(unitOfMeasureRepository, unitOfWork)
 	unitOfMeasureHandler := v1.NewUnitOfMeasureHandler(unitOfMeasureService)
 	inventoryHistoryRepository := repositoryimplement.NewInventoryHistoryRepository(db)
-	inventoryService := serviceimplement.NewInventoryService(inventoryRepository, inventoryHistoryRepository, userRepository, productRepository, unitOfWork)
+	inventoryReservationRepository := repositoryimplement.NewInventoryReservationRepository(db)
+	inventoryLotRepository := repositoryimplement.NewInventoryLotRepository(db)
+	inventoryCostLayerRepository := repositoryimplement.NewInventoryCostLayerRepository(db)
+	inventoryCostLayerMovementRepository := repositoryimplement.NewInventoryCostLayerMovementRepository(db)
+	inventoryService := serviceimplement.NewInventoryService(inventoryRepository, inventoryHistoryRepository, userRepository, productRepository, inventoryReservationRepository, unitOfWork, warehouseRepository, inventoryLotRepository, inventoryCostLayerRepository, inventoryCostLayerMovementRepository)
 	inventoryHandler := v1.NewInventoryHandler(inventoryService)
 	inventoryHistoryService := serviceimplement.NewInventoryHistoryService(inventoryHistoryRepository)
 	inventoryHistoryHandler := v1.NewInventoryHistoryHandler(inventoryHistoryService)
 	inventoryReceiptRepository := repositoryimplement.NewInventoryReceiptRepository(db)
 	inventoryReceiptItemRepository := repositoryimplement.NewInventoryReceiptItemRepository(db)
-	inventoryReceiptService := serviceimplement.NewInventoryReceiptService(inventoryReceiptRepository, inventoryReceiptItemRepository, inventoryRepository, inventoryHistoryRepository, userRepository, productRepository, unitOfWork)
+	supplierRepository := repositoryimplement.NewSupplierRepository(db)
+	purchaseOrderRepository := repositoryimplement.NewPurchaseOrderRepository(db)
+	purchaseOrderItemRepository := repositoryimplement.NewPurchaseOrderItemRepository(db)
+	inventoryReceiptService := serviceimplement.NewInventoryReceiptService(inventoryReceiptRepository, inventoryReceiptItemRepository, inventoryRepository, inventoryHistoryRepository, userRepository, productRepository, unitOfWork, unitConversionRepository, supplierRepository, purchaseOrderRepository, purchaseOrderItemRepository, warehouseRepository, productCategoryRepository, inventoryLotRepository, productCostHistoryRepository, inventoryCostLayerRepository, inventoryCostLayerMovementRepository)
 	inventoryReceiptHandler := v1.NewInventoryReceiptHandler(inventoryReceiptService)
 	customerRepository := repositoryimplement.NewCustomerRepository(db)
 	customerService := serviceimplement.NewCustomerService(customerRepository, unitOfWork)
@@ -62,12 +78,55 @@ func InitializeContainer(db database.Db) *controller.ApiContainer {
 	statisticsHandler := v1.NewStatisticsHandler(statisticsService)
 	productImageService := serviceimplement.NewProductImageService(productImageRepository, unitOfWork, s3Service)
 	productImageHandler := v1.NewProductImageHandler(productImageService)
-	orderRepository := repositoryimplement.NewOrderRepository(db)
-	orderItemRepository := repositoryimplement.NewOrderItemRepository(db)
 	orderImageRepository := repositoryimplement.NewOrderImageRepository(db)
-	orderService := serviceimplement.NewOrderService(orderRepository, inventoryRepository, inventoryHistoryRepository, orderItemRepository, productRepository, productBomRepository, unitOfWork, userRepository, orderImageRepository, s3Service, customerRepository, unitOfMeasureRepository)
+	orderStatusHistoryRepository := repositoryimplement.NewOrderStatusHistoryRepository(db)
+	paymentRepository := repositoryimplement.NewPaymentRepository(db)
+	salesReturnRepository := repositoryimplement.NewSalesReturnRepository(db)
+	orderItemLotRepository := repositoryimplement.NewOrderItemLotRepository(db)
+	orderService := serviceimplement.NewOrderService(orderRepository, inventoryRepository, inventoryHistoryRepository, orderItemRepository, productRepository, productBomRepository, unitOfWork, userRepository, orderImageRepository, s3Service, customerRepository, unitOfMeasureRepository, orderStatusHistoryRepository, paymentRepository, salesReturnRepository, inventoryReservationRepository, unitConversionRepository, warehouseRepository, inventoryLotRepository, orderItemLotRepository, inventoryCostLayerRepository, inventoryCostLayerMovementRepository)
 	orderHandler := v1.NewOrderHandler(orderService)
-	server := http.NewServer(healthHandler, helloWorldHandler, authMiddleware, userHandler, productHandler, productBomHandler, productCategoryHandler, unitOfMeasureHandler, inventoryHandler, inventoryHistoryHandler, inventoryReceiptHandler, customerHandler, statisticsHandler, productImageHandler, orderHandler)
+	paymentService := serviceimplement.NewPaymentService(paymentRepository, orderRepository, orderItemRepository, salesReturnRepository, userRepository, unitOfWork)
+	paymentHandler := v1.NewPaymentHandler(paymentService)
+	workOrderLotRepository := repositoryimplement.NewWorkOrderLotRepository(db)
+	reportService := serviceimplement.NewReportService(orderRepository, orderItemRepository, paymentRepository, salesReturnRepository, customerRepository, productRepository, warehouseRepository, inventoryLotRepository, orderItemLotRepository, productCategoryRepository, inventoryHistoryRepository, productCostHistoryRepository, inventoryCostLayerRepository, workOrderLotRepository)
+	reportHandler := v1.NewReportHandler(reportService)
+	salesReturnItemRepository := repositoryimplement.NewSalesReturnItemRepository(db)
+	salesReturnService := serviceimplement.NewSalesReturnService(salesReturnRepository, salesReturnItemRepository, orderRepository, orderItemRepository, inventoryRepository, inventoryHistoryRepository, productRepository, productBomRepository, userRepository, unitOfWork, inventoryLotRepository, orderItemLotRepository, inventoryCostLayerRepository, inventoryCostLayerMovementRepository)
+	salesReturnHandler := v1.NewSalesReturnHandler(salesReturnService)
+	orderDocumentService := serviceimplement.NewOrderDocumentService(orderService)
+	orderDocumentHandler := v1.NewOrderDocumentHandler(orderDocumentService)
+	quotationRepository := repositoryimplement.NewQuotationRepository(db)
+	quotationItemRepository := repositoryimplement.NewQuotationItemRepository(db)
+	quotationService := serviceimplement.NewQuotationService(quotationRepository, quotationItemRepository, customerRepository, productRepository, unitOfMeasureRepository, unitOfWork, orderService, unitConversionRepository)
+	quotationHandler := v1.NewQuotationHandler(quotationService)
+	workOrderRepository := repositoryimplement.NewWorkOrderRepository(db)
+	workOrderItemRepository := repositoryimplement.NewWorkOrderItemRepository(db)
+	workOrderService := serviceimplement.NewWorkOrderService(workOrderRepository, workOrderItemRepository, productRepository, productBomRepository, inventoryRepository, inventoryHistoryRepository, inventoryReservationRepository, unitOfMeasureRepository, userRepository, unitOfWork, warehouseRepository, inventoryLotRepository, inventoryCostLayerRepository, inventoryCostLayerMovementRepository, workOrderLotRepository, productCategoryRepository)
+	workOrderHandler := v1.NewWorkOrderHandler(workOrderService)
+	unitConversionService := serviceimplement.NewUnitConversionService(unitConversionRepository, unitOfMeasureRepository, productRepository)
+	unitConversionHandler := v1.NewUnitConversionHandler(unitConversionService)
+	mrpRunRepository := repositoryimplement.NewMrpRunRepository(db)
+	mrpRunItemRepository := repositoryimplement.NewMrpRunItemRepository(db)
+	mrpRunDemandRepository := repositoryimplement.NewMrpRunDemandRepository(db)
+	mrpRunService := serviceimplement.NewMrpRunService(mrpRunRepository, mrpRunItemRepository, mrpRunDemandRepository, orderRepository, orderItemRepository, productRepository, productBomRepository, inventoryRepository, inventoryReservationRepository, unitOfMeasureRepository, userRepository, unitOfWork, unitConversionRepository)
+	mrpRunHandler := v1.NewMrpRunHandler(mrpRunService)
+	supplierService := serviceimplement.NewSupplierService(supplierRepository, unitOfWork)
+	supplierHandler := v1.NewSupplierHandler(supplierService)
+	purchaseOrderService := serviceimplement.NewPurchaseOrderService(purchaseOrderRepository, purchaseOrderItemRepository, supplierRepository, productRepository, unitOfWork, unitConversionRepository)
+	purchaseOrderHandler := v1.NewPurchaseOrderHandler(purchaseOrderService)
+	warehouseService := serviceimplement.NewWarehouseService(warehouseRepository, unitOfWork)
+	warehouseHandler := v1.NewWarehouseHandler(warehouseService)
+	stockTransferRepository := repositoryimplement.NewStockTransferRepository(db)
+	stockTransferItemRepository := repositoryimplement.NewStockTransferItemRepository(db)
+	stockTransferService := serviceimplement.NewStockTransferService(stockTransferRepository, stockTransferItemRepository, warehouseRepository, inventoryRepository, inventoryHistoryRepository, inventoryReservationRepository, productRepository, unitOfMeasureRepository, userRepository, unitOfWork, unitConversionRepository, inventoryLotRepository)
+	stockTransferHandler := v1.NewStockTransferHandler(stockTransferService)
+	inventoryLotService := serviceimplement.NewInventoryLotService(inventoryLotRepository, productRepository, warehouseRepository, inventoryRepository, inventoryHistoryRepository, userRepository, inventoryCostLayerRepository, inventoryCostLayerMovementRepository, unitOfWork)
+	inventoryLotHandler := v1.NewInventoryLotHandler(inventoryLotService)
+	productCostHistoryService := serviceimplement.NewProductCostHistoryService(productCostHistoryRepository, productRepository)
+	productCostHistoryHandler := v1.NewProductCostHistoryHandler(productCostHistoryService)
+	inventoryCostLayerService := serviceimplement.NewInventoryCostLayerService(inventoryCostLayerRepository, inventoryRepository, productRepository)
+	inventoryCostLayerHandler := v1.NewInventoryCostLayerHandler(inventoryCostLayerService)
+	server := http.NewServer(healthHandler, helloWorldHandler, authMiddleware, idempotencyMiddleware, userHandler, productHandler, productBomHandler, productCategoryHandler, unitOfMeasureHandler, inventoryHandler, inventoryHistoryHandler, inventoryReceiptHandler, customerHandler, statisticsHandler, productImageHandler, orderHandler, paymentHandler, reportHandler, salesReturnHandler, orderDocumentHandler, quotationHandler, workOrderHandler, unitConversionHandler, mrpRunHandler, supplierHandler, purchaseOrderHandler, warehouseHandler, stockTransferHandler, inventoryLotHandler, productCostHistoryHandler, inventoryCostLayerHandler)
 	apiContainer := controller.NewApiContainer(server)
 	return apiContainer
 }
@@ -80,12 +139,12 @@ var container = wire.NewSet(controller.NewApiContainer)
 var serverSet = wire.NewSet(http.NewServer)
 
 // handler === controller | with service and repository layers to form 3 layers architecture
-var handlerSet = wire.NewSet(v1.NewHealthHandler, v1.NewHelloWorldHandler, v1.NewUserHandler, v1.NewProductHandler, v1.NewProductBomHandler, v1.NewProductCategoryHandler, v1.NewUnitOfMeasureHandler, v1.NewInventoryHandler, v1.NewInventoryHistoryHandler, v1.NewCustomerHandler, v1.NewStatisticsHandler, v1.NewInventoryReceiptHandler, v1.NewProductImageHandler, v1.NewOrderHandler)
+var handlerSet = wire.NewSet(v1.NewHealthHandler, v1.NewHelloWorldHandler, v1.NewUserHandler, v1.NewProductHandler, v1.NewProductBomHandler, v1.NewProductCategoryHandler, v1.NewUnitOfMeasureHandler, v1.NewInventoryHandler, v1.NewInventoryHistoryHandler, v1.NewCustomerHandler, v1.NewStatisticsHandler, v1.NewInventoryReceiptHandler, v1.NewProductImageHandler, v1.NewOrderHandler, v1.NewPaymentHandler, v1.NewReportHandler, v1.NewSalesReturnHandler, v1.NewOrderDocumentHandler, v1.NewQuotationHandler, v1.NewWorkOrderHandler, v1.NewUnitConversionHandler, v1.NewMrpRunHandler, v1.NewSupplierHandler, v1.NewPurchaseOrderHandler, v1.NewWarehouseHandler, v1.NewStockTransferHandler, v1.NewInventoryLotHandler, v1.NewProductCostHistoryHandler, v1.NewInventoryCostLayerHandler)
 
-var serviceSet = wire.NewSet(serviceimplement.NewHelloWorldService, serviceimplement.NewUserService, serviceimplement.NewProductService, serviceimplement.NewInventoryService, serviceimplement.NewInventoryHistoryService, serviceimplement.NewCustomerService, serviceimplement.NewStatisticsService, serviceimplement.NewUnitOfMeasureService, serviceimplement.NewProductCategoryService, serviceimplement.NewProductImageService, serviceimplement.NewProductBomService, serviceimplement.NewInventoryReceiptService, serviceimplement.NewOrderService, serviceimplement.NewOrderImageService)
+var serviceSet = wire.NewSet(serviceimplement.NewHelloWorldService, serviceimplement.NewUserService, serviceimplement.NewProductService, serviceimplement.NewInventoryService, serviceimplement.NewInventoryHistoryService, serviceimplement.NewCustomerService, serviceimplement.NewStatisticsService, serviceimplement.NewUnitOfMeasureService, serviceimplement.NewProductCategoryService, serviceimplement.NewProductImageService, serviceimplement.NewProductBomService, serviceimplement.NewInventoryReceiptService, serviceimplement.NewOrderService, serviceimplement.NewOrderImageService, serviceimplement.NewPaymentService, serviceimplement.NewReportService, serviceimplement.NewSalesReturnService, serviceimplement.NewOrderDocumentService, serviceimplement.NewQuotationService, serviceimplement.NewIdempotencyService, serviceimplement.NewWorkOrderService, serviceimplement.NewUnitConversionService, serviceimplement.NewMrpRunService, serviceimplement.NewSupplierService, serviceimplement.NewPurchaseOrderService, serviceimplement.NewWarehouseService, serviceimplement.NewStockTransferService, serviceimplement.NewInventoryLotService, serviceimplement.NewProductCostHistoryService, serviceimplement.NewInventoryCostLayerService)
 
-var repositorySet = wire.NewSet(repositoryimplement.NewHelloWorldRepository, repositoryimplement.NewUserRepository, repositoryimplement.NewProductRepository, repositoryimplement.NewInventoryRepository, repositoryimplement.NewInventoryHistoryRepository, repositoryimplement.NewUnitOfWork, repositoryimplement.NewCustomerRepository, repositoryimplement.NewUnitOfMeasureRepository, repositoryimplement.NewProductCategoryRepository, repositoryimplement.NewProductImageRepository, repositoryimplement.NewProductBomRepository, repositoryimplement.NewInventoryReceiptRepository, repositoryimplement.NewInventoryReceiptItemRepository, repositoryimplement.NewOrderRepository, repositoryimplement.NewOrderItemRepository, repositoryimplement.NewOrderImageRepository)
+var repositorySet = wire.NewSet(repositoryimplement.NewHelloWorldRepository, repositoryimplement.NewUserRepository, repositoryimplement.NewProductRepository, repositoryimplement.NewInventoryRepository, repositoryimplement.NewInventoryHistoryRepository, repositoryimplement.NewUnitOfWork, repositoryimplement.NewCustomerRepository, repositoryimplement.NewUnitOfMeasureRepository, repositoryimplement.NewProductCategoryRepository, repositoryimplement.NewProductImageRepository, repositoryimplement.NewProductBomRepository, repositoryimplement.NewInventoryReceiptRepository, repositoryimplement.NewInventoryReceiptItemRepository, repositoryimplement.NewOrderRepository, repositoryimplement.NewOrderItemRepository, repositoryimplement.NewOrderImageRepository, repositoryimplement.NewOrderStatusHistoryRepository, repositoryimplement.NewPaymentRepository, repositoryimplement.NewSalesReturnRepository, repositoryimplement.NewSalesReturnItemRepository, repositoryimplement.NewQuotationRepository, repositoryimplement.NewQuotationItemRepository, repositoryimplement.NewInventoryReservationRepository, repositoryimplement.NewIdempotencyKeyRepository, repositoryimplement.NewWorkOrderRepository, repositoryimplement.NewWorkOrderItemRepository, repositoryimplement.NewUnitConversionRepository, repositoryimplement.NewProductBomVersionRepository, repositoryimplement.NewMrpRunRepository, repositoryimplement.NewMrpRunItemRepository, repositoryimplement.NewMrpRunDemandRepository, repositoryimplement.NewSupplierRepository, repositoryimplement.NewPurchaseOrderRepository, repositoryimplement.NewPurchaseOrderItemRepository, repositoryimplement.NewWarehouseRepository, repositoryimplement.NewStockTransferRepository, repositoryimplement.NewStockTransferItemRepository, repositoryimplement.NewInventoryLotRepository, repositoryimplement.NewOrderItemLotRepository, repositoryimplement.NewProductCostHistoryRepository, repositoryimplement.NewInventoryCostLayerRepository, repositoryimplement.NewInventoryCostLayerMovementRepository, repositoryimplement.NewWorkOrderLotRepository)
 
-var middlewareSet = wire.NewSet(middleware.NewAuthMiddleware)
+var middlewareSet = wire.NewSet(middleware.NewAuthMiddleware, middleware.NewIdempotencyMiddleware)
 
 var beanSet = wire.NewSet(beanimplement.NewBcryptPasswordEncoder, beanimplement.NewS3Service)
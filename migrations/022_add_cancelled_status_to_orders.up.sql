@@ -0,0 +1,6 @@
+-- Drop the inline CHECK created in migration 021 (auto-named by MySQL)
+ALTER TABLE `orders` DROP CHECK `orders_chk_1`;
+
+-- Re-add the CHECK with CANCELLED as a terminal status
+ALTER TABLE `orders` ADD CONSTRAINT `check_orders_delivery_status`
+    CHECK (delivery_status IN ('PENDING', 'DELIVERED', 'UNPAID', 'COMPLETED', 'CANCELLED'));
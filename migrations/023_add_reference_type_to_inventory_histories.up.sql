@@ -0,0 +1,10 @@
+ALTER TABLE `inventory_histories`
+ADD COLUMN `reference_type` VARCHAR(30) NULL COMMENT 'Loại tham chiếu (ORDER, INVENTORY_RECEIPT, ...)' AFTER `reference_id`,
+ADD KEY `idx_inventory_histories_reference` (`reference_type`, `reference_id`);
+
+-- Backfill reference type for existing rows based on the notes written by the services
+UPDATE `inventory_histories` SET `reference_type` = 'ORDER'
+WHERE `reference_id` IS NOT NULL AND `note` LIKE 'Xuất cho đơn hàng:%';
+
+UPDATE `inventory_histories` SET `reference_type` = 'INVENTORY_RECEIPT'
+WHERE `reference_id` IS NOT NULL AND `note` LIKE 'Nhập kho từ phiếu nhập%';
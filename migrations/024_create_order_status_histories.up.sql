@@ -0,0 +1,14 @@
+CREATE TABLE `order_status_histories` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `order_id` int NOT NULL COMMENT 'Đơn hàng',
+  `from_status` varchar(20) DEFAULT NULL COMMENT 'Trạng thái trước (NULL khi tạo đơn)',
+  `to_status` varchar(20) NOT NULL COMMENT 'Trạng thái mới',
+  `changed_by` int DEFAULT NULL COMMENT 'Người thay đổi',
+  `changed_by_name` varchar(255) NOT NULL COMMENT 'Tên người thay đổi',
+  `reason` text COMMENT 'Lý do thay đổi',
+  `changed_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Thời gian thay đổi',
+  PRIMARY KEY (`id`),
+  KEY `order_id` (`order_id`),
+  CONSTRAINT `order_status_histories_ibfk_1` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `order_status_histories_ibfk_2` FOREIGN KEY (`changed_by`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
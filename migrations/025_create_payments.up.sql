@@ -0,0 +1,18 @@
+CREATE TABLE `payments` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `order_id` int NOT NULL COMMENT 'Đơn hàng',
+  `amount` int NOT NULL COMMENT 'Số tiền thanh toán',
+  `payment_method` varchar(20) NOT NULL COMMENT 'Hình thức thanh toán',
+  `payment_date` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Ngày thanh toán',
+  `reference_number` varchar(100) DEFAULT NULL COMMENT 'Mã giao dịch / số chứng từ',
+  `note` text COMMENT 'Ghi chú',
+  `created_by` int DEFAULT NULL COMMENT 'Người ghi nhận',
+  `created_by_name` varchar(255) NOT NULL COMMENT 'Tên người ghi nhận',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `order_id` (`order_id`),
+  CONSTRAINT `payments_ibfk_1` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `payments_ibfk_2` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`),
+  CONSTRAINT `check_payments_amount` CHECK (`amount` > 0),
+  CONSTRAINT `check_payments_method` CHECK (`payment_method` IN ('CASH', 'BANK_TRANSFER', 'OTHER'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
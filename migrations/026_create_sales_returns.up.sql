@@ -0,0 +1,35 @@
+CREATE TABLE `sales_returns` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `code` varchar(20) NOT NULL COMMENT 'Mã phiếu trả hàng (TH00001)',
+  `order_id` int NOT NULL COMMENT 'Đơn hàng',
+  `return_date` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Ngày trả hàng',
+  `reason` text COMMENT 'Lý do trả hàng',
+  `credit_amount` int NOT NULL DEFAULT '0' COMMENT 'Số tiền ghi giảm công nợ',
+  `created_by` int DEFAULT NULL COMMENT 'Người ghi nhận',
+  `created_by_name` varchar(255) NOT NULL COMMENT 'Tên người ghi nhận',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `code` (`code`),
+  KEY `order_id` (`order_id`),
+  CONSTRAINT `sales_returns_ibfk_1` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`),
+  CONSTRAINT `sales_returns_ibfk_2` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `sales_return_items` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `sales_return_id` int NOT NULL COMMENT 'Phiếu trả hàng',
+  `order_item_id` int NOT NULL COMMENT 'Dòng đơn hàng được trả',
+  `product_id` int NOT NULL COMMENT 'Sản phẩm',
+  `quantity` int NOT NULL COMMENT 'Số lượng trả',
+  `disposition` varchar(30) NOT NULL COMMENT 'Cách xử lý hàng trả',
+  `reason` text COMMENT 'Lý do trả',
+  `credit_amount` int NOT NULL DEFAULT '0' COMMENT 'Số tiền ghi giảm (chưa gồm thuế)',
+  PRIMARY KEY (`id`),
+  KEY `sales_return_id` (`sales_return_id`),
+  KEY `order_item_id` (`order_item_id`),
+  CONSTRAINT `sales_return_items_ibfk_1` FOREIGN KEY (`sales_return_id`) REFERENCES `sales_returns` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `sales_return_items_ibfk_2` FOREIGN KEY (`order_item_id`) REFERENCES `order_items` (`id`),
+  CONSTRAINT `sales_return_items_ibfk_3` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `check_sales_return_items_quantity` CHECK (`quantity` > 0),
+  CONSTRAINT `check_sales_return_items_disposition` CHECK (`disposition` IN ('RESTOCK_PRODUCT', 'RESTOCK_MATERIALS', 'WRITE_OFF'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
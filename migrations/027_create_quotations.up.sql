@@ -0,0 +1,41 @@
+CREATE TABLE `quotations` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `code` varchar(10) NOT NULL COMMENT 'Mã báo giá (BG00001)',
+  `customer_id` int NOT NULL COMMENT 'Khách hàng',
+  `quotation_date` datetime NOT NULL COMMENT 'Ngày báo giá',
+  `valid_from` date NOT NULL COMMENT 'Hiệu lực từ ngày',
+  `valid_until` date NOT NULL COMMENT 'Hiệu lực đến ngày',
+  `note` text COMMENT 'Ghi chú',
+  `additional_cost` int NOT NULL DEFAULT '0' COMMENT 'Chi phí phát sinh',
+  `additional_cost_note` text COMMENT 'Ghi chú chi phí phát sinh',
+  `tax_percent` int NOT NULL DEFAULT '0' COMMENT 'Thuế suất',
+  `status` varchar(20) NOT NULL DEFAULT 'DRAFT' COMMENT 'Trạng thái báo giá',
+  `order_id` int DEFAULT NULL COMMENT 'Đơn hàng được tạo từ báo giá',
+  `created_by` int DEFAULT NULL COMMENT 'Người tạo',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_quotations_code` (`code`),
+  UNIQUE KEY `uk_quotations_order_id` (`order_id`),
+  KEY `customer_id` (`customer_id`),
+  CONSTRAINT `quotations_ibfk_1` FOREIGN KEY (`customer_id`) REFERENCES `customers` (`id`),
+  CONSTRAINT `quotations_ibfk_2` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`),
+  CONSTRAINT `quotations_ibfk_3` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`),
+  CONSTRAINT `check_quotations_status` CHECK (`status` IN ('DRAFT', 'SENT', 'ACCEPTED', 'EXPIRED')),
+  CONSTRAINT `check_quotations_validity` CHECK (`valid_until` >= `valid_from`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `quotation_items` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `quotation_id` int NOT NULL COMMENT 'Báo giá',
+  `product_id` int NOT NULL COMMENT 'Sản phẩm',
+  `quantity` int NOT NULL COMMENT 'Số lượng',
+  `selling_price` int NOT NULL COMMENT 'Giá bán',
+  `original_price` int NOT NULL COMMENT 'Giá vốn',
+  `discount_percent` int NOT NULL COMMENT 'Chiết khấu',
+  `final_amount` int NOT NULL COMMENT 'Tổng tiền',
+  PRIMARY KEY (`id`),
+  KEY `quotation_id` (`quotation_id`),
+  CONSTRAINT `quotation_items_ibfk_1` FOREIGN KEY (`quotation_id`) REFERENCES `quotations` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `quotation_items_ibfk_2` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,16 @@
+CREATE TABLE `inventory_reservations` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL COMMENT 'Nguyên vật liệu được giữ chỗ',
+  `order_id` int NOT NULL COMMENT 'Đơn hàng giữ chỗ',
+  `quantity` int NOT NULL COMMENT 'Số lượng thay đổi (dương = giữ chỗ, âm = nhả hoặc xuất kho)',
+  `entry_type` varchar(20) NOT NULL COMMENT 'Loại bút toán giữ chỗ',
+  `note` text COMMENT 'Ghi chú',
+  `created_by_name` varchar(255) NOT NULL COMMENT 'Tên người thực hiện',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `product_id` (`product_id`),
+  KEY `order_id` (`order_id`),
+  CONSTRAINT `inventory_reservations_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `inventory_reservations_ibfk_2` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `check_inventory_reservations_entry_type` CHECK (`entry_type` IN ('RESERVE', 'RELEASE', 'ISSUE'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,16 @@
+CREATE TABLE `idempotency_keys` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL COMMENT 'Người gửi yêu cầu',
+  `idempotency_key` varchar(255) NOT NULL COMMENT 'Giá trị header Idempotency-Key',
+  `request_hash` char(64) NOT NULL COMMENT 'SHA-256 của method, đường dẫn và nội dung yêu cầu',
+  `status` varchar(20) NOT NULL DEFAULT 'PROCESSING' COMMENT 'Trạng thái xử lý',
+  `response_status_code` int DEFAULT NULL COMMENT 'HTTP status của phản hồi gốc',
+  `response_body` mediumtext COMMENT 'Nội dung phản hồi gốc',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `expires_at` timestamp NOT NULL COMMENT 'Hết hạn lưu giữ',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_user_idempotency_key` (`user_id`, `idempotency_key`),
+  KEY `expires_at` (`expires_at`),
+  CONSTRAINT `idempotency_keys_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `check_idempotency_keys_status` CHECK (`status` IN ('PROCESSING', 'COMPLETED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
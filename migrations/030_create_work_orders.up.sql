@@ -0,0 +1,36 @@
+CREATE TABLE `work_orders` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `code` varchar(10) NOT NULL COMMENT 'Mã lệnh sản xuất (SX00001)',
+  `product_id` int NOT NULL COMMENT 'Thành phẩm cần sản xuất',
+  `quantity` int NOT NULL COMMENT 'Số lượng sản xuất',
+  `multi_level` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'Sản xuất cả bán thành phẩm còn thiếu theo BOM nhiều cấp',
+  `status` varchar(20) NOT NULL DEFAULT 'PLANNED' COMMENT 'Trạng thái lệnh sản xuất',
+  `planned_date` date DEFAULT NULL COMMENT 'Ngày dự kiến sản xuất',
+  `note` text COMMENT 'Ghi chú',
+  `started_at` timestamp NULL DEFAULT NULL COMMENT 'Thời điểm bắt đầu (xuất nguyên liệu)',
+  `completed_at` timestamp NULL DEFAULT NULL COMMENT 'Thời điểm hoàn thành (nhập thành phẩm)',
+  `created_by` int DEFAULT NULL COMMENT 'Người tạo',
+  `created_by_name` varchar(255) NOT NULL COMMENT 'Tên người tạo',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_work_orders_code` (`code`),
+  KEY `product_id` (`product_id`),
+  KEY `status` (`status`),
+  CONSTRAINT `work_orders_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `work_orders_ibfk_2` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`),
+  CONSTRAINT `check_work_orders_quantity` CHECK (`quantity` > 0),
+  CONSTRAINT `check_work_orders_status` CHECK (`status` IN ('PLANNED', 'IN_PROGRESS', 'DONE'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `work_order_items` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `work_order_id` int NOT NULL COMMENT 'Lệnh sản xuất',
+  `product_id` int NOT NULL COMMENT 'Thành phần đã xuất kho',
+  `quantity` int NOT NULL COMMENT 'Số lượng đã tiêu hao',
+  PRIMARY KEY (`id`),
+  KEY `work_order_id` (`work_order_id`),
+  CONSTRAINT `work_order_items_ibfk_1` FOREIGN KEY (`work_order_id`) REFERENCES `work_orders` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `work_order_items_ibfk_2` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `check_work_order_items_quantity` CHECK (`quantity` > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,25 @@
+CREATE TABLE `unit_conversions` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `product_id` int DEFAULT NULL COMMENT 'Sản phẩm áp dụng (NULL = áp dụng cho mọi sản phẩm)',
+  `from_unit_id` int NOT NULL COMMENT 'Đơn vị quy đổi',
+  `to_unit_id` int NOT NULL COMMENT 'Đơn vị đích',
+  `factor` decimal(18,6) NOT NULL COMMENT 'Hệ số: 1 from_unit = factor to_unit',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_product_unit_conversion` (`product_id`,`from_unit_id`,`to_unit_id`),
+  KEY `from_unit_id` (`from_unit_id`),
+  KEY `to_unit_id` (`to_unit_id`),
+  CONSTRAINT `unit_conversions_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `unit_conversions_ibfk_2` FOREIGN KEY (`from_unit_id`) REFERENCES `units_of_measure` (`id`),
+  CONSTRAINT `unit_conversions_ibfk_3` FOREIGN KEY (`to_unit_id`) REFERENCES `units_of_measure` (`id`),
+  CONSTRAINT `check_unit_conversions_factor` CHECK (`factor` > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Order items keep the unit they were sold in, base_quantity is the quantity in the product's base unit
+ALTER TABLE `order_items`
+ADD COLUMN `unit_id` int DEFAULT NULL COMMENT 'Đơn vị bán (NULL = đơn vị cơ bản của sản phẩm)',
+ADD COLUMN `base_quantity` int NOT NULL DEFAULT 0 COMMENT 'Số lượng quy đổi theo đơn vị cơ bản của sản phẩm',
+ADD CONSTRAINT `order_items_unit_fk` FOREIGN KEY (`unit_id`) REFERENCES `units_of_measure` (`id`);
+
+UPDATE `order_items` SET `base_quantity` = `quantity`;
@@ -0,0 +1,36 @@
+CREATE TABLE `product_bom_versions` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `parent_product_id` int NOT NULL COMMENT 'Sản phẩm thành phẩm của BOM',
+  `version_number` int NOT NULL COMMENT 'Số phiên bản, tăng dần theo sản phẩm',
+  `effective_from` datetime NOT NULL COMMENT 'Thời điểm phiên bản bắt đầu có hiệu lực',
+  `effective_to` datetime DEFAULT NULL COMMENT 'Thời điểm phiên bản hết hiệu lực (NULL = chưa hết hiệu lực)',
+  `is_active` tinyint(1) NOT NULL DEFAULT '1' COMMENT 'Phiên bản bị tắt sẽ không được dùng khi tính nguyên liệu',
+  `note` text COMMENT 'Ghi chú thay đổi',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_parent_version` (`parent_product_id`,`version_number`),
+  CONSTRAINT `product_bom_versions_ibfk_1` FOREIGN KEY (`parent_product_id`) REFERENCES `products` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `check_product_bom_versions_effective_range` CHECK (`effective_to` IS NULL OR `effective_to` > `effective_from`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Existing BOMs become version 1 of their product
+INSERT INTO `product_bom_versions` (`parent_product_id`, `version_number`, `effective_from`, `note`)
+SELECT `parent_product_id`, 1, COALESCE(MIN(`created_at`), NOW()), 'Phiên bản ban đầu'
+FROM `product_boms`
+GROUP BY `parent_product_id`;
+
+ALTER TABLE `product_boms`
+  ADD COLUMN `bom_version_id` int DEFAULT NULL COMMENT 'Phiên bản BOM chứa dòng nguyên liệu' AFTER `id`;
+
+UPDATE `product_boms` b
+JOIN `product_bom_versions` v ON v.`parent_product_id` = b.`parent_product_id`
+SET b.`bom_version_id` = v.`id`;
+
+-- A component may now appear once per version instead of once per product
+ALTER TABLE `product_boms` ADD KEY `parent_product_id` (`parent_product_id`);
+ALTER TABLE `product_boms`
+  DROP INDEX `unique_parent_component`,
+  MODIFY COLUMN `bom_version_id` int NOT NULL COMMENT 'Phiên bản BOM chứa dòng nguyên liệu',
+  ADD UNIQUE KEY `unique_version_component` (`bom_version_id`,`component_product_id`),
+  ADD CONSTRAINT `product_boms_ibfk_3` FOREIGN KEY (`bom_version_id`) REFERENCES `product_bom_versions` (`id`) ON DELETE CASCADE;
@@ -0,0 +1,42 @@
+CREATE TABLE `mrp_runs` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `code` varchar(10) NOT NULL COMMENT 'Mã kế hoạch nhu cầu nguyên liệu (MRP00001)',
+  `include_open_orders` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'Tính cả nhu cầu của các đơn hàng đang chờ giao',
+  `note` text COMMENT 'Ghi chú',
+  `total_materials` int NOT NULL DEFAULT '0' COMMENT 'Số sản phẩm trong kế hoạch',
+  `shortage_materials` int NOT NULL DEFAULT '0' COMMENT 'Số nguyên liệu cần mua thêm',
+  `created_by` int DEFAULT NULL COMMENT 'Người chạy kế hoạch',
+  `created_by_name` varchar(255) NOT NULL COMMENT 'Tên người chạy kế hoạch',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_mrp_runs_code` (`code`),
+  CONSTRAINT `mrp_runs_ibfk_1` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `mrp_run_demands` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `mrp_run_id` int NOT NULL COMMENT 'Kế hoạch',
+  `product_id` int NOT NULL COMMENT 'Sản phẩm có nhu cầu',
+  `order_id` int DEFAULT NULL COMMENT 'Đơn hàng phát sinh nhu cầu (NULL = nhập tay)',
+  `quantity` int NOT NULL COMMENT 'Số lượng theo đơn vị cơ bản',
+  PRIMARY KEY (`id`),
+  KEY `mrp_run_id` (`mrp_run_id`),
+  CONSTRAINT `mrp_run_demands_ibfk_1` FOREIGN KEY (`mrp_run_id`) REFERENCES `mrp_runs` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `mrp_run_demands_ibfk_2` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `mrp_run_demands_ibfk_3` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `mrp_run_items` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `mrp_run_id` int NOT NULL COMMENT 'Kế hoạch',
+  `product_id` int NOT NULL COMMENT 'Sản phẩm/nguyên liệu',
+  `gross_requirement` decimal(14,3) NOT NULL COMMENT 'Tổng nhu cầu trước khi trừ tồn kho',
+  `available_stock` int NOT NULL COMMENT 'Tồn kho dùng được cho kế hoạch (trừ phần giữ chỗ của đơn hàng khi không tính đơn hàng)',
+  `net_requirement` decimal(14,3) NOT NULL COMMENT 'Phần thiếu sau khi trừ tồn kho',
+  `planned_production` int NOT NULL DEFAULT '0' COMMENT 'Số lượng cần sản xuất (sản phẩm có BOM)',
+  `suggested_purchase` int NOT NULL DEFAULT '0' COMMENT 'Số lượng đề xuất mua (nguyên liệu không có BOM)',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `unique_mrp_run_product` (`mrp_run_id`,`product_id`),
+  CONSTRAINT `mrp_run_items_ibfk_1` FOREIGN KEY (`mrp_run_id`) REFERENCES `mrp_runs` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `mrp_run_items_ibfk_2` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,9 @@
+-- Share of a component lost while producing, on top of the BOM quantity
+ALTER TABLE `product_boms`
+  ADD COLUMN `scrap_percent` decimal(5,2) NOT NULL DEFAULT '0.00' COMMENT 'Tỷ lệ hao hụt của nguyên liệu (%)' AFTER `quantity`,
+  ADD CONSTRAINT `check_product_boms_scrap_percent` CHECK (`scrap_percent` >= 0 AND `scrap_percent` < 100);
+
+-- Share of the produced quantity that comes out good, all components are scaled by it
+ALTER TABLE `products`
+  ADD COLUMN `yield_percent` decimal(5,2) NOT NULL DEFAULT '100.00' COMMENT 'Hiệu suất sản xuất thành phẩm (%)' AFTER `operation_type`,
+  ADD CONSTRAINT `check_products_yield_percent` CHECK (`yield_percent` > 0 AND `yield_percent` <= 100);
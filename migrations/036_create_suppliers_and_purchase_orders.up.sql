@@ -0,0 +1,58 @@
+CREATE TABLE `suppliers` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `code` varchar(10) NOT NULL COMMENT 'Mã nhà cung cấp (NCC00001)',
+  `name` varchar(255) NOT NULL,
+  `phone` varchar(30) DEFAULT NULL,
+  `email` varchar(255) DEFAULT NULL,
+  `address` varchar(255) DEFAULT NULL,
+  `tax_code` varchar(20) DEFAULT NULL COMMENT 'Mã số thuế',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_suppliers_code` (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `purchase_orders` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `code` varchar(10) NOT NULL COMMENT 'Mã đơn mua hàng (MH00001)',
+  `supplier_id` int NOT NULL COMMENT 'Nhà cung cấp',
+  `order_date` datetime NOT NULL COMMENT 'Ngày đặt mua',
+  `expected_date` date DEFAULT NULL COMMENT 'Ngày dự kiến nhận hàng',
+  `status` varchar(20) NOT NULL DEFAULT 'DRAFT' COMMENT 'Trạng thái đơn mua hàng',
+  `note` text COMMENT 'Ghi chú',
+  `created_by` int DEFAULT NULL COMMENT 'Người tạo',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_purchase_orders_code` (`code`),
+  KEY `supplier_id` (`supplier_id`),
+  CONSTRAINT `purchase_orders_ibfk_1` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers` (`id`),
+  CONSTRAINT `purchase_orders_ibfk_2` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`),
+  CONSTRAINT `check_purchase_orders_status` CHECK (`status` IN ('DRAFT', 'ORDERED', 'PARTIALLY_RECEIVED', 'RECEIVED', 'CANCELLED'))
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `purchase_order_items` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `purchase_order_id` int NOT NULL COMMENT 'Đơn mua hàng',
+  `product_id` int NOT NULL COMMENT 'Sản phẩm',
+  `quantity` int NOT NULL COMMENT 'Số lượng đặt (theo đơn vị cơ bản)',
+  `received_quantity` int NOT NULL DEFAULT '0' COMMENT 'Số lượng đã nhận',
+  `unit_cost` decimal(10,3) DEFAULT NULL COMMENT 'Đơn giá theo đơn vị cơ bản',
+  `expected_date` date DEFAULT NULL COMMENT 'Ngày dự kiến nhận dòng hàng',
+  `note` text COMMENT 'Ghi chú',
+  PRIMARY KEY (`id`),
+  KEY `purchase_order_id` (`purchase_order_id`),
+  KEY `product_id` (`product_id`),
+  CONSTRAINT `purchase_order_items_ibfk_1` FOREIGN KEY (`purchase_order_id`) REFERENCES `purchase_orders` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `purchase_order_items_ibfk_2` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `check_purchase_order_items_quantity` CHECK (`quantity` > 0),
+  CONSTRAINT `check_purchase_order_items_received` CHECK (`received_quantity` >= 0 AND `received_quantity` <= `quantity`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE `inventory_receipts`
+  ADD COLUMN `supplier_id` int DEFAULT NULL COMMENT 'Nhà cung cấp' AFTER `user_id`,
+  ADD KEY `supplier_id` (`supplier_id`),
+  ADD CONSTRAINT `inventory_receipts_ibfk_2` FOREIGN KEY (`supplier_id`) REFERENCES `suppliers` (`id`);
+
+ALTER TABLE `inventory_receipt_items`
+  ADD COLUMN `purchase_order_item_id` int DEFAULT NULL COMMENT 'Dòng đơn mua hàng được nhận' AFTER `product_id`,
+  ADD KEY `purchase_order_item_id` (`purchase_order_item_id`),
+  ADD CONSTRAINT `inventory_receipt_items_ibfk_3` FOREIGN KEY (`purchase_order_item_id`) REFERENCES `purchase_order_items` (`id`);
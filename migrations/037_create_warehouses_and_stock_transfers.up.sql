@@ -0,0 +1,90 @@
+CREATE TABLE `warehouses` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `code` varchar(10) NOT NULL COMMENT 'Mã kho (KHO00001)',
+  `name` varchar(255) NOT NULL COMMENT 'Tên kho',
+  `address` varchar(255) DEFAULT NULL COMMENT 'Địa chỉ',
+  `is_default` tinyint(1) NOT NULL DEFAULT '0' COMMENT 'Kho mặc định khi chứng từ không chỉ định kho',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_warehouses_code` (`code`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Existing stock lives in the main warehouse
+INSERT INTO `warehouses` (`id`, `code`, `name`, `is_default`) VALUES (1, 'KHO00001', 'Kho chính', 1);
+
+ALTER TABLE `inventory`
+  ADD COLUMN `warehouse_id` int NOT NULL DEFAULT '1' COMMENT 'Kho' AFTER `product_id`,
+  ADD UNIQUE KEY `unique_product_warehouse_inventory` (`product_id`, `warehouse_id`),
+  DROP INDEX `unique_product_inventory`,
+  ADD KEY `warehouse_id` (`warehouse_id`),
+  ADD CONSTRAINT `inventory_ibfk_2` FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`);
+
+ALTER TABLE `inventory` ALTER COLUMN `warehouse_id` DROP DEFAULT;
+
+ALTER TABLE `inventory_histories`
+  ADD COLUMN `warehouse_id` int DEFAULT NULL COMMENT 'Kho phát sinh biến động' AFTER `product_id`,
+  ADD KEY `warehouse_id` (`warehouse_id`),
+  ADD CONSTRAINT `inventory_histories_ibfk_2` FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`);
+
+UPDATE `inventory_histories` SET `warehouse_id` = 1;
+
+ALTER TABLE `inventory_reservations`
+  ADD COLUMN `warehouse_id` int NOT NULL DEFAULT '1' COMMENT 'Kho giữ chỗ' AFTER `product_id`,
+  ADD KEY `warehouse_id` (`warehouse_id`),
+  ADD CONSTRAINT `inventory_reservations_ibfk_3` FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`);
+
+ALTER TABLE `inventory_reservations` ALTER COLUMN `warehouse_id` DROP DEFAULT;
+
+ALTER TABLE `orders`
+  ADD COLUMN `warehouse_id` int NOT NULL DEFAULT '1' COMMENT 'Kho xuất hàng' AFTER `customer_id`,
+  ADD KEY `warehouse_id` (`warehouse_id`),
+  ADD CONSTRAINT `orders_ibfk_2` FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`);
+
+ALTER TABLE `orders` ALTER COLUMN `warehouse_id` DROP DEFAULT;
+
+ALTER TABLE `inventory_receipts`
+  ADD COLUMN `warehouse_id` int NOT NULL DEFAULT '1' COMMENT 'Kho nhập hàng' AFTER `supplier_id`,
+  ADD KEY `warehouse_id` (`warehouse_id`),
+  ADD CONSTRAINT `inventory_receipts_ibfk_3` FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`);
+
+ALTER TABLE `inventory_receipts` ALTER COLUMN `warehouse_id` DROP DEFAULT;
+
+ALTER TABLE `work_orders`
+  ADD COLUMN `warehouse_id` int NOT NULL DEFAULT '1' COMMENT 'Kho xuất nguyên liệu và nhập thành phẩm' AFTER `product_id`,
+  ADD KEY `warehouse_id` (`warehouse_id`),
+  ADD CONSTRAINT `work_orders_ibfk_3` FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`);
+
+ALTER TABLE `work_orders` ALTER COLUMN `warehouse_id` DROP DEFAULT;
+
+CREATE TABLE `stock_transfers` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `code` varchar(10) NOT NULL COMMENT 'Mã phiếu chuyển kho (CK00001)',
+  `from_warehouse_id` int NOT NULL COMMENT 'Kho xuất',
+  `to_warehouse_id` int NOT NULL COMMENT 'Kho nhận',
+  `transfer_date` datetime NOT NULL COMMENT 'Ngày chuyển kho',
+  `note` text COMMENT 'Ghi chú',
+  `created_by` int DEFAULT NULL COMMENT 'Người tạo',
+  `created_by_name` varchar(255) NOT NULL COMMENT 'Tên người tạo',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_stock_transfers_code` (`code`),
+  KEY `from_warehouse_id` (`from_warehouse_id`),
+  KEY `to_warehouse_id` (`to_warehouse_id`),
+  CONSTRAINT `stock_transfers_ibfk_1` FOREIGN KEY (`from_warehouse_id`) REFERENCES `warehouses` (`id`),
+  CONSTRAINT `stock_transfers_ibfk_2` FOREIGN KEY (`to_warehouse_id`) REFERENCES `warehouses` (`id`),
+  CONSTRAINT `stock_transfers_ibfk_3` FOREIGN KEY (`created_by`) REFERENCES `users` (`id`),
+  CONSTRAINT `check_stock_transfers_warehouses` CHECK (`from_warehouse_id` <> `to_warehouse_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE `stock_transfer_items` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `stock_transfer_id` int NOT NULL COMMENT 'Phiếu chuyển kho',
+  `product_id` int NOT NULL COMMENT 'Sản phẩm',
+  `quantity` int NOT NULL COMMENT 'Số lượng chuyển (theo đơn vị cơ bản)',
+  `note` text COMMENT 'Ghi chú',
+  PRIMARY KEY (`id`),
+  KEY `stock_transfer_id` (`stock_transfer_id`),
+  KEY `product_id` (`product_id`),
+  CONSTRAINT `stock_transfer_items_ibfk_1` FOREIGN KEY (`stock_transfer_id`) REFERENCES `stock_transfers` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `stock_transfer_items_ibfk_2` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `check_stock_transfer_items_quantity` CHECK (`quantity` > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
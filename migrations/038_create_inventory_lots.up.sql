@@ -0,0 +1,44 @@
+CREATE TABLE `inventory_lots` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL COMMENT 'Sản phẩm',
+  `warehouse_id` int NOT NULL COMMENT 'Kho',
+  `lot_number` varchar(50) NOT NULL COMMENT 'Số lô',
+  `expiry_date` date DEFAULT NULL COMMENT 'Hạn sử dụng',
+  `quantity` int NOT NULL DEFAULT '0' COMMENT 'Tồn kho của lô (theo đơn vị cơ bản)',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_inventory_lots_product_warehouse_lot` (`product_id`, `warehouse_id`, `lot_number`),
+  KEY `idx_inventory_lots_fefo` (`product_id`, `warehouse_id`, `expiry_date`),
+  KEY `idx_inventory_lots_lot_number` (`lot_number`),
+  KEY `warehouse_id` (`warehouse_id`),
+  CONSTRAINT `inventory_lots_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `inventory_lots_ibfk_2` FOREIGN KEY (`warehouse_id`) REFERENCES `warehouses` (`id`),
+  CONSTRAINT `check_inventory_lots_quantity` CHECK (`quantity` >= 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE `inventory_receipt_items`
+  ADD COLUMN `lot_number` varchar(50) DEFAULT NULL COMMENT 'Số lô' AFTER `unit_cost`,
+  ADD COLUMN `expiry_date` date DEFAULT NULL COMMENT 'Hạn sử dụng' AFTER `lot_number`,
+  ADD COLUMN `inventory_lot_id` int DEFAULT NULL COMMENT 'Lô được nhập vào' AFTER `expiry_date`,
+  ADD KEY `inventory_lot_id` (`inventory_lot_id`),
+  ADD CONSTRAINT `inventory_receipt_items_ibfk_4` FOREIGN KEY (`inventory_lot_id`) REFERENCES `inventory_lots` (`id`);
+
+-- Ledger of the lots each order item drew, reversals are negative entries
+CREATE TABLE `order_item_lots` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `order_id` int NOT NULL COMMENT 'Đơn hàng',
+  `order_item_id` int DEFAULT NULL COMMENT 'Dòng đơn hàng (NULL khi là nguyên liệu của thành phẩm)',
+  `product_id` int NOT NULL COMMENT 'Sản phẩm xuất kho',
+  `inventory_lot_id` int NOT NULL COMMENT 'Lô xuất kho',
+  `quantity` int NOT NULL COMMENT 'Số lượng (dương = xuất, âm = hoàn lại lô)',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `order_id` (`order_id`),
+  KEY `order_item_id` (`order_item_id`),
+  KEY `inventory_lot_id` (`inventory_lot_id`),
+  CONSTRAINT `order_item_lots_ibfk_1` FOREIGN KEY (`order_id`) REFERENCES `orders` (`id`),
+  CONSTRAINT `order_item_lots_ibfk_2` FOREIGN KEY (`order_item_id`) REFERENCES `order_items` (`id`) ON DELETE SET NULL,
+  CONSTRAINT `order_item_lots_ibfk_3` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `order_item_lots_ibfk_4` FOREIGN KEY (`inventory_lot_id`) REFERENCES `inventory_lots` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
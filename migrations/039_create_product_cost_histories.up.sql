@@ -0,0 +1,17 @@
+-- Moving weighted-average cost changes of products, one row per receipt line or manual correction
+CREATE TABLE `product_cost_histories` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL COMMENT 'Sản phẩm',
+  `previous_cost` decimal(10,3) NOT NULL COMMENT 'Giá vốn trước thay đổi (VND)',
+  `new_cost` decimal(10,3) NOT NULL COMMENT 'Giá vốn sau thay đổi (VND)',
+  `on_hand_quantity` int NOT NULL DEFAULT '0' COMMENT 'Tồn kho (mọi kho) trước khi nhập',
+  `received_quantity` int NOT NULL DEFAULT '0' COMMENT 'Số lượng nhập (theo đơn vị cơ bản)',
+  `unit_cost` decimal(10,3) DEFAULT NULL COMMENT 'Đơn giá nhập theo đơn vị cơ bản',
+  `reference_type` varchar(50) NOT NULL COMMENT 'Nguồn thay đổi (INVENTORY_RECEIPT, MANUAL)',
+  `reference_id` int DEFAULT NULL COMMENT 'Chứng từ gây thay đổi',
+  `created_by_name` varchar(255) DEFAULT NULL COMMENT 'Người thực hiện',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_product_cost_histories_product` (`product_id`, `created_at`),
+  CONSTRAINT `product_cost_histories_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,79 @@
+-- FIFO cost layers, one per stock inflow. Layers are kept per product across warehouses since a
+-- stock transfer does not change what the stock cost.
+CREATE TABLE `inventory_cost_layers` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `product_id` int NOT NULL COMMENT 'Sản phẩm',
+  `source_type` varchar(50) NOT NULL COMMENT 'Nguồn nhập (OPENING_BALANCE, INVENTORY_RECEIPT, INVENTORY_ADJUSTMENT, WORK_ORDER, RETURN)',
+  `source_id` int DEFAULT NULL COMMENT 'Chứng từ nhập',
+  `inventory_receipt_item_id` int DEFAULT NULL COMMENT 'Dòng phiếu nhập tạo lớp giá',
+  `unit_cost` decimal(10,3) NOT NULL COMMENT 'Đơn giá theo đơn vị cơ bản (VND)',
+  `quantity` int NOT NULL COMMENT 'Số lượng nhập (theo đơn vị cơ bản)',
+  `remaining_quantity` int NOT NULL COMMENT 'Số lượng còn lại chưa xuất',
+  `received_at` datetime NOT NULL COMMENT 'Thời điểm nhập, thứ tự xuất FIFO',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_inventory_cost_layers_fifo` (`product_id`, `received_at`, `id`),
+  KEY `inventory_receipt_item_id` (`inventory_receipt_item_id`),
+  CONSTRAINT `inventory_cost_layers_ibfk_1` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `inventory_cost_layers_ibfk_2` FOREIGN KEY (`inventory_receipt_item_id`) REFERENCES `inventory_receipt_items` (`id`),
+  CONSTRAINT `check_inventory_cost_layers_quantity` CHECK (`quantity` > 0 AND `remaining_quantity` >= 0 AND `remaining_quantity` <= `quantity`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Signed ledger of what each document took from (negative) or put back into (positive) the layers
+CREATE TABLE `inventory_cost_layer_movements` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `cost_layer_id` int NOT NULL COMMENT 'Lớp giá',
+  `product_id` int NOT NULL COMMENT 'Sản phẩm',
+  `reference_type` varchar(50) NOT NULL COMMENT 'Chứng từ (ORDER, WORK_ORDER, INVENTORY_ADJUSTMENT)',
+  `reference_id` int DEFAULT NULL COMMENT 'ID chứng từ',
+  `quantity` int NOT NULL COMMENT 'Số lượng (âm = xuất khỏi lớp, dương = trả lại lớp)',
+  `unit_cost` decimal(10,3) NOT NULL COMMENT 'Đơn giá của lớp',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `cost_layer_id` (`cost_layer_id`),
+  KEY `idx_inventory_cost_layer_movements_reference` (`reference_type`, `reference_id`),
+  CONSTRAINT `inventory_cost_layer_movements_ibfk_1` FOREIGN KEY (`cost_layer_id`) REFERENCES `inventory_cost_layers` (`id`),
+  CONSTRAINT `inventory_cost_layer_movements_ibfk_2` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Stock on hand today is rebuilt from the latest receipt lines that cover it, newest first, each
+-- at its own unit cost and receipt date
+INSERT INTO `inventory_cost_layers` (`product_id`, `source_type`, `source_id`, `inventory_receipt_item_id`, `unit_cost`, `quantity`, `remaining_quantity`, `received_at`)
+SELECT `product_id`, 'INVENTORY_RECEIPT', `inventory_receipt_id`, `id`, `unit_cost`, `covered`, `covered`, `receipt_date`
+FROM (
+  SELECT ri.`id`, ri.`inventory_receipt_id`, ri.`product_id`, COALESCE(ri.`unit_cost`, p.`cost`) AS `unit_cost`,
+         COALESCE(r.`receipt_date`, r.`created_at`, NOW()) AS `receipt_date`,
+         -- What is left of the stock on hand once the newer receipt lines are taken off
+         LEAST(ri.`quantity`, oh.`on_hand` - (SUM(ri.`quantity`) OVER w - ri.`quantity`)) AS `covered`
+  FROM `inventory_receipt_items` ri
+  JOIN `inventory_receipts` r ON r.`id` = ri.`inventory_receipt_id`
+  JOIN `products` p ON p.`id` = ri.`product_id`
+  JOIN (
+    SELECT `product_id`, SUM(`quantity`) AS `on_hand`
+    FROM `inventory`
+    GROUP BY `product_id`
+    HAVING SUM(`quantity`) > 0
+  ) oh ON oh.`product_id` = ri.`product_id`
+  WHERE ri.`quantity` > 0
+  WINDOW w AS (PARTITION BY ri.`product_id` ORDER BY COALESCE(r.`receipt_date`, r.`created_at`) DESC, ri.`id` DESC ROWS UNBOUNDED PRECEDING)
+) newest
+WHERE `covered` > 0;
+
+-- What the receipts do not cover is older stock: one OPENING_BALANCE layer at the product's cost,
+-- dated before its receipt layers so that it is issued first
+INSERT INTO `inventory_cost_layers` (`product_id`, `source_type`, `unit_cost`, `quantity`, `remaining_quantity`, `received_at`)
+SELECT oh.`product_id`, 'OPENING_BALANCE', p.`cost`, oh.`on_hand` - COALESCE(l.`covered`, 0), oh.`on_hand` - COALESCE(l.`covered`, 0),
+       COALESCE(l.`oldest` - INTERVAL 1 SECOND, NOW())
+FROM (
+  SELECT `product_id`, SUM(`quantity`) AS `on_hand`
+  FROM `inventory`
+  GROUP BY `product_id`
+  HAVING SUM(`quantity`) > 0
+) oh
+JOIN `products` p ON p.`id` = oh.`product_id`
+LEFT JOIN (
+  SELECT `product_id`, SUM(`quantity`) AS `covered`, MIN(`received_at`) AS `oldest`
+  FROM `inventory_cost_layers`
+  GROUP BY `product_id`
+) l ON l.`product_id` = oh.`product_id`
+WHERE oh.`on_hand` > COALESCE(l.`covered`, 0);
@@ -0,0 +1,22 @@
+-- Ledger of the lots each work order drew its components from
+CREATE TABLE `work_order_lots` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `work_order_id` int NOT NULL COMMENT 'Lệnh sản xuất',
+  `product_id` int NOT NULL COMMENT 'Thành phần xuất kho',
+  `inventory_lot_id` int NOT NULL COMMENT 'Lô xuất kho',
+  `quantity` int NOT NULL COMMENT 'Số lượng đã tiêu hao từ lô (theo đơn vị cơ bản)',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `work_order_id` (`work_order_id`),
+  KEY `inventory_lot_id` (`inventory_lot_id`),
+  CONSTRAINT `work_order_lots_ibfk_1` FOREIGN KEY (`work_order_id`) REFERENCES `work_orders` (`id`),
+  CONSTRAINT `work_order_lots_ibfk_2` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`),
+  CONSTRAINT `work_order_lots_ibfk_3` FOREIGN KEY (`inventory_lot_id`) REFERENCES `inventory_lots` (`id`),
+  CONSTRAINT `check_work_order_lots_quantity` CHECK (`quantity` > 0)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- The lot the finished goods are received into, so a recalled component lot can be followed to them
+ALTER TABLE `work_orders`
+  ADD COLUMN `finished_lot_id` int DEFAULT NULL COMMENT 'Lô thành phẩm nhập kho' AFTER `completed_at`,
+  ADD KEY `finished_lot_id` (`finished_lot_id`),
+  ADD CONSTRAINT `work_orders_ibfk_4` FOREIGN KEY (`finished_lot_id`) REFERENCES `inventory_lots` (`id`);
@@ -0,0 +1,2 @@
+ALTER TABLE `work_order_items`
+  ADD COLUMN `unit_cost` decimal(10,3) NOT NULL DEFAULT '0' COMMENT 'Giá vốn nguyên liệu theo đơn vị cơ bản, theo phương pháp tính giá kho' AFTER `quantity`;
@@ -0,0 +1,4 @@
+-- Quotation items keep the unit they were quoted in, so the order converted from them sells the same quantity
+ALTER TABLE `quotation_items`
+ADD COLUMN `unit_id` int DEFAULT NULL COMMENT 'Đơn vị bán (NULL = đơn vị cơ bản của sản phẩm)' AFTER `quantity`,
+ADD CONSTRAINT `quotation_items_unit_fk` FOREIGN KEY (`unit_id`) REFERENCES `units_of_measure` (`id`);